@@ -10,16 +10,71 @@ const (
 	UserIDContextKey = "user-id"
 	// issuer is the issuer of the jwt token.
 	Issuer = "memos"
-	// Signing key section. For now, this is only used for signing, not for verifying since we only
-	// have 1 version. But it will be used to maintain backward compatibility if we change the signing mechanism.
+	// KeyID is the ID of the first key of the signing keyring, which is the secret session.
+	// The tokens signed before the keyring was introduced have it as their kid header.
 	KeyID = "v1"
 	// AccessTokenAudienceName is the audience name of the access token.
 	AccessTokenAudienceName = "user.access-token"
-	AccessTokenDuration     = 7 * 24 * time.Hour
+	// AccessTokenDuration is short, as the access token is renewed with the refresh token of the session.
+	AccessTokenDuration = 15 * time.Minute
+	// TwoFactorTokenAudienceName is the audience name of the token proving the password of a two-step sign-in.
+	TwoFactorTokenAudienceName = "user.two-factor"
+	// TwoFactorTokenDuration is how long the user has to enter the code of the two-step sign-in.
+	TwoFactorTokenDuration = 5 * time.Minute
+	// SSOStateAudienceName is the audience name of the token keeping the authorization request of a SSO sign-in.
+	SSOStateAudienceName = "user.sso-state"
+	// SSOStateDuration is how long the user has to sign in with the identity provider.
+	SSOStateDuration = 10 * time.Minute
+	// PasskeyRegistrationAudienceName is the audience name of the token keeping the challenge of a passkey registration.
+	PasskeyRegistrationAudienceName = "user.passkey-registration"
+	// PasskeySignInAudienceName is the audience name of the token keeping the challenge of a passkey sign-in.
+	PasskeySignInAudienceName = "user.passkey-signin"
+	// PasskeyChallengeDuration is how long the user has to answer the challenge with the authenticator.
+	PasskeyChallengeDuration = 5 * time.Minute
+	// PasswordResetTokenDuration is how long the link resetting the password of a user is valid.
+	PasswordResetTokenDuration = time.Hour
+	// EmailVerificationTokenDuration is how long the link verifying the email of a user is valid.
+	EmailVerificationTokenDuration = 24 * time.Hour
+	// RefreshTokenDuration is how long a session lasts without being used.
+	RefreshTokenDuration = 30 * 24 * time.Hour
 
-	// CookieExpDuration expires slightly earlier than the jwt expiration. Client would be logged out if the user
-	// cookie expires, thus the client would always logout first before attempting to make a request with the expired jwt.
-	CookieExpDuration = AccessTokenDuration - 1*time.Minute
+	// CookieExpDuration expires slightly earlier than the session expiration. Client would be logged out if the user
+	// cookie expires, thus the client would always logout first before attempting to make a request with the expired session.
+	CookieExpDuration = RefreshTokenDuration - 1*time.Minute
 	// AccessTokenCookieName is the cookie name of access token.
 	AccessTokenCookieName = "memos.access-token"
+	// RefreshTokenCookieName is the cookie name of refresh token.
+	RefreshTokenCookieName = "memos.refresh-token"
+	// SSOStateCookieName is the cookie name of the token keeping the authorization request of a SSO sign-in.
+	SSOStateCookieName = "memos.sso-state"
+	// PasskeyChallengeCookieName is the cookie name of the token keeping the challenge of a passkey registration or sign-in.
+	PasskeyChallengeCookieName = "memos.passkey-challenge"
+	// The key name used to store the session id in the context,
+	// session id is extracted from the jwt token id field.
+	SessionIDContextKey = "session-id"
 )
+
+const (
+	// PersonalAccessTokenPrefix is the prefix of personal access tokens, which tells them apart from JWTs.
+	PersonalAccessTokenPrefix = "memos_pat_"
+	// The key name used to store the scopes of the personal access token in the context,
+	// it's absent when the user is authenticated by the web session.
+	ScopesContextKey = "scopes"
+)
+
+// Scope is the permission granted to a personal access token.
+type Scope string
+
+const (
+	// ScopeMemoRead allows reading memos, tags, shortcuts and resources.
+	ScopeMemoRead Scope = "memo.read"
+	// ScopeMemoWrite allows creating, updating and deleting memos, tags and shortcuts.
+	ScopeMemoWrite Scope = "memo.write"
+	// ScopeResourceUpload allows uploading, updating and deleting resources.
+	ScopeResourceUpload Scope = "resource.upload"
+	// ScopeAdmin allows everything, including managing users and system settings.
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes are all the scopes of personal access tokens.
+var Scopes = []Scope{ScopeMemoRead, ScopeMemoWrite, ScopeResourceUpload, ScopeAdmin}
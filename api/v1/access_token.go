@@ -0,0 +1,280 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/store"
+	"golang.org/x/exp/slices"
+)
+
+// accessTokenUsageInterval is the interval to record the last usage of access tokens,
+// so that requests in a row don't all write to the database.
+const accessTokenUsageInterval = 60
+
+type AccessToken struct {
+	ID int32 `json:"id"`
+
+	// Standard fields
+	UserID    int32 `json:"userId"`
+	CreatedTs int64 `json:"createdTs"`
+
+	// Domain specific fields
+	Name       string       `json:"name"`
+	Scopes     []auth.Scope `json:"scopes"`
+	ExpiresTs  int64        `json:"expiresTs"`
+	LastUsedTs int64        `json:"lastUsedTs"`
+	// Token is only returned when the access token is created, as only its hash is stored.
+	Token string `json:"token,omitempty"`
+}
+
+type CreateAccessTokenRequest struct {
+	Name      string       `json:"name"`
+	Scopes    []auth.Scope `json:"scopes"`
+	ExpiresTs int64        `json:"expiresTs"`
+}
+
+func (s *APIV1Service) registerAccessTokenRoutes(g *echo.Group) {
+	g.GET("/access-token", s.GetAccessTokenList)
+	g.POST("/access-token", s.CreateAccessToken)
+	g.DELETE("/access-token/:accessTokenId", s.DeleteAccessToken)
+}
+
+// GetAccessTokenList godoc
+//
+//	@Summary	Get a list of personal access tokens of the current user
+//	@Tags		access-token
+//	@Produce	json
+//	@Success	200	{object}	[]AccessToken	"Access token list"
+//	@Failure	401	{object}	nil				"Missing user in session"
+//	@Failure	403	{object}	nil				"Access tokens can't be managed with access tokens"
+//	@Failure	500	{object}	nil				"Failed to fetch access token list"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/access-token [GET]
+func (s *APIV1Service) GetAccessTokenList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := getAccessTokenManager(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := s.Store.ListAccessTokens(ctx, &store.FindAccessToken{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch access token list").SetInternal(err)
+	}
+	accessTokenMessageList := []*AccessToken{}
+	for _, accessToken := range list {
+		accessTokenMessageList = append(accessTokenMessageList, convertAccessTokenFromStore(accessToken))
+	}
+	return c.JSON(http.StatusOK, accessTokenMessageList)
+}
+
+// CreateAccessToken godoc
+//
+//	@Summary	Create a personal access token
+//	@Tags		access-token
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		CreateAccessTokenRequest	true	"Request object."
+//	@Success	200		{object}	AccessToken					"Created access token, with the token which is only returned once"
+//	@Failure	400		{object}	nil							"Malformatted post access token request | Invalid access token request"
+//	@Failure	401		{object}	nil							"Missing user in session"
+//	@Failure	403		{object}	nil							"Access tokens can't be managed with access tokens"
+//	@Failure	500		{object}	nil							"Failed to generate access token | Failed to create access token"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/access-token [POST]
+func (s *APIV1Service) CreateAccessToken(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := getAccessTokenManager(c)
+	if err != nil {
+		return err
+	}
+
+	request := &CreateAccessTokenRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post access token request").SetInternal(err)
+	}
+	if err := request.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid access token request").SetInternal(err)
+	}
+
+	token, err := generatePersonalAccessToken()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate access token").SetInternal(err)
+	}
+	scopes := []string{}
+	for _, scope := range request.Scopes {
+		if !slices.Contains(scopes, string(scope)) {
+			scopes = append(scopes, string(scope))
+		}
+	}
+	accessToken, err := s.Store.CreateAccessToken(ctx, &store.AccessToken{
+		UserID:    userID,
+		Name:      request.Name,
+		TokenHash: HashAccessToken(token),
+		Scopes:    scopes,
+		ExpiresTs: request.ExpiresTs,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create access token").SetInternal(err)
+	}
+
+	accessTokenMessage := convertAccessTokenFromStore(accessToken)
+	accessTokenMessage.Token = token
+	return c.JSON(http.StatusOK, accessTokenMessage)
+}
+
+// DeleteAccessToken godoc
+//
+//	@Summary	Revoke a personal access token
+//	@Tags		access-token
+//	@Produce	json
+//	@Param		accessTokenId	path		int		true	"Access token ID"
+//	@Success	200				{boolean}	true	"Access token revoked"
+//	@Failure	400				{object}	nil		"ID is not a number: %s"
+//	@Failure	401				{object}	nil		"Missing user in session"
+//	@Failure	403				{object}	nil		"Access tokens can't be managed with access tokens"
+//	@Failure	404				{object}	nil		"Access token not found: %d"
+//	@Failure	500				{object}	nil		"Failed to find access token | Failed to delete access token"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/access-token/{accessTokenId} [DELETE]
+func (s *APIV1Service) DeleteAccessToken(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := getAccessTokenManager(c)
+	if err != nil {
+		return err
+	}
+	accessTokenID, err := util.ConvertStringToInt32(c.Param("accessTokenId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("accessTokenId"))).SetInternal(err)
+	}
+
+	accessToken, err := s.Store.GetAccessToken(ctx, &store.FindAccessToken{
+		ID:     &accessTokenID,
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find access token").SetInternal(err)
+	}
+	if accessToken == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Access token not found: %d", accessTokenID))
+	}
+
+	if err := s.Store.DeleteAccessToken(ctx, &store.DeleteAccessToken{
+		ID: &accessToken.ID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete access token").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+func (create CreateAccessTokenRequest) Validate() error {
+	if create.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(create.Name) > 64 {
+		return fmt.Errorf("name is too long, maximum length is 64")
+	}
+	if len(create.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range create.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+	if create.ExpiresTs <= time.Now().Unix() {
+		return fmt.Errorf("expiry must be in the future")
+	}
+	return nil
+}
+
+// getAccessTokenManager returns the ID of the current user, who must be signed in with the web session,
+// so that a leaked access token can't be used to create others.
+func getAccessTokenManager(c echo.Context) (int32, error) {
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return 0, echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	if _, ok := c.Get(auth.ScopesContextKey).([]string); ok {
+		return 0, echo.NewHTTPError(http.StatusForbidden, "Access tokens can't be managed with access tokens")
+	}
+	return userID, nil
+}
+
+// generatePersonalAccessToken returns a new random personal access token.
+func generatePersonalAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return auth.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAccessToken returns the hash of a personal access token or a refresh token to store.
+// The tokens are random enough that a fast hash is as safe as a slow one.
+func HashAccessToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// AuthenticatePersonalAccessToken returns the access token matching the token, and records its usage.
+// It returns nil if the token doesn't exist or has expired.
+func AuthenticatePersonalAccessToken(ctx context.Context, s *store.Store, token string) (*store.AccessToken, error) {
+	tokenHash := HashAccessToken(token)
+	accessToken, err := s.GetAccessToken(ctx, &store.FindAccessToken{
+		TokenHash: &tokenHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find access token")
+	}
+	currentTs := time.Now().Unix()
+	if accessToken == nil || accessToken.ExpiresTs <= currentTs {
+		return nil, nil
+	}
+
+	if currentTs-accessToken.LastUsedTs >= accessTokenUsageInterval {
+		if err := s.UpdateAccessToken(ctx, &store.UpdateAccessToken{
+			ID:         accessToken.ID,
+			LastUsedTs: &currentTs,
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to update access token")
+		}
+		accessToken.LastUsedTs = currentTs
+	}
+	return accessToken, nil
+}
+
+// HasScope returns whether the scopes grant the scope, the admin scope grants all of them.
+func HasScope(scopes []string, scope auth.Scope) bool {
+	return slices.Contains(scopes, string(scope)) || slices.Contains(scopes, string(auth.ScopeAdmin))
+}
+
+func convertAccessTokenFromStore(accessToken *store.AccessToken) *AccessToken {
+	scopes := []auth.Scope{}
+	for _, scope := range accessToken.Scopes {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+	return &AccessToken{
+		ID:         accessToken.ID,
+		UserID:     accessToken.UserID,
+		CreatedTs:  accessToken.CreatedTs,
+		Name:       accessToken.Name,
+		Scopes:     scopes,
+		ExpiresTs:  accessToken.ExpiresTs,
+		LastUsedTs: accessToken.LastUsedTs,
+	}
+}
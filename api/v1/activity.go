@@ -43,6 +43,8 @@ const (
 	ActivityTagCreate ActivityType = "tag.create"
 	// ActivityTagDelete is the type for deleting tags.
 	ActivityTagDelete ActivityType = "tag.delete"
+	// ActivityTagRename is the type for renaming or merging tags in memos.
+	ActivityTagRename ActivityType = "tag.rename"
 
 	// Server related.
 
@@ -101,6 +103,12 @@ type ActivityTagCreatePayload struct {
 	TagName string `json:"tagName"`
 }
 
+type ActivityTagRenamePayload struct {
+	TagNameList []string `json:"tagNameList"`
+	NewTagName  string   `json:"newTagName"`
+	MemoCount   int      `json:"memoCount"`
+}
+
 type ActivityServerStartPayload struct {
 	ServerID string           `json:"serverId"`
 	Profile  *profile.Profile `json:"profile"`
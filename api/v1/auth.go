@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,8 @@ import (
 	"github.com/usememos/memos/common/util"
 	"github.com/usememos/memos/plugin/idp"
 	"github.com/usememos/memos/plugin/idp/oauth2"
+	"github.com/usememos/memos/plugin/idp/oidc"
+	"github.com/usememos/memos/plugin/mail"
 	"github.com/usememos/memos/store"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -24,18 +27,30 @@ type SSOSignIn struct {
 	IdentityProviderID int32  `json:"identityProviderId"`
 	Code               string `json:"code"`
 	RedirectURI        string `json:"redirectUri"`
+	// State is the state of the authorization request, which is required by OpenID Connect identity providers.
+	State string `json:"state"`
 }
 
 type SignUp struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// Email is required if the instance requires verified email, the user signs in once it's verified.
+	Email string `json:"email"`
 }
 
 func (s *APIV1Service) registerAuthRoutes(g *echo.Group) {
 	g.POST("/auth/signin", s.SignIn)
 	g.POST("/auth/signin/sso", s.SignInSSO)
+	g.POST("/auth/sso/authorize", s.AuthorizeSSO)
+	g.POST("/auth/signin/totp", s.SignInTOTP)
+	g.POST("/auth/signin/passkey", s.SignInPasskey)
+	g.POST("/auth/passkey/challenge", s.CreatePasskeySignInChallenge)
 	g.POST("/auth/signout", s.SignOut)
+	g.POST("/auth/refresh", s.RefreshSession)
 	g.POST("/auth/signup", s.SignUp)
+	g.POST("/auth/password-reset", s.RequestPasswordReset)
+	g.POST("/auth/password-reset/confirm", s.ResetPassword)
+	g.POST("/auth/email-verification", s.VerifyEmail)
 }
 
 // SignIn godoc
@@ -44,12 +59,13 @@ func (s *APIV1Service) registerAuthRoutes(g *echo.Group) {
 //	@Tags		auth
 //	@Accept		json
 //	@Produce	json
-//	@Param		body	body		SignIn		true	"Sign-in object"
-//	@Success	200		{object}	store.User	"User information"
-//	@Failure	400		{object}	nil			"Malformatted signin request"
-//	@Failure	401		{object}	nil			"Password login is deactivated | Incorrect login credentials, please try again"
-//	@Failure	403		{object}	nil			"User has been archived with username %s"
-//	@Failure	500		{object}	nil			"Failed to find system setting | Failed to unmarshal system setting | Incorrect login credentials, please try again | Failed to generate tokens | Failed to create activity"
+//	@Param		body	body		SignIn				true	"Sign-in object"
+//	@Success	200		{object}	store.User			"User information"
+//	@Success	202		{object}	TwoFactorChallenge	"Two-factor authentication code required, sign in with it at /api/v1/auth/signin/totp"
+//	@Failure	400		{object}	nil					"Malformatted signin request"
+//	@Failure	401		{object}	nil					"Password login is deactivated | Incorrect login credentials, please try again"
+//	@Failure	403		{object}	nil					"User has been archived with username %s | Email address is not verified, please open the link sent to it"
+//	@Failure	500		{object}	nil					"Failed to find system setting | Failed to unmarshal system setting | Failed to sign in with LDAP | Incorrect login credentials, please try again | Failed to find token | Failed to generate two-factor challenge | Failed to generate tokens | Failed to create activity"
 //	@Router		/api/v1/auth/signin [POST]
 func (s *APIV1Service) SignIn(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -76,25 +92,61 @@ func (s *APIV1Service) SignIn(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted signin request").SetInternal(err)
 	}
 
-	user, err := s.Store.GetUser(ctx, &store.FindUser{
-		Username: &signin.Username,
-	})
+	// The users of the LDAP directories sign in with their directory password, the others with their local password.
+	user, err := s.signInWithLDAP(ctx, signin.Username, signin.Password)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Incorrect login credentials, please try again")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to sign in with LDAP").SetInternal(err)
 	}
-	if user == nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect login credentials, please try again")
-	} else if user.RowStatus == store.Archived {
-		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", signin.Username))
+	if user != nil {
+		if user.RowStatus == store.Archived {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", user.Username))
+		}
+	} else {
+		user, err = s.Store.GetUser(ctx, &store.FindUser{
+			Username: &signin.Username,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Incorrect login credentials, please try again")
+		}
+		if user == nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect login credentials, please try again")
+		} else if user.RowStatus == store.Archived {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", signin.Username))
+		}
+		// The users linked to an identity provider sign in with it, not with a local password.
+		if user.IdentityProviderID != 0 {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect login credentials, please try again")
+		}
+
+		// Compare the stored hashed password, with the hashed version of the password that was received.
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(signin.Password)); err != nil {
+			// If the two passwords don't match, return a 401 status.
+			return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect login credentials, please try again")
+		}
+
+		// The users signing up with an unverified email are let in once they open the link sent to it.
+		pending, err := IsEmailVerificationPending(ctx, s.Store, user)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+		}
+		if pending {
+			if err := s.resendEmailVerification(c, user); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find token").SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusForbidden, "Email address is not verified, please open the link sent to it")
+		}
 	}
 
-	// Compare the stored hashed password, with the hashed version of the password that was received.
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(signin.Password)); err != nil {
-		// If the two passwords don't match, return a 401 status.
-		return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect login credentials, please try again")
+	// The sign-in of users with two-factor authentication is finished with a code at the second step.
+	twoFactorChallenge, err := s.getTwoFactorChallenge(ctx, user)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate two-factor challenge").SetInternal(err)
+	}
+	if twoFactorChallenge != nil {
+		return c.JSON(http.StatusAccepted, twoFactorChallenge)
 	}
 
-	if err := GenerateTokensAndSetCookies(c, user, s.Secret); err != nil {
+	if err := GenerateTokensAndSetCookies(c, s.Store, user); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate tokens").SetInternal(err)
 	}
 	if err := s.createAuthSignInActivity(c, user); err != nil {
@@ -112,11 +164,12 @@ func (s *APIV1Service) SignIn(c echo.Context) error {
 //	@Produce	json
 //	@Param		body	body		SSOSignIn	true	"SSO sign-in object"
 //	@Success	200		{object}	store.User	"User information"
-//	@Failure	400		{object}	nil			"Malformatted signin request"
-//	@Failure	401		{object}	nil			"Access denied, identifier does not match the filter."
+//	@Failure	400		{object}	nil			"Malformatted signin request | Invalid or expired authorization state, please sign in again | Unsupported identity provider type %s"
+//	@Failure	401		{object}	nil			"Access denied, identifier does not match the filter. | Failed to verify ID token | Access denied, signing up with the identity provider is disabled"
 //	@Failure	403		{object}	nil			"User has been archived with username {username}"
 //	@Failure	404		{object}	nil			"Identity provider not found"
-//	@Failure	500		{object}	nil			"Failed to find identity provider | Failed to create identity provider instance | Failed to exchange token | Failed to get user info | Failed to compile identifier filter | Incorrect login credentials, please try again | Failed to generate random password | Failed to generate password hash | Failed to create user | Failed to generate tokens | Failed to create activity"
+//	@Failure	409		{object}	nil			"Username %s is taken by a user which isn't linked to the identity provider"
+//	@Failure	500		{object}	nil			"Failed to find identity provider | Failed to create identity provider instance | Failed to exchange token | Failed to get user info | Failed to compile identifier filter | Failed to provision user | Failed to generate tokens | Failed to create activity"
 //	@Router		/api/v1/auth/signin/sso [POST]
 func (s *APIV1Service) SignInSSO(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -149,6 +202,29 @@ func (s *APIV1Service) SignInSSO(c echo.Context) error {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user info").SetInternal(err)
 		}
+	} else if identityProvider.Type == store.IdentityProviderOIDCType {
+		ssoState, err := consumeSSOState(c, s.Store, identityProvider.ID, signin.State)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired authorization state, please sign in again").SetInternal(err)
+		}
+		oidcIdentityProvider, err := oidc.NewIdentityProvider(identityProvider.Config.OIDCConfig)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create identity provider instance").SetInternal(err)
+		}
+		idToken, err := oidcIdentityProvider.ExchangeToken(ctx, ssoState.RedirectURI, signin.Code, ssoState.CodeVerifier)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to exchange token").SetInternal(err)
+		}
+		claims, err := oidcIdentityProvider.VerifyIDToken(ctx, idToken, ssoState.Nonce)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Failed to verify ID token").SetInternal(err)
+		}
+		userInfo, err = oidcIdentityProvider.UserInfo(claims)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user info").SetInternal(err)
+		}
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported identity provider type %s", identityProvider.Type))
 	}
 
 	identifierFilter := identityProvider.IdentifierFilter
@@ -162,40 +238,21 @@ func (s *APIV1Service) SignInSSO(c echo.Context) error {
 		}
 	}
 
-	user, err := s.Store.GetUser(ctx, &store.FindUser{
-		Username: &userInfo.Identifier,
-	})
+	user, err := s.provisionIdentityProviderUser(ctx, identityProvider, userInfo)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Incorrect login credentials, please try again")
+		if errors.Is(err, errIdentityProviderUsernameTaken) {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Username %s is taken by a user which isn't linked to the identity provider", userInfo.Identifier))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to provision user").SetInternal(err)
 	}
 	if user == nil {
-		userCreate := &store.User{
-			Username: userInfo.Identifier,
-			// The new signup user should be normal user by default.
-			Role:     store.RoleUser,
-			Nickname: userInfo.DisplayName,
-			Email:    userInfo.Email,
-			OpenID:   util.GenUUID(),
-		}
-		password, err := util.RandomString(20)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate random password").SetInternal(err)
-		}
-		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
-		}
-		userCreate.PasswordHash = string(passwordHash)
-		user, err = s.Store.CreateUser(ctx, userCreate)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create user").SetInternal(err)
-		}
+		return echo.NewHTTPError(http.StatusUnauthorized, "Access denied, signing up with the identity provider is disabled")
 	}
 	if user.RowStatus == store.Archived {
 		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", userInfo.Identifier))
 	}
 
-	if err := GenerateTokensAndSetCookies(c, user, s.Secret); err != nil {
+	if err := GenerateTokensAndSetCookies(c, s.Store, user); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate tokens").SetInternal(err)
 	}
 	if err := s.createAuthSignInActivity(c, user); err != nil {
@@ -205,14 +262,64 @@ func (s *APIV1Service) SignInSSO(c echo.Context) error {
 	return c.JSON(http.StatusOK, userMessage)
 }
 
+// createIdentityProviderUser creates the user signing in with an identity provider for the first time,
+// which is linked to the identity provider.
+// The user has a random password, as it always signs in with the identity provider.
+func (s *APIV1Service) createIdentityProviderUser(ctx context.Context, identityProvider *store.IdentityProvider, userInfo *idp.IdentityProviderUserInfo) (*store.User, error) {
+	userCreate := &store.User{
+		Username: userInfo.Identifier,
+		// The new signup user should be normal user by default.
+		Role:                       store.RoleUser,
+		Nickname:                   userInfo.DisplayName,
+		Email:                      userInfo.Email,
+		AvatarURL:                  userInfo.AvatarURL,
+		OpenID:                     util.GenUUID(),
+		IdentityProviderID:         identityProvider.ID,
+		IdentityProviderIdentifier: userInfo.Identifier,
+	}
+	// The email is only verified if the identity provider asserts it.
+	if userInfo.EmailVerified {
+		userCreate.VerifiedEmail = userInfo.Email
+	}
+	password, err := util.RandomString(20)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate random password")
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate password hash")
+	}
+	userCreate.PasswordHash = string(passwordHash)
+	return s.Store.CreateUser(ctx, userCreate)
+}
+
 // SignOut godoc
 //
 //	@Summary	Sign-out from memos.
 //	@Tags		auth
 //	@Produce	json
 //	@Success	200	{boolean}	true	"Sign-out success"
+//	@Failure	500	{object}	nil		"Failed to find session | Failed to delete session"
 //	@Router		/api/v1/auth/signout [POST]
-func (*APIV1Service) SignOut(c echo.Context) error {
+func (s *APIV1Service) SignOut(c echo.Context) error {
+	ctx := c.Request().Context()
+	// Revoke the session, so that its tokens can't be used anymore.
+	if refreshToken := findRefreshToken(c); refreshToken != "" {
+		refreshTokenHash := HashAccessToken(refreshToken)
+		session, err := s.Store.GetSession(ctx, &store.FindSession{
+			RefreshTokenHash: &refreshTokenHash,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find session").SetInternal(err)
+		}
+		if session != nil {
+			if err := s.Store.DeleteSession(ctx, &store.DeleteSession{
+				ID: &session.ID,
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete session").SetInternal(err)
+			}
+		}
+	}
 	RemoveTokensAndCookies(c)
 	return c.JSON(http.StatusOK, true)
 }
@@ -225,11 +332,12 @@ func (*APIV1Service) SignOut(c echo.Context) error {
 //	@Produce	json
 //	@Param		body	body		SignUp		true	"Sign-up object"
 //	@Success	200		{object}	store.User	"User information"
-//	@Failure	400		{object}	nil			"Malformatted signup request | Failed to find users"
+//	@Success	202		{object}	store.User	"User information without the open ID, the user signs in once the email is verified with the link sent to it"
+//	@Failure	400		{object}	nil			"Malformatted signup request | Failed to find users | Invalid email format | Email is required to sign up"
 //	@Failure	401		{object}	nil			"signup is disabled"
 //	@Failure	403		{object}	nil			"Forbidden"
 //	@Failure	404		{object}	nil			"Not found"
-//	@Failure	500		{object}	nil			"Failed to find system setting | Failed to unmarshal system setting allow signup | Failed to generate password hash | Failed to create user | Failed to generate tokens | Failed to create activity"
+//	@Failure	500		{object}	nil			"Failed to find system setting | Failed to unmarshal system setting allow signup | Failed to generate password hash | Failed to create user | Failed to send email | Failed to generate tokens | Failed to create activity"
 //	@Router		/api/v1/auth/signup [POST]
 func (s *APIV1Service) SignUp(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -246,10 +354,15 @@ func (s *APIV1Service) SignUp(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Failed to find users").SetInternal(err)
 	}
 
+	if signup.Email != "" && (len(signup.Email) > 256 || !util.ValidateEmail(signup.Email)) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid email format")
+	}
+
 	userCreate := &store.User{
 		Username: signup.Username,
 		// The new signup user should be normal user by default.
 		Role:     store.RoleUser,
+		Email:    signup.Email,
 		Nickname: signup.Username,
 		OpenID:   util.GenUUID(),
 	}
@@ -276,6 +389,27 @@ func (s *APIV1Service) SignUp(c echo.Context) error {
 		}
 	}
 
+	// The host signing up first can't verify its email, as SMTP is configured afterwards.
+	var mailConfig *mail.Config
+	if userCreate.Role == store.RoleUser {
+		requireEmailVerification, err := s.isEmailVerificationRequired(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+		}
+		if requireEmailVerification {
+			if signup.Email == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "Email is required to sign up")
+			}
+			mailConfig, err = s.getMailConfig(ctx)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+			}
+			if mailConfig == nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to send email").SetInternal(errors.New("smtp is not configured"))
+			}
+		}
+	}
+
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(signup.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
@@ -286,7 +420,20 @@ func (s *APIV1Service) SignUp(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create user").SetInternal(err)
 	}
-	if err := GenerateTokensAndSetCookies(c, user, s.Secret); err != nil {
+	if mailConfig != nil {
+		if err := s.createAuthSignUpActivity(c, user); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+		}
+		// The user signs in once the email is verified, signing in again resends the link if this one is lost.
+		if err := s.sendMailToken(c, mailConfig, user, store.MailTokenEmailVerification); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to send email").SetInternal(err)
+		}
+		// The open ID would sign in without verifying the email.
+		userMessage := convertUserFromStore(user)
+		userMessage.OpenID = ""
+		return c.JSON(http.StatusAccepted, userMessage)
+	}
+	if err := GenerateTokensAndSetCookies(c, s.Store, user); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate tokens").SetInternal(err)
 	}
 	if err := s.createAuthSignUpActivity(c, user); err != nil {
@@ -23,53 +23,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/auth/signin": {
-            "post": {
-                "consumes": [
-                    "application/json"
+        "/api/v1/access-token": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "Sign-in to memos.",
-                "parameters": [
-                    {
-                        "description": "Sign-in object",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.SignIn"
-                        }
-                    }
+                    "access-token"
                 ],
+                "summary": "Get a list of personal access tokens of the current user",
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "Access token list",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.AccessToken"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Malformatted signin request"
-                    },
                     "401": {
-                        "description": "Password login is deactivated | Incorrect login credentials, please try again"
+                        "description": "Missing user in session"
                     },
                     "403": {
-                        "description": "User has been archived with username %s"
+                        "description": "Access tokens can't be managed with access tokens"
                     },
                     "500": {
-                        "description": "Failed to find system setting | Failed to unmarshal system setting | Incorrect login credentials, please try again | Failed to generate tokens | Failed to create activity"
+                        "description": "Failed to fetch access token list"
                     }
                 }
-            }
-        },
-        "/api/v1/auth/signin/sso": {
+            },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -77,65 +71,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "access-token"
                 ],
-                "summary": "Sign-in to memos using SSO.",
+                "summary": "Create a personal access token",
                 "parameters": [
                     {
-                        "description": "SSO sign-in object",
+                        "description": "Request object.",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.SSOSignIn"
+                            "$ref": "#/definitions/v1.CreateAccessTokenRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "Created access token, with the token which is only returned once",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "$ref": "#/definitions/v1.AccessToken"
                         }
                     },
                     "400": {
-                        "description": "Malformatted signin request"
+                        "description": "Malformatted post access token request | Invalid access token request"
                     },
                     "401": {
-                        "description": "Access denied, identifier does not match the filter."
+                        "description": "Missing user in session"
                     },
                     "403": {
-                        "description": "User has been archived with username {username}"
-                    },
-                    "404": {
-                        "description": "Identity provider not found"
+                        "description": "Access tokens can't be managed with access tokens"
                     },
                     "500": {
-                        "description": "Failed to find identity provider | Failed to create identity provider instance | Failed to exchange token | Failed to get user info | Failed to compile identifier filter | Incorrect login credentials, please try again | Failed to generate random password | Failed to generate password hash | Failed to create user | Failed to generate tokens | Failed to create activity"
+                        "description": "Failed to generate access token | Failed to create access token"
                     }
                 }
             }
         },
-        "/api/v1/auth/signout": {
-            "post": {
+        "/api/v1/access-token/{accessTokenId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "access-token"
+                ],
+                "summary": "Revoke a personal access token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Access token ID",
+                        "name": "accessTokenId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Sign-out from memos.",
                 "responses": {
                     "200": {
-                        "description": "Sign-out success",
+                        "description": "Access token revoked",
                         "schema": {
                             "type": "boolean"
                         }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "403": {
+                        "description": "Access tokens can't be managed with access tokens"
+                    },
+                    "404": {
+                        "description": "Access token not found: %d"
+                    },
+                    "500": {
+                        "description": "Failed to find access token | Failed to delete access token"
                     }
                 }
             }
         },
-        "/api/v1/auth/signup": {
+        "/api/v1/auth/email-verification": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -146,74 +166,60 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Sign-up to memos.",
+                "summary": "Verify the email of a user with the token of an email verification link",
                 "parameters": [
                     {
-                        "description": "Sign-up object",
+                        "description": "Verify email request object",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.SignUp"
+                            "$ref": "#/definitions/v1.VerifyEmailRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "Email verified",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "Malformatted signup request | Failed to find users"
-                    },
-                    "401": {
-                        "description": "signup is disabled"
-                    },
-                    "403": {
-                        "description": "Forbidden"
-                    },
-                    "404": {
-                        "description": "Not found"
+                        "description": "Malformatted verify email request | Invalid or expired token, please request another link"
                     },
                     "500": {
-                        "description": "Failed to find system setting | Failed to unmarshal system setting allow signup | Failed to generate password hash | Failed to create user | Failed to generate tokens | Failed to create activity"
+                        "description": "Failed to find token | Failed to find user | Failed to update user"
                     }
                 }
             }
         },
-        "/api/v1/idp": {
-            "get": {
-                "description": "*clientSecret is only available for host user",
+        "/api/v1/auth/passkey/challenge": {
+            "post": {
+                "description": "The challenge is kept in a cookie, which is checked by the passkey sign-in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "idp"
+                    "auth"
                 ],
-                "summary": "Get a list of identity providers",
+                "summary": "Start a passkey sign-in",
                 "responses": {
                     "200": {
-                        "description": "List of available identity providers",
+                        "description": "Options of navigator.credentials.get()",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v1.IdentityProvider"
-                            }
+                            "$ref": "#/definitions/v1.PasskeySignInChallenge"
                         }
                     },
                     "500": {
-                        "description": "Failed to find identity provider list | Failed to find user"
+                        "description": "Failed to generate passkey challenge"
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/auth/password-reset": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+                "description": "The response doesn't tell whether a user has the email.",
                 "consumes": [
                     "application/json"
                 ],
@@ -221,46 +227,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "idp"
+                    "auth"
                 ],
-                "summary": "Create Identity Provider",
+                "summary": "Send a link resetting the password to the users with the email",
                 "parameters": [
                     {
-                        "description": "Identity provider information",
+                        "description": "Password reset request object",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateIdentityProviderRequest"
+                            "$ref": "#/definitions/v1.RequestPasswordResetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Identity provider information",
+                        "description": "Password reset requested",
                         "schema": {
-                            "$ref": "#/definitions/store.IdentityProvider"
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post identity provider request"
+                        "description": "Malformatted password reset request | Invalid email format | Mail is not configured"
                     },
                     "401": {
-                        "description": "Missing user in session | Unauthorized"
+                        "description": "Password login is deactivated"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to create identity provider"
+                        "description": "Failed to find system setting | Failed to find users"
                     }
                 }
             }
         },
-        "/api/v1/idp/{idpId}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+        "/api/v1/auth/password-reset/confirm": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -268,45 +269,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "idp"
+                    "auth"
                 ],
-                "summary": "Get an identity provider by ID",
+                "summary": "Reset the password with the token of a password reset link",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Identity provider ID",
-                        "name": "idpId",
-                        "in": "path",
-                        "required": true
+                        "description": "Reset password request object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Requested identity provider",
+                        "description": "Password reset",
                         "schema": {
-                            "$ref": "#/definitions/store.IdentityProvider"
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s"
+                        "description": "Malformatted reset password request | Invalid reset password request | Invalid or expired token, please request another link"
                     },
                     "401": {
-                        "description": "Missing user in session | Unauthorized"
+                        "description": "Password login is deactivated"
                     },
-                    "404": {
-                        "description": "Identity provider not found"
+                    "403": {
+                        "description": "User has been archived with username %s"
                     },
                     "500": {
-                        "description": "Failed to find identity provider list | Failed to find user"
+                        "description": "Failed to find system setting | Failed to find token | Failed to find user | Failed to generate password hash | Failed to update user | Failed to revoke user credentials"
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
                 ],
+                "summary": "Renew the access token with the refresh token of the session, which is rotated.",
+                "responses": {
+                    "200": {
+                        "description": "User information",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired refresh token"
+                    },
+                    "500": {
+                        "description": "Failed to refresh session | Failed to find user"
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/signin": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -314,42 +339,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "idp"
+                    "auth"
                 ],
-                "summary": "Delete an identity provider by ID",
+                "summary": "Sign-in to memos.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Identity Provider ID",
-                        "name": "idpId",
-                        "in": "path",
-                        "required": true
+                        "description": "Sign-in object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SignIn"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Identity Provider deleted",
+                        "description": "User information",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "202": {
+                        "description": "Two-factor authentication code required, sign in with it at /api/v1/auth/signin/totp",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TwoFactorChallenge"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted patch identity provider request"
+                        "description": "Malformatted signin request"
                     },
                     "401": {
-                        "description": "Missing user in session | Unauthorized"
+                        "description": "Password login is deactivated | Incorrect login credentials, please try again"
+                    },
+                    "403": {
+                        "description": "User has been archived with username %s | Email address is not verified, please open the link sent to it"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to patch identity provider"
+                        "description": "Failed to find system setting | Failed to unmarshal system setting | Failed to sign in with LDAP | Incorrect login credentials, please try again | Failed to find token | Failed to generate two-factor challenge | Failed to generate tokens | Failed to create activity"
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+            }
+        },
+        "/api/v1/auth/signin/passkey": {
+            "post": {
+                "description": "The passkeys verify the user, so neither the password nor the two-factor authentication code is asked. The sign-in is available even if password login is deactivated.",
                 "consumes": [
                     "application/json"
                 ],
@@ -357,139 +391,95 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "idp"
+                    "auth"
                 ],
-                "summary": "Update an identity provider by ID",
+                "summary": "Sign-in to memos with a passkey.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Identity Provider ID",
-                        "name": "idpId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Patched identity provider information",
+                        "description": "Response of navigator.credentials.get()",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateIdentityProviderRequest"
+                            "$ref": "#/definitions/v1.PasskeySignIn"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Patched identity provider",
+                        "description": "User information",
                         "schema": {
-                            "$ref": "#/definitions/store.IdentityProvider"
+                            "$ref": "#/definitions/store.User"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted patch identity provider request"
+                        "description": "Malformatted signin request"
                     },
                     "401": {
-                        "description": "Missing user in session | Unauthorized"
+                        "description": "Invalid or expired passkey challenge, please sign in again | Invalid passkey"
+                    },
+                    "403": {
+                        "description": "User has been archived with username %s"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to patch identity provider"
+                        "description": "Failed to find system setting | Failed to find passkey | Failed to find user | Failed to update passkey | Failed to generate tokens | Failed to create activity"
                     }
                 }
             }
         },
-        "/api/v1/memo": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/api/v1/auth/signin/sso": {
+            "post": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
+                    "auth"
                 ],
-                "summary": "Get a list of memos matching optional filters",
+                "summary": "Sign-in to memos using SSO.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Creator ID",
-                        "name": "creatorId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Creator username",
-                        "name": "creatorUsername",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "NORMAL",
-                            "ARCHIVED"
-                        ],
-                        "type": "string",
-                        "description": "Row status",
-                        "name": "rowStatus",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Pinned",
-                        "name": "pinned",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search for tag. Do not append #",
-                        "name": "tag",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search for content",
-                        "name": "content",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "SSO sign-in object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SSOSignIn"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo list",
+                        "description": "User information",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/store.Memo"
-                            }
+                            "$ref": "#/definitions/store.User"
                         }
                     },
                     "400": {
-                        "description": "Missing user to find memo"
+                        "description": "Malformatted signin request | Invalid or expired authorization state, please sign in again | Unsupported identity provider type %s"
+                    },
+                    "401": {
+                        "description": "Access denied, identifier does not match the filter. | Failed to verify ID token | Access denied, signing up with the identity provider is disabled"
+                    },
+                    "403": {
+                        "description": "User has been archived with username {username}"
+                    },
+                    "404": {
+                        "description": "Identity provider not found"
+                    },
+                    "409": {
+                        "description": "Username %s is taken by a user which isn't linked to the identity provider"
                     },
                     "500": {
-                        "description": "Failed to get memo display with updated ts setting value | Failed to fetch memo list | Failed to compose memo response"
+                        "description": "Failed to find identity provider | Failed to create identity provider instance | Failed to exchange token | Failed to get user info | Failed to compile identifier filter | Failed to provision user | Failed to generate tokens | Failed to create activity"
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/auth/signin/totp": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Visibility can be PUBLIC, PROTECTED or PRIVATE\n*You should omit fields to use their default values",
                 "consumes": [
                     "application/json"
                 ],
@@ -497,225 +487,195 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
+                    "auth"
                 ],
-                "summary": "Create a memo",
+                "summary": "Finish the two-step sign-in with a code of the authenticator app or a recovery code.",
                 "parameters": [
                     {
-                        "description": "Request object.",
+                        "description": "Two-factor token of the password sign-in and the code",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateMemoRequest"
+                            "$ref": "#/definitions/v1.TOTPSignIn"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Stored memo",
+                        "description": "User information",
                         "schema": {
-                            "$ref": "#/definitions/store.Memo"
+                            "$ref": "#/definitions/store.User"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post memo request | Content size overflow, up to 1MB"
+                        "description": "Malformatted signin request"
                     },
                     "401": {
-                        "description": "Missing user in session"
+                        "description": "Invalid or expired two-factor token, please sign in again | Incorrect two-factor authentication code"
                     },
-                    "404": {
-                        "description": "User not found | Memo not found: %d"
+                    "403": {
+                        "description": "User has been archived with username %s"
+                    },
+                    "429": {
+                        "description": "Too many incorrect two-factor authentication codes, please try again later"
                     },
                     "500": {
-                        "description": "Failed to find user setting | Failed to unmarshal user setting value | Failed to find system setting | Failed to unmarshal system setting | Failed to find user | Failed to create memo | Failed to create activity | Failed to upsert memo resource | Failed to upsert memo relation | Failed to compose memo | Failed to compose memo response"
+                        "description": "Failed to find user | Failed to find two-factor authentication | Failed to verify two-factor authentication code | Failed to generate tokens | Failed to create activity"
                     }
                 }
             }
         },
-        "/api/v1/memo/all": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "This should also list protected memos if the user is logged in\nAuthentication is optional",
+        "/api/v1/auth/signout": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
-                ],
-                "summary": "Get a list of public memos matching optional filters",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "Sign-out from memos.",
                 "responses": {
                     "200": {
-                        "description": "Memo list",
+                        "description": "Sign-out success",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/store.Memo"
-                            }
+                            "type": "boolean"
                         }
                     },
                     "500": {
-                        "description": "Failed to get memo display with updated ts setting value | Failed to fetch all memo list | Failed to compose memo response"
+                        "description": "Failed to find session | Failed to delete session"
                     }
                 }
             }
         },
-        "/api/v1/memo/stats": {
-            "get": {
-                "description": "Used to generate the heatmap",
+        "/api/v1/auth/signup": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
+                    "auth"
                 ],
-                "summary": "Get memo stats by creator ID or username",
+                "summary": "Sign-up to memos.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Creator ID",
-                        "name": "creatorId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Creator username",
-                        "name": "creatorUsername",
-                        "in": "query"
+                        "description": "Sign-up object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SignUp"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo createdTs list",
+                        "description": "User information",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "202": {
+                        "description": "User information without the open ID, the user signs in once the email is verified with the link sent to it",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
                         }
                     },
                     "400": {
-                        "description": "Missing user id to find memo"
+                        "description": "Malformatted signup request | Failed to find users | Invalid email format | Email is required to sign up"
+                    },
+                    "401": {
+                        "description": "signup is disabled"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not found"
                     },
                     "500": {
-                        "description": "Failed to get memo display with updated ts setting value | Failed to find memo list | Failed to compose memo response"
+                        "description": "Failed to find system setting | Failed to unmarshal system setting allow signup | Failed to generate password hash | Failed to create user | Failed to send email | Failed to generate tokens | Failed to create activity"
                     }
                 }
             }
         },
-        "/api/v1/memo/{memoId}": {
-            "get": {
+        "/api/v1/auth/sso/authorize": {
+            "post": {
+                "description": "The state, nonce and PKCE code verifier of the authorization request are kept in a cookie, which is checked by the SSO sign-in.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
+                    "auth"
                 ],
-                "summary": "Get memo by ID",
+                "summary": "Start a SSO sign-in with an OpenID Connect identity provider.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Memo ID",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
+                        "description": "SSO authorization object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SSOAuthorize"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo list",
+                        "description": "Authorization URL",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/store.Memo"
-                            }
+                            "$ref": "#/definitions/v1.SSOAuthorization"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s"
-                    },
-                    "401": {
-                        "description": "Missing user in session"
-                    },
-                    "403": {
-                        "description": "this memo is private only | this memo is protected, missing user in session"
+                        "description": "Malformatted authorization request | Identity provider type %s doesn't support authorization requests"
                     },
                     "404": {
-                        "description": "Memo not found: %d"
+                        "description": "Identity provider not found"
                     },
                     "500": {
-                        "description": "Failed to find memo by ID: %v | Failed to compose memo response"
+                        "description": "Failed to find identity provider | Failed to create identity provider instance | Failed to generate authorization request | Failed to get authorization URL"
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+            }
+        },
+        "/api/v1/idp": {
+            "get": {
+                "description": "*clientSecret and bindPassword are only available for host user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
-                ],
-                "summary": "Delete memo by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Memo ID to delete",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "idp"
                 ],
+                "summary": "Get a list of identity providers",
                 "responses": {
                     "200": {
-                        "description": "Memo deleted",
+                        "description": "List of available identity providers",
                         "schema": {
-                            "type": "boolean"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.IdentityProvider"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "ID is not a number: %s"
-                    },
-                    "401": {
-                        "description": "Missing user in session | Unauthorized"
-                    },
-                    "404": {
-                        "description": "Memo not found: %d"
-                    },
                     "500": {
-                        "description": "Failed to find memo | Failed to delete memo ID: %v"
+                        "description": "Failed to find identity provider list | Failed to find user"
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Visibility can be PUBLIC, PROTECTED or PRIVATE\n*You should omit fields to use their default values",
                 "consumes": [
                     "application/json"
                 ],
@@ -723,51 +683,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memo"
+                    "idp"
                 ],
-                "summary": "Update a memo",
+                "summary": "Create Identity Provider",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID of memo to update",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Patched object.",
+                        "description": "Identity provider information",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.PatchMemoRequest"
+                            "$ref": "#/definitions/v1.CreateIdentityProviderRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Stored memo",
+                        "description": "Identity provider information",
                         "schema": {
-                            "$ref": "#/definitions/store.Memo"
+                            "$ref": "#/definitions/store.IdentityProvider"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted patch memo request | Content size overflow, up to 1MB"
+                        "description": "Malformatted post identity provider request | Invalid provisioning rules"
                     },
                     "401": {
                         "description": "Missing user in session | Unauthorized"
                     },
-                    "404": {
-                        "description": "Memo not found: %d"
-                    },
                     "500": {
-                        "description": "Failed to find memo | Failed to patch memo | Failed to upsert memo resource | Failed to delete memo resource | Failed to compose memo response"
+                        "description": "Failed to find user | Failed to create identity provider"
                     }
                 }
             }
         },
-        "/api/v1/memo/{memoId}/organizer": {
-            "post": {
+        "/api/v1/idp/{idpId}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -780,51 +730,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memo-organizer"
+                    "idp"
                 ],
-                "summary": "Organize memo (pin/unpin)",
+                "summary": "Get an identity provider by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of memo to organize",
-                        "name": "memoId",
+                        "description": "Identity provider ID",
+                        "name": "idpId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Memo organizer object",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpsertMemoOrganizerRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo information",
+                        "description": "Requested identity provider",
                         "schema": {
-                            "$ref": "#/definitions/store.Memo"
+                            "$ref": "#/definitions/store.IdentityProvider"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted post memo organizer request"
+                        "description": "ID is not a number: %s"
                     },
                     "401": {
                         "description": "Missing user in session | Unauthorized"
                     },
                     "404": {
-                        "description": "Memo not found: %v"
+                        "description": "Identity provider not found"
                     },
                     "500": {
-                        "description": "Failed to find memo | Failed to upsert memo organizer | Failed to find memo by ID: %v | Failed to compose memo response"
+                        "description": "Failed to find identity provider list | Failed to find user"
                     }
                 }
-            }
-        },
-        "/api/v1/memo/{memoId}/relation": {
-            "get": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -832,38 +776,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memo-relation"
+                    "idp"
                 ],
-                "summary": "Get a list of Memo Relations",
+                "summary": "Delete an identity provider by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of memo to find relations",
-                        "name": "memoId",
+                        "description": "Identity Provider ID",
+                        "name": "idpId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo relation information list",
+                        "description": "Identity Provider deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/store.MemoRelation"
-                            }
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s"
+                        "description": "ID is not a number: %s | Malformatted patch identity provider request"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
                     },
                     "500": {
-                        "description": "Failed to list memo relations"
+                        "description": "Failed to find user | Failed to patch identity provider"
                     }
                 }
             },
-            "post": {
-                "description": "Create a relation between two memos",
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -871,135 +819,129 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memo-relation"
+                    "idp"
                 ],
-                "summary": "Create Memo Relation",
+                "summary": "Update an identity provider by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of memo to relate",
-                        "name": "memoId",
+                        "description": "Identity Provider ID",
+                        "name": "idpId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Memo relation object",
+                        "description": "Patched identity provider information",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpsertMemoRelationRequest"
+                            "$ref": "#/definitions/v1.UpdateIdentityProviderRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo relation information",
+                        "description": "Patched identity provider",
                         "schema": {
-                            "$ref": "#/definitions/store.MemoRelation"
+                            "$ref": "#/definitions/store.IdentityProvider"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted post memo relation request"
+                        "description": "ID is not a number: %s | Malformatted patch identity provider request | Invalid provisioning rules"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
                     },
                     "500": {
-                        "description": "Failed to upsert memo relation"
+                        "description": "Failed to find user | Failed to patch identity provider"
                     }
                 }
             }
         },
-        "/api/v1/memo/{memoId}/relation/{relatedMemoId}/type/{relationType}": {
-            "delete": {
-                "description": "Removes a relation between two memos",
-                "consumes": [
-                    "application/json"
+        "/api/v1/memo": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo-relation"
+                    "memo"
                 ],
-                "summary": "Delete a Memo Relation",
+                "summary": "Get a list of memos matching optional filters",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of memo to find relations",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
+                        "description": "Creator ID",
+                        "name": "creatorId",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "ID of memo to remove relation to",
-                        "name": "relatedMemoId",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Creator username",
+                        "name": "creatorUsername",
+                        "in": "query"
                     },
                     {
                         "enum": [
-                            "REFERENCE",
-                            "ADDITIONAL"
+                            "NORMAL",
+                            "ARCHIVED"
                         ],
                         "type": "string",
-                        "description": "Type of relation to remove",
-                        "name": "relationType",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Memo relation deleted",
-                        "schema": {
-                            "type": "boolean"
-                        }
+                        "description": "Row status",
+                        "name": "rowStatus",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Memo ID is not a number: %s | Related memo ID is not a number: %s"
+                    {
+                        "type": "boolean",
+                        "description": "Pinned",
+                        "name": "pinned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search for tag. Do not append #",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search for content, the results are ranked by relevance",
+                        "name": "content",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Failed to delete memo relation"
-                    }
-                }
-            }
-        },
-        "/api/v1/memo/{memoId}/resource": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "memo-resource"
-                ],
-                "summary": "Get resource list of a memo",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of memo to fetch resource list from",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo resource list",
+                        "description": "Memo list",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v1.Resource"
+                                "$ref": "#/definitions/store.Memo"
                             }
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s"
+                        "description": "Missing user to find memo"
                     },
                     "500": {
-                        "description": "Failed to fetch resource list"
+                        "description": "Failed to get memo display with updated ts setting value | Failed to fetch memo list | Failed to compose memo response"
                     }
                 }
             },
@@ -1009,6 +951,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Visibility can be PUBLIC, PROTECTED or PRIVATE\n*You should omit fields to use their default values",
                 "consumes": [
                     "application/json"
                 ],
@@ -1016,280 +959,286 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memo-resource"
+                    "memo"
                 ],
-                "summary": "Bind resource to memo",
+                "summary": "Create a memo",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID of memo to bind resource to",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Memo resource request object",
+                        "description": "Request object.",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpsertMemoResourceRequest"
+                            "$ref": "#/definitions/v1.CreateMemoRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo resource binded",
+                        "description": "Stored memo",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/store.Memo"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted post memo resource request | Resource not found"
+                        "description": "Malformatted post memo request | Content size overflow, up to 1MB"
                     },
                     "401": {
-                        "description": "Missing user in session | Unauthorized to bind this resource"
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "User not found | Memo not found: %d"
                     },
                     "500": {
-                        "description": "Failed to fetch resource | Failed to upsert memo resource"
+                        "description": "Failed to find user setting | Failed to unmarshal user setting value | Failed to find system setting | Failed to unmarshal system setting | Failed to find user | Failed to create memo | Failed to create activity | Failed to upsert memo resource | Failed to upsert memo relation | Failed to compose memo | Failed to compose memo response"
                     }
                 }
             }
         },
-        "/api/v1/memo/{memoId}/resource/{resourceId}": {
-            "delete": {
+        "/api/v1/memo/all": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "This should also list protected memos if the user is logged in\nAuthentication is optional",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "memo-resource"
+                    "memo"
                 ],
-                "summary": "Unbind resource from memo",
+                "summary": "Get a list of public memos matching optional filters",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of memo to unbind resource from",
-                        "name": "memoId",
-                        "in": "path",
-                        "required": true
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "ID of resource to unbind from memo",
-                        "name": "resourceId",
-                        "in": "path",
-                        "required": true
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memo resource unbinded. *200 is returned even if the reference doesn't exists ",
+                        "description": "Memo list",
                         "schema": {
-                            "type": "boolean"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Memo"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Memo ID is not a number: %s | Resource ID is not a number: %s | Memo not found"
-                    },
-                    "401": {
-                        "description": "Missing user in session | Unauthorized"
-                    },
                     "500": {
-                        "description": "Failed to find memo | Failed to fetch resource list"
+                        "description": "Failed to get memo display with updated ts setting value | Failed to fetch all memo list | Failed to compose memo response"
                     }
                 }
             }
         },
-        "/api/v1/ping": {
-            "get": {
+        "/api/v1/memo/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "memo"
+                ],
+                "summary": "Import memos from the export of another tool",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool of the export, one of keep, flomo and obsidian",
+                        "name": "source",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Zip file of the export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
-                "summary": "Ping the system",
                 "responses": {
                     "200": {
-                        "description": "If succeed to ping the system",
+                        "description": "Imported memo and resource counts",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/v1.ImportMemosResponse"
                         }
+                    },
+                    "400": {
+                        "description": "Upload file not found | File size exceeds allowed limit of %d MiB | Invalid zip file | Failed to parse export"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to get uploading file | Failed to open file | Failed to import memos"
                     }
                 }
             }
         },
-        "/api/v1/resource": {
+        "/api/v1/memo/stats": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+                "description": "Used to generate the heatmap",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "resource"
+                    "memo"
                 ],
-                "summary": "Get a list of resources",
+                "summary": "Get memo stats by creator ID or username",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
+                        "description": "Creator ID",
+                        "name": "creatorId",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "Creator username",
+                        "name": "creatorUsername",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Resource list",
+                        "description": "Memo createdTs list",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/store.Resource"
+                                "type": "integer"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Missing user in session"
+                    "400": {
+                        "description": "Missing user id to find memo"
                     },
                     "500": {
-                        "description": "Failed to fetch resource list"
+                        "description": "Failed to get memo display with updated ts setting value | Failed to find memo list | Failed to compose memo response"
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/memo/trash": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "resource"
-                ],
-                "summary": "Create resource",
-                "parameters": [
-                    {
-                        "description": "Request object.",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CreateResourceRequest"
-                        }
-                    }
+                    "memo"
                 ],
+                "summary": "Get the memos of the current user in trash",
                 "responses": {
                     "200": {
-                        "description": "Created resource",
+                        "description": "Memo list in trash",
                         "schema": {
-                            "$ref": "#/definitions/store.Resource"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Memo"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Malformatted post resource request | Invalid external link | Invalid external link scheme | Failed to request %s | Failed to read %s | Failed to read mime from %s"
-                    },
                     "401": {
                         "description": "Missing user in session"
                     },
                     "500": {
-                        "description": "Failed to save resource | Failed to create resource | Failed to create activity"
+                        "description": "Failed to fetch memo list | Failed to compose memo response"
                     }
                 }
             }
         },
-        "/api/v1/resource/blob": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "consumes": [
-                    "multipart/form-data"
-                ],
+        "/api/v1/memo/{memoId}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "resource"
+                    "memo"
                 ],
-                "summary": "Upload resource",
+                "summary": "Get memo by ID",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "File to upload",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Memo ID",
+                        "name": "memoId",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created resource",
+                        "description": "Memo list",
                         "schema": {
-                            "$ref": "#/definitions/store.Resource"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Memo"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Upload file not found | File size exceeds allowed limit of %d MiB | Failed to parse upload data"
+                        "description": "ID is not a number: %s"
                     },
                     "401": {
                         "description": "Missing user in session"
                     },
+                    "403": {
+                        "description": "this memo is private only | this memo is protected, missing user in session"
+                    },
+                    "404": {
+                        "description": "Memo not found: %d"
+                    },
                     "500": {
-                        "description": "Failed to get uploading file | Failed to open file | Failed to save resource | Failed to create resource | Failed to create activity"
+                        "description": "Failed to find memo by ID: %v | Failed to compose memo response"
                     }
                 }
-            }
-        },
-        "/api/v1/resource/{resourceId}": {
+            },
             "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Memos in trash can be restored until they are purged after the retention period",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "resource"
+                    "memo"
                 ],
-                "summary": "Delete a resource",
+                "summary": "Move memo to trash by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Resource ID",
-                        "name": "resourceId",
+                        "description": "Memo ID to delete",
+                        "name": "memoId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Resource deleted",
+                        "description": "Memo deleted",
                         "schema": {
                             "type": "boolean"
                         }
@@ -1298,13 +1247,13 @@ const docTemplate = `{
                         "description": "ID is not a number: %s"
                     },
                     "401": {
-                        "description": "Missing user in session"
+                        "description": "Missing user in session | Unauthorized"
                     },
                     "404": {
-                        "description": "Resource not found: %d"
+                        "description": "Memo not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find resource | Failed to delete resource"
+                        "description": "Failed to find memo | Failed to delete memo ID: %v"
                     }
                 }
             },
@@ -1314,116 +1263,155 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Visibility can be PUBLIC, PROTECTED or PRIVATE\n*You should omit fields to use their default values",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "resource"
+                    "memo"
                 ],
-                "summary": "Update a resource",
+                "summary": "Update a memo",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Resource ID",
-                        "name": "resourceId",
+                        "description": "ID of memo to update",
+                        "name": "memoId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Patch resource request",
-                        "name": "patch",
+                        "description": "Patched object.",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateResourceRequest"
+                            "$ref": "#/definitions/v1.PatchMemoRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated resource",
+                        "description": "Stored memo",
                         "schema": {
-                            "$ref": "#/definitions/store.Resource"
+                            "$ref": "#/definitions/store.Memo"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted patch resource request"
+                        "description": "ID is not a number: %s | Malformatted patch memo request | Content size overflow, up to 1MB"
                     },
                     "401": {
                         "description": "Missing user in session | Unauthorized"
                     },
                     "404": {
-                        "description": "Resource not found: %d"
+                        "description": "Memo not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find resource | Failed to patch resource"
+                        "description": "Failed to find memo | Failed to patch memo | Failed to upsert memo resource | Failed to delete memo resource | Failed to compose memo response"
                     }
                 }
             }
         },
-        "/api/v1/status": {
-            "get": {
-                "produces": [
-                    "application/json"
+        "/api/v1/memo/{memoId}/organizer": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "memo-organizer"
+                ],
+                "summary": "Organize memo (pin/unpin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of memo to organize",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memo organizer object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpsertMemoOrganizerRequest"
+                        }
+                    }
                 ],
-                "summary": "Get system GetSystemStatus",
                 "responses": {
                     "200": {
-                        "description": "System GetSystemStatus",
+                        "description": "Memo information",
                         "schema": {
-                            "$ref": "#/definitions/v1.SystemStatus"
+                            "$ref": "#/definitions/store.Memo"
                         }
                     },
+                    "400": {
+                        "description": "ID is not a number: %s | Malformatted post memo organizer request"
+                    },
                     "401": {
                         "description": "Missing user in session | Unauthorized"
                     },
+                    "404": {
+                        "description": "Memo not found: %v"
+                    },
                     "500": {
-                        "description": "Failed to find host user | Failed to find system setting list | Failed to unmarshal system setting customized profile value"
+                        "description": "Failed to find memo | Failed to upsert memo organizer | Failed to find memo by ID: %v | Failed to compose memo response"
                     }
                 }
             }
         },
-        "/api/v1/storage": {
+        "/api/v1/memo/{memoId}/relation": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "memo-relation"
+                ],
+                "summary": "Get a list of Memo Relations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of memo to find relations",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get a list of storages",
                 "responses": {
                     "200": {
-                        "description": "List of storages",
+                        "description": "Memo relation information list",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/store.Storage"
+                                "$ref": "#/definitions/store.MemoRelation"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Missing user in session | Unauthorized"
+                    "400": {
+                        "description": "ID is not a number: %s"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to convert storage"
+                        "description": "Failed to list memo relations"
                     }
                 }
             },
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+                "description": "Create a relation between two memos",
                 "consumes": [
                     "application/json"
                 ],
@@ -1431,159 +1419,135 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "memo-relation"
                 ],
-                "summary": "Create storage",
+                "summary": "Create Memo Relation",
                 "parameters": [
                     {
-                        "description": "Request object.",
+                        "type": "integer",
+                        "description": "ID of memo to relate",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memo relation object",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateStorageRequest"
+                            "$ref": "#/definitions/v1.UpsertMemoRelationRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created storage",
+                        "description": "Memo relation information",
                         "schema": {
-                            "$ref": "#/definitions/store.Storage"
+                            "$ref": "#/definitions/store.MemoRelation"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post storage request"
-                    },
-                    "401": {
-                        "description": "Missing user in session"
+                        "description": "ID is not a number: %s | Malformatted post memo relation request"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to create storage | Failed to convert storage"
+                        "description": "Failed to upsert memo relation"
                     }
                 }
             }
         },
-        "/api/v1/storage/{storageId}": {
+        "/api/v1/memo/{memoId}/relation/{relatedMemoId}/type/{relationType}": {
             "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+                "description": "Removes a relation between two memos",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "storage"
+                    "memo-relation"
                 ],
-                "summary": "Delete a storage",
+                "summary": "Delete a Memo Relation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Storage ID",
-                        "name": "storageId",
+                        "description": "ID of memo to find relations",
+                        "name": "memoId",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Storage deleted",
-                        "schema": {
-                            "type": "boolean"
-                        }
-                    },
-                    "400": {
-                        "description": "ID is not a number: %s | Storage service %d is using"
                     },
-                    "401": {
-                        "description": "Missing user in session | Unauthorized"
-                    },
-                    "500": {
-                        "description": "Failed to find user | Failed to find storage | Failed to unmarshal storage service id | Failed to delete storage"
-                    }
-                }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "storage"
-                ],
-                "summary": "Update a storage",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Storage ID",
-                        "name": "storageId",
+                        "description": "ID of memo to remove relation to",
+                        "name": "relatedMemoId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Patch request",
-                        "name": "patch",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateStorageRequest"
-                        }
+                        "enum": [
+                            "REFERENCE",
+                            "ADDITIONAL"
+                        ],
+                        "type": "string",
+                        "description": "Type of relation to remove",
+                        "name": "relationType",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated resource",
+                        "description": "Memo relation deleted",
                         "schema": {
-                            "$ref": "#/definitions/store.Storage"
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Malformatted patch storage request | Malformatted post storage request"
-                    },
-                    "401": {
-                        "description": "Missing user in session | Unauthorized"
+                        "description": "Memo ID is not a number: %s | Related memo ID is not a number: %s"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to patch storage | Failed to convert storage"
+                        "description": "Failed to delete memo relation"
                     }
                 }
             }
         },
-        "/api/v1/system/setting": {
+        "/api/v1/memo/{memoId}/resource": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system-setting"
+                    "memo-resource"
+                ],
+                "summary": "Get resource list of a memo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of memo to fetch resource list from",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get a list of system settings",
                 "responses": {
                     "200": {
-                        "description": "System setting list",
+                        "description": "Memo resource list",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v1.SystemSetting"
+                                "$ref": "#/definitions/v1.Resource"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Missing user in session | Unauthorized"
+                    "400": {
+                        "description": "ID is not a number: %s"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to find system setting list"
+                        "description": "Failed to fetch resource list"
                     }
                 }
             },
@@ -1600,74 +1564,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system-setting"
+                    "memo-resource"
                 ],
-                "summary": "Create system setting",
+                "summary": "Bind resource to memo",
                 "parameters": [
                     {
-                        "description": "Request object.",
+                        "type": "integer",
+                        "description": "ID of memo to bind resource to",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memo resource request object",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpsertSystemSettingRequest"
+                            "$ref": "#/definitions/v1.UpsertMemoResourceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created system setting",
+                        "description": "Memo resource binded",
                         "schema": {
-                            "$ref": "#/definitions/store.SystemSetting"
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post system setting request | invalid system setting"
+                        "description": "ID is not a number: %s | Malformatted post memo resource request | Resource not found"
                     },
                     "401": {
-                        "description": "Missing user in session | Unauthorized"
-                    },
-                    "403": {
-                        "description": "Cannot disable passwords if no SSO identity provider is configured."
+                        "description": "Missing user in session | Unauthorized to bind this resource"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to upsert system setting"
+                        "description": "Failed to fetch resource | Failed to upsert memo resource"
                     }
                 }
             }
         },
-        "/api/v1/system/vacuum": {
-            "post": {
+        "/api/v1/memo/{memoId}/resource/{resourceId}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "memo-resource"
+                ],
+                "summary": "Unbind resource from memo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of memo to unbind resource from",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of resource to unbind from memo",
+                        "name": "resourceId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Vacuum the database",
                 "responses": {
                     "200": {
-                        "description": "Database vacuumed",
+                        "description": "Memo resource unbinded. *200 is returned even if the reference doesn't exists ",
                         "schema": {
                             "type": "boolean"
                         }
                     },
+                    "400": {
+                        "description": "Memo ID is not a number: %s | Resource ID is not a number: %s | Memo not found"
+                    },
                     "401": {
                         "description": "Missing user in session | Unauthorized"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to ExecVacuum database"
+                        "description": "Failed to find memo | Failed to fetch resource list"
                     }
                 }
             }
         },
-        "/api/v1/tag": {
-            "get": {
+        "/api/v1/memo/{memoId}/restore": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1677,178 +1667,271 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "memo"
+                ],
+                "summary": "Restore a memo from trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of memo to restore",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get a list of tags",
                 "responses": {
                     "200": {
-                        "description": "Tag list",
+                        "description": "Restored memo",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/store.Memo"
                         }
                     },
                     "400": {
-                        "description": "Missing user id to find tag"
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Memo not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find tag list"
+                        "description": "Failed to find memo | Failed to restore memo | Failed to compose memo response"
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/memo/{memoId}/revision": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "memo-revision"
                 ],
-                "summary": "Create a tag",
+                "summary": "Get the revisions of a memo, the latest first",
                 "parameters": [
                     {
-                        "description": "Request object.",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpsertTagRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID of memo to find revisions",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created tag name",
+                        "description": "Memo revision list",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.MemoRevision"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Malformatted post tag request | Tag name shouldn't be empty"
+                        "description": "ID is not a number: %s"
                     },
                     "401": {
-                        "description": "Missing user in session"
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Memo not found: %d"
                     },
                     "500": {
-                        "description": "Failed to upsert tag | Failed to create activity"
+                        "description": "Failed to find memo | Failed to list memo revisions"
                     }
                 }
             }
         },
-        "/api/v1/tag/delete": {
-            "post": {
+        "/api/v1/memo/{memoId}/revision/diff": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "memo-revision"
                 ],
-                "summary": "Delete a tag",
+                "summary": "Get the line based diff between two revisions of a memo",
                 "parameters": [
                     {
-                        "description": "Request object.",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.DeleteTagRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID of memo",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the old revision",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the new revision",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Tag deleted",
+                        "description": "Memo revision diff",
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/v1.MemoRevisionDiff"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post tag request | Tag name shouldn't be empty"
+                        "description": "ID is not a number: %s | Revision ID is not a number: %s"
                     },
                     "401": {
-                        "description": "Missing user in session"
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Memo not found: %d | Memo revision not found: %d"
                     },
                     "500": {
-                        "description": "Failed to delete tag name: %v"
+                        "description": "Failed to find memo | Failed to find memo revision"
                     }
                 }
             }
         },
-        "/api/v1/tag/suggestion": {
-            "get": {
+        "/api/v1/memo/{memoId}/revision/{revisionId}/restore": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "The content and visibility of the revision become current, which is saved as a new revision",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "memo-revision"
+                ],
+                "summary": "Restore a revision of a memo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of memo",
+                        "name": "memoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of revision to restore",
+                        "name": "revisionId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get a list of tags suggested from other memos contents",
                 "responses": {
                     "200": {
-                        "description": "Tag list",
+                        "description": "Restored memo",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/store.Memo"
                         }
                     },
                     "400": {
-                        "description": "Missing user session"
+                        "description": "ID is not a number: %s | Revision ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Memo not found: %d | Memo revision not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find memo list | Failed to find tag list"
+                        "description": "Failed to find memo | Failed to find memo revision | Failed to restore memo revision | Failed to compose memo response"
                     }
                 }
             }
         },
-        "/api/v1/user": {
+        "/api/v1/ping": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "system"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Ping the system",
                 "responses": {
                     "200": {
-                        "description": "User list",
+                        "description": "If succeed to ping the system",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/resource": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Get a list of resources",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource list",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/store.User"
+                                "$ref": "#/definitions/store.Resource"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
                     "500": {
-                        "description": "Failed to fetch user list"
+                        "description": "Failed to fetch resource list"
                     }
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1856,506 +1939,3413 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "resource"
                 ],
-                "summary": "Create a user",
+                "summary": "Create resource",
                 "parameters": [
                     {
-                        "description": "Request object",
+                        "description": "Request object.",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateUserRequest"
+                            "$ref": "#/definitions/v1.CreateResourceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created user",
+                        "description": "Created resource",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "$ref": "#/definitions/store.Resource"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post user request | Invalid user create format"
+                        "description": "Malformatted post resource request | Invalid external link | Invalid external link scheme | Failed to request %s | Failed to read %s | Failed to read mime from %s"
                     },
                     "401": {
-                        "description": "Missing auth session | Unauthorized to create user"
-                    },
-                    "403": {
-                        "description": "Could not create host user"
+                        "description": "Missing user in session"
                     },
                     "500": {
-                        "description": "Failed to find user by id | Failed to generate password hash | Failed to create user | Failed to create activity"
+                        "description": "Failed to save resource | Failed to create resource | Failed to create activity"
                     }
                 }
             }
         },
-        "/api/v1/user/me": {
-            "get": {
+        "/api/v1/resource/blob": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "resource"
+                ],
+                "summary": "Upload resource",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user",
                 "responses": {
                     "200": {
-                        "description": "Current user",
+                        "description": "Created resource",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "$ref": "#/definitions/store.Resource"
                         }
                     },
+                    "400": {
+                        "description": "Upload file not found | File size exceeds allowed limit of %d MiB | Failed to parse upload data"
+                    },
                     "401": {
-                        "description": "Missing auth session"
+                        "description": "Missing user in session"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to find userSettingList"
+                        "description": "Failed to get uploading file | Failed to open file | Failed to save resource | Failed to create resource | Failed to create activity"
                     }
                 }
             }
         },
-        "/api/v1/user/name/{username}": {
-            "get": {
+        "/api/v1/resource/{resourceId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "resource"
                 ],
-                "summary": "Get user by username",
+                "summary": "Delete a resource",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Username",
-                        "name": "username",
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "resourceId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Requested user",
+                        "description": "Resource deleted",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "type": "boolean"
                         }
                     },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
                     "404": {
-                        "description": "User not found"
+                        "description": "Resource not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find user"
+                        "description": "Failed to find resource | Failed to delete resource"
                     }
                 }
-            }
-        },
-        "/api/v1/user/setting": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user-setting"
+                    "resource"
                 ],
-                "summary": "Upsert user setting",
+                "summary": "Update a resource",
                 "parameters": [
                     {
-                        "description": "Request object.",
-                        "name": "body",
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "resourceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch resource request",
+                        "name": "patch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpsertUserSettingRequest"
+                            "$ref": "#/definitions/v1.UpdateResourceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Created user setting",
+                        "description": "Updated resource",
                         "schema": {
-                            "$ref": "#/definitions/store.UserSetting"
+                            "$ref": "#/definitions/store.Resource"
                         }
                     },
                     "400": {
-                        "description": "Malformatted post user setting upsert request | Invalid user setting format"
+                        "description": "ID is not a number: %s | Malformatted patch resource request"
                     },
                     "401": {
-                        "description": "Missing auth session"
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Resource not found: %d"
                     },
                     "500": {
-                        "description": "Failed to upsert user setting"
+                        "description": "Failed to find resource | Failed to patch resource"
                     }
                 }
             }
         },
-        "/api/v1/user/{id}": {
+        "/api/v1/session": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Get user by id",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "session"
                 ],
+                "summary": "Get a list of active sessions of the current user",
                 "responses": {
                     "200": {
-                        "description": "Requested user",
+                        "description": "Session list",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.Session"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Malformatted user id"
-                    },
-                    "404": {
-                        "description": "User not found"
+                    "401": {
+                        "description": "Missing user in session"
                     },
                     "500": {
-                        "description": "Failed to find user"
+                        "description": "Failed to fetch session list"
                     }
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Delete a user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "session"
                 ],
+                "summary": "Revoke all the sessions of the current user, including the current one",
                 "responses": {
                     "200": {
-                        "description": "User deleted",
+                        "description": "Sessions revoked",
                         "schema": {
                             "type": "boolean"
                         }
                     },
-                    "400": {
-                        "description": "ID is not a number: %s | Current session user not found with ID: %d"
-                    },
                     "401": {
                         "description": "Missing user in session"
                     },
-                    "403": {
-                        "description": "Unauthorized to delete user"
-                    },
                     "500": {
-                        "description": "Failed to find user | Failed to delete user"
+                        "description": "Failed to delete sessions"
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/session/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "session"
                 ],
-                "summary": "Update a user",
+                "summary": "Revoke a session of the current user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "sessionId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Patch request",
-                        "name": "patch",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateUserRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated user",
+                        "description": "Session revoked",
                         "schema": {
-                            "$ref": "#/definitions/store.User"
+                            "type": "boolean"
                         }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Current session user not found with ID: %d | Malformatted patch user request | Invalid update user request"
+                        "description": "ID is not a number: %s"
                     },
                     "401": {
                         "description": "Missing user in session"
                     },
-                    "403": {
-                        "description": "Unauthorized to update user"
+                    "404": {
+                        "description": "Session not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find user | Failed to generate password hash | Failed to patch user | Failed to find userSettingList"
+                        "description": "Failed to find session | Failed to delete session"
                     }
                 }
             }
         },
-        "/explore/rss.xml": {
+        "/api/v1/shortcut": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
-                    "text/xml"
+                    "application/json"
                 ],
                 "tags": [
-                    "rss"
+                    "shortcut"
+                ],
+                "summary": "Get a list of shortcuts of the current user",
+                "parameters": [
+                    {
+                        "enum": [
+                            "NORMAL",
+                            "ARCHIVED"
+                        ],
+                        "type": "string",
+                        "description": "Row status",
+                        "name": "rowStatus",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get RSS",
                 "responses": {
                     "200": {
-                        "description": "RSS"
+                        "description": "Shortcut list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Shortcut"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session"
                     },
                     "500": {
-                        "description": "Failed to get system customized profile | Failed to find memo list | Failed to generate rss"
+                        "description": "Failed to fetch shortcut list"
                     }
                 }
-            }
-        },
-        "/o/get/GetImage": {
-            "get": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "GetImage/*"
+                    "application/json"
                 ],
                 "tags": [
-                    "get"
+                    "shortcut"
                 ],
-                "summary": "Get GetImage from URL",
+                "summary": "Create a shortcut",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Image url",
-                        "name": "url",
-                        "in": "query",
-                        "required": true
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateShortcutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image"
+                        "description": "Created shortcut",
+                        "schema": {
+                            "$ref": "#/definitions/store.Shortcut"
+                        }
                     },
                     "400": {
-                        "description": "Missing GetImage url | Wrong url | Failed to get GetImage url: %s"
+                        "description": "Malformatted post shortcut request | Shortcut title is required | Invalid shortcut payload"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
                     },
                     "500": {
-                        "description": "Failed to write GetImage blob"
+                        "description": "Failed to create shortcut"
                     }
                 }
             }
         },
-        "/o/get/GetWebsiteMetadata": {
+        "/api/v1/shortcut/{shortcutId}": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "get"
+                    "shortcut"
                 ],
-                "summary": "Get website metadata",
+                "summary": "Get a shortcut",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Website URL",
-                        "name": "url",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Shortcut ID",
+                        "name": "shortcutId",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Extracted metadata",
+                        "description": "Shortcut",
                         "schema": {
-                            "$ref": "#/definitions/getter.HTMLMeta"
+                            "$ref": "#/definitions/store.Shortcut"
                         }
                     },
                     "400": {
-                        "description": "Missing website url | Wrong url"
+                        "description": "ID is not a number: %s"
                     },
-                    "406": {
-                        "description": "Failed to get website meta with url: %s"
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Shortcut not found: %d"
+                    },
+                    "500": {
+                        "description": "Failed to find shortcut"
                     }
                 }
-            }
-        },
-        "/o/r/{resourceId}": {
-            "get": {
-                "description": "*Swagger UI may have problems displaying other file types than images",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "resource"
+                    "shortcut"
                 ],
-                "summary": "Stream a resource",
+                "summary": "Delete a shortcut",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Resource ID",
-                        "name": "resourceId",
+                        "description": "Shortcut ID",
+                        "name": "shortcutId",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shortcut deleted",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Shortcut not found: %d"
                     },
+                    "500": {
+                        "description": "Failed to find shortcut | Failed to delete shortcut"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shortcut"
+                ],
+                "summary": "Update a shortcut",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Thumbnail",
-                        "name": "thumbnail",
-                        "in": "query"
+                        "description": "Shortcut ID",
+                        "name": "shortcutId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch request",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateShortcutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Requested resource"
+                        "description": "Updated shortcut",
+                        "schema": {
+                            "$ref": "#/definitions/store.Shortcut"
+                        }
                     },
                     "400": {
-                        "description": "ID is not a number: %s | Failed to get resource visibility"
+                        "description": "ID is not a number: %s | Malformatted patch shortcut request | Shortcut title is required | Invalid shortcut payload"
                     },
                     "401": {
-                        "description": "Resource visibility not match"
+                        "description": "Missing user in session"
                     },
                     "404": {
-                        "description": "Resource not found: %d"
+                        "description": "Shortcut not found: %d"
                     },
                     "500": {
-                        "description": "Failed to find resource by ID: %v | Failed to open the local resource: %s | Failed to read the local resource: %s"
+                        "description": "Failed to find shortcut | Failed to patch shortcut"
                     }
                 }
             }
         },
-        "/u/{id}/rss.xml": {
+        "/api/v1/shortcut/{shortcutId}/memo": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
-                    "text/xml"
+                    "application/json"
                 ],
                 "tags": [
-                    "rss"
+                    "shortcut"
                 ],
-                "summary": "Get RSS for a user",
+                "summary": "Get the memos matching the filter of a shortcut",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Shortcut ID",
+                        "name": "shortcutId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "RSS"
+                        "description": "Memo list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Memo"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "User id is not a number"
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "404": {
+                        "description": "Shortcut not found: %d"
                     },
                     "500": {
-                        "description": "Failed to get system customized profile | Failed to find memo list | Failed to generate rss"
+                        "description": "Failed to find shortcut | Failed to get memo display with updated ts setting value | Failed to fetch memo list | Failed to compose memo response"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "getter.HTMLMeta": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "image": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+        },
+        "/api/v1/status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system GetSystemStatus",
+                "responses": {
+                    "200": {
+                        "description": "System GetSystemStatus",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SystemStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find host user | Failed to find system setting list | Failed to unmarshal system setting customized profile value | Failed to unmarshal system setting smtp value"
+                    }
                 }
             }
         },
-        "profile.Profile": {
-            "type": "object",
-            "properties": {
-                "mode": {
-                    "description": "Mode can be \"prod\" or \"dev\" or \"demo\"",
-                    "type": "string"
-                },
-                "version": {
-                    "description": "Version is the current version of server",
-                    "type": "string"
+        "/api/v1/storage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Get a list of storages",
+                "responses": {
+                    "200": {
+                        "description": "List of storages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Storage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to convert storage"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Create storage",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateStorageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created storage",
+                        "schema": {
+                            "$ref": "#/definitions/store.Storage"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post storage request"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to create storage | Failed to convert storage"
+                    }
                 }
             }
         },
-        "store.FieldMapping": {
-            "type": "object",
-            "properties": {
-                "displayName": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
+        "/api/v1/storage/{storageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Delete a storage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Storage ID",
+                        "name": "storageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Storage deleted",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Storage service %d is using"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find storage | Failed to unmarshal storage service id | Failed to delete storage"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage"
+                ],
+                "summary": "Update a storage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Storage ID",
+                        "name": "storageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch request",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateStorageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated resource",
+                        "schema": {
+                            "$ref": "#/definitions/store.Storage"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Malformatted patch storage request | Malformatted post storage request"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to patch storage | Failed to convert storage"
+                    }
+                }
+            }
+        },
+        "/api/v1/system/setting": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system-setting"
+                ],
+                "summary": "Get a list of system settings",
+                "responses": {
+                    "200": {
+                        "description": "System setting list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.SystemSetting"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find system setting list"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system-setting"
+                ],
+                "summary": "Create system setting",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpsertSystemSettingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created system setting",
+                        "schema": {
+                            "$ref": "#/definitions/store.SystemSetting"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post system setting request | invalid system setting"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "403": {
+                        "description": "Cannot disable passwords if no SSO identity provider or passkey is configured. | Cannot require two-factor authentication before enabling it for yourself. | Cannot require email verification if SMTP is not configured. | Cannot disable SMTP while email verification is required. | Cannot enable SMTP if the external URL is not set. | Cannot unset the external URL while SMTP is enabled."
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find identity providers | Failed to find user credentials | Failed to find two-factor authentication | Failed to find system setting | Failed to upsert system setting"
+                    }
+                }
+            }
+        },
+        "/api/v1/system/signing-key": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get the keyring signing the access tokens, without the secrets",
+                "responses": {
+                    "200": {
+                        "description": "Signing key list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.SigningKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find signing keys"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Add a new signing key, which signs the new access tokens from now on",
+                "responses": {
+                    "200": {
+                        "description": "Signing key list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.SigningKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find signing keys | Failed to generate signing key | Failed to update signing keys"
+                    }
+                }
+            }
+        },
+        "/api/v1/system/signing-key/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Retire a signing key, which invalidates all the access tokens signed with it",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signing key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Signing key list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.SigningKey"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The only signing key can't be retired"
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "404": {
+                        "description": "Signing key not found: %s"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find signing keys | Failed to update signing keys"
+                    }
+                }
+            }
+        },
+        "/api/v1/system/vacuum": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Vacuum the database",
+                "responses": {
+                    "200": {
+                        "description": "Database vacuumed",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session | Unauthorized"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to ExecVacuum database"
+                    }
+                }
+            }
+        },
+        "/api/v1/tag": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Get a list of tags",
+                "responses": {
+                    "200": {
+                        "description": "Tag list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing user id to find tag"
+                    },
+                    "500": {
+                        "description": "Failed to find tag list"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Create a tag",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpsertTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created tag name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post tag request | Tag name shouldn't be empty"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to upsert tag | Failed to create activity"
+                    }
+                }
+            }
+        },
+        "/api/v1/tag/count": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Get the tags in the memos of the current user with the number of memos of each",
+                "responses": {
+                    "200": {
+                        "description": "Tag count list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.TagCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing user session"
+                    },
+                    "500": {
+                        "description": "Failed to find tag count list"
+                    }
+                }
+            }
+        },
+        "/api/v1/tag/delete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeleteTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag deleted",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post tag request | Tag name shouldn't be empty"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to delete tag name: %v"
+                    }
+                }
+            }
+        },
+        "/api/v1/tag/merge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Merge tags into one in all memos of the current user",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MergeTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of memos changed",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RenameTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted merge tags request | Tag name list shouldn't be empty | Invalid tag name: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to merge tags"
+                    }
+                }
+            }
+        },
+        "/api/v1/tag/rename": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Rename a tag in all memos of the current user",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RenameTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of memos changed",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RenameTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted rename tag request | Invalid tag name: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to rename tag"
+                    }
+                }
+            }
+        },
+        "/api/v1/tag/suggestion": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Get a list of tags suggested from other memos contents",
+                "responses": {
+                    "200": {
+                        "description": "Tag list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing user session"
+                    },
+                    "500": {
+                        "description": "Failed to find tag count list | Failed to find tag list"
+                    }
+                }
+            }
+        },
+        "/api/v1/user": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a list of users",
+                "responses": {
+                    "200": {
+                        "description": "User list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch user list"
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "Request object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created user",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post user request | Invalid user create format"
+                    },
+                    "401": {
+                        "description": "Missing auth session | Unauthorized to create user"
+                    },
+                    "403": {
+                        "description": "Could not create host user"
+                    },
+                    "500": {
+                        "description": "Failed to find user by id | Failed to generate password hash | Failed to create user | Failed to create activity"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "Current user",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing auth session"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find userSettingList"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/email-verification": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Send a link verifying the email of the current user",
+                "responses": {
+                    "200": {
+                        "description": "Email verification sent",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "Email is not set | Email is already verified | Mail is not configured"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find system setting | Failed to send email"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/passkey": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a list of passkeys of the current user",
+                "responses": {
+                    "200": {
+                        "description": "Passkey list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.UserPasskey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to fetch passkey list"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a passkey for the current user with the response of the authenticator to the registration challenge",
+                "parameters": [
+                    {
+                        "description": "Response of navigator.credentials.create()",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateUserPasskeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created passkey",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserPasskey"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post passkey request | Invalid or expired passkey challenge, please try again | Invalid passkey"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "409": {
+                        "description": "Passkey already exists"
+                    },
+                    "500": {
+                        "description": "Failed to find system setting | Failed to find passkey | Failed to create passkey"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/passkey/challenge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The challenge is kept in a cookie, which is checked when creating the passkey with the response of the authenticator.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Start the registration of a passkey for the current user",
+                "responses": {
+                    "200": {
+                        "description": "Options of navigator.credentials.create()",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PasskeyRegistrationChallenge"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to fetch passkey list | Failed to generate passkey challenge"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/passkey/{passkeyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete a passkey of the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Passkey ID",
+                        "name": "passkeyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Passkey deleted",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "403": {
+                        "description": "Cannot delete the last passkey while password login is deactivated and no SSO identity provider is configured."
+                    },
+                    "404": {
+                        "description": "Passkey not found: %d"
+                    },
+                    "500": {
+                        "description": "Failed to find passkey | Failed to find system setting | Failed to delete passkey"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/totp": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the two-factor authentication status of the current user",
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication status",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserTOTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "500": {
+                        "description": "Failed to find two-factor authentication"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Generate a new TOTP secret for the current user, which is enabled once a code of it is confirmed",
+                "responses": {
+                    "200": {
+                        "description": "TOTP secret and its provisioning URI",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserTOTPEnrollment"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to find two-factor authentication | Failed to generate TOTP secret | Failed to upsert two-factor authentication"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/totp/disable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Disable two-factor authentication of the current user with a code or a recovery code",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app, or a recovery code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserTOTPCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication disabled",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted disable TOTP request | Two-factor authentication is not enabled | Incorrect two-factor authentication code"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "429": {
+                        "description": "Too many incorrect two-factor authentication codes, please try again later"
+                    },
+                    "500": {
+                        "description": "Failed to find two-factor authentication | Failed to verify two-factor authentication code | Failed to delete two-factor authentication"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/totp/enable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Enable two-factor authentication of the current user by confirming a code of the enrolled secret",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator app",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserTOTPCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recovery codes, which are only returned once",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserTOTPRecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted enable TOTP request | Two-factor authentication is not enrolled | Incorrect two-factor authentication code"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled"
+                    },
+                    "429": {
+                        "description": "Too many incorrect two-factor authentication codes, please try again later"
+                    },
+                    "500": {
+                        "description": "Failed to find two-factor authentication | Failed to verify two-factor authentication code | Failed to generate recovery codes | Failed to update two-factor authentication"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/name/{username}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user by username",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requested user",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found"
+                    },
+                    "500": {
+                        "description": "Failed to find user"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/setting": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-setting"
+                ],
+                "summary": "Upsert user setting",
+                "parameters": [
+                    {
+                        "description": "Request object.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpsertUserSettingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Created user setting",
+                        "schema": {
+                            "$ref": "#/definitions/store.UserSetting"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted post user setting upsert request | Invalid user setting format"
+                    },
+                    "401": {
+                        "description": "Missing auth session"
+                    },
+                    "500": {
+                        "description": "Failed to upsert user setting"
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requested user",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted user id"
+                    },
+                    "404": {
+                        "description": "User not found"
+                    },
+                    "500": {
+                        "description": "Failed to find user"
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Current session user not found with ID: %d"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "403": {
+                        "description": "Unauthorized to delete user"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to delete user"
+                    }
+                }
+            },
+            "patch": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch request",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated user",
+                        "schema": {
+                            "$ref": "#/definitions/store.User"
+                        }
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Current session user not found with ID: %d | Malformatted patch user request | Invalid update user request"
+                    },
+                    "401": {
+                        "description": "Missing user in session"
+                    },
+                    "403": {
+                        "description": "Unauthorized to update user"
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to generate password hash | Failed to patch user | Failed to revoke user credentials | Failed to find userSettingList"
+                    }
+                }
+            }
+        },
+        "/explore/rss.xml": {
+            "get": {
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "rss"
+                ],
+                "summary": "Get RSS",
+                "responses": {
+                    "200": {
+                        "description": "RSS"
+                    },
+                    "500": {
+                        "description": "Failed to get system customized profile | Failed to find memo list | Failed to generate rss"
+                    }
+                }
+            }
+        },
+        "/o/get/GetImage": {
+            "get": {
+                "produces": [
+                    "GetImage/*"
+                ],
+                "tags": [
+                    "get"
+                ],
+                "summary": "Get GetImage from URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image url",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image"
+                    },
+                    "400": {
+                        "description": "Missing GetImage url | Wrong url | Failed to get GetImage url: %s"
+                    },
+                    "500": {
+                        "description": "Failed to write GetImage blob"
+                    }
+                }
+            }
+        },
+        "/o/get/GetWebsiteMetadata": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "get"
+                ],
+                "summary": "Get website metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Website URL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Extracted metadata",
+                        "schema": {
+                            "$ref": "#/definitions/getter.HTMLMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing website url | Wrong url"
+                    },
+                    "406": {
+                        "description": "Failed to get website meta with url: %s"
+                    }
+                }
+            }
+        },
+        "/o/r/{resourceId}": {
+            "get": {
+                "description": "*Swagger UI may have problems displaying other file types than images",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "resource"
+                ],
+                "summary": "Stream a resource",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "resourceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Thumbnail",
+                        "name": "thumbnail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requested resource"
+                    },
+                    "400": {
+                        "description": "ID is not a number: %s | Failed to get resource visibility"
+                    },
+                    "401": {
+                        "description": "Resource visibility not match"
+                    },
+                    "404": {
+                        "description": "Resource not found: %d"
+                    },
+                    "500": {
+                        "description": "Failed to find resource by ID: %v | Failed to open the local resource: %s | Failed to read the local resource: %s"
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Get a list of users with SCIM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. userName eq \\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based index of the first user",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of users",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User list",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported filter %s | Invalid pagination",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing personal access token | Invalid or expired access token",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token lacks the admin scope | Only host can provision users",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find user list",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "The user is created with the USER role, and a random password if none is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Create a user with SCIM",
+                "parameters": [
+                    {
+                        "description": "User information",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted SCIM user | Invalid SCIM user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing personal access token | Invalid or expired access token",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token lacks the admin scope | Only host can provision users",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists with username %s",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to generate password | Failed to generate password hash | Failed to create user | Failed to update user | Failed to create activity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Get a user by ID with SCIM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requested user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMUser"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing personal access token | Invalid or expired access token",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token lacks the admin scope | Only host can provision users",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "The user is archived if it's not active, and its sessions are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Replace a user with SCIM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User information",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Replaced user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted SCIM user | Invalid SCIM user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing personal access token | Invalid or expired access token",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token lacks the admin scope | Only host can provision users | Host user can't be deactivated or deleted with SCIM",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists with username %s",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to generate password hash | Failed to update user | Failed to revoke user credentials",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Delete a user with SCIM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User deleted"
+                    },
+                    "401": {
+                        "description": "Missing personal access token | Invalid or expired access token",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token lacks the admin scope | Only host can provision users | Host user can't be deactivated or deleted with SCIM",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to delete user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "The operations on the attributes which aren't mapped onto the user are ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Patch a user with SCIM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch operations",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patched user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformatted SCIM patch request | Invalid SCIM patch operation | Invalid SCIM user",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing personal access token | Invalid or expired access token",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "403": {
+                        "description": "Access token lacks the admin scope | Only host can provision users | Host user can't be deactivated or deleted with SCIM",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists with username %s",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find user | Failed to generate password hash | Failed to update user | Failed to revoke user credentials",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SCIMError"
+                        }
+                    }
+                }
+            }
+        },
+        "/u/{id}/rss.xml": {
+            "get": {
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "rss"
+                ],
+                "summary": "Get RSS for a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "RSS"
+                    },
+                    "400": {
+                        "description": "User id is not a number"
+                    },
+                    "500": {
+                        "description": "Failed to get system customized profile | Failed to find memo list | Failed to generate rss"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.Scope": {
+            "type": "string",
+            "enum": [
+                "memo.read",
+                "memo.write",
+                "resource.upload",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "ScopeMemoRead",
+                "ScopeMemoWrite",
+                "ScopeResourceUpload",
+                "ScopeAdmin"
+            ]
+        },
+        "getter.HTMLMeta": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "profile.Profile": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Mode can be \"prod\" or \"dev\" or \"demo\"",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the current version of server",
+                    "type": "string"
+                }
+            }
+        },
+        "store.FieldMapping": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "identifier": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.IdentityProvider": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/store.IdentityProviderConfig"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identifierFilter": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provisioning": {
+                    "$ref": "#/definitions/store.IdentityProviderProvisioning"
+                },
+                "type": {
+                    "$ref": "#/definitions/store.IdentityProviderType"
+                }
+            }
+        },
+        "store.IdentityProviderClaimRule": {
+            "type": "object",
+            "properties": {
+                "claim": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "Pattern is a regular expression, e.g. @example\\.com$ matching the email domain.",
+                    "type": "string"
+                }
+            }
+        },
+        "store.IdentityProviderConfig": {
+            "type": "object",
+            "properties": {
+                "ldapconfig": {
+                    "$ref": "#/definitions/store.IdentityProviderLDAPConfig"
+                },
+                "oauth2Config": {
+                    "$ref": "#/definitions/store.IdentityProviderOAuth2Config"
+                },
+                "oidcconfig": {
+                    "$ref": "#/definitions/store.IdentityProviderOIDCConfig"
+                }
+            }
+        },
+        "store.IdentityProviderLDAPConfig": {
+            "type": "object",
+            "properties": {
+                "adminGroups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "baseDn": {
+                    "type": "string"
+                },
+                "bindDn": {
+                    "description": "BindDN and BindPassword are the credentials of the service account searching the users.",
+                    "type": "string"
+                },
+                "bindPassword": {
+                    "type": "string"
+                },
+                "fieldMapping": {
+                    "$ref": "#/definitions/store.FieldMapping"
+                },
+                "groupAttribute": {
+                    "description": "GroupAttribute is the attribute of the user listing its groups, e.g. memberOf.\nThe role of the user is mapped from its groups on each sign-in if it's set.",
+                    "type": "string"
+                },
+                "hostGroups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startTls": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "description": "URL is the address of the directory, e.g. ldaps://ldap.example.com:636.",
+                    "type": "string"
+                },
+                "userFilter": {
+                    "description": "UserFilter is the filter searching the user, where %s is replaced with the username, e.g. (uid=%s).",
+                    "type": "string"
+                }
+            }
+        },
+        "store.IdentityProviderOAuth2Config": {
+            "type": "object",
+            "properties": {
+                "authUrl": {
+                    "type": "string"
+                },
+                "clientId": {
+                    "type": "string"
+                },
+                "clientSecret": {
+                    "type": "string"
+                },
+                "fieldMapping": {
+                    "$ref": "#/definitions/store.FieldMapping"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tokenUrl": {
+                    "type": "string"
+                },
+                "userInfoUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.IdentityProviderOIDCConfig": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "type": "string"
+                },
+                "clientSecret": {
+                    "type": "string"
+                },
+                "fieldMapping": {
+                    "$ref": "#/definitions/store.FieldMapping"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "store.IdentityProviderProvisioning": {
+            "type": "object",
+            "properties": {
+                "archiveRule": {
+                    "description": "ArchiveRule archives the user when it matches, e.g. when the identity provider reports the user as disabled.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.IdentityProviderClaimRule"
+                        }
+                    ]
+                },
+                "disableSignUp": {
+                    "description": "DisableSignUp prevents the unknown users from being created on their first sign-in.",
+                    "type": "boolean"
+                },
+                "roleMappings": {
+                    "description": "RoleMappings map the claims to the role of the user on each sign-in, the first matching one wins.\nThe user gets the USER role if none matches, and keeps its role if there is no mapping.\nThe claims carrying the email are only matched if the identity provider asserts that it's verified.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.IdentityProviderRoleMapping"
+                    }
+                },
+                "syncProfile": {
+                    "description": "SyncProfile updates the nickname, email and avatar of the user from the claims on each sign-in.\nThe email is only synced if the identity provider asserts that it's verified.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "store.IdentityProviderRoleMapping": {
+            "type": "object",
+            "properties": {
+                "claim": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/store.Role"
+                }
+            }
+        },
+        "store.IdentityProviderType": {
+            "type": "string",
+            "enum": [
+                "OAUTH2",
+                "OIDC",
+                "LDAP"
+            ],
+            "x-enum-varnames": [
+                "IdentityProviderOAuth2Type",
+                "IdentityProviderOIDCType",
+                "IdentityProviderLDAPType"
+            ]
+        },
+        "store.Memo": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                },
+                "createdTs": {
+                    "type": "integer"
+                },
+                "creatorID": {
+                    "type": "integer"
+                },
+                "deletedTs": {
+                    "description": "DeletedTs is the time the memo is moved to trash, or 0 if it isn't in trash.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "description": "Composed fields",
+                    "type": "boolean"
+                },
+                "relationList": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.MemoRelation"
+                    }
+                },
+                "resourceIDList": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rowStatus": {
+                    "description": "Standard fields",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.RowStatus"
+                        }
+                    ]
+                },
+                "snippet": {
+                    "description": "Search fields\nSnippet is the highlighted excerpt of content matching the content search,\nas HTML with the content escaped and the matches in \u003cmark\u003e tags.",
+                    "type": "string"
+                },
+                "updatedTs": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/store.Visibility"
+                }
+            }
+        },
+        "store.MemoRelation": {
+            "type": "object",
+            "properties": {
+                "memoID": {
+                    "type": "integer"
+                },
+                "relatedMemoID": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/store.MemoRelationType"
+                }
+            }
+        },
+        "store.MemoRelationType": {
+            "type": "string",
+            "enum": [
+                "REFERENCE",
+                "ADDITIONAL"
+            ],
+            "x-enum-varnames": [
+                "MemoRelationReference",
+                "MemoRelationAdditional"
+            ]
+        },
+        "store.MemoRevision": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdTs": {
+                    "description": "Standard fields",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "memoID": {
+                    "description": "Domain specific fields",
+                    "type": "integer"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/store.Visibility"
+                }
+            }
+        },
+        "store.Resource": {
+            "type": "object",
+            "properties": {
+                "blob": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "createdTs": {
+                    "type": "integer"
+                },
+                "creatorID": {
+                    "description": "Standard fields",
+                    "type": "integer"
+                },
+                "externalLink": {
+                    "type": "string"
+                },
+                "filename": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internalPath": {
+                    "type": "string"
+                },
+                "linkedMemoAmount": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedTs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.Role": {
+            "type": "string",
+            "enum": [
+                "HOST",
+                "ADMIN",
+                "USER"
+            ],
+            "x-enum-varnames": [
+                "RoleHost",
+                "RoleAdmin",
+                "RoleUser"
+            ]
+        },
+        "store.RowStatus": {
+            "type": "string",
+            "enum": [
+                "NORMAL",
+                "ARCHIVED"
+            ],
+            "x-enum-varnames": [
+                "Normal",
+                "Archived"
+            ]
+        },
+        "store.Shortcut": {
+            "type": "object",
+            "properties": {
+                "createdTs": {
+                    "type": "integer"
+                },
+                "creatorID": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "$ref": "#/definitions/store.ShortcutPayload"
+                },
+                "rowStatus": {
+                    "description": "Standard fields",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.RowStatus"
+                        }
+                    ]
+                },
+                "title": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                },
+                "updatedTs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.ShortcutPayload": {
+            "type": "object",
+            "properties": {
+                "createdTsAfter": {
+                    "type": "integer"
+                },
+                "createdTsBefore": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "visibilityList": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.Visibility"
+                    }
+                }
+            }
+        },
+        "store.Storage": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.SystemSetting": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.User": {
+            "type": "object",
+            "properties": {
+                "avatarURL": {
+                    "type": "string"
+                },
+                "createdTs": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identityProviderID": {
+                    "description": "IdentityProviderID and IdentityProviderIdentifier link the user to the identity provider it has been\nprovisioned by, which it signs in with instead of its local password.",
+                    "type": "integer"
+                },
+                "identityProviderIdentifier": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "openID": {
+                    "type": "string"
+                },
+                "passwordHash": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/store.Role"
+                },
+                "rowStatus": {
+                    "description": "Standard fields",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.RowStatus"
+                        }
+                    ]
+                },
+                "updatedTs": {
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                },
+                "verifiedEmail": {
+                    "description": "VerifiedEmail is the email proven to be owned by the user, which is verified as long as it's the email.",
+                    "type": "string"
+                }
+            }
+        },
+        "store.UserSetting": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.Visibility": {
+            "type": "string",
+            "enum": [
+                "PUBLIC",
+                "PROTECTED",
+                "PRIVATE"
+            ],
+            "x-enum-varnames": [
+                "Public",
+                "Protected",
+                "Private"
+            ]
+        },
+        "util.DiffOperation": {
+            "type": "string",
+            "enum": [
+                "EQUAL",
+                "INSERT",
+                "DELETE"
+            ],
+            "x-enum-varnames": [
+                "DiffEqual",
+                "DiffInsert",
+                "DiffDelete"
+            ]
+        },
+        "v1.AccessToken": {
+            "type": "object",
+            "properties": {
+                "createdTs": {
+                    "type": "integer"
+                },
+                "expiresTs": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedTs": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Scope"
+                    }
+                },
+                "token": {
+                    "description": "Token is only returned when the access token is created, as only its hash is stored.",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Standard fields",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.CreateAccessTokenRequest": {
+            "type": "object",
+            "properties": {
+                "expiresTs": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Scope"
+                    }
+                }
+            }
+        },
+        "v1.CreateIdentityProviderRequest": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/v1.IdentityProviderConfig"
+                },
+                "identifierFilter": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provisioning": {
+                    "$ref": "#/definitions/v1.IdentityProviderProvisioning"
+                },
+                "type": {
+                    "$ref": "#/definitions/v1.IdentityProviderType"
+                }
+            }
+        },
+        "v1.CreateMemoRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdTs": {
+                    "type": "integer"
+                },
+                "relationList": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.UpsertMemoRelationRequest"
+                    }
+                },
+                "resourceIdList": {
+                    "description": "Related fields",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "visibility": {
+                    "description": "Domain specific fields",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1.Visibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "v1.CreateResourceRequest": {
+            "type": "object",
+            "properties": {
+                "downloadToLocal": {
+                    "type": "boolean"
+                },
+                "externalLink": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "internalPath": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateShortcutRequest": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "$ref": "#/definitions/v1.ShortcutPayload"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateStorageRequest": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/v1.StorageConfig"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/v1.StorageType"
+                }
+            }
+        },
+        "v1.CreateUserPasskeyRequest": {
+            "type": "object",
+            "properties": {
+                "credential": {
+                    "$ref": "#/definitions/v1.PasskeyAttestationCredential"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/v1.Role"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CustomizedProfile": {
+            "type": "object",
+            "properties": {
+                "appearance": {
+                    "description": "Appearance is the server default appearance.",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description is the server description.",
+                    "type": "string"
+                },
+                "externalUrl": {
+                    "description": "ExternalURL is the external url of server. e.g. https://usermemos.com",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale is the server default locale.",
+                    "type": "string"
+                },
+                "logoUrl": {
+                    "description": "LogoURL is the url of logo image.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the server name, default is ` + "`" + `memos` + "`" + `",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.DeleteTagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.FieldMapping": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "identifier": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.IdentityProvider": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/v1.IdentityProviderConfig"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identifierFilter": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provisioning": {
+                    "$ref": "#/definitions/v1.IdentityProviderProvisioning"
+                },
+                "type": {
+                    "$ref": "#/definitions/v1.IdentityProviderType"
+                }
+            }
+        },
+        "v1.IdentityProviderClaimRule": {
+            "type": "object",
+            "properties": {
+                "claim": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.IdentityProviderConfig": {
+            "type": "object",
+            "properties": {
+                "ldapConfig": {
+                    "$ref": "#/definitions/v1.IdentityProviderLDAPConfig"
+                },
+                "oauth2Config": {
+                    "$ref": "#/definitions/v1.IdentityProviderOAuth2Config"
+                },
+                "oidcConfig": {
+                    "$ref": "#/definitions/v1.IdentityProviderOIDCConfig"
+                }
+            }
+        },
+        "v1.IdentityProviderLDAPConfig": {
+            "type": "object",
+            "properties": {
+                "adminGroups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "baseDn": {
+                    "type": "string"
+                },
+                "bindDn": {
+                    "type": "string"
+                },
+                "bindPassword": {
+                    "type": "string"
+                },
+                "fieldMapping": {
+                    "$ref": "#/definitions/v1.FieldMapping"
                 },
-                "identifier": {
+                "groupAttribute": {
                     "type": "string"
-                }
-            }
-        },
-        "store.IdentityProvider": {
-            "type": "object",
-            "properties": {
-                "config": {
-                    "$ref": "#/definitions/store.IdentityProviderConfig"
                 },
-                "id": {
-                    "type": "integer"
+                "hostGroups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "identifierFilter": {
-                    "type": "string"
+                "startTls": {
+                    "type": "boolean"
                 },
-                "name": {
+                "url": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/store.IdentityProviderType"
-                }
-            }
-        },
-        "store.IdentityProviderConfig": {
-            "type": "object",
-            "properties": {
-                "oauth2Config": {
-                    "$ref": "#/definitions/store.IdentityProviderOAuth2Config"
+                "userFilter": {
+                    "type": "string"
                 }
             }
         },
-        "store.IdentityProviderOAuth2Config": {
+        "v1.IdentityProviderOAuth2Config": {
             "type": "object",
             "properties": {
                 "authUrl": {
@@ -2368,7 +5358,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "fieldMapping": {
-                    "$ref": "#/definitions/store.FieldMapping"
+                    "$ref": "#/definitions/v1.FieldMapping"
                 },
                 "scopes": {
                     "type": "array",
@@ -2384,78 +5374,88 @@ const docTemplate = `{
                 }
             }
         },
-        "store.IdentityProviderType": {
-            "type": "string",
-            "enum": [
-                "OAUTH2"
-            ],
-            "x-enum-varnames": [
-                "IdentityProviderOAuth2Type"
-            ]
-        },
-        "store.Memo": {
+        "v1.IdentityProviderOIDCConfig": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "Domain specific fields",
+                "clientId": {
                     "type": "string"
                 },
-                "createdTs": {
-                    "type": "integer"
-                },
-                "creatorID": {
-                    "type": "integer"
+                "clientSecret": {
+                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "fieldMapping": {
+                    "$ref": "#/definitions/v1.FieldMapping"
                 },
-                "pinned": {
-                    "description": "Composed fields",
-                    "type": "boolean"
+                "issuer": {
+                    "type": "string"
                 },
-                "relationList": {
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/store.MemoRelation"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "v1.IdentityProviderProvisioning": {
+            "type": "object",
+            "properties": {
+                "archiveRule": {
+                    "$ref": "#/definitions/v1.IdentityProviderClaimRule"
                 },
-                "resourceIDList": {
+                "disableSignUp": {
+                    "type": "boolean"
+                },
+                "roleMappings": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/v1.IdentityProviderRoleMapping"
                     }
                 },
-                "rowStatus": {
-                    "description": "Standard fields",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/store.RowStatus"
-                        }
-                    ]
+                "syncProfile": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.IdentityProviderRoleMapping": {
+            "type": "object",
+            "properties": {
+                "claim": {
+                    "type": "string"
                 },
-                "updatedTs": {
-                    "type": "integer"
+                "pattern": {
+                    "type": "string"
                 },
-                "visibility": {
-                    "$ref": "#/definitions/store.Visibility"
+                "role": {
+                    "$ref": "#/definitions/v1.Role"
                 }
             }
         },
-        "store.MemoRelation": {
+        "v1.IdentityProviderType": {
+            "type": "string",
+            "enum": [
+                "OAUTH2",
+                "OIDC",
+                "LDAP"
+            ],
+            "x-enum-varnames": [
+                "IdentityProviderOAuth2Type",
+                "IdentityProviderOIDCType",
+                "IdentityProviderLDAPType"
+            ]
+        },
+        "v1.ImportMemosResponse": {
             "type": "object",
             "properties": {
-                "memoID": {
+                "memoCount": {
                     "type": "integer"
                 },
-                "relatedMemoID": {
+                "resourceCount": {
                     "type": "integer"
-                },
-                "type": {
-                    "$ref": "#/definitions/store.MemoRelationType"
                 }
             }
         },
-        "store.MemoRelationType": {
+        "v1.MemoRelationType": {
             "type": "string",
             "enum": [
                 "REFERENCE",
@@ -2466,83 +5466,176 @@ const docTemplate = `{
                 "MemoRelationAdditional"
             ]
         },
-        "store.Resource": {
+        "v1.MemoRevision": {
             "type": "object",
             "properties": {
-                "blob": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "content": {
+                    "type": "string"
                 },
                 "createdTs": {
-                    "type": "integer"
-                },
-                "creatorID": {
                     "description": "Standard fields",
                     "type": "integer"
                 },
-                "externalLink": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "filename": {
+                "memoId": {
                     "description": "Domain specific fields",
+                    "type": "integer"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/v1.Visibility"
+                }
+            }
+        },
+        "v1.MemoRevisionDiff": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/v1.MemoRevision"
+                },
+                "lineList": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.MemoRevisionDiffLine"
+                    }
+                },
+                "to": {
+                    "$ref": "#/definitions/v1.MemoRevision"
+                }
+            }
+        },
+        "v1.MemoRevisionDiffLine": {
+            "type": "object",
+            "properties": {
+                "operation": {
+                    "$ref": "#/definitions/util.DiffOperation"
+                },
+                "text": {
                     "type": "string"
+                }
+            }
+        },
+        "v1.MergeTagsRequest": {
+            "type": "object",
+            "properties": {
+                "nameList": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
+                "newName": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.PasskeyAssertionCredential": {
+            "type": "object",
+            "properties": {
                 "id": {
-                    "type": "integer"
+                    "description": "ID is the base64url encoded credential ID.",
+                    "type": "string"
                 },
-                "internalPath": {
+                "response": {
+                    "$ref": "#/definitions/v1.PasskeyAssertionResponse"
+                }
+            }
+        },
+        "v1.PasskeyAssertionResponse": {
+            "type": "object",
+            "properties": {
+                "authenticatorData": {
                     "type": "string"
                 },
-                "linkedMemoAmount": {
-                    "type": "integer"
+                "clientDataJSON": {
+                    "type": "string"
                 },
-                "size": {
-                    "type": "integer"
+                "signature": {
+                    "type": "string"
                 },
-                "type": {
+                "userHandle": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.PasskeyAttestationCredential": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is the base64url encoded credential ID.",
                     "type": "string"
                 },
-                "updatedTs": {
-                    "type": "integer"
+                "response": {
+                    "$ref": "#/definitions/v1.PasskeyAttestationResponse"
                 }
             }
         },
-        "store.Role": {
-            "type": "string",
-            "enum": [
-                "HOST",
-                "ADMIN",
-                "USER"
-            ],
-            "x-enum-varnames": [
-                "RoleHost",
-                "RoleAdmin",
-                "RoleUser"
-            ]
+        "v1.PasskeyAttestationResponse": {
+            "type": "object",
+            "properties": {
+                "attestationObject": {
+                    "type": "string"
+                },
+                "clientDataJSON": {
+                    "type": "string"
+                }
+            }
         },
-        "store.RowStatus": {
-            "type": "string",
-            "enum": [
-                "NORMAL",
-                "ARCHIVED"
-            ],
-            "x-enum-varnames": [
-                "Normal",
-                "Archived"
-            ]
+        "v1.PasskeyAuthenticatorSelection": {
+            "type": "object",
+            "properties": {
+                "requireResidentKey": {
+                    "type": "boolean"
+                },
+                "residentKey": {
+                    "type": "string"
+                },
+                "userVerification": {
+                    "type": "string"
+                }
+            }
         },
-        "store.Storage": {
+        "v1.PasskeyCreationOptions": {
             "type": "object",
             "properties": {
-                "config": {
+                "attestation": {
                     "type": "string"
                 },
-                "id": {
+                "authenticatorSelection": {
+                    "$ref": "#/definitions/v1.PasskeyAuthenticatorSelection"
+                },
+                "challenge": {
+                    "type": "string"
+                },
+                "excludeCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PasskeyCredentialDescriptor"
+                    }
+                },
+                "pubKeyCredParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PasskeyCredentialParameter"
+                    }
+                },
+                "rp": {
+                    "$ref": "#/definitions/v1.PasskeyRelyingParty"
+                },
+                "timeout": {
                     "type": "integer"
                 },
-                "name": {
+                "user": {
+                    "$ref": "#/definitions/v1.PasskeyUser"
+                }
+            }
+        },
+        "v1.PasskeyCredentialDescriptor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is the base64url encoded credential ID.",
                     "type": "string"
                 },
                 "type": {
@@ -2550,115 +5643,100 @@ const docTemplate = `{
                 }
             }
         },
-        "store.SystemSetting": {
+        "v1.PasskeyCredentialParameter": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "Alg is the COSE algorithm of the credential public key.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.PasskeyRegistrationChallenge": {
+            "type": "object",
+            "properties": {
+                "publicKey": {
+                    "$ref": "#/definitions/v1.PasskeyCreationOptions"
+                }
+            }
+        },
+        "v1.PasskeyRelyingParty": {
             "type": "object",
             "properties": {
-                "description": {
+                "id": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
-                },
-                "value": {
-                    "type": "string"
                 }
             }
         },
-        "store.User": {
+        "v1.PasskeyRequestOptions": {
             "type": "object",
             "properties": {
-                "avatarURL": {
-                    "type": "string"
-                },
-                "createdTs": {
-                    "type": "integer"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "nickname": {
-                    "type": "string"
+                "allowCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PasskeyCredentialDescriptor"
+                    }
                 },
-                "openID": {
+                "challenge": {
                     "type": "string"
                 },
-                "passwordHash": {
+                "rpId": {
                     "type": "string"
                 },
-                "role": {
-                    "$ref": "#/definitions/store.Role"
-                },
-                "rowStatus": {
-                    "description": "Standard fields",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/store.RowStatus"
-                        }
-                    ]
-                },
-                "updatedTs": {
+                "timeout": {
                     "type": "integer"
                 },
-                "username": {
-                    "description": "Domain specific fields",
+                "userVerification": {
                     "type": "string"
                 }
             }
         },
-        "store.UserSetting": {
+        "v1.PasskeySignIn": {
             "type": "object",
             "properties": {
-                "key": {
-                    "type": "string"
-                },
-                "userID": {
-                    "type": "integer"
-                },
-                "value": {
-                    "type": "string"
+                "credential": {
+                    "$ref": "#/definitions/v1.PasskeyAssertionCredential"
                 }
             }
         },
-        "store.Visibility": {
-            "type": "string",
-            "enum": [
-                "PUBLIC",
-                "PROTECTED",
-                "PRIVATE"
-            ],
-            "x-enum-varnames": [
-                "Public",
-                "Protected",
-                "Private"
-            ]
+        "v1.PasskeySignInChallenge": {
+            "type": "object",
+            "properties": {
+                "publicKey": {
+                    "$ref": "#/definitions/v1.PasskeyRequestOptions"
+                }
+            }
         },
-        "v1.CreateIdentityProviderRequest": {
+        "v1.PasskeyUser": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/v1.IdentityProviderConfig"
+                "displayName": {
+                    "type": "string"
                 },
-                "identifierFilter": {
+                "id": {
+                    "description": "ID is the base64url encoded user handle.",
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/v1.IdentityProviderType"
                 }
             }
         },
-        "v1.CreateMemoRequest": {
+        "v1.PatchMemoRequest": {
             "type": "object",
             "properties": {
                 "content": {
+                    "description": "Domain specific fields",
                     "type": "string"
                 },
                 "createdTs": {
+                    "description": "Standard fields",
                     "type": "integer"
                 },
                 "relationList": {
@@ -2674,303 +5752,346 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "rowStatus": {
+                    "$ref": "#/definitions/v1.RowStatus"
+                },
+                "updatedTs": {
+                    "type": "integer"
+                },
                 "visibility": {
-                    "description": "Domain specific fields",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/v1.Visibility"
-                        }
-                    ]
+                    "$ref": "#/definitions/v1.Visibility"
                 }
             }
         },
-        "v1.CreateResourceRequest": {
+        "v1.RenameTagRequest": {
             "type": "object",
             "properties": {
-                "downloadToLocal": {
-                    "type": "boolean"
-                },
-                "externalLink": {
-                    "type": "string"
-                },
-                "filename": {
-                    "type": "string"
-                },
-                "internalPath": {
+                "name": {
                     "type": "string"
                 },
-                "type": {
+                "newName": {
                     "type": "string"
                 }
             }
         },
-        "v1.CreateStorageRequest": {
+        "v1.RenameTagResponse": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/v1.StorageConfig"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/v1.StorageType"
+                "memoCount": {
+                    "description": "MemoCount is the number of memos changed.",
+                    "type": "integer"
                 }
             }
         },
-        "v1.CreateUserRequest": {
+        "v1.RequestPasswordResetRequest": {
             "type": "object",
             "properties": {
                 "email": {
                     "type": "string"
-                },
-                "nickname": {
-                    "type": "string"
-                },
+                }
+            }
+        },
+        "v1.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
                 "password": {
                     "type": "string"
                 },
-                "role": {
-                    "$ref": "#/definitions/v1.Role"
-                },
-                "username": {
+                "token": {
+                    "description": "Token is the token of the link sent to the email of the user.",
                     "type": "string"
                 }
             }
         },
-        "v1.CustomizedProfile": {
+        "v1.Resource": {
             "type": "object",
             "properties": {
-                "appearance": {
-                    "description": "Appearance is the server default appearance.",
-                    "type": "string"
+                "createdTs": {
+                    "type": "integer"
                 },
-                "description": {
-                    "description": "Description is the server description.",
-                    "type": "string"
+                "creatorId": {
+                    "description": "Standard fields",
+                    "type": "integer"
                 },
-                "externalUrl": {
-                    "description": "ExternalURL is the external url of server. e.g. https://usermemos.com",
+                "externalLink": {
                     "type": "string"
                 },
-                "locale": {
-                    "description": "Locale is the server default locale.",
+                "filename": {
+                    "description": "Domain specific fields",
                     "type": "string"
                 },
-                "logoUrl": {
-                    "description": "LogoURL is the url of logo image.",
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "name": {
-                    "description": "Name is the server name, default is ` + "`" + `memos` + "`" + `",
+                "linkedMemoAmount": {
+                    "description": "Related fields",
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
+                },
+                "updatedTs": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.DeleteTagRequest": {
+        "v1.Role": {
+            "type": "string",
+            "enum": [
+                "HOST",
+                "ADMIN",
+                "USER"
+            ],
+            "x-enum-varnames": [
+                "RoleHost",
+                "RoleAdmin",
+                "RoleUser"
+            ]
+        },
+        "v1.RowStatus": {
+            "type": "string",
+            "enum": [
+                "NORMAL",
+                "ARCHIVED"
+            ],
+            "x-enum-varnames": [
+                "Normal",
+                "Archived"
+            ]
+        },
+        "v1.SCIMEmail": {
             "type": "object",
             "properties": {
-                "name": {
+                "primary": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "v1.FieldMapping": {
+        "v1.SCIMError": {
             "type": "object",
             "properties": {
-                "displayName": {
+                "detail": {
                     "type": "string"
                 },
-                "email": {
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scimType": {
                     "type": "string"
                 },
-                "identifier": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "v1.IdentityProvider": {
+        "v1.SCIMListResponse": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/v1.IdentityProviderConfig"
+                "Resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SCIMUser"
+                    }
                 },
-                "id": {
+                "itemsPerPage": {
                     "type": "integer"
                 },
-                "identifierFilter": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "startIndex": {
+                    "type": "integer"
                 },
-                "type": {
-                    "$ref": "#/definitions/v1.IdentityProviderType"
-                }
-            }
-        },
-        "v1.IdentityProviderConfig": {
-            "type": "object",
-            "properties": {
-                "oauth2Config": {
-                    "$ref": "#/definitions/v1.IdentityProviderOAuth2Config"
+                "totalResults": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.IdentityProviderOAuth2Config": {
+        "v1.SCIMMeta": {
             "type": "object",
             "properties": {
-                "authUrl": {
+                "created": {
                     "type": "string"
                 },
-                "clientId": {
+                "lastModified": {
                     "type": "string"
                 },
-                "clientSecret": {
+                "location": {
                     "type": "string"
                 },
-                "fieldMapping": {
-                    "$ref": "#/definitions/v1.FieldMapping"
-                },
-                "scopes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "resourceType": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.SCIMName": {
+            "type": "object",
+            "properties": {
+                "familyName": {
+                    "type": "string"
                 },
-                "tokenUrl": {
+                "formatted": {
                     "type": "string"
                 },
-                "userInfoUrl": {
+                "givenName": {
                     "type": "string"
                 }
             }
         },
-        "v1.IdentityProviderType": {
-            "type": "string",
-            "enum": [
-                "OAUTH2"
-            ],
-            "x-enum-varnames": [
-                "IdentityProviderOAuth2Type"
-            ]
-        },
-        "v1.MemoRelationType": {
-            "type": "string",
-            "enum": [
-                "REFERENCE",
-                "ADDITIONAL"
-            ],
-            "x-enum-varnames": [
-                "MemoRelationReference",
-                "MemoRelationAdditional"
-            ]
+        "v1.SCIMPatchRequest": {
+            "type": "object"
         },
-        "v1.PatchMemoRequest": {
+        "v1.SCIMUser": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "Domain specific fields",
-                    "type": "string"
+                "active": {
+                    "description": "Active is false for the archived users.",
+                    "type": "boolean"
                 },
-                "createdTs": {
-                    "description": "Standard fields",
-                    "type": "integer"
+                "displayName": {
+                    "type": "string"
                 },
-                "relationList": {
+                "emails": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.UpsertMemoRelationRequest"
+                        "$ref": "#/definitions/v1.SCIMEmail"
                     }
                 },
-                "resourceIdList": {
-                    "description": "Related fields",
+                "id": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/v1.SCIMMeta"
+                },
+                "name": {
+                    "$ref": "#/definitions/v1.SCIMName"
+                },
+                "password": {
+                    "description": "Password is only written, it's never returned.",
+                    "type": "string"
+                },
+                "schemas": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "rowStatus": {
-                    "$ref": "#/definitions/v1.RowStatus"
-                },
-                "updatedTs": {
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.SSOAuthorization": {
+            "type": "object",
+            "properties": {
+                "authorizationUrl": {
+                    "description": "AuthorizationURL is where the user signs in with the identity provider, which redirects back with the code and the state.",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.SSOAuthorize": {
+            "type": "object",
+            "properties": {
+                "identityProviderId": {
                     "type": "integer"
                 },
-                "visibility": {
-                    "$ref": "#/definitions/v1.Visibility"
+                "redirectUri": {
+                    "type": "string"
                 }
             }
         },
-        "v1.Resource": {
+        "v1.SSOSignIn": {
             "type": "object",
             "properties": {
-                "createdTs": {
-                    "type": "integer"
+                "code": {
+                    "type": "string"
                 },
-                "creatorId": {
-                    "description": "Standard fields",
+                "identityProviderId": {
                     "type": "integer"
                 },
-                "externalLink": {
+                "redirectUri": {
                     "type": "string"
                 },
-                "filename": {
-                    "description": "Domain specific fields",
+                "state": {
+                    "description": "State is the state of the authorization request, which is required by OpenID Connect identity providers.",
                     "type": "string"
-                },
-                "id": {
+                }
+            }
+        },
+        "v1.Session": {
+            "type": "object",
+            "properties": {
+                "createdTs": {
                     "type": "integer"
                 },
-                "linkedMemoAmount": {
-                    "description": "Related fields",
+                "current": {
+                    "description": "Current is whether the session is the one of the request.",
+                    "type": "boolean"
+                },
+                "expiresTs": {
+                    "description": "Domain specific fields",
                     "type": "integer"
                 },
-                "size": {
+                "id": {
                     "type": "integer"
                 },
-                "type": {
+                "ipAddress": {
                     "type": "string"
                 },
                 "updatedTs": {
                     "type": "integer"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Standard fields",
+                    "type": "integer"
                 }
             }
         },
-        "v1.Role": {
-            "type": "string",
-            "enum": [
-                "HOST",
-                "ADMIN",
-                "USER"
-            ],
-            "x-enum-varnames": [
-                "RoleHost",
-                "RoleAdmin",
-                "RoleUser"
-            ]
-        },
-        "v1.RowStatus": {
-            "type": "string",
-            "enum": [
-                "NORMAL",
-                "ARCHIVED"
-            ],
-            "x-enum-varnames": [
-                "Normal",
-                "Archived"
-            ]
-        },
-        "v1.SSOSignIn": {
+        "v1.ShortcutPayload": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string"
+                "createdTsAfter": {
+                    "type": "integer"
                 },
-                "identityProviderId": {
+                "createdTsBefore": {
                     "type": "integer"
                 },
-                "redirectUri": {
+                "pinned": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
                     "type": "string"
+                },
+                "visibilityList": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Visibility"
+                    }
                 }
             }
         },
@@ -2988,6 +6109,10 @@ const docTemplate = `{
         "v1.SignUp": {
             "type": "object",
             "properties": {
+                "email": {
+                    "description": "Email is required if the instance requires verified email, the user signs in once it's verified.",
+                    "type": "string"
+                },
                 "password": {
                     "type": "string"
                 },
@@ -2996,6 +6121,21 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.SigningKey": {
+            "type": "object",
+            "properties": {
+                "createdTs": {
+                    "type": "integer"
+                },
+                "current": {
+                    "description": "Current is whether the key signs the new access tokens, which is the newest one.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.StorageConfig": {
             "type": "object",
             "properties": {
@@ -3073,7 +6213,13 @@ const docTemplate = `{
                 "local-storage-path",
                 "telegram-bot-token",
                 "memo-display-with-updated-ts",
-                "auto-backup-interval"
+                "auto-backup-interval",
+                "memo-trash-retention-days",
+                "signing-keys",
+                "memo-tags-backfilled",
+                "require-admin-two-factor",
+                "smtp",
+                "require-email-verification"
             ],
             "x-enum-varnames": [
                 "SystemSettingServerIDName",
@@ -3089,7 +6235,13 @@ const docTemplate = `{
                 "SystemSettingLocalStoragePathName",
                 "SystemSettingTelegramBotTokenName",
                 "SystemSettingMemoDisplayWithUpdatedTsName",
-                "SystemSettingAutoBackupIntervalName"
+                "SystemSettingAutoBackupIntervalName",
+                "SystemSettingMemoTrashRetentionDaysName",
+                "SystemSettingSigningKeysName",
+                "SystemSettingMemoTagsBackfilledName",
+                "SystemSettingRequireAdminTwoFactorName",
+                "SystemSettingSMTPName",
+                "SystemSettingRequireEmailVerificationName"
             ]
         },
         "v1.SystemStatus": {
@@ -3137,6 +6289,10 @@ const docTemplate = `{
                     "description": "Local storage path.",
                     "type": "string"
                 },
+                "mailEnabled": {
+                    "description": "Mail enabled, which is true if the SMTP server is configured.",
+                    "type": "boolean"
+                },
                 "maxUploadSizeMiB": {
                     "description": "Max upload size.",
                     "type": "integer"
@@ -3148,12 +6304,50 @@ const docTemplate = `{
                 "profile": {
                     "$ref": "#/definitions/profile.Profile"
                 },
+                "requireAdminTwoFactor": {
+                    "description": "Require two-factor authentication for hosts and admins.",
+                    "type": "boolean"
+                },
+                "requireEmailVerification": {
+                    "description": "Require verified email for the users signing up.",
+                    "type": "boolean"
+                },
                 "storageServiceId": {
                     "description": "Storage service ID.",
                     "type": "integer"
                 }
             }
         },
+        "v1.TOTPSignIn": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "twoFactorToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TagCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TwoFactorChallenge": {
+            "type": "object",
+            "properties": {
+                "twoFactorToken": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateIdentityProviderRequest": {
             "type": "object",
             "properties": {
@@ -3166,6 +6360,9 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "provisioning": {
+                    "$ref": "#/definitions/v1.IdentityProviderProvisioning"
+                },
                 "type": {
                     "$ref": "#/definitions/v1.IdentityProviderType"
                 }
@@ -3179,6 +6376,20 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.UpdateShortcutRequest": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "$ref": "#/definitions/v1.ShortcutPayload"
+                },
+                "rowStatus": {
+                    "$ref": "#/definitions/v1.RowStatus"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateStorageRequest": {
             "type": "object",
             "properties": {
@@ -3294,6 +6505,9 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "emailVerified": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -3329,6 +6543,25 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.UserPasskey": {
+            "type": "object",
+            "properties": {
+                "createdTs": {
+                    "description": "Standard fields",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedTs": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Domain specific fields",
+                    "type": "string"
+                }
+            }
+        },
         "v1.UserSetting": {
             "type": "object",
             "properties": {
@@ -3358,6 +6591,59 @@ const docTemplate = `{
                 "UserSettingTelegramUserIDKey"
             ]
         },
+        "v1.UserTOTP": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recoveryCodesLeft": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.UserTOTPCodeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a code of the authenticator app, or a recovery code.",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UserTOTPEnrollment": {
+            "type": "object",
+            "properties": {
+                "provisioningUri": {
+                    "description": "ProvisioningURI is the otpauth URI to be shown as a QR code, which is scanned by authenticator apps.",
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UserTOTPRecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "description": "RecoveryCodes are only returned once, each of them can be used instead of a code once.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.VerifyEmailRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "description": "Token is the token of the link sent to the email of the user.",
+                    "type": "string"
+                }
+            }
+        },
         "v1.Visibility": {
             "type": "string",
             "enum": [
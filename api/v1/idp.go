@@ -15,6 +15,8 @@ type IdentityProviderType string
 
 const (
 	IdentityProviderOAuth2Type IdentityProviderType = "OAUTH2"
+	IdentityProviderOIDCType   IdentityProviderType = "OIDC"
+	IdentityProviderLDAPType   IdentityProviderType = "LDAP"
 )
 
 func (t IdentityProviderType) String() string {
@@ -22,7 +24,9 @@ func (t IdentityProviderType) String() string {
 }
 
 type IdentityProviderConfig struct {
-	OAuth2Config *IdentityProviderOAuth2Config `json:"oauth2Config"`
+	OAuth2Config *IdentityProviderOAuth2Config `json:"oauth2Config,omitempty"`
+	OIDCConfig   *IdentityProviderOIDCConfig   `json:"oidcConfig,omitempty"`
+	LDAPConfig   *IdentityProviderLDAPConfig   `json:"ldapConfig,omitempty"`
 }
 
 type IdentityProviderOAuth2Config struct {
@@ -35,33 +39,76 @@ type IdentityProviderOAuth2Config struct {
 	FieldMapping *FieldMapping `json:"fieldMapping"`
 }
 
+type IdentityProviderOIDCConfig struct {
+	Issuer       string        `json:"issuer"`
+	ClientID     string        `json:"clientId"`
+	ClientSecret string        `json:"clientSecret"`
+	Scopes       []string      `json:"scopes"`
+	FieldMapping *FieldMapping `json:"fieldMapping"`
+}
+
+type IdentityProviderLDAPConfig struct {
+	URL            string        `json:"url"`
+	StartTLS       bool          `json:"startTls"`
+	BindDN         string        `json:"bindDn"`
+	BindPassword   string        `json:"bindPassword"`
+	BaseDN         string        `json:"baseDn"`
+	UserFilter     string        `json:"userFilter"`
+	FieldMapping   *FieldMapping `json:"fieldMapping"`
+	GroupAttribute string        `json:"groupAttribute"`
+	HostGroups     []string      `json:"hostGroups"`
+	AdminGroups    []string      `json:"adminGroups"`
+}
+
 type FieldMapping struct {
 	Identifier  string `json:"identifier"`
 	DisplayName string `json:"displayName"`
 	Email       string `json:"email"`
+	AvatarURL   string `json:"avatarUrl"`
+}
+
+type IdentityProviderProvisioning struct {
+	DisableSignUp bool                           `json:"disableSignUp"`
+	SyncProfile   bool                           `json:"syncProfile"`
+	RoleMappings  []*IdentityProviderRoleMapping `json:"roleMappings"`
+	ArchiveRule   *IdentityProviderClaimRule     `json:"archiveRule"`
+}
+
+type IdentityProviderClaimRule struct {
+	Claim   string `json:"claim"`
+	Pattern string `json:"pattern"`
+}
+
+type IdentityProviderRoleMapping struct {
+	Claim   string `json:"claim"`
+	Pattern string `json:"pattern"`
+	Role    Role   `json:"role"`
 }
 
 type IdentityProvider struct {
-	ID               int32                   `json:"id"`
-	Name             string                  `json:"name"`
-	Type             IdentityProviderType    `json:"type"`
-	IdentifierFilter string                  `json:"identifierFilter"`
-	Config           *IdentityProviderConfig `json:"config"`
+	ID               int32                         `json:"id"`
+	Name             string                        `json:"name"`
+	Type             IdentityProviderType          `json:"type"`
+	IdentifierFilter string                        `json:"identifierFilter"`
+	Config           *IdentityProviderConfig       `json:"config"`
+	Provisioning     *IdentityProviderProvisioning `json:"provisioning"`
 }
 
 type CreateIdentityProviderRequest struct {
-	Name             string                  `json:"name"`
-	Type             IdentityProviderType    `json:"type"`
-	IdentifierFilter string                  `json:"identifierFilter"`
-	Config           *IdentityProviderConfig `json:"config"`
+	Name             string                        `json:"name"`
+	Type             IdentityProviderType          `json:"type"`
+	IdentifierFilter string                        `json:"identifierFilter"`
+	Config           *IdentityProviderConfig       `json:"config"`
+	Provisioning     *IdentityProviderProvisioning `json:"provisioning"`
 }
 
 type UpdateIdentityProviderRequest struct {
-	ID               int32                   `json:"-"`
-	Type             IdentityProviderType    `json:"type"`
-	Name             *string                 `json:"name"`
-	IdentifierFilter *string                 `json:"identifierFilter"`
-	Config           *IdentityProviderConfig `json:"config"`
+	ID               int32                         `json:"-"`
+	Type             IdentityProviderType          `json:"type"`
+	Name             *string                       `json:"name"`
+	IdentifierFilter *string                       `json:"identifierFilter"`
+	Config           *IdentityProviderConfig       `json:"config"`
+	Provisioning     *IdentityProviderProvisioning `json:"provisioning"`
 }
 
 func (s *APIV1Service) registerIdentityProviderRoutes(g *echo.Group) {
@@ -75,7 +122,7 @@ func (s *APIV1Service) registerIdentityProviderRoutes(g *echo.Group) {
 // GetIdentityProviderList godoc
 //
 //	@Summary		Get a list of identity providers
-//	@Description	*clientSecret is only available for host user
+//	@Description	*clientSecret and bindPassword are only available for host user
 //	@Tags			idp
 //	@Produce		json
 //	@Success		200	{object}	[]IdentityProvider	"List of available identity providers"
@@ -107,7 +154,15 @@ func (s *APIV1Service) GetIdentityProviderList(c echo.Context) error {
 		identityProvider := convertIdentityProviderFromStore(item)
 		// data desensitize
 		if !isHostUser {
-			identityProvider.Config.OAuth2Config.ClientSecret = ""
+			if identityProvider.Config.OAuth2Config != nil {
+				identityProvider.Config.OAuth2Config.ClientSecret = ""
+			}
+			if identityProvider.Config.OIDCConfig != nil {
+				identityProvider.Config.OIDCConfig.ClientSecret = ""
+			}
+			if identityProvider.Config.LDAPConfig != nil {
+				identityProvider.Config.LDAPConfig.BindPassword = ""
+			}
 		}
 		identityProviderList = append(identityProviderList, identityProvider)
 	}
@@ -123,7 +178,7 @@ func (s *APIV1Service) GetIdentityProviderList(c echo.Context) error {
 //	@Param		body	body		CreateIdentityProviderRequest	true	"Identity provider information"
 //	@Success	200		{object}	store.IdentityProvider			"Identity provider information"
 //	@Failure	401		{object}	nil								"Missing user in session | Unauthorized"
-//	@Failure	400		{object}	nil								"Malformatted post identity provider request"
+//	@Failure	400		{object}	nil								"Malformatted post identity provider request | Invalid provisioning rules"
 //	@Failure	500		{object}	nil								"Failed to find user | Failed to create identity provider"
 //	@Security	ApiKeyAuth
 //	@Router		/api/v1/idp [POST]
@@ -148,12 +203,16 @@ func (s *APIV1Service) CreateIdentityProvider(c echo.Context) error {
 	if err := json.NewDecoder(c.Request().Body).Decode(identityProviderCreate); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post identity provider request").SetInternal(err)
 	}
+	if err := validateIdentityProviderProvisioning(identityProviderCreate.Provisioning); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid provisioning rules").SetInternal(err)
+	}
 
 	identityProvider, err := s.Store.CreateIdentityProvider(ctx, &store.IdentityProvider{
 		Name:             identityProviderCreate.Name,
 		Type:             store.IdentityProviderType(identityProviderCreate.Type),
 		IdentifierFilter: identityProviderCreate.IdentifierFilter,
 		Config:           convertIdentityProviderConfigToStore(identityProviderCreate.Config),
+		Provisioning:     convertIdentityProviderProvisioningToStore(identityProviderCreate.Provisioning),
 	})
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create identity provider").SetInternal(err)
@@ -259,7 +318,7 @@ func (s *APIV1Service) DeleteIdentityProvider(c echo.Context) error {
 //	@Param		idpId	path		int								true	"Identity Provider ID"
 //	@Param		body	body		UpdateIdentityProviderRequest	true	"Patched identity provider information"
 //	@Success	200		{object}	store.IdentityProvider			"Patched identity provider"
-//	@Failure	400		{object}	nil								"ID is not a number: %s | Malformatted patch identity provider request"
+//	@Failure	400		{object}	nil								"ID is not a number: %s | Malformatted patch identity provider request | Invalid provisioning rules"
 //	@Failure	401		{object}	nil								"Missing user in session | Unauthorized
 //	@Failure	500		{object}	nil								"Failed to find user | Failed to patch identity provider"
 //	@Security	ApiKeyAuth
@@ -292,6 +351,9 @@ func (s *APIV1Service) UpdateIdentityProvider(c echo.Context) error {
 	if err := json.NewDecoder(c.Request().Body).Decode(identityProviderPatch); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted patch identity provider request").SetInternal(err)
 	}
+	if err := validateIdentityProviderProvisioning(identityProviderPatch.Provisioning); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid provisioning rules").SetInternal(err)
+	}
 
 	identityProvider, err := s.Store.UpdateIdentityProvider(ctx, &store.UpdateIdentityProvider{
 		ID:               identityProviderPatch.ID,
@@ -299,6 +361,7 @@ func (s *APIV1Service) UpdateIdentityProvider(c echo.Context) error {
 		Name:             identityProviderPatch.Name,
 		IdentifierFilter: identityProviderPatch.IdentifierFilter,
 		Config:           convertIdentityProviderConfigToStore(identityProviderPatch.Config),
+		Provisioning:     convertIdentityProviderProvisioningToStore(identityProviderPatch.Provisioning),
 	})
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to patch identity provider").SetInternal(err)
@@ -313,41 +376,161 @@ func convertIdentityProviderFromStore(identityProvider *store.IdentityProvider)
 		Type:             IdentityProviderType(identityProvider.Type),
 		IdentifierFilter: identityProvider.IdentifierFilter,
 		Config:           convertIdentityProviderConfigFromStore(identityProvider.Config),
+		Provisioning:     convertIdentityProviderProvisioningFromStore(identityProvider.Provisioning),
 	}
 }
 
 func convertIdentityProviderConfigFromStore(config *store.IdentityProviderConfig) *IdentityProviderConfig {
-	return &IdentityProviderConfig{
-		OAuth2Config: &IdentityProviderOAuth2Config{
-			ClientID:     config.OAuth2Config.ClientID,
-			ClientSecret: config.OAuth2Config.ClientSecret,
-			AuthURL:      config.OAuth2Config.AuthURL,
-			TokenURL:     config.OAuth2Config.TokenURL,
-			UserInfoURL:  config.OAuth2Config.UserInfoURL,
-			Scopes:       config.OAuth2Config.Scopes,
-			FieldMapping: &FieldMapping{
-				Identifier:  config.OAuth2Config.FieldMapping.Identifier,
-				DisplayName: config.OAuth2Config.FieldMapping.DisplayName,
-				Email:       config.OAuth2Config.FieldMapping.Email,
-			},
-		},
+	identityProviderConfig := &IdentityProviderConfig{}
+	if v := config.OAuth2Config; v != nil {
+		identityProviderConfig.OAuth2Config = &IdentityProviderOAuth2Config{
+			ClientID:     v.ClientID,
+			ClientSecret: v.ClientSecret,
+			AuthURL:      v.AuthURL,
+			TokenURL:     v.TokenURL,
+			UserInfoURL:  v.UserInfoURL,
+			Scopes:       v.Scopes,
+			FieldMapping: convertFieldMappingFromStore(v.FieldMapping),
+		}
+	}
+	if v := config.OIDCConfig; v != nil {
+		identityProviderConfig.OIDCConfig = &IdentityProviderOIDCConfig{
+			Issuer:       v.Issuer,
+			ClientID:     v.ClientID,
+			ClientSecret: v.ClientSecret,
+			Scopes:       v.Scopes,
+			FieldMapping: convertFieldMappingFromStore(v.FieldMapping),
+		}
+	}
+	if v := config.LDAPConfig; v != nil {
+		identityProviderConfig.LDAPConfig = &IdentityProviderLDAPConfig{
+			URL:            v.URL,
+			StartTLS:       v.StartTLS,
+			BindDN:         v.BindDN,
+			BindPassword:   v.BindPassword,
+			BaseDN:         v.BaseDN,
+			UserFilter:     v.UserFilter,
+			FieldMapping:   convertFieldMappingFromStore(v.FieldMapping),
+			GroupAttribute: v.GroupAttribute,
+			HostGroups:     v.HostGroups,
+			AdminGroups:    v.AdminGroups,
+		}
+	}
+	return identityProviderConfig
+}
+
+func convertFieldMappingFromStore(fieldMapping *store.FieldMapping) *FieldMapping {
+	if fieldMapping == nil {
+		return nil
+	}
+	return &FieldMapping{
+		Identifier:  fieldMapping.Identifier,
+		DisplayName: fieldMapping.DisplayName,
+		Email:       fieldMapping.Email,
+		AvatarURL:   fieldMapping.AvatarURL,
 	}
 }
 
 func convertIdentityProviderConfigToStore(config *IdentityProviderConfig) *store.IdentityProviderConfig {
-	return &store.IdentityProviderConfig{
-		OAuth2Config: &store.IdentityProviderOAuth2Config{
-			ClientID:     config.OAuth2Config.ClientID,
-			ClientSecret: config.OAuth2Config.ClientSecret,
-			AuthURL:      config.OAuth2Config.AuthURL,
-			TokenURL:     config.OAuth2Config.TokenURL,
-			UserInfoURL:  config.OAuth2Config.UserInfoURL,
-			Scopes:       config.OAuth2Config.Scopes,
-			FieldMapping: &store.FieldMapping{
-				Identifier:  config.OAuth2Config.FieldMapping.Identifier,
-				DisplayName: config.OAuth2Config.FieldMapping.DisplayName,
-				Email:       config.OAuth2Config.FieldMapping.Email,
-			},
-		},
+	if config == nil {
+		return nil
+	}
+	identityProviderConfig := &store.IdentityProviderConfig{}
+	if v := config.OAuth2Config; v != nil {
+		identityProviderConfig.OAuth2Config = &store.IdentityProviderOAuth2Config{
+			ClientID:     v.ClientID,
+			ClientSecret: v.ClientSecret,
+			AuthURL:      v.AuthURL,
+			TokenURL:     v.TokenURL,
+			UserInfoURL:  v.UserInfoURL,
+			Scopes:       v.Scopes,
+			FieldMapping: convertFieldMappingToStore(v.FieldMapping),
+		}
+	}
+	if v := config.OIDCConfig; v != nil {
+		identityProviderConfig.OIDCConfig = &store.IdentityProviderOIDCConfig{
+			Issuer:       v.Issuer,
+			ClientID:     v.ClientID,
+			ClientSecret: v.ClientSecret,
+			Scopes:       v.Scopes,
+			FieldMapping: convertFieldMappingToStore(v.FieldMapping),
+		}
+	}
+	if v := config.LDAPConfig; v != nil {
+		identityProviderConfig.LDAPConfig = &store.IdentityProviderLDAPConfig{
+			URL:            v.URL,
+			StartTLS:       v.StartTLS,
+			BindDN:         v.BindDN,
+			BindPassword:   v.BindPassword,
+			BaseDN:         v.BaseDN,
+			UserFilter:     v.UserFilter,
+			FieldMapping:   convertFieldMappingToStore(v.FieldMapping),
+			GroupAttribute: v.GroupAttribute,
+			HostGroups:     v.HostGroups,
+			AdminGroups:    v.AdminGroups,
+		}
+	}
+	return identityProviderConfig
+}
+
+func convertFieldMappingToStore(fieldMapping *FieldMapping) *store.FieldMapping {
+	if fieldMapping == nil {
+		return nil
+	}
+	return &store.FieldMapping{
+		Identifier:  fieldMapping.Identifier,
+		DisplayName: fieldMapping.DisplayName,
+		Email:       fieldMapping.Email,
+		AvatarURL:   fieldMapping.AvatarURL,
+	}
+}
+
+func convertIdentityProviderProvisioningFromStore(provisioning *store.IdentityProviderProvisioning) *IdentityProviderProvisioning {
+	if provisioning == nil {
+		return nil
+	}
+	identityProviderProvisioning := &IdentityProviderProvisioning{
+		DisableSignUp: provisioning.DisableSignUp,
+		SyncProfile:   provisioning.SyncProfile,
+		RoleMappings:  []*IdentityProviderRoleMapping{},
+	}
+	for _, roleMapping := range provisioning.RoleMappings {
+		identityProviderProvisioning.RoleMappings = append(identityProviderProvisioning.RoleMappings, &IdentityProviderRoleMapping{
+			Claim:   roleMapping.Claim,
+			Pattern: roleMapping.Pattern,
+			Role:    Role(roleMapping.Role),
+		})
+	}
+	if v := provisioning.ArchiveRule; v != nil {
+		identityProviderProvisioning.ArchiveRule = &IdentityProviderClaimRule{
+			Claim:   v.Claim,
+			Pattern: v.Pattern,
+		}
+	}
+	return identityProviderProvisioning
+}
+
+func convertIdentityProviderProvisioningToStore(provisioning *IdentityProviderProvisioning) *store.IdentityProviderProvisioning {
+	if provisioning == nil {
+		return nil
+	}
+	identityProviderProvisioning := &store.IdentityProviderProvisioning{
+		DisableSignUp: provisioning.DisableSignUp,
+		SyncProfile:   provisioning.SyncProfile,
+		RoleMappings:  []*store.IdentityProviderRoleMapping{},
+	}
+	for _, roleMapping := range provisioning.RoleMappings {
+		identityProviderProvisioning.RoleMappings = append(identityProviderProvisioning.RoleMappings, &store.IdentityProviderRoleMapping{
+			Claim:   roleMapping.Claim,
+			Pattern: roleMapping.Pattern,
+			Role:    store.Role(roleMapping.Role),
+		})
+	}
+	if v := provisioning.ArchiveRule; v != nil {
+		identityProviderProvisioning.ArchiveRule = &store.IdentityProviderClaimRule{
+			Claim:   v.Claim,
+			Pattern: v.Pattern,
+		}
 	}
+	return identityProviderProvisioning
 }
@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/common/log"
+	"github.com/usememos/memos/plugin/idp"
+	"github.com/usememos/memos/store"
+	"golang.org/x/exp/slices"
+)
+
+// errIdentityProviderUsernameTaken is returned when the username of a user signing in with an identity provider
+// for the first time is taken by a user which isn't linked to the identity provider.
+var errIdentityProviderUsernameTaken = errors.New("username is taken by a user which isn't linked to the identity provider")
+
+// provisionIdentityProviderUser finds the user linked to the identity provider or creates it,
+// then syncs its profile, role and status from the claims with the provisioning rules of the identity provider.
+// It returns nil if the user is unknown and the sign-up with the identity provider is disabled.
+func (s *APIV1Service) provisionIdentityProviderUser(ctx context.Context, identityProvider *store.IdentityProvider, userInfo *idp.IdentityProviderUserInfo) (*store.User, error) {
+	provisioning := identityProvider.Provisioning
+	if provisioning == nil {
+		provisioning = &store.IdentityProviderProvisioning{}
+	}
+
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		IdentityProviderID:         &identityProvider.ID,
+		IdentityProviderIdentifier: &userInfo.Identifier,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find user")
+	}
+	if user == nil {
+		if provisioning.DisableSignUp {
+			return nil, nil
+		}
+		// The users which aren't linked to the identity provider aren't taken over by its users with the same username.
+		existingUser, err := s.Store.GetUser(ctx, &store.FindUser{
+			Username: &userInfo.Identifier,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find user")
+		}
+		if existingUser != nil {
+			return nil, errIdentityProviderUsernameTaken
+		}
+		user, err = s.createIdentityProviderUser(ctx, identityProvider, userInfo)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create user")
+		}
+	}
+
+	userUpdate := &store.UpdateUser{
+		ID: user.ID,
+	}
+	if provisioning.SyncProfile {
+		if userInfo.DisplayName != "" && userInfo.DisplayName != user.Nickname {
+			userUpdate.Nickname = &userInfo.DisplayName
+		}
+		// The email is only synced if the identity provider asserts that it's verified.
+		if userInfo.Email != "" && userInfo.EmailVerified {
+			if userInfo.Email != user.Email {
+				userUpdate.Email = &userInfo.Email
+			}
+			if userInfo.Email != user.VerifiedEmail {
+				userUpdate.VerifiedEmail = &userInfo.Email
+			}
+		}
+		if userInfo.AvatarURL != "" && userInfo.AvatarURL != user.AvatarURL {
+			userUpdate.AvatarURL = &userInfo.AvatarURL
+		}
+	}
+	if len(provisioning.RoleMappings) > 0 {
+		role := store.RoleUser
+		claims := roleMappingClaims(userInfo)
+		for _, roleMapping := range provisioning.RoleMappings {
+			matched, err := matchClaim(claims, roleMapping.Claim, roleMapping.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				role = roleMapping.Role
+				break
+			}
+		}
+		if role != user.Role {
+			userUpdate.Role = &role
+		}
+	}
+	if v := provisioning.ArchiveRule; v != nil && user.RowStatus != store.Archived {
+		matched, err := matchClaim(userInfo.Claims, v.Claim, v.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			rowStatus := store.Archived
+			userUpdate.RowStatus = &rowStatus
+		}
+	}
+	if userUpdate.Nickname == nil && userUpdate.Email == nil && userUpdate.VerifiedEmail == nil && userUpdate.AvatarURL == nil && userUpdate.Role == nil && userUpdate.RowStatus == nil {
+		return user, nil
+	}
+
+	// The last host is neither demoted nor archived, so that the instance remains manageable.
+	if (userUpdate.Role != nil || userUpdate.RowStatus != nil) && user.Role == store.RoleHost {
+		isLastHost, err := s.isLastHost(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if isLastHost {
+			log.Warn(fmt.Sprintf("the last host %s isn't demoted or archived by the provisioning rules", user.Username))
+			userUpdate.Role, userUpdate.RowStatus = nil, nil
+		}
+	}
+	updatedTs := time.Now().Unix()
+	userUpdate.UpdatedTs = &updatedTs
+	user, err = s.Store.UpdateUser(ctx, userUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+	if user.RowStatus == store.Archived && userUpdate.RowStatus != nil {
+		if err := RevokeUserCredentials(ctx, s.Store, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// updateIdentityProviderUserRole updates the role of the user to the one mapped by its identity provider.
+// The last host isn't demoted, so that the instance remains manageable.
+func (s *APIV1Service) updateIdentityProviderUserRole(ctx context.Context, user *store.User, role store.Role) (*store.User, error) {
+	if user.Role == store.RoleHost {
+		isLastHost, err := s.isLastHost(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if isLastHost {
+			log.Warn(fmt.Sprintf("the last host %s isn't demoted to %s", user.Username, role))
+			return user, nil
+		}
+	}
+
+	updatedTs := time.Now().Unix()
+	user, err := s.Store.UpdateUser(ctx, &store.UpdateUser{
+		ID:        user.ID,
+		UpdatedTs: &updatedTs,
+		Role:      &role,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update user role")
+	}
+	return user, nil
+}
+
+func (s *APIV1Service) isLastHost(ctx context.Context, user *store.User) (bool, error) {
+	if user.Role != store.RoleHost {
+		return false, nil
+	}
+	hostRole := store.RoleHost
+	hosts, err := s.Store.ListUsers(ctx, &store.FindUser{
+		Role: &hostRole,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to find host users")
+	}
+	for _, host := range hosts {
+		if host.ID != user.ID && host.RowStatus == store.Normal {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// roleMappingClaims returns the claims matched by the role mappings, without the ones carrying the email if the identity
+// provider doesn't assert that it's verified, so that the users don't get a role from an email or domain they don't own.
+func roleMappingClaims(userInfo *idp.IdentityProviderUserInfo) map[string][]string {
+	if userInfo.EmailVerified {
+		return userInfo.Claims
+	}
+	claims := map[string][]string{}
+	for name, values := range userInfo.Claims {
+		if name == "email" || (userInfo.Email != "" && slices.Contains(values, userInfo.Email)) {
+			continue
+		}
+		claims[name] = values
+	}
+	return claims
+}
+
+// matchClaim returns true if any value of the claim matches the pattern.
+func matchClaim(claims map[string][]string, claim, pattern string) (bool, error) {
+	patternRegex, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to compile pattern of claim %s", claim)
+	}
+	for _, value := range claims[claim] {
+		if patternRegex.MatchString(value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func validateIdentityProviderProvisioning(provisioning *IdentityProviderProvisioning) error {
+	if provisioning == nil {
+		return nil
+	}
+	rules := []*IdentityProviderClaimRule{}
+	for _, roleMapping := range provisioning.RoleMappings {
+		if roleMapping == nil {
+			return errors.New("role mapping is empty")
+		}
+		if roleMapping.Role != RoleHost && roleMapping.Role != RoleAdmin && roleMapping.Role != RoleUser {
+			return errors.Errorf("invalid role %s", roleMapping.Role)
+		}
+		rules = append(rules, &IdentityProviderClaimRule{
+			Claim:   roleMapping.Claim,
+			Pattern: roleMapping.Pattern,
+		})
+	}
+	if provisioning.ArchiveRule != nil {
+		rules = append(rules, provisioning.ArchiveRule)
+	}
+	for _, rule := range rules {
+		if rule.Claim == "" {
+			return errors.New("claim is empty")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return errors.Wrapf(err, "invalid pattern of claim %s", rule.Claim)
+		}
+	}
+	return nil
+}
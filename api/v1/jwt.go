@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -19,28 +20,53 @@ type claimsMessage struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateAccessToken generates an access token for web.
-func GenerateAccessToken(username string, userID int32, secret string) (string, error) {
+// GenerateAccessToken generates an access token of the session for web, signed with the current signing key.
+func GenerateAccessToken(ctx context.Context, s *store.Store, username string, userID, sessionID int32) (string, error) {
+	key, err := getCurrentSigningKey(ctx, s)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signing key")
+	}
 	expirationTime := time.Now().Add(auth.AccessTokenDuration)
-	return generateToken(username, userID, auth.AccessTokenAudienceName, expirationTime, []byte(secret))
+	return generateToken(username, userID, sessionID, auth.AccessTokenAudienceName, expirationTime, key.ID, []byte(key.Secret))
 }
 
-// GenerateTokensAndSetCookies generates jwt token and saves it to the http-only cookie.
-func GenerateTokensAndSetCookies(c echo.Context, user *store.User, secret string) error {
-	accessToken, err := GenerateAccessToken(user.Username, user.ID, secret)
+// GenerateTokensAndSetCookies creates a new session of the user, then saves its jwt token and refresh token to the http-only cookies.
+func GenerateTokensAndSetCookies(c echo.Context, s *store.Store, user *store.User) error {
+	refreshToken, err := generateRefreshToken()
 	if err != nil {
-		return errors.Wrap(err, "failed to generate access token")
+		return errors.Wrap(err, "failed to generate refresh token")
 	}
-
-	cookieExp := time.Now().Add(auth.CookieExpDuration)
-	setTokenCookie(c, auth.AccessTokenCookieName, accessToken, cookieExp)
-	return nil
+	session, err := s.CreateSession(c.Request().Context(), &store.Session{
+		UserID:           user.ID,
+		ExpiresTs:        time.Now().Add(auth.RefreshTokenDuration).Unix(),
+		RefreshTokenHash: HashAccessToken(refreshToken),
+		UserAgent:        c.Request().UserAgent(),
+		IPAddress:        c.RealIP(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create session")
+	}
+	return setSessionCookies(c, s, user, session.ID, refreshToken)
 }
 
-// RemoveTokensAndCookies removes the jwt token from the cookies.
+// RemoveTokensAndCookies removes the jwt token and the refresh token from the cookies.
 func RemoveTokensAndCookies(c echo.Context) {
 	cookieExp := time.Now().Add(-1 * time.Hour)
 	setTokenCookie(c, auth.AccessTokenCookieName, "", cookieExp)
+	setTokenCookie(c, auth.RefreshTokenCookieName, "", cookieExp)
+}
+
+// setSessionCookies generates a jwt token of the session and saves it with the refresh token to the http-only cookies.
+func setSessionCookies(c echo.Context, s *store.Store, user *store.User, sessionID int32, refreshToken string) error {
+	accessToken, err := GenerateAccessToken(c.Request().Context(), s, user.Username, user.ID, sessionID)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate access token")
+	}
+
+	cookieExp := time.Now().Add(auth.CookieExpDuration)
+	setTokenCookie(c, auth.AccessTokenCookieName, accessToken, cookieExp)
+	setTokenCookie(c, auth.RefreshTokenCookieName, refreshToken, cookieExp)
+	return nil
 }
 
 // setTokenCookie sets the token to the cookie.
@@ -57,7 +83,7 @@ func setTokenCookie(c echo.Context, name, token string, expiration time.Time) {
 }
 
 // generateToken generates a jwt token.
-func generateToken(username string, userID int32, aud string, expirationTime time.Time, secret []byte) (string, error) {
+func generateToken(username string, userID, sessionID int32, aud string, expirationTime time.Time, keyID string, secret []byte) (string, error) {
 	// Create the JWT claims, which includes the username and expiry time.
 	claims := &claimsMessage{
 		Name: username,
@@ -68,12 +94,13 @@ func generateToken(username string, userID int32, aud string, expirationTime tim
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    auth.Issuer,
 			Subject:   fmt.Sprintf("%d", userID),
+			ID:        fmt.Sprintf("%d", sessionID),
 		},
 	}
 
 	// Declare the token with the HS256 algorithm used for signing, and the claims.
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token.Header["kid"] = auth.KeyID
+	token.Header["kid"] = keyID
 
 	// Create the JWT string.
 	tokenString, err := token.SignedString(secret)
@@ -111,6 +138,14 @@ func findAccessToken(c echo.Context) string {
 	return accessToken
 }
 
+func findRefreshToken(c echo.Context) string {
+	cookie, _ := c.Cookie(auth.RefreshTokenCookieName)
+	if cookie == nil {
+		return ""
+	}
+	return cookie.Value
+}
+
 func audienceContains(audience jwt.ClaimStrings, token string) bool {
 	for _, v := range audience {
 		if v == token {
@@ -121,7 +156,7 @@ func audienceContains(audience jwt.ClaimStrings, token string) bool {
 }
 
 // JWTMiddleware validates the access token.
-func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc, secret string) echo.HandlerFunc {
+func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 		path := c.Request().URL.Path
@@ -132,12 +167,13 @@ func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc, secret string) e
 		}
 
 		// Skip validation for server status endpoints.
-		if util.HasPrefixes(path, "/api/v1/ping", "/api/v1/idp", "/api/v1/status", "/api/v1/user") && path != "/api/v1/user/me" && method == http.MethodGet {
+		if util.HasPrefixes(path, "/api/v1/ping", "/api/v1/idp", "/api/v1/status", "/api/v1/user") && !util.HasPrefixes(path, "/api/v1/user/me") && method == http.MethodGet {
 			return next(c)
 		}
 
 		token := findAccessToken(c)
-		if token == "" {
+		// The access token may have been dropped with its cookie, while the session can still be refreshed.
+		if token == "" && findRefreshToken(c) == "" {
 			// Allow the user to access the public endpoints.
 			if util.HasPrefixes(path, "/o") {
 				return next(c)
@@ -149,31 +185,27 @@ func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc, secret string) e
 			return echo.NewHTTPError(http.StatusUnauthorized, "Missing access token")
 		}
 
-		claims := &claimsMessage{}
-		_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
-			if t.Method.Alg() != jwt.SigningMethodHS256.Name {
-				return nil, errors.Errorf("unexpected access token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
+		var userID int32
+		if strings.HasPrefix(token, auth.PersonalAccessTokenPrefix) {
+			accessToken, err := AuthenticatePersonalAccessToken(ctx, server.Store, token)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Server error to authenticate access token").SetInternal(err)
 			}
-			if kid, ok := t.Header["kid"].(string); ok {
-				if kid == "v1" {
-					return []byte(secret), nil
-				}
+			if accessToken == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired access token")
 			}
-			return nil, errors.Errorf("unexpected access token kid=%v", t.Header["kid"])
-		})
-
-		if err != nil {
-			RemoveTokensAndCookies(c)
-			return echo.NewHTTPError(http.StatusUnauthorized, errors.Wrap(err, "Invalid or expired access token"))
-		}
-		if !audienceContains(claims.Audience, auth.AccessTokenAudienceName) {
-			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Invalid access token, audience mismatch, got %q, expected %q.", claims.Audience, auth.AccessTokenAudienceName))
-		}
-
-		// We either have a valid access token or we will attempt to generate new access token.
-		userID, err := util.ConvertStringToInt32(claims.Subject)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Malformed ID in the token.")
+			if scope := getRequiredScope(method, path); !HasScope(accessToken.Scopes, scope) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Access token lacks the %s scope", scope))
+			}
+			userID = accessToken.UserID
+			c.Set(auth.ScopesContextKey, accessToken.Scopes)
+		} else {
+			session, err := server.authenticateSession(c, token)
+			if err != nil {
+				return err
+			}
+			userID = session.UserID
+			c.Set(auth.SessionIDContextKey, session.ID)
 		}
 
 		// Even if there is no error, we still need to make sure the user still exists.
@@ -186,6 +218,28 @@ func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc, secret string) e
 		if user == nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Failed to find user ID: %d", userID))
 		}
+		// The archived users are refused whatever the credentials, including the access tokens created before.
+		if user.RowStatus == store.Archived {
+			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("User ID %d has been archived", userID))
+		}
+		// Hosts and admins signed in with sessions may be required to enable two-factor authentication before anything else.
+		if _, ok := c.Get(auth.ScopesContextKey).([]string); !ok && !util.HasPrefixes(path, "/api/v1/user/me") {
+			required, err := IsTwoFactorEnrollmentRequired(ctx, server.Store, user)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Server error to check two-factor authentication").SetInternal(err)
+			}
+			if required {
+				return echo.NewHTTPError(http.StatusForbidden, "Two-factor authentication is required, please enable it first")
+			}
+			// The sessions created before the email has to be verified, or before it's changed, don't let in either.
+			pending, err := IsEmailVerificationPending(ctx, server.Store, user)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Server error to check email verification").SetInternal(err)
+			}
+			if pending {
+				return echo.NewHTTPError(http.StatusForbidden, "Email address is not verified, please open the link sent to it")
+			}
+		}
 
 		// Stores userID into context.
 		c.Set(auth.UserIDContextKey, userID)
@@ -193,6 +247,24 @@ func JWTMiddleware(server *APIV1Service, next echo.HandlerFunc, secret string) e
 	}
 }
 
+// getRequiredScope returns the scope of personal access tokens required by the request.
+func getRequiredScope(method, path string) auth.Scope {
+	if util.HasPrefixes(path, "/api/v1/resource", "/o/r") {
+		if method == http.MethodGet {
+			return auth.ScopeMemoRead
+		}
+		return auth.ScopeResourceUpload
+	}
+	if util.HasPrefixes(path, "/api/v1/memo", "/api/v1/tag", "/api/v1/shortcut", "/o/get") {
+		if method == http.MethodGet {
+			return auth.ScopeMemoRead
+		}
+		return auth.ScopeMemoWrite
+	}
+	// The other endpoints, including the current user with its open ID, are only accessible with the admin scope.
+	return auth.ScopeAdmin
+}
+
 func (s *APIV1Service) defaultAuthSkipper(c echo.Context) bool {
 	ctx := c.Request().Context()
 	path := c.Path()
@@ -212,6 +284,13 @@ func (s *APIV1Service) defaultAuthSkipper(c echo.Context) bool {
 			return false
 		}
 		if user != nil {
+			// The open ID doesn't let in the archived users, nor the ones who haven't verified their email.
+			if user.RowStatus == store.Archived {
+				return false
+			}
+			if pending, err := IsEmailVerificationPending(ctx, s.Store, user); err != nil || pending {
+				return false
+			}
 			// Stores userID into context.
 			c.Set(auth.UserIDContextKey, user.ID)
 			return true
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/common/log"
+	"github.com/usememos/memos/plugin/idp/ldap"
+	"github.com/usememos/memos/store"
+	"go.uber.org/zap"
+)
+
+// signInWithLDAP authenticates the user with the LDAP identity providers, in the order of their IDs.
+// The user is provisioned with the rules of the identity provider, and its role is mapped from its groups on each sign-in.
+// It returns nil if no directory accepts the credentials or provisions the user, so that the user signs in with the local password.
+func (s *APIV1Service) signInWithLDAP(ctx context.Context, username, password string) (*store.User, error) {
+	identityProviders, err := s.Store.ListIdentityProviders(ctx, &store.FindIdentityProvider{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find identity provider list")
+	}
+
+	for _, identityProvider := range identityProviders {
+		if identityProvider.Type != store.IdentityProviderLDAPType {
+			continue
+		}
+		ldapIdentityProvider, err := ldap.NewIdentityProvider(identityProvider.Config.LDAPConfig)
+		if err != nil {
+			log.Warn(fmt.Sprintf("failed to create LDAP identity provider %s", identityProvider.Name), zap.Error(err))
+			continue
+		}
+		// An unavailable directory doesn't prevent the local users from signing in.
+		userInfo, err := ldapIdentityProvider.Authenticate(username, password)
+		if err != nil {
+			if !errors.Is(err, ldap.ErrInvalidCredentials) {
+				log.Warn(fmt.Sprintf("failed to authenticate with LDAP identity provider %s", identityProvider.Name), zap.Error(err))
+			}
+			continue
+		}
+		if identityProvider.IdentifierFilter != "" {
+			identifierFilterRegex, err := regexp.Compile(identityProvider.IdentifierFilter)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compile identifier filter")
+			}
+			if !identifierFilterRegex.MatchString(userInfo.Identifier) {
+				continue
+			}
+		}
+
+		user, err := s.provisionIdentityProviderUser(ctx, identityProvider, userInfo)
+		if err != nil {
+			// The local user with the same username signs in with its local password.
+			if errors.Is(err, errIdentityProviderUsernameTaken) {
+				log.Warn(fmt.Sprintf("the user %s of LDAP identity provider %s isn't linked to the local user with the same username", userInfo.Identifier, identityProvider.Name))
+				continue
+			}
+			return nil, err
+		}
+		if user == nil {
+			continue
+		}
+		// The role mappings of the provisioning rules take precedence over the groups of the directory.
+		if len(identityProvider.Provisioning.RoleMappings) == 0 {
+			if role, ok := ldapIdentityProvider.Role(userInfo.Groups); ok && role != user.Role {
+				user, err = s.updateIdentityProviderUserRole(ctx, user, role)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		return user, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,491 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/log"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/plugin/mail"
+	"github.com/usememos/memos/store"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type RequestPasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	// Token is the token of the link sent to the email of the user.
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
+	// Token is the token of the link sent to the email of the user.
+	Token string `json:"token"`
+}
+
+func (s *APIV1Service) registerMailRoutes(g *echo.Group) {
+	g.POST("/user/me/email-verification", s.SendEmailVerification)
+}
+
+// RequestPasswordReset godoc
+//
+//	@Summary		Send a link resetting the password to the users with the email
+//	@Description	The response doesn't tell whether a user has the email.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		RequestPasswordResetRequest	true	"Password reset request object"
+//	@Success		200		{boolean}	true						"Password reset requested"
+//	@Failure		400		{object}	nil							"Malformatted password reset request | Invalid email format | Mail is not configured"
+//	@Failure		401		{object}	nil							"Password login is deactivated"
+//	@Failure		500		{object}	nil							"Failed to find system setting | Failed to find users"
+//	@Router			/api/v1/auth/password-reset [POST]
+func (s *APIV1Service) RequestPasswordReset(c echo.Context) error {
+	ctx := c.Request().Context()
+	disablePasswordLogin, err := s.isPasswordLoginDisabled(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	if disablePasswordLogin {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Password login is deactivated")
+	}
+
+	request := &RequestPasswordResetRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted password reset request").SetInternal(err)
+	}
+	if !util.ValidateEmail(request.Email) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid email format")
+	}
+	mailConfig, err := s.getMailConfig(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	if mailConfig == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Mail is not configured")
+	}
+
+	normalStatus := store.Normal
+	userList, err := s.Store.ListUsers(ctx, &store.FindUser{
+		Email:     &request.Email,
+		RowStatus: &normalStatus,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find users").SetInternal(err)
+	}
+	for _, user := range userList {
+		// Only the last link resets the password.
+		tokenType := store.MailTokenPasswordReset
+		if _, err := s.Store.DeleteMailToken(ctx, &store.DeleteMailToken{
+			UserID: &user.ID,
+			Type:   &tokenType,
+		}); err != nil {
+			log.Warn("Failed to delete password reset tokens", zap.Error(err))
+			continue
+		}
+		// The failures are only logged, so that the response doesn't tell whether a user has the email.
+		if err := s.sendMailToken(c, mailConfig, user, tokenType); err != nil {
+			log.Warn("Failed to send password reset email", zap.Error(err))
+		}
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// ResetPassword godoc
+//
+//	@Summary	Reset the password with the token of a password reset link
+//	@Tags		auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		ResetPasswordRequest	true	"Reset password request object"
+//	@Success	200		{boolean}	true					"Password reset"
+//	@Failure	400		{object}	nil						"Malformatted reset password request | Invalid reset password request | Invalid or expired token, please request another link"
+//	@Failure	401		{object}	nil						"Password login is deactivated"
+//	@Failure	403		{object}	nil						"User has been archived with username %s"
+//	@Failure	500		{object}	nil						"Failed to find system setting | Failed to find token | Failed to find user | Failed to generate password hash | Failed to update user | Failed to revoke user credentials"
+//	@Router		/api/v1/auth/password-reset/confirm [POST]
+func (s *APIV1Service) ResetPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+	disablePasswordLogin, err := s.isPasswordLoginDisabled(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	if disablePasswordLogin {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Password login is deactivated")
+	}
+
+	request := &ResetPasswordRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted reset password request").SetInternal(err)
+	}
+	if err := request.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid reset password request").SetInternal(err)
+	}
+
+	mailToken, err := s.consumeMailToken(ctx, store.MailTokenPasswordReset, request.Token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find token").SetInternal(err)
+	}
+	if mailToken == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired token, please request another link")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &mailToken.UserID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired token, please request another link")
+	}
+	if user.RowStatus == store.Archived {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", user.Username))
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
+	}
+	passwordHashStr := string(passwordHash)
+	currentTs := time.Now().Unix()
+	userUpdate := &store.UpdateUser{
+		ID:           user.ID,
+		UpdatedTs:    &currentTs,
+		PasswordHash: &passwordHashStr,
+	}
+	// Receiving the link proves the user owns the email as well.
+	if mailToken.Email == user.Email {
+		userUpdate.VerifiedEmail = &user.Email
+	}
+	if _, err := s.Store.UpdateUser(ctx, userUpdate); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update user").SetInternal(err)
+	}
+	// The sessions, access tokens and open ID obtained with the forgotten password are revoked.
+	if err := RevokeUserCredentials(ctx, s.Store, user.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke user credentials").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// SendEmailVerification godoc
+//
+//	@Summary	Send a link verifying the email of the current user
+//	@Tags		user
+//	@Produce	json
+//	@Success	200	{boolean}	true	"Email verification sent"
+//	@Failure	400	{object}	nil		"Email is not set | Email is already verified | Mail is not configured"
+//	@Failure	401	{object}	nil		"Missing user in session"
+//	@Failure	500	{object}	nil		"Failed to find user | Failed to find system setting | Failed to send email"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/email-verification [POST]
+func (s *APIV1Service) SendEmailVerification(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	if user.Email == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Email is not set")
+	}
+	if user.VerifiedEmail == user.Email {
+		return echo.NewHTTPError(http.StatusBadRequest, "Email is already verified")
+	}
+
+	mailConfig, err := s.getMailConfig(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	if mailConfig == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Mail is not configured")
+	}
+	if err := s.sendMailToken(c, mailConfig, user, store.MailTokenEmailVerification); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to send email").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// VerifyEmail godoc
+//
+//	@Summary	Verify the email of a user with the token of an email verification link
+//	@Tags		auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		VerifyEmailRequest	true	"Verify email request object"
+//	@Success	200		{boolean}	true				"Email verified"
+//	@Failure	400		{object}	nil					"Malformatted verify email request | Invalid or expired token, please request another link"
+//	@Failure	500		{object}	nil					"Failed to find token | Failed to find user | Failed to update user"
+//	@Router		/api/v1/auth/email-verification [POST]
+func (s *APIV1Service) VerifyEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+	request := &VerifyEmailRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted verify email request").SetInternal(err)
+	}
+
+	mailToken, err := s.consumeMailToken(ctx, store.MailTokenEmailVerification, request.Token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find token").SetInternal(err)
+	}
+	if mailToken == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired token, please request another link")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &mailToken.UserID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	// The link verifies nothing once the user has changed the email.
+	if user == nil || user.Email != mailToken.Email {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired token, please request another link")
+	}
+
+	currentTs := time.Now().Unix()
+	if _, err := s.Store.UpdateUser(ctx, &store.UpdateUser{
+		ID:            user.ID,
+		UpdatedTs:     &currentTs,
+		VerifiedEmail: &mailToken.Email,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update user").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+func (request ResetPasswordRequest) Validate() error {
+	if len(request.Password) < 3 {
+		return fmt.Errorf("password is too short, minimum length is 3")
+	}
+	if len(request.Password) > 512 {
+		return fmt.Errorf("password is too long, maximum length is 512")
+	}
+	return nil
+}
+
+// sendMailToken sends a link with a new token of the type to the email of the user.
+func (s *APIV1Service) sendMailToken(c echo.Context, mailConfig *mail.Config, user *store.User, tokenType store.MailTokenType) error {
+	ctx := c.Request().Context()
+	customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to find system customized profile")
+	}
+	token, err := generateRefreshToken()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate token")
+	}
+
+	message := &mail.Message{
+		To: user.Email,
+	}
+	// The link is never built from the host of the request, which anyone sending the request chooses.
+	link := strings.TrimSuffix(customizedProfile.ExternalURL, "/")
+	if link == "" {
+		return errors.New("external URL is not set")
+	}
+	var duration time.Duration
+	switch tokenType {
+	case store.MailTokenPasswordReset:
+		duration = auth.PasswordResetTokenDuration
+		link += "/auth/reset-password?token=" + url.QueryEscape(token)
+		message.Subject = fmt.Sprintf("Reset your password on %s", customizedProfile.Name)
+		message.Body = fmt.Sprintf("Hi %s,\n\nSomeone requested to reset the password of your account %q on %s. Open the link below within an hour to choose a new password:\n\n%s\n\nIf you didn't request it, you can ignore this email.\n", user.Nickname, user.Username, customizedProfile.Name, link)
+	case store.MailTokenEmailVerification:
+		duration = auth.EmailVerificationTokenDuration
+		link += "/auth/verify-email?token=" + url.QueryEscape(token)
+		message.Subject = fmt.Sprintf("Verify your email on %s", customizedProfile.Name)
+		message.Body = fmt.Sprintf("Hi %s,\n\nOpen the link below within 24 hours to verify the email of your account %q on %s:\n\n%s\n\nIf you didn't sign up, you can ignore this email.\n", user.Nickname, user.Username, customizedProfile.Name, link)
+	default:
+		return errors.Errorf("unsupported mail token type %s", tokenType)
+	}
+
+	if _, err := s.Store.CreateMailToken(ctx, &store.MailToken{
+		UserID:    user.ID,
+		Type:      tokenType,
+		TokenHash: HashAccessToken(token),
+		Email:     user.Email,
+		ExpiresTs: time.Now().Add(duration).Unix(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to create mail token")
+	}
+	if err := mail.Send(mailConfig, message); err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+	return nil
+}
+
+// resendEmailVerification sends another link verifying the email of the user, unless the last one is still valid.
+// The failures of sending the email are only logged.
+func (s *APIV1Service) resendEmailVerification(c echo.Context, user *store.User) error {
+	ctx := c.Request().Context()
+	mailConfig, err := s.getMailConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if mailConfig == nil {
+		return nil
+	}
+	tokenType := store.MailTokenEmailVerification
+	mailTokenList, err := s.Store.ListMailTokens(ctx, &store.FindMailToken{
+		UserID: &user.ID,
+		Type:   &tokenType,
+	})
+	if err != nil {
+		return err
+	}
+	for _, mailToken := range mailTokenList {
+		if mailToken.Email == user.Email && mailToken.ExpiresTs > time.Now().Unix() {
+			return nil
+		}
+	}
+	if err := s.sendMailToken(c, mailConfig, user, tokenType); err != nil {
+		log.Warn("Failed to send email verification", zap.Error(err))
+	}
+	return nil
+}
+
+// consumeMailToken deletes the token of the type and returns it, or nil if it's invalid, expired or already used.
+func (s *APIV1Service) consumeMailToken(ctx context.Context, tokenType store.MailTokenType, token string) (*store.MailToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+	tokenHash := HashAccessToken(token)
+	mailToken, err := s.Store.GetMailToken(ctx, &store.FindMailToken{
+		Type:      &tokenType,
+		TokenHash: &tokenHash,
+	})
+	if err != nil || mailToken == nil {
+		return nil, err
+	}
+	// Only the request deleting the token uses it.
+	deleted, err := s.Store.DeleteMailToken(ctx, &store.DeleteMailToken{
+		ID: &mailToken.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 || mailToken.ExpiresTs < time.Now().Unix() {
+		return nil, nil
+	}
+	return mailToken, nil
+}
+
+// getMailConfig returns the configuration of the SMTP server, or nil if the emails are not sent.
+func (s *APIV1Service) getMailConfig(ctx context.Context) (*mail.Config, error) {
+	systemSetting, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingSMTPName.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if systemSetting == nil {
+		return nil, nil
+	}
+	smtpConfig := &SMTPConfig{}
+	if err := json.Unmarshal([]byte(systemSetting.Value), smtpConfig); err != nil {
+		return nil, err
+	}
+	if smtpConfig.Host == "" {
+		return nil, nil
+	}
+	// The emails aren't sent without the external URL, which the links are built from.
+	customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if customizedProfile.ExternalURL == "" {
+		return nil, nil
+	}
+	return convertMailConfigFromSMTPConfig(smtpConfig), nil
+}
+
+// IsEmailVerificationPending returns whether the user has to verify the email before using the APIs,
+// as the system setting requires the users signing up to verify their email.
+func IsEmailVerificationPending(ctx context.Context, s *store.Store, user *store.User) (bool, error) {
+	if user.Role != store.RoleUser || user.Email == "" || user.VerifiedEmail == user.Email {
+		return false, nil
+	}
+	systemSetting, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingRequireEmailVerificationName.String(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to find system setting")
+	}
+	if systemSetting == nil {
+		return false, nil
+	}
+	requireEmailVerification := false
+	if err := json.Unmarshal([]byte(systemSetting.Value), &requireEmailVerification); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal system setting")
+	}
+	return requireEmailVerification, nil
+}
+
+func (s *APIV1Service) isEmailVerificationRequired(ctx context.Context) (bool, error) {
+	systemSetting, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingRequireEmailVerificationName.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if systemSetting == nil {
+		return false, nil
+	}
+	requireEmailVerification := false
+	if err := json.Unmarshal([]byte(systemSetting.Value), &requireEmailVerification); err != nil {
+		return false, err
+	}
+	return requireEmailVerification, nil
+}
+
+func (s *APIV1Service) isPasswordLoginDisabled(ctx context.Context) (bool, error) {
+	systemSetting, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingDisablePasswordLoginName.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if systemSetting == nil {
+		return false, nil
+	}
+	disablePasswordLogin := false
+	if err := json.Unmarshal([]byte(systemSetting.Value), &disablePasswordLogin); err != nil {
+		return false, err
+	}
+	return disablePasswordLogin, nil
+}
+
+func convertMailConfigFromSMTPConfig(smtpConfig *SMTPConfig) *mail.Config {
+	security := mail.Security(smtpConfig.Security)
+	if security == "" {
+		security = mail.SecurityStartTLS
+	}
+	return &mail.Config{
+		Host:      smtpConfig.Host,
+		Port:      smtpConfig.Port,
+		Username:  smtpConfig.Username,
+		Password:  smtpConfig.Password,
+		Security:  security,
+		FromEmail: smtpConfig.FromEmail,
+		FromName:  smtpConfig.FromName,
+	}
+}
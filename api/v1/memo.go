@@ -53,12 +53,17 @@ type Memo struct {
 	Content    string     `json:"content"`
 	Visibility Visibility `json:"visibility"`
 	Pinned     bool       `json:"pinned"`
+	DeletedTs  int64      `json:"deletedTs,omitempty"`
 
 	// Related fields
 	CreatorName     string          `json:"creatorName"`
 	CreatorUsername string          `json:"creatorUsername"`
 	ResourceList    []*Resource     `json:"resourceList"`
 	RelationList    []*MemoRelation `json:"relationList"`
+
+	// Search fields
+	// Snippet is the excerpt of content matching the search, as escaped HTML with the matches in <mark> tags.
+	Snippet string `json:"snippet,omitempty"`
 }
 
 type CreateMemoRequest struct {
@@ -132,7 +137,7 @@ func (s *APIV1Service) registerMemoRoutes(g *echo.Group) {
 //	@Param		rowStatus		query		store.RowStatus	false	"Row status"
 //	@Param		pinned			query		bool			false	"Pinned"
 //	@Param		tag				query		string			false	"Search for tag. Do not append #"
-//	@Param		content			query		string			false	"Search for content"
+//	@Param		content			query		string			false	"Search for content, the results are ranked by relevance"
 //	@Param		limit			query		int				false	"Limit"
 //	@Param		offset			query		int				false	"Offset"
 //	@Success	200				{object}	[]store.Memo	"Memo list"
@@ -183,16 +188,15 @@ func (s *APIV1Service) GetMemoList(c echo.Context) error {
 		findMemoMessage.Pinned = &pinned
 	}
 
-	contentSearch := []string{}
 	tag := c.QueryParam("tag")
 	if tag != "" {
-		contentSearch = append(contentSearch, "#"+tag)
+		findMemoMessage.TagList = []string{tag}
 	}
 	contentSlice := c.QueryParams()["content"]
 	if len(contentSlice) > 0 {
-		contentSearch = append(contentSearch, contentSlice...)
+		findMemoMessage.ContentSearch = contentSlice
+		findMemoMessage.OrderByRank = true
 	}
-	findMemoMessage.ContentSearch = contentSearch
 
 	if limit, err := strconv.Atoi(c.QueryParam("limit")); err == nil {
 		findMemoMessage.Limit = &limit
@@ -535,45 +539,31 @@ func (s *APIV1Service) GetMemo(c echo.Context) error {
 
 // DeleteMemo godoc
 //
-//	@Summary	Delete memo by ID
-//	@Tags		memo
-//	@Produce	json
-//	@Param		memoId	path		int		true	"Memo ID to delete"
-//	@Success	200		{boolean}	true	"Memo deleted"
-//	@Failure	400		{object}	nil		"ID is not a number: %s"
-//	@Failure	401		{object}	nil		"Missing user in session | Unauthorized"
-//	@Failure	404		{object}	nil		"Memo not found: %d"
-//	@Failure	500		{object}	nil		"Failed to find memo | Failed to delete memo ID: %v"
-//	@Security	ApiKeyAuth
-//	@Router		/api/v1/memo/{memoId} [DELETE]
+//	@Summary		Move memo to trash by ID
+//	@Description	Memos in trash can be restored until they are purged after the retention period
+//	@Tags			memo
+//	@Produce		json
+//	@Param			memoId	path		int		true	"Memo ID to delete"
+//	@Success		200		{boolean}	true	"Memo deleted"
+//	@Failure		400		{object}	nil		"ID is not a number: %s"
+//	@Failure		401		{object}	nil		"Missing user in session | Unauthorized"
+//	@Failure		404		{object}	nil		"Memo not found: %d"
+//	@Failure		500		{object}	nil		"Failed to find memo | Failed to delete memo ID: %v"
+//	@Security		ApiKeyAuth
+//	@Router			/api/v1/memo/{memoId} [DELETE]
 func (s *APIV1Service) DeleteMemo(c echo.Context) error {
 	ctx := c.Request().Context()
-	userID, ok := c.Get(auth.UserIDContextKey).(int32)
-	if !ok {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
-	}
-	memoID, err := util.ConvertStringToInt32(c.Param("memoId"))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("memoId"))).SetInternal(err)
-	}
-
-	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
-		ID: &memoID,
-	})
+	memo, err := s.getCurrentUserMemo(c, false)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
-	}
-	if memo == nil {
-		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Memo not found: %d", memoID))
-	}
-	if memo.CreatorID != userID {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+		return err
 	}
 
-	if err := s.Store.DeleteMemo(ctx, &store.DeleteMemo{
-		ID: memoID,
+	deletedTs := time.Now().Unix()
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
 	}); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete memo ID: %v", memoID)).SetInternal(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete memo ID: %v", memo.ID)).SetInternal(err)
 	}
 	return c.JSON(http.StatusOK, true)
 }
@@ -756,6 +746,8 @@ func (s *APIV1Service) convertMemoFromStore(ctx context.Context, memo *store.Mem
 		Content:    memo.Content,
 		Visibility: Visibility(memo.Visibility.String()),
 		Pinned:     memo.Pinned,
+		DeletedTs:  memo.DeletedTs,
+		Snippet:    memo.Snippet,
 	}
 
 	// Compose creator name.
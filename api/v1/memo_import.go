@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/log"
+	"github.com/usememos/memos/plugin/gomark/parser"
+	"github.com/usememos/memos/plugin/importer"
+	"github.com/usememos/memos/store"
+	"go.uber.org/zap"
+)
+
+// maxImportSizeRatio is how many times the max upload size the files of an export are at most once inflated.
+const maxImportSizeRatio = 4
+
+type ImportMemosResponse struct {
+	MemoCount     int `json:"memoCount"`
+	ResourceCount int `json:"resourceCount"`
+}
+
+func (s *APIV1Service) registerMemoImportRoutes(g *echo.Group) {
+	g.POST("/memo/import", s.ImportMemos)
+}
+
+// ImportMemos godoc
+//
+//	@Summary	Import memos from the export of another tool
+//	@Tags		memo
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		source	formData	string				true	"Tool of the export, one of keep, flomo and obsidian"
+//	@Param		file	formData	file				true	"Zip file of the export"
+//	@Success	200		{object}	ImportMemosResponse	"Imported memo and resource counts"
+//	@Failure	400		{object}	nil					"Upload file not found | File size exceeds allowed limit of %d MiB | Invalid zip file | Failed to parse export"
+//	@Failure	401		{object}	nil					"Missing user in session"
+//	@Failure	500		{object}	nil					"Failed to get uploading file | Failed to open file | Failed to import memos"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/memo/import [POST]
+func (s *APIV1Service) ImportMemos(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	// The export is limited by the max upload size of resources as well.
+	maxUploadSetting := s.Store.GetSystemSettingValueWithDefault(&ctx, SystemSettingMaxUploadSizeMiBName.String(), "32")
+	var settingMaxUploadSizeBytes int
+	if settingMaxUploadSizeMiB, err := strconv.Atoi(maxUploadSetting); err == nil {
+		settingMaxUploadSizeBytes = settingMaxUploadSizeMiB * MebiByte
+	} else {
+		log.Warn("Failed to parse max upload size", zap.Error(err))
+		settingMaxUploadSizeBytes = 0
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get uploading file").SetInternal(err)
+	}
+	if file == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload file not found").SetInternal(err)
+	}
+	if file.Size > int64(settingMaxUploadSizeBytes) {
+		message := fmt.Sprintf("File size exceeds allowed limit of %d MiB", settingMaxUploadSizeBytes/MebiByte)
+		return echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+
+	sourceFile, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open file").SetInternal(err)
+	}
+	defer sourceFile.Close()
+
+	zipReader, err := zip.NewReader(sourceFile, file.Size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid zip file").SetInternal(err)
+	}
+	// Each file is limited by the max upload size once inflated, and all the files by a multiple of it.
+	fsys := importer.LimitFS(zipReader, int64(settingMaxUploadSizeBytes), int64(settingMaxUploadSizeBytes)*maxImportSizeRatio)
+	notes, err := importer.Parse(importer.Source(c.FormValue("source")), fsys)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to parse export: %v", err)).SetInternal(err)
+	}
+
+	result, err := ImportNotes(ctx, s.Store, userID, notes)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import memos").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ImportNotes saves notes parsed by the importer as memos of the creator, with their attachments as resources.
+func ImportNotes(ctx context.Context, s *store.Store, creatorID int32, notes []*importer.Note) (*ImportMemosResponse, error) {
+	result := &ImportMemosResponse{}
+	for _, note := range notes {
+		if len(note.Content) > maxContentLength {
+			return nil, fmt.Errorf("content size of the memo created at %d overflows", note.CreatedTs)
+		}
+
+		memo, err := s.CreateMemo(ctx, &store.Memo{
+			CreatorID:  creatorID,
+			CreatedTs:  note.CreatedTs,
+			Content:    note.Content,
+			Visibility: store.Private,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create memo: %w", err)
+		}
+		update := &store.UpdateMemo{
+			ID: memo.ID,
+		}
+		if note.UpdatedTs != 0 {
+			update.UpdatedTs = &note.UpdatedTs
+		}
+		if note.Archived {
+			rowStatus := store.Archived
+			update.RowStatus = &rowStatus
+		}
+		if update.UpdatedTs != nil || update.RowStatus != nil {
+			if err := s.UpdateMemo(ctx, update); err != nil {
+				return nil, fmt.Errorf("failed to update memo: %w", err)
+			}
+		}
+		if note.Pinned {
+			if _, err := s.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+				MemoID: memo.ID,
+				UserID: creatorID,
+				Pinned: true,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to pin memo: %w", err)
+			}
+		}
+
+		for _, attachment := range note.Attachments {
+			create := &store.Resource{
+				CreatorID: creatorID,
+				Filename:  attachment.Filename,
+				Type:      attachment.Type,
+				Size:      int64(len(attachment.Blob)),
+			}
+			if err := SaveResourceBlob(ctx, s, create, bytes.NewReader(attachment.Blob)); err != nil {
+				return nil, fmt.Errorf("failed to save resource %s: %w", attachment.Filename, err)
+			}
+			resource, err := s.CreateResource(ctx, create)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create resource %s: %w", attachment.Filename, err)
+			}
+			if _, err := s.UpsertMemoResource(ctx, &store.UpsertMemoResource{
+				MemoID:     memo.ID,
+				ResourceID: resource.ID,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to bind resource %s: %w", attachment.Filename, err)
+			}
+			result.ResourceCount++
+		}
+
+		for _, tag := range parser.FindTagList(note.Content) {
+			if _, err := s.UpsertTag(ctx, &store.Tag{
+				Name:      tag,
+				CreatorID: creatorID,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to upsert tag %s: %w", tag, err)
+			}
+		}
+		result.MemoCount++
+	}
+	return result, nil
+}
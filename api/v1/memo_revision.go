@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/store"
+)
+
+type MemoRevision struct {
+	ID int32 `json:"id"`
+
+	// Standard fields
+	CreatedTs int64 `json:"createdTs"`
+
+	// Domain specific fields
+	MemoID     int32      `json:"memoId"`
+	Content    string     `json:"content"`
+	Visibility Visibility `json:"visibility"`
+}
+
+type MemoRevisionDiffLine struct {
+	Operation util.DiffOperation `json:"operation"`
+	Text      string             `json:"text"`
+}
+
+type MemoRevisionDiff struct {
+	From     *MemoRevision           `json:"from"`
+	To       *MemoRevision           `json:"to"`
+	LineList []*MemoRevisionDiffLine `json:"lineList"`
+}
+
+func (s *APIV1Service) registerMemoRevisionRoutes(g *echo.Group) {
+	g.GET("/memo/:memoId/revision", s.GetMemoRevisionList)
+	g.GET("/memo/:memoId/revision/diff", s.GetMemoRevisionDiff)
+	g.POST("/memo/:memoId/revision/:revisionId/restore", s.RestoreMemoRevision)
+}
+
+// GetMemoRevisionList godoc
+//
+//	@Summary	Get the revisions of a memo, the latest first
+//	@Tags		memo-revision
+//	@Produce	json
+//	@Param		memoId	path		int						true	"ID of memo to find revisions"
+//	@Success	200		{object}	[]store.MemoRevision	"Memo revision list"
+//	@Failure	400		{object}	nil						"ID is not a number: %s"
+//	@Failure	401		{object}	nil						"Missing user in session | Unauthorized"
+//	@Failure	404		{object}	nil						"Memo not found: %d"
+//	@Failure	500		{object}	nil						"Failed to find memo | Failed to list memo revisions"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/memo/{memoId}/revision [GET]
+func (s *APIV1Service) GetMemoRevisionList(c echo.Context) error {
+	ctx := c.Request().Context()
+	memo, err := s.getCurrentUserMemo(c, false)
+	if err != nil {
+		return err
+	}
+
+	list, err := s.Store.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list memo revisions").SetInternal(err)
+	}
+	memoRevisionList := []*MemoRevision{}
+	for _, memoRevision := range list {
+		memoRevisionList = append(memoRevisionList, convertMemoRevisionFromStore(memoRevision))
+	}
+	return c.JSON(http.StatusOK, memoRevisionList)
+}
+
+// GetMemoRevisionDiff godoc
+//
+//	@Summary	Get the line based diff between two revisions of a memo
+//	@Tags		memo-revision
+//	@Produce	json
+//	@Param		memoId	path		int					true	"ID of memo"
+//	@Param		from	query		int					true	"ID of the old revision"
+//	@Param		to		query		int					true	"ID of the new revision"
+//	@Success	200		{object}	MemoRevisionDiff	"Memo revision diff"
+//	@Failure	400		{object}	nil					"ID is not a number: %s | Revision ID is not a number: %s"
+//	@Failure	401		{object}	nil					"Missing user in session | Unauthorized"
+//	@Failure	404		{object}	nil					"Memo not found: %d | Memo revision not found: %d"
+//	@Failure	500		{object}	nil					"Failed to find memo | Failed to find memo revision"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/memo/{memoId}/revision/diff [GET]
+func (s *APIV1Service) GetMemoRevisionDiff(c echo.Context) error {
+	memo, err := s.getCurrentUserMemo(c, false)
+	if err != nil {
+		return err
+	}
+	from, err := s.getMemoRevision(c, memo.ID, c.QueryParam("from"))
+	if err != nil {
+		return err
+	}
+	to, err := s.getMemoRevision(c, memo.ID, c.QueryParam("to"))
+	if err != nil {
+		return err
+	}
+
+	memoRevisionDiff := &MemoRevisionDiff{
+		From:     convertMemoRevisionFromStore(from),
+		To:       convertMemoRevisionFromStore(to),
+		LineList: []*MemoRevisionDiffLine{},
+	}
+	for _, line := range util.DiffLines(from.Content, to.Content) {
+		memoRevisionDiff.LineList = append(memoRevisionDiff.LineList, &MemoRevisionDiffLine{
+			Operation: line.Operation,
+			Text:      line.Text,
+		})
+	}
+	return c.JSON(http.StatusOK, memoRevisionDiff)
+}
+
+// RestoreMemoRevision godoc
+//
+//	@Summary		Restore a revision of a memo
+//	@Description	The content and visibility of the revision become current, which is saved as a new revision
+//	@Tags			memo-revision
+//	@Produce		json
+//	@Param			memoId		path		int			true	"ID of memo"
+//	@Param			revisionId	path		int			true	"ID of revision to restore"
+//	@Success		200			{object}	store.Memo	"Restored memo"
+//	@Failure		400			{object}	nil			"ID is not a number: %s | Revision ID is not a number: %s"
+//	@Failure		401			{object}	nil			"Missing user in session | Unauthorized"
+//	@Failure		404			{object}	nil			"Memo not found: %d | Memo revision not found: %d"
+//	@Failure		500			{object}	nil			"Failed to find memo | Failed to find memo revision | Failed to restore memo revision | Failed to compose memo response"
+//	@Security		ApiKeyAuth
+//	@Router			/api/v1/memo/{memoId}/revision/{revisionId}/restore [POST]
+func (s *APIV1Service) RestoreMemoRevision(c echo.Context) error {
+	ctx := c.Request().Context()
+	memo, err := s.getCurrentUserMemo(c, false)
+	if err != nil {
+		return err
+	}
+	memoRevision, err := s.getMemoRevision(c, memo.ID, c.Param("revisionId"))
+	if err != nil {
+		return err
+	}
+
+	currentTs := time.Now().Unix()
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:         memo.ID,
+		UpdatedTs:  &currentTs,
+		Content:    &memoRevision.Content,
+		Visibility: &memoRevision.Visibility,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to restore memo revision").SetInternal(err)
+	}
+
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
+	}
+	memoResponse, err := s.convertMemoFromStore(ctx, memo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose memo response").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, memoResponse)
+}
+
+// getCurrentUserMemo returns the memo in path, which must be created by the current user.
+// Memos in trash are only found with inTrash.
+func (s *APIV1Service) getCurrentUserMemo(c echo.Context, inTrash bool) (*store.Memo, error) {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	memoID, err := util.ConvertStringToInt32(c.Param("memoId"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("memoId"))).SetInternal(err)
+	}
+
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+		ID:      &memoID,
+		InTrash: inTrash,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
+	}
+	if memo == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Memo not found: %d", memoID))
+	}
+	if memo.CreatorID != userID {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+	}
+	return memo, nil
+}
+
+func (s *APIV1Service) getMemoRevision(c echo.Context, memoID int32, revisionIDString string) (*store.MemoRevision, error) {
+	ctx := c.Request().Context()
+	revisionID, err := util.ConvertStringToInt32(revisionIDString)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Revision ID is not a number: %s", revisionIDString)).SetInternal(err)
+	}
+
+	memoRevision, err := s.Store.GetMemoRevision(ctx, &store.FindMemoRevision{
+		ID:     &revisionID,
+		MemoID: &memoID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo revision").SetInternal(err)
+	}
+	if memoRevision == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Memo revision not found: %d", revisionID))
+	}
+	return memoRevision, nil
+}
+
+func convertMemoRevisionFromStore(memoRevision *store.MemoRevision) *MemoRevision {
+	return &MemoRevision{
+		ID:         memoRevision.ID,
+		CreatedTs:  memoRevision.CreatedTs,
+		MemoID:     memoRevision.MemoID,
+		Content:    memoRevision.Content,
+		Visibility: Visibility(memoRevision.Visibility.String()),
+	}
+}
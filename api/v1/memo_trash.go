@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/store"
+)
+
+// DefaultMemoTrashRetentionDays is the days to keep memos in trash if SystemSettingMemoTrashRetentionDaysName is not set.
+const DefaultMemoTrashRetentionDays = 30
+
+func (s *APIV1Service) registerMemoTrashRoutes(g *echo.Group) {
+	g.GET("/memo/trash", s.GetMemoTrashList)
+	g.POST("/memo/:memoId/restore", s.RestoreMemo)
+}
+
+// GetMemoTrashList godoc
+//
+//	@Summary	Get the memos of the current user in trash
+//	@Tags		memo
+//	@Produce	json
+//	@Success	200	{object}	[]store.Memo	"Memo list in trash"
+//	@Failure	401	{object}	nil				"Missing user in session"
+//	@Failure	500	{object}	nil				"Failed to fetch memo list | Failed to compose memo response"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/memo/trash [GET]
+func (s *APIV1Service) GetMemoTrashList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	list, err := s.Store.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &userID,
+		InTrash:   true,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch memo list").SetInternal(err)
+	}
+	memoResponseList := []*Memo{}
+	for _, memo := range list {
+		memoResponse, err := s.convertMemoFromStore(ctx, memo)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose memo response").SetInternal(err)
+		}
+		memoResponseList = append(memoResponseList, memoResponse)
+	}
+	return c.JSON(http.StatusOK, memoResponseList)
+}
+
+// RestoreMemo godoc
+//
+//	@Summary	Restore a memo from trash
+//	@Tags		memo
+//	@Produce	json
+//	@Param		memoId	path		int			true	"ID of memo to restore"
+//	@Success	200		{object}	store.Memo	"Restored memo"
+//	@Failure	400		{object}	nil			"ID is not a number: %s"
+//	@Failure	401		{object}	nil			"Missing user in session | Unauthorized"
+//	@Failure	404		{object}	nil			"Memo not found: %d"
+//	@Failure	500		{object}	nil			"Failed to find memo | Failed to restore memo | Failed to compose memo response"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/memo/{memoId}/restore [POST]
+func (s *APIV1Service) RestoreMemo(c echo.Context) error {
+	ctx := c.Request().Context()
+	memo, err := s.getCurrentUserMemo(c, true)
+	if err != nil {
+		return err
+	}
+
+	deletedTs := int64(0)
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to restore memo").SetInternal(err)
+	}
+
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo").SetInternal(err)
+	}
+	memoResponse, err := s.convertMemoFromStore(ctx, memo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose memo response").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, memoResponse)
+}
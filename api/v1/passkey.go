@@ -0,0 +1,640 @@
+package v1
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/plugin/webauthn"
+	"github.com/usememos/memos/store"
+)
+
+type UserPasskey struct {
+	ID int32 `json:"id"`
+
+	// Standard fields
+	CreatedTs int64 `json:"createdTs"`
+
+	// Domain specific fields
+	Name       string `json:"name"`
+	LastUsedTs int64  `json:"lastUsedTs"`
+}
+
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PasskeyUser struct {
+	// ID is the base64url encoded user handle.
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type PasskeyCredentialParameter struct {
+	Type string `json:"type"`
+	// Alg is the COSE algorithm of the credential public key.
+	Alg int `json:"alg"`
+}
+
+type PasskeyCredentialDescriptor struct {
+	Type string `json:"type"`
+	// ID is the base64url encoded credential ID.
+	ID string `json:"id"`
+}
+
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// PasskeyCreationOptions are the options of navigator.credentials.create(), whose binary fields are base64url encoded.
+type PasskeyCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     *PasskeyRelyingParty           `json:"rp"`
+	User                   *PasskeyUser                   `json:"user"`
+	PubKeyCredParams       []*PasskeyCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []*PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection *PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+// PasskeyRequestOptions are the options of navigator.credentials.get(), whose binary fields are base64url encoded.
+// The allowed credentials are empty, as the passkeys are discoverable.
+type PasskeyRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	RPID             string                         `json:"rpId"`
+	Timeout          int64                          `json:"timeout"`
+	AllowCredentials []*PasskeyCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+type PasskeyRegistrationChallenge struct {
+	PublicKey *PasskeyCreationOptions `json:"publicKey"`
+}
+
+type PasskeySignInChallenge struct {
+	PublicKey *PasskeyRequestOptions `json:"publicKey"`
+}
+
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+}
+
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle"`
+}
+
+type PasskeyAttestationCredential struct {
+	// ID is the base64url encoded credential ID.
+	ID       string                      `json:"id"`
+	Response *PasskeyAttestationResponse `json:"response"`
+}
+
+type PasskeyAssertionCredential struct {
+	// ID is the base64url encoded credential ID.
+	ID       string                    `json:"id"`
+	Response *PasskeyAssertionResponse `json:"response"`
+}
+
+type CreateUserPasskeyRequest struct {
+	Name       string                        `json:"name"`
+	Credential *PasskeyAttestationCredential `json:"credential"`
+}
+
+type PasskeySignIn struct {
+	Credential *PasskeyAssertionCredential `json:"credential"`
+}
+
+// passkeyChallengeClaims is the challenge of a passkey registration or sign-in, which is kept in a cookie
+// until the authenticator answers it.
+type passkeyChallengeClaims struct {
+	Challenge string `json:"challenge"`
+	jwt.RegisteredClaims
+}
+
+func (s *APIV1Service) registerUserPasskeyRoutes(g *echo.Group) {
+	g.GET("/user/me/passkey", s.GetUserPasskeyList)
+	g.POST("/user/me/passkey/challenge", s.CreateUserPasskeyChallenge)
+	g.POST("/user/me/passkey", s.CreateUserPasskey)
+	g.DELETE("/user/me/passkey/:passkeyId", s.DeleteUserPasskey)
+}
+
+// GetUserPasskeyList godoc
+//
+//	@Summary	Get a list of passkeys of the current user
+//	@Tags		user
+//	@Produce	json
+//	@Success	200	{object}	[]UserPasskey	"Passkey list"
+//	@Failure	401	{object}	nil				"Missing user in session"
+//	@Failure	500	{object}	nil				"Failed to fetch passkey list"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/passkey [GET]
+func (s *APIV1Service) GetUserPasskeyList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	list, err := s.Store.ListUserCredentials(ctx, &store.FindUserCredential{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch passkey list").SetInternal(err)
+	}
+	userPasskeyMessageList := []*UserPasskey{}
+	for _, userCredential := range list {
+		userPasskeyMessageList = append(userPasskeyMessageList, convertUserPasskeyFromStore(userCredential))
+	}
+	return c.JSON(http.StatusOK, userPasskeyMessageList)
+}
+
+// CreateUserPasskeyChallenge godoc
+//
+//	@Summary		Start the registration of a passkey for the current user
+//	@Description	The challenge is kept in a cookie, which is checked when creating the passkey with the response of the authenticator.
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	PasskeyRegistrationChallenge	"Options of navigator.credentials.create()"
+//	@Failure		401	{object}	nil								"Missing user in session"
+//	@Failure		500	{object}	nil								"Failed to find user | Failed to fetch passkey list | Failed to generate passkey challenge"
+//	@Security		ApiKeyAuth
+//	@Router			/api/v1/user/me/passkey/challenge [POST]
+func (s *APIV1Service) CreateUserPasskeyChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	list, err := s.Store.ListUserCredentials(ctx, &store.FindUserCredential{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch passkey list").SetInternal(err)
+	}
+	customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate passkey challenge").SetInternal(err)
+	}
+	rp, err := s.getPasskeyRelyingParty(c, customizedProfile)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate passkey challenge").SetInternal(err)
+	}
+	challenge, err := s.setPasskeyChallenge(c, auth.PasskeyRegistrationAudienceName, fmt.Sprintf("%d", user.ID))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate passkey challenge").SetInternal(err)
+	}
+
+	options := &PasskeyCreationOptions{
+		Challenge: challenge,
+		RP: &PasskeyRelyingParty{
+			ID:   rp.ID,
+			Name: customizedProfile.Name,
+		},
+		User: &PasskeyUser{
+			ID:          getPasskeyUserHandle(user.ID),
+			Name:        user.Username,
+			DisplayName: user.Nickname,
+		},
+		PubKeyCredParams: []*PasskeyCredentialParameter{},
+		Timeout:          auth.PasskeyChallengeDuration.Milliseconds(),
+		// The authenticators already having a passkey of the user don't create another one.
+		ExcludeCredentials: []*PasskeyCredentialDescriptor{},
+		// The passkeys are discoverable and verify the user, so that they are enough to sign in.
+		AuthenticatorSelection: &PasskeyAuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}
+	for _, algorithm := range webauthn.SupportedAlgorithms {
+		options.PubKeyCredParams = append(options.PubKeyCredParams, &PasskeyCredentialParameter{
+			Type: "public-key",
+			Alg:  algorithm,
+		})
+	}
+	for _, userCredential := range list {
+		options.ExcludeCredentials = append(options.ExcludeCredentials, &PasskeyCredentialDescriptor{
+			Type: "public-key",
+			ID:   userCredential.CredentialID,
+		})
+	}
+	return c.JSON(http.StatusOK, &PasskeyRegistrationChallenge{
+		PublicKey: options,
+	})
+}
+
+// CreateUserPasskey godoc
+//
+//	@Summary	Create a passkey for the current user with the response of the authenticator to the registration challenge
+//	@Tags		user
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		CreateUserPasskeyRequest	true	"Response of navigator.credentials.create()"
+//	@Success	200		{object}	UserPasskey					"Created passkey"
+//	@Failure	400		{object}	nil							"Malformatted post passkey request | Invalid or expired passkey challenge, please try again | Invalid passkey"
+//	@Failure	401		{object}	nil							"Missing user in session"
+//	@Failure	409		{object}	nil							"Passkey already exists"
+//	@Failure	500		{object}	nil							"Failed to find system setting | Failed to find passkey | Failed to create passkey"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/passkey [POST]
+func (s *APIV1Service) CreateUserPasskey(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	request := &CreateUserPasskeyRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post passkey request").SetInternal(err)
+	}
+	if request.Credential == nil || request.Credential.Response == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post passkey request")
+	}
+	if len(request.Name) > 64 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post passkey request")
+	}
+
+	claims, err := s.consumePasskeyChallenge(c, auth.PasskeyRegistrationAudienceName)
+	if err != nil || claims.Subject != fmt.Sprintf("%d", userID) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired passkey challenge, please try again").SetInternal(err)
+	}
+	customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	rp, err := s.getPasskeyRelyingParty(c, customizedProfile)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	credential, err := rp.VerifyRegistration(claims.Challenge, request.Credential.ID, &webauthn.AttestationResponse{
+		ClientDataJSON:    request.Credential.Response.ClientDataJSON,
+		AttestationObject: request.Credential.Response.AttestationObject,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid passkey").SetInternal(err)
+	}
+
+	userCredential, err := s.Store.GetUserCredential(ctx, &store.FindUserCredential{
+		CredentialID: &credential.ID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find passkey").SetInternal(err)
+	}
+	if userCredential != nil {
+		return echo.NewHTTPError(http.StatusConflict, "Passkey already exists")
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		name = "Passkey"
+	}
+	userCredential, err = s.Store.CreateUserCredential(ctx, &store.UserCredential{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: credential.ID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create passkey").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertUserPasskeyFromStore(userCredential))
+}
+
+// DeleteUserPasskey godoc
+//
+//	@Summary	Delete a passkey of the current user
+//	@Tags		user
+//	@Produce	json
+//	@Param		passkeyId	path		int		true	"Passkey ID"
+//	@Success	200			{boolean}	true	"Passkey deleted"
+//	@Failure	400			{object}	nil		"ID is not a number: %s"
+//	@Failure	401			{object}	nil		"Missing user in session"
+//	@Failure	403			{object}	nil		"Cannot delete the last passkey while password login is deactivated and no SSO identity provider is configured."
+//	@Failure	404			{object}	nil		"Passkey not found: %d"
+//	@Failure	500			{object}	nil		"Failed to find passkey | Failed to find system setting | Failed to delete passkey"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/passkey/{passkeyId} [DELETE]
+func (s *APIV1Service) DeleteUserPasskey(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	passkeyID, err := util.ConvertStringToInt32(c.Param("passkeyId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("passkeyId"))).SetInternal(err)
+	}
+
+	list, err := s.Store.ListUserCredentials(ctx, &store.FindUserCredential{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find passkey").SetInternal(err)
+	}
+	var userCredential *store.UserCredential
+	for _, item := range list {
+		if item.ID == passkeyID {
+			userCredential = item
+		}
+	}
+	if userCredential == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Passkey not found: %d", passkeyID))
+	}
+	// The user would be locked out without the last passkey on passkey-only instances.
+	if len(list) == 1 {
+		passkeyOnly, err := s.isPasskeyOnly(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+		}
+		if passkeyOnly {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot delete the last passkey while password login is deactivated and no SSO identity provider is configured.")
+		}
+	}
+
+	if err := s.Store.DeleteUserCredential(ctx, &store.DeleteUserCredential{
+		ID:     &userCredential.ID,
+		UserID: &userID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete passkey").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// CreatePasskeySignInChallenge godoc
+//
+//	@Summary		Start a passkey sign-in
+//	@Description	The challenge is kept in a cookie, which is checked by the passkey sign-in.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	PasskeySignInChallenge	"Options of navigator.credentials.get()"
+//	@Failure		500	{object}	nil						"Failed to generate passkey challenge"
+//	@Router			/api/v1/auth/passkey/challenge [POST]
+func (s *APIV1Service) CreatePasskeySignInChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate passkey challenge").SetInternal(err)
+	}
+	rp, err := s.getPasskeyRelyingParty(c, customizedProfile)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate passkey challenge").SetInternal(err)
+	}
+	challenge, err := s.setPasskeyChallenge(c, auth.PasskeySignInAudienceName, "")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate passkey challenge").SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, &PasskeySignInChallenge{
+		PublicKey: &PasskeyRequestOptions{
+			Challenge:        challenge,
+			RPID:             rp.ID,
+			Timeout:          auth.PasskeyChallengeDuration.Milliseconds(),
+			AllowCredentials: []*PasskeyCredentialDescriptor{},
+			UserVerification: "required",
+		},
+	})
+}
+
+// SignInPasskey godoc
+//
+//	@Summary		Sign-in to memos with a passkey.
+//	@Description	The passkeys verify the user, so neither the password nor the two-factor authentication code is asked. The sign-in is available even if password login is deactivated.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		PasskeySignIn	true	"Response of navigator.credentials.get()"
+//	@Success		200		{object}	store.User		"User information"
+//	@Failure		400		{object}	nil				"Malformatted signin request"
+//	@Failure		401		{object}	nil				"Invalid or expired passkey challenge, please sign in again | Invalid passkey"
+//	@Failure		403		{object}	nil				"User has been archived with username %s"
+//	@Failure		500		{object}	nil				"Failed to find system setting | Failed to find passkey | Failed to find user | Failed to update passkey | Failed to generate tokens | Failed to create activity"
+//	@Router			/api/v1/auth/signin/passkey [POST]
+func (s *APIV1Service) SignInPasskey(c echo.Context) error {
+	ctx := c.Request().Context()
+	signin := &PasskeySignIn{}
+	if err := json.NewDecoder(c.Request().Body).Decode(signin); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted signin request").SetInternal(err)
+	}
+	if signin.Credential == nil || signin.Credential.Response == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted signin request")
+	}
+
+	claims, err := s.consumePasskeyChallenge(c, auth.PasskeySignInAudienceName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired passkey challenge, please sign in again").SetInternal(err)
+	}
+	customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	rp, err := s.getPasskeyRelyingParty(c, customizedProfile)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+	}
+	credentialID := strings.TrimRight(signin.Credential.ID, "=")
+	userCredential, err := s.Store.GetUserCredential(ctx, &store.FindUserCredential{
+		CredentialID: &credentialID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find passkey").SetInternal(err)
+	}
+	if userCredential == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid passkey")
+	}
+	// The user handle returned by the discoverable passkeys must be the one of the owner.
+	if v := signin.Credential.Response.UserHandle; v != "" && strings.TrimRight(v, "=") != getPasskeyUserHandle(userCredential.UserID) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid passkey")
+	}
+	signCount, err := rp.VerifyAssertion(claims.Challenge, &webauthn.Credential{
+		ID:        userCredential.CredentialID,
+		PublicKey: userCredential.PublicKey,
+		SignCount: uint32(userCredential.SignCount),
+	}, &webauthn.AssertionResponse{
+		ClientDataJSON:    signin.Credential.Response.ClientDataJSON,
+		AuthenticatorData: signin.Credential.Response.AuthenticatorData,
+		Signature:         signin.Credential.Response.Signature,
+		UserHandle:        signin.Credential.Response.UserHandle,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid passkey").SetInternal(err)
+	}
+
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userCredential.UserID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid passkey")
+	} else if user.RowStatus == store.Archived {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", user.Username))
+	}
+
+	// The update is conditional on the verified sign count, so that concurrent assertions with the same count are told apart.
+	updated, err := s.Store.UpdateUserCredentialSignCount(ctx, &store.UpdateUserCredentialSignCount{
+		ID:              userCredential.ID,
+		StoredSignCount: userCredential.SignCount,
+		SignCount:       int64(signCount),
+		LastUsedTs:      time.Now().Unix(),
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update passkey").SetInternal(err)
+	}
+	if !updated {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid passkey")
+	}
+	if err := GenerateTokensAndSetCookies(c, s.Store, user); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate tokens").SetInternal(err)
+	}
+	if err := s.createAuthSignInActivity(c, user); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+	}
+	userMessage := convertUserFromStore(user)
+	return c.JSON(http.StatusOK, userMessage)
+}
+
+// getPasskeyRelyingParty returns the relying party of the external URL of the instance, or of the request if it isn't set.
+func (*APIV1Service) getPasskeyRelyingParty(c echo.Context, customizedProfile *CustomizedProfile) (*webauthn.RelyingParty, error) {
+	return webauthn.NewRelyingParty(getInstanceURL(c, customizedProfile))
+}
+
+// setPasskeyChallenge generates a new challenge, which is kept in the cookie along with the subject.
+func (s *APIV1Service) setPasskeyChallenge(c echo.Context, audience, subject string) (string, error) {
+	challenge, err := webauthn.GenerateChallenge()
+	if err != nil {
+		return "", err
+	}
+	claims := &passkeyChallengeClaims{
+		Challenge: challenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(auth.PasskeyChallengeDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    auth.Issuer,
+		},
+	}
+	key, err := getCurrentSigningKey(c.Request().Context(), s.Store)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signing key")
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	signedToken, err := token.SignedString([]byte(key.Secret))
+	if err != nil {
+		return "", err
+	}
+	// The challenge is also kept server-side, so that replays are refused even for authenticators without a sign count.
+	ctx, currentTs := c.Request().Context(), time.Now().Unix()
+	if _, err := s.Store.DeletePasskeyChallenge(ctx, &store.DeletePasskeyChallenge{
+		ExpiresTsBefore: &currentTs,
+	}); err != nil {
+		return "", errors.Wrap(err, "failed to delete expired passkey challenges")
+	}
+	if _, err := s.Store.CreatePasskeyChallenge(ctx, &store.PasskeyChallenge{
+		Challenge: challenge,
+		ExpiresTs: claims.ExpiresAt.Unix(),
+	}); err != nil {
+		return "", errors.Wrap(err, "failed to create passkey challenge")
+	}
+	setTokenCookie(c, auth.PasskeyChallengeCookieName, signedToken, claims.ExpiresAt.Time)
+	return challenge, nil
+}
+
+// consumePasskeyChallenge returns the challenge kept in the cookie, which is removed along with its record so that it's answered once.
+func (s *APIV1Service) consumePasskeyChallenge(c echo.Context, audience string) (*passkeyChallengeClaims, error) {
+	cookie, err := c.Cookie(auth.PasskeyChallengeCookieName)
+	if err != nil {
+		return nil, errors.New("missing passkey challenge cookie")
+	}
+	setTokenCookie(c, auth.PasskeyChallengeCookieName, "", time.Now().Add(-1*time.Hour))
+
+	claims := &passkeyChallengeClaims{}
+	if _, err := jwt.ParseWithClaims(cookie.Value, claims, SigningKeyFunc(c.Request().Context(), s.Store)); err != nil {
+		return nil, errors.Wrap(err, "invalid passkey challenge token")
+	}
+	if !audienceContains(claims.Audience, audience) {
+		return nil, errors.Errorf("unexpected passkey challenge token audience %v", claims.Audience)
+	}
+	// Only the request deleting the challenge answers it.
+	deleted, err := s.Store.DeletePasskeyChallenge(c.Request().Context(), &store.DeletePasskeyChallenge{
+		Challenge: &claims.Challenge,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete passkey challenge")
+	}
+	if deleted == 0 {
+		return nil, errors.New("passkey challenge has already been answered")
+	}
+	return claims, nil
+}
+
+// isPasskeyOnly returns true if password login is deactivated and no SSO identity provider is configured.
+func (s *APIV1Service) isPasskeyOnly(ctx context.Context) (bool, error) {
+	systemSetting, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingDisablePasswordLoginName.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if systemSetting == nil {
+		return false, nil
+	}
+	disablePasswordLogin := false
+	if err := json.Unmarshal([]byte(systemSetting.Value), &disablePasswordLogin); err != nil {
+		return false, err
+	}
+	if !disablePasswordLogin {
+		return false, nil
+	}
+	identityProviderList, err := s.Store.ListIdentityProviders(ctx, &store.FindIdentityProvider{})
+	if err != nil {
+		return false, err
+	}
+	return len(identityProviderList) == 0, nil
+}
+
+// getPasskeyUserHandle returns the base64url encoded user handle of the passkeys of the user.
+func getPasskeyUserHandle(userID int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d", userID)))
+}
+
+func convertUserPasskeyFromStore(userCredential *store.UserCredential) *UserPasskey {
+	return &UserPasskey{
+		ID:         userCredential.ID,
+		CreatedTs:  userCredential.CreatedTs,
+		Name:       userCredential.Name,
+		LastUsedTs: userCredential.LastUsedTs,
+	}
+}
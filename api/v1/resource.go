@@ -328,18 +328,7 @@ func (s *APIV1Service) DeleteResource(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Resource not found: %d", resourceID))
 	}
 
-	if resource.InternalPath != "" {
-		if err := os.Remove(resource.InternalPath); err != nil {
-			log.Warn(fmt.Sprintf("failed to delete local file with path %s", resource.InternalPath), zap.Error(err))
-		}
-	}
-
-	ext := filepath.Ext(resource.Filename)
-	thumbnailPath := filepath.Join(s.Profile.Data, thumbnailImagePath, fmt.Sprintf("%d%s", resource.ID, ext))
-	if err := os.Remove(thumbnailPath); err != nil {
-		log.Warn(fmt.Sprintf("failed to delete local thumbnail with path %s", thumbnailPath), zap.Error(err))
-	}
-
+	DeleteResourceFiles(s.Store, resource)
 	if err := s.Store.DeleteResource(ctx, &store.DeleteResource{
 		ID: resourceID,
 	}); err != nil {
@@ -429,7 +418,7 @@ func (s *APIV1Service) streamResource(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("resourceId"))).SetInternal(err)
 	}
 
-	resourceVisibility, err := checkResourceVisibility(ctx, s.Store, resourceID)
+	resourceVisibility, err := CheckResourceVisibility(ctx, s.Store, resourceID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Failed to get resource visibility").SetInternal(err)
 	}
@@ -587,7 +576,8 @@ func getOrGenerateThumbnailImage(srcBlob []byte, dstPath string) ([]byte, error)
 	return dstBlob, nil
 }
 
-func checkResourceVisibility(ctx context.Context, s *store.Store, resourceID int32) (store.Visibility, error) {
+// CheckResourceVisibility returns the most open visibility of the memos with the resource, or private if there is none.
+func CheckResourceVisibility(ctx context.Context, s *store.Store, resourceID int32) (store.Visibility, error) {
 	memoResources, err := s.ListMemoResources(ctx, &store.FindMemoResource{
 		ResourceID: &resourceID,
 	})
@@ -644,6 +634,21 @@ func convertResourceFromStore(resource *store.Resource) *Resource {
 	}
 }
 
+// DeleteResourceFiles deletes the local file and thumbnail of the resource, failures are only logged.
+func DeleteResourceFiles(s *store.Store, resource *store.Resource) {
+	if resource.InternalPath != "" {
+		if err := os.Remove(resource.InternalPath); err != nil {
+			log.Warn(fmt.Sprintf("failed to delete local file with path %s", resource.InternalPath), zap.Error(err))
+		}
+	}
+
+	ext := filepath.Ext(resource.Filename)
+	thumbnailPath := filepath.Join(s.Profile.Data, thumbnailImagePath, fmt.Sprintf("%d%s", resource.ID, ext))
+	if err := os.Remove(thumbnailPath); err != nil {
+		log.Warn(fmt.Sprintf("failed to delete local thumbnail with path %s", thumbnailPath), zap.Error(err))
+	}
+}
+
 // SaveResourceBlob save the blob of resource based on the storage config
 //
 // Depend on the storage config, some fields of *store.ResourceCreate will be changed:
@@ -704,6 +709,9 @@ func SaveResourceBlob(ctx context.Context, s *store.Store, create *store.Resourc
 		defer dst.Close()
 		_, err = io.Copy(dst, r)
 		if err != nil {
+			// The content may be streamed, so the partially written file is removed.
+			dst.Close()
+			os.Remove(filePath)
 			return fmt.Errorf("Failed to copy file: %s", err)
 		}
 
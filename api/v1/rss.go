@@ -173,6 +173,14 @@ func (s *APIV1Service) getSystemCustomizedProfile(ctx context.Context) (*Customi
 	return customizedProfile, nil
 }
 
+// getInstanceURL returns the external URL of the instance, or the URL of the request if it isn't set.
+func getInstanceURL(c echo.Context, customizedProfile *CustomizedProfile) string {
+	if customizedProfile.ExternalURL != "" {
+		return strings.TrimSuffix(customizedProfile.ExternalURL, "/")
+	}
+	return fmt.Sprintf("%s://%s", c.Scheme(), c.Request().Host)
+}
+
 func getRSSItemTitle(content string) string {
 	var title string
 	if isTitleDefined(content) {
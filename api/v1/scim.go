@@ -0,0 +1,705 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	scimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimContentType        = "application/scim+json"
+	// scimMaxResults is the default and maximum number of users of a page.
+	scimMaxResults = 100
+)
+
+// SCIMUser is a user of the SCIM core schema, mapped onto the memos user.
+// The attributes which aren't mapped onto the user, e.g. externalId, are ignored.
+type SCIMUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *SCIMName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []*SCIMEmail `json:"emails,omitempty"`
+	// Active is false for the archived users.
+	Active *bool `json:"active,omitempty"`
+	// Password is only written, it's never returned.
+	Password string    `json:"password,omitempty"`
+	Meta     *SCIMMeta `json:"meta,omitempty"`
+}
+
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type SCIMMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created"`
+	LastModified string `json:"lastModified"`
+	Location     string `json:"location"`
+}
+
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    []*SCIMUser `json:"Resources"`
+}
+
+type SCIMPatchRequest struct {
+	Schemas    []string              `json:"schemas"`
+	Operations []*SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	// Op is one of add, replace and remove, case-insensitive.
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func (e *SCIMError) String() string {
+	return e.Detail
+}
+
+func (s *APIV1Service) registerSCIMRoutes(g *echo.Group) {
+	g.GET("/Users", s.GetSCIMUserList)
+	g.POST("/Users", s.CreateSCIMUser)
+	g.GET("/Users/:id", s.GetSCIMUser)
+	g.PUT("/Users/:id", s.ReplaceSCIMUser)
+	g.PATCH("/Users/:id", s.PatchSCIMUser)
+	g.DELETE("/Users/:id", s.DeleteSCIMUser)
+}
+
+// SCIMMiddleware authenticates the requests with a personal access token of a host with the admin scope,
+// and returns the errors in the SCIM format.
+func SCIMMiddleware(server *APIV1Service, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := server.authenticateSCIM(c)
+		if err == nil {
+			err = next(c)
+		}
+		if err == nil {
+			return nil
+		}
+
+		httpError, ok := err.(*echo.HTTPError)
+		if !ok {
+			httpError = echo.NewHTTPError(http.StatusInternalServerError, "Internal server error").SetInternal(err)
+		}
+		// The error handler responds with the message, which is the SCIM error.
+		if _, ok := httpError.Message.(*SCIMError); !ok {
+			httpError = echo.NewHTTPError(httpError.Code, newSCIMError(httpError.Code, "", fmt.Sprint(httpError.Message))).SetInternal(httpError.Internal)
+		}
+		return httpError
+	}
+}
+
+func (s *APIV1Service) authenticateSCIM(c echo.Context) error {
+	ctx := c.Request().Context()
+	token, err := extractTokenFromHeader(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Malformatted authorization header").SetInternal(err)
+	}
+	if !strings.HasPrefix(token, auth.PersonalAccessTokenPrefix) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing personal access token")
+	}
+	accessToken, err := AuthenticatePersonalAccessToken(ctx, s.Store, token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Server error to authenticate access token").SetInternal(err)
+	}
+	if accessToken == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired access token")
+	}
+	if !HasScope(accessToken.Scopes, auth.ScopeAdmin) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Access token lacks the %s scope", auth.ScopeAdmin))
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &accessToken.UserID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil || user.RowStatus == store.Archived || user.Role != store.RoleHost {
+		return echo.NewHTTPError(http.StatusForbidden, "Only host can provision users")
+	}
+
+	c.Set(auth.UserIDContextKey, user.ID)
+	c.Set(auth.ScopesContextKey, accessToken.Scopes)
+	return nil
+}
+
+// GetSCIMUserList godoc
+//
+//	@Summary	Get a list of users with SCIM
+//	@Tags		scim
+//	@Produce	json
+//	@Param		filter		query		string				false	"Filter, e.g. userName eq \"john\""
+//	@Param		startIndex	query		int					false	"1-based index of the first user"
+//	@Param		count		query		int					false	"Maximum number of users"
+//	@Success	200			{object}	SCIMListResponse	"User list"
+//	@Failure	400			{object}	SCIMError			"Unsupported filter %s | Invalid pagination"
+//	@Failure	401			{object}	SCIMError			"Missing personal access token | Invalid or expired access token"
+//	@Failure	403			{object}	SCIMError			"Access token lacks the admin scope | Only host can provision users"
+//	@Failure	500			{object}	SCIMError			"Failed to find user list"
+//	@Router		/scim/v2/Users [GET]
+func (s *APIV1Service) GetSCIMUserList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userFind := &store.FindUser{}
+	if filter := c.QueryParam("filter"); filter != "" {
+		if err := parseSCIMUserFilter(filter, userFind); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidFilter", fmt.Sprintf("Unsupported filter %s", filter))).SetInternal(err)
+		}
+	}
+	startIndex, count := 1, scimMaxResults
+	for name, value := range map[string]*int{"startIndex": &startIndex, "count": &count} {
+		if v := c.QueryParam(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidValue", "Invalid pagination")).SetInternal(err)
+			}
+			*value = n
+		}
+	}
+	// Out of range values are interpreted as the closest valid ones, as defined by the RFC 7644.
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	} else if count > scimMaxResults {
+		count = scimMaxResults
+	}
+
+	users, err := s.Store.ListUsers(ctx, userFind)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user list").SetInternal(err)
+	}
+	// The users are listed in a stable order, so that the pages don't overlap.
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	listResponse := &SCIMListResponse{
+		Schemas:      []string{scimListResponseSchema},
+		TotalResults: len(users),
+		StartIndex:   startIndex,
+		Resources:    []*SCIMUser{},
+	}
+	for i := startIndex - 1; i < len(users) && len(listResponse.Resources) < count; i++ {
+		listResponse.Resources = append(listResponse.Resources, convertSCIMUserFromStore(c, users[i]))
+	}
+	listResponse.ItemsPerPage = len(listResponse.Resources)
+	return writeSCIMJSON(c, http.StatusOK, listResponse)
+}
+
+// GetSCIMUser godoc
+//
+//	@Summary	Get a user by ID with SCIM
+//	@Tags		scim
+//	@Produce	json
+//	@Param		id	path		string		true	"User ID"
+//	@Success	200	{object}	SCIMUser	"Requested user"
+//	@Failure	401	{object}	SCIMError	"Missing personal access token | Invalid or expired access token"
+//	@Failure	403	{object}	SCIMError	"Access token lacks the admin scope | Only host can provision users"
+//	@Failure	404	{object}	SCIMError	"User not found"
+//	@Failure	500	{object}	SCIMError	"Failed to find user"
+//	@Router		/scim/v2/Users/{id} [GET]
+func (s *APIV1Service) GetSCIMUser(c echo.Context) error {
+	user, err := s.findSCIMUser(c)
+	if err != nil {
+		return err
+	}
+	return writeSCIMJSON(c, http.StatusOK, convertSCIMUserFromStore(c, user))
+}
+
+// CreateSCIMUser godoc
+//
+//	@Summary		Create a user with SCIM
+//	@Description	The user is created with the USER role, and a random password if none is given.
+//	@Tags			scim
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		SCIMUser	true	"User information"
+//	@Success		201		{object}	SCIMUser	"Created user"
+//	@Failure		400		{object}	SCIMError	"Malformatted SCIM user | Invalid SCIM user"
+//	@Failure		401		{object}	SCIMError	"Missing personal access token | Invalid or expired access token"
+//	@Failure		403		{object}	SCIMError	"Access token lacks the admin scope | Only host can provision users"
+//	@Failure		409		{object}	SCIMError	"User already exists with username %s"
+//	@Failure		500		{object}	SCIMError	"Failed to find user | Failed to generate password | Failed to generate password hash | Failed to create user | Failed to update user | Failed to create activity"
+//	@Router			/scim/v2/Users [POST]
+func (s *APIV1Service) CreateSCIMUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	scimUser := &SCIMUser{}
+	if err := json.NewDecoder(c.Request().Body).Decode(scimUser); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidSyntax", "Malformatted SCIM user")).SetInternal(err)
+	}
+	if scimUser.Password == "" {
+		password, err := util.RandomString(20)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password").SetInternal(err)
+		}
+		scimUser.Password = password
+	}
+	userCreate := CreateUserRequest{
+		Username: scimUser.UserName,
+		Role:     RoleUser,
+		Email:    scimUser.primaryEmail(),
+		Nickname: scimUser.nickname(),
+		Password: scimUser.Password,
+	}
+	if err := userCreate.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidValue", "Invalid SCIM user")).SetInternal(err)
+	}
+	if err := s.checkSCIMUsernameAvailable(ctx, userCreate.Username, 0); err != nil {
+		return err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(userCreate.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
+	}
+	user, err := s.Store.CreateUser(ctx, &store.User{
+		Username:     userCreate.Username,
+		Role:         store.Role(userCreate.Role),
+		Email:        userCreate.Email,
+		Nickname:     userCreate.Nickname,
+		PasswordHash: string(passwordHash),
+		OpenID:       util.GenUUID(),
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create user").SetInternal(err)
+	}
+	if scimUser.Active != nil && !*scimUser.Active {
+		rowStatus := store.Archived
+		user, err = s.Store.UpdateUser(ctx, &store.UpdateUser{
+			ID:        user.ID,
+			RowStatus: &rowStatus,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update user").SetInternal(err)
+		}
+	}
+	if err := s.createUserCreateActivity(c, convertUserFromStore(user)); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+	}
+	return writeSCIMJSON(c, http.StatusCreated, convertSCIMUserFromStore(c, user))
+}
+
+// ReplaceSCIMUser godoc
+//
+//	@Summary		Replace a user with SCIM
+//	@Description	The user is archived if it's not active, and its sessions are revoked.
+//	@Tags			scim
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string		true	"User ID"
+//	@Param			body	body		SCIMUser	true	"User information"
+//	@Success		200		{object}	SCIMUser	"Replaced user"
+//	@Failure		400		{object}	SCIMError	"Malformatted SCIM user | Invalid SCIM user"
+//	@Failure		401		{object}	SCIMError	"Missing personal access token | Invalid or expired access token"
+//	@Failure		403		{object}	SCIMError	"Access token lacks the admin scope | Only host can provision users | Host user can't be deactivated or deleted with SCIM"
+//	@Failure		404		{object}	SCIMError	"User not found"
+//	@Failure		409		{object}	SCIMError	"User already exists with username %s"
+//	@Failure		500		{object}	SCIMError	"Failed to find user | Failed to generate password hash | Failed to update user | Failed to revoke user credentials"
+//	@Router			/scim/v2/Users/{id} [PUT]
+func (s *APIV1Service) ReplaceSCIMUser(c echo.Context) error {
+	user, err := s.findSCIMUser(c)
+	if err != nil {
+		return err
+	}
+	scimUser := &SCIMUser{}
+	if err := json.NewDecoder(c.Request().Body).Decode(scimUser); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidSyntax", "Malformatted SCIM user")).SetInternal(err)
+	}
+	// The omitted attributes are cleared by a replacement, and the users are active by default.
+	if scimUser.Active == nil {
+		active := true
+		scimUser.Active = &active
+	}
+
+	user, err = s.updateSCIMUser(c.Request().Context(), user, scimUser)
+	if err != nil {
+		return err
+	}
+	return writeSCIMJSON(c, http.StatusOK, convertSCIMUserFromStore(c, user))
+}
+
+// PatchSCIMUser godoc
+//
+//	@Summary		Patch a user with SCIM
+//	@Description	The operations on the attributes which aren't mapped onto the user are ignored.
+//	@Tags			scim
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"User ID"
+//	@Param			body	body		SCIMPatchRequest	true	"Patch operations"
+//	@Success		200		{object}	SCIMUser			"Patched user"
+//	@Failure		400		{object}	SCIMError			"Malformatted SCIM patch request | Invalid SCIM patch operation | Invalid SCIM user"
+//	@Failure		401		{object}	SCIMError			"Missing personal access token | Invalid or expired access token"
+//	@Failure		403		{object}	SCIMError			"Access token lacks the admin scope | Only host can provision users | Host user can't be deactivated or deleted with SCIM"
+//	@Failure		404		{object}	SCIMError			"User not found"
+//	@Failure		409		{object}	SCIMError			"User already exists with username %s"
+//	@Failure		500		{object}	SCIMError			"Failed to find user | Failed to generate password hash | Failed to update user | Failed to revoke user credentials"
+//	@Router			/scim/v2/Users/{id} [PATCH]
+func (s *APIV1Service) PatchSCIMUser(c echo.Context) error {
+	user, err := s.findSCIMUser(c)
+	if err != nil {
+		return err
+	}
+	patch := &SCIMPatchRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(patch); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidSyntax", "Malformatted SCIM patch request")).SetInternal(err)
+	}
+
+	scimUser := convertSCIMUserFromStore(c, user)
+	for _, operation := range patch.Operations {
+		if err := scimUser.applyPatchOperation(operation); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidValue", "Invalid SCIM patch operation")).SetInternal(err)
+		}
+	}
+
+	user, err = s.updateSCIMUser(c.Request().Context(), user, scimUser)
+	if err != nil {
+		return err
+	}
+	return writeSCIMJSON(c, http.StatusOK, convertSCIMUserFromStore(c, user))
+}
+
+// DeleteSCIMUser godoc
+//
+//	@Summary	Delete a user with SCIM
+//	@Tags		scim
+//	@Param		id	path	string	true	"User ID"
+//	@Success	204	"User deleted"
+//	@Failure	401	{object}	SCIMError	"Missing personal access token | Invalid or expired access token"
+//	@Failure	403	{object}	SCIMError	"Access token lacks the admin scope | Only host can provision users | Host user can't be deactivated or deleted with SCIM"
+//	@Failure	404	{object}	SCIMError	"User not found"
+//	@Failure	500	{object}	SCIMError	"Failed to find user | Failed to delete user"
+//	@Router		/scim/v2/Users/{id} [DELETE]
+func (s *APIV1Service) DeleteSCIMUser(c echo.Context) error {
+	user, err := s.findSCIMUser(c)
+	if err != nil {
+		return err
+	}
+	if user.Role == store.RoleHost {
+		return echo.NewHTTPError(http.StatusForbidden, "Host user can't be deactivated or deleted with SCIM")
+	}
+
+	if err := s.Store.DeleteUser(c.Request().Context(), &store.DeleteUser{
+		ID: user.ID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete user").SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *APIV1Service) findSCIMUser(c echo.Context) (*store.User, error) {
+	userID, err := util.ConvertStringToInt32(c.Param("id"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found").SetInternal(err)
+	}
+	user, err := s.Store.GetUser(c.Request().Context(), &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+	return user, nil
+}
+
+// updateSCIMUser updates the user to the SCIM user, archiving it and revoking its credentials if it's deactivated.
+func (s *APIV1Service) updateSCIMUser(ctx context.Context, user *store.User, scimUser *SCIMUser) (*store.User, error) {
+	nickname, email := scimUser.nickname(), scimUser.primaryEmail()
+	userUpdate := UpdateUserRequest{
+		Username: &scimUser.UserName,
+		Nickname: &nickname,
+		Email:    &email,
+	}
+	if scimUser.Password != "" {
+		userUpdate.Password = &scimUser.Password
+	}
+	if err := userUpdate.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, newSCIMError(http.StatusBadRequest, "invalidValue", "Invalid SCIM user")).SetInternal(err)
+	}
+	if *userUpdate.Username != user.Username {
+		if err := s.checkSCIMUsernameAvailable(ctx, *userUpdate.Username, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	rowStatus := store.Normal
+	if scimUser.Active != nil && !*scimUser.Active {
+		rowStatus = store.Archived
+	}
+	if rowStatus == store.Archived && user.Role == store.RoleHost {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Host user can't be deactivated or deleted with SCIM")
+	}
+
+	updatedTs := time.Now().Unix()
+	update := &store.UpdateUser{
+		ID:        user.ID,
+		UpdatedTs: &updatedTs,
+		RowStatus: &rowStatus,
+		Username:  userUpdate.Username,
+		Nickname:  userUpdate.Nickname,
+		Email:     userUpdate.Email,
+	}
+	if userUpdate.Password != nil {
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(*userUpdate.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate password hash").SetInternal(err)
+		}
+		passwordHashStr := string(passwordHash)
+		update.PasswordHash = &passwordHashStr
+	}
+	user, err := s.Store.UpdateUser(ctx, update)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to update user").SetInternal(err)
+	}
+	// The deactivated users are signed out of all their sessions, and their access tokens are deleted.
+	if rowStatus == store.Archived {
+		if err := RevokeUserCredentials(ctx, s.Store, user.ID); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke user credentials").SetInternal(err)
+		}
+	}
+	return user, nil
+}
+
+func (s *APIV1Service) checkSCIMUsernameAvailable(ctx context.Context, username string, userID int32) error {
+	existingUser, err := s.Store.GetUser(ctx, &store.FindUser{
+		Username: &username,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if existingUser != nil && existingUser.ID != userID {
+		return echo.NewHTTPError(http.StatusConflict, newSCIMError(http.StatusConflict, "uniqueness", fmt.Sprintf("User already exists with username %s", username)))
+	}
+	return nil
+}
+
+// scimUserFilterRegexp matches the filters comparing an attribute to a string, e.g. userName eq "john".
+var scimUserFilterRegexp = regexp.MustCompile(`(?i)^\s*([a-z.]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseSCIMUserFilter parses the filter into the user find, only the equality of userName, displayName and emails is supported.
+func parseSCIMUserFilter(filter string, find *store.FindUser) error {
+	matches := scimUserFilterRegexp.FindStringSubmatch(filter)
+	if matches == nil {
+		return errors.Errorf("invalid filter %q", filter)
+	}
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return errors.Wrap(err, "failed to unquote filter value")
+	}
+	switch strings.ToLower(matches[1]) {
+	case "username":
+		find.Username = &value
+	case "displayname":
+		find.Nickname = &value
+	case "emails", "emails.value":
+		find.Email = &value
+	default:
+		return errors.Errorf("unsupported filter attribute %q", matches[1])
+	}
+	return nil
+}
+
+// scimEmailPathRegexp matches the paths of the email value, e.g. emails[type eq "work"].value.
+var scimEmailPathRegexp = regexp.MustCompile(`^emails(\[.*\])?\.value$`)
+
+// applyPatchOperation applies the patch operation to the user, the paths being case-insensitive.
+func (u *SCIMUser) applyPatchOperation(operation *SCIMPatchOperation) error {
+	if operation == nil {
+		return errors.New("operation is empty")
+	}
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return errors.Errorf("unsupported operation %q", operation.Op)
+	}
+	path := strings.ToLower(operation.Path)
+	path = strings.TrimPrefix(path, strings.ToLower(scimUserSchema)+":")
+
+	// Without path, the value is an object of the attributes to patch.
+	if path == "" {
+		if op == "remove" {
+			return errors.New("path is required to remove attributes")
+		}
+		attributes := map[string]json.RawMessage{}
+		if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+			return errors.Wrap(err, "failed to unmarshal attributes")
+		}
+		for name, value := range attributes {
+			if err := u.applyPatchOperation(&SCIMPatchOperation{
+				Op:    op,
+				Path:  name,
+				Value: value,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if op == "remove" {
+		switch {
+		case path == "displayname":
+			u.DisplayName, u.Name = "", nil
+		case path == "name" || strings.HasPrefix(path, "name."):
+			u.Name = nil
+		case path == "emails" || scimEmailPathRegexp.MatchString(path):
+			u.Emails = nil
+		case path == "username" || path == "active":
+			return errors.Errorf("attribute %q is required", operation.Path)
+		}
+		return nil
+	}
+
+	switch {
+	case path == "username":
+		return json.Unmarshal(operation.Value, &u.UserName)
+	case path == "displayname":
+		return json.Unmarshal(operation.Value, &u.DisplayName)
+	case path == "name.formatted":
+		// The display name takes precedence over the formatted name, so that it's replaced as well.
+		u.DisplayName = ""
+		u.Name = &SCIMName{}
+		return json.Unmarshal(operation.Value, &u.Name.Formatted)
+	case path == "emails":
+		u.Emails = nil
+		return json.Unmarshal(operation.Value, &u.Emails)
+	case scimEmailPathRegexp.MatchString(path):
+		email := &SCIMEmail{Primary: true}
+		u.Emails = []*SCIMEmail{email}
+		return json.Unmarshal(operation.Value, &email.Value)
+	case path == "password":
+		return json.Unmarshal(operation.Value, &u.Password)
+	case path == "active":
+		active, err := unmarshalSCIMBoolean(operation.Value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+	}
+	return nil
+}
+
+// unmarshalSCIMBoolean unmarshals a boolean, which some clients send as a string, e.g. "False".
+func unmarshalSCIMBoolean(data json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal boolean")
+	}
+	return strconv.ParseBool(s)
+}
+
+// primaryEmail returns the primary email of the user, or the first one.
+func (u *SCIMUser) primaryEmail() string {
+	for _, email := range u.Emails {
+		if email != nil && email.Primary {
+			return email.Value
+		}
+	}
+	for _, email := range u.Emails {
+		if email != nil {
+			return email.Value
+		}
+	}
+	return ""
+}
+
+// nickname returns the display name of the user, or its formatted name, or its username.
+func (u *SCIMUser) nickname() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return u.UserName
+}
+
+func newSCIMError(status int, scimType, detail string) *SCIMError {
+	return &SCIMError{
+		Schemas:  []string{scimErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+func writeSCIMJSON(c echo.Context, status int, v any) error {
+	c.Response().Header().Set(echo.HeaderContentType, scimContentType)
+	c.Response().WriteHeader(status)
+	return json.NewEncoder(c.Response()).Encode(v)
+}
+
+func convertSCIMUserFromStore(c echo.Context, user *store.User) *SCIMUser {
+	id := strconv.Itoa(int(user.ID))
+	active := user.RowStatus == store.Normal
+	scimUser := &SCIMUser{
+		Schemas:     []string{scimUserSchema},
+		ID:          id,
+		UserName:    user.Username,
+		DisplayName: user.Nickname,
+		Active:      &active,
+		Meta: &SCIMMeta{
+			ResourceType: "User",
+			Created:      time.Unix(user.CreatedTs, 0).UTC().Format(time.RFC3339),
+			LastModified: time.Unix(user.UpdatedTs, 0).UTC().Format(time.RFC3339),
+			Location:     fmt.Sprintf("%s://%s/scim/v2/Users/%s", c.Scheme(), c.Request().Host, id),
+		},
+	}
+	if user.Email != "" {
+		scimUser.Emails = []*SCIMEmail{
+			{
+				Value:   user.Email,
+				Primary: true,
+			},
+		}
+	}
+	return scimUser
+}
@@ -0,0 +1,352 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/store"
+)
+
+// sessionRefreshGracePeriod is the period in seconds during which the previous refresh token of a session is still
+// accepted along with its access token, so that concurrent requests with the same expired access token don't
+// sign the user out.
+const sessionRefreshGracePeriod = 10
+
+type Session struct {
+	ID int32 `json:"id"`
+
+	// Standard fields
+	UserID    int32 `json:"userId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	ExpiresTs int64  `json:"expiresTs"`
+	UserAgent string `json:"userAgent"`
+	IPAddress string `json:"ipAddress"`
+	// Current is whether the session is the one of the request.
+	Current bool `json:"current"`
+}
+
+func (s *APIV1Service) registerSessionRoutes(g *echo.Group) {
+	g.GET("/session", s.GetSessionList)
+	g.DELETE("/session", s.DeleteSessionList)
+	g.DELETE("/session/:sessionId", s.DeleteSession)
+}
+
+// RefreshSession godoc
+//
+//	@Summary	Renew the access token with the refresh token of the session, which is rotated.
+//	@Tags		auth
+//	@Produce	json
+//	@Success	200	{object}	store.User	"User information"
+//	@Failure	401	{object}	nil			"Invalid or expired refresh token"
+//	@Failure	500	{object}	nil			"Failed to refresh session | Failed to find user"
+//	@Router		/api/v1/auth/refresh [POST]
+func (s *APIV1Service) RefreshSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	session, err := s.refreshSession(c, nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to refresh session").SetInternal(err)
+	}
+	if session == nil {
+		RemoveTokensAndCookies(c)
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired refresh token")
+	}
+
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &session.UserID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	userMessage := convertUserFromStore(user)
+	return c.JSON(http.StatusOK, userMessage)
+}
+
+// GetSessionList godoc
+//
+//	@Summary	Get a list of active sessions of the current user
+//	@Tags		session
+//	@Produce	json
+//	@Success	200	{object}	[]Session	"Session list"
+//	@Failure	401	{object}	nil			"Missing user in session"
+//	@Failure	500	{object}	nil			"Failed to fetch session list"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/session [GET]
+func (s *APIV1Service) GetSessionList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	list, err := s.Store.ListSessions(ctx, &store.FindSession{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch session list").SetInternal(err)
+	}
+	currentSessionID, _ := c.Get(auth.SessionIDContextKey).(int32)
+	currentTs := time.Now().Unix()
+	sessionMessageList := []*Session{}
+	for _, session := range list {
+		if session.ExpiresTs <= currentTs {
+			continue
+		}
+		sessionMessage := convertSessionFromStore(session)
+		sessionMessage.Current = session.ID == currentSessionID
+		sessionMessageList = append(sessionMessageList, sessionMessage)
+	}
+	return c.JSON(http.StatusOK, sessionMessageList)
+}
+
+// DeleteSession godoc
+//
+//	@Summary	Revoke a session of the current user
+//	@Tags		session
+//	@Produce	json
+//	@Param		sessionId	path		int		true	"Session ID"
+//	@Success	200			{boolean}	true	"Session revoked"
+//	@Failure	400			{object}	nil		"ID is not a number: %s"
+//	@Failure	401			{object}	nil		"Missing user in session"
+//	@Failure	404			{object}	nil		"Session not found: %d"
+//	@Failure	500			{object}	nil		"Failed to find session | Failed to delete session"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/session/{sessionId} [DELETE]
+func (s *APIV1Service) DeleteSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	sessionID, err := util.ConvertStringToInt32(c.Param("sessionId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("sessionId"))).SetInternal(err)
+	}
+
+	session, err := s.Store.GetSession(ctx, &store.FindSession{
+		ID:     &sessionID,
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find session").SetInternal(err)
+	}
+	if session == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Session not found: %d", sessionID))
+	}
+
+	if err := s.Store.DeleteSession(ctx, &store.DeleteSession{
+		ID: &session.ID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete session").SetInternal(err)
+	}
+	if currentSessionID, ok := c.Get(auth.SessionIDContextKey).(int32); ok && currentSessionID == session.ID {
+		RemoveTokensAndCookies(c)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// DeleteSessionList godoc
+//
+//	@Summary	Revoke all the sessions of the current user, including the current one
+//	@Tags		session
+//	@Produce	json
+//	@Success	200	{boolean}	true	"Sessions revoked"
+//	@Failure	401	{object}	nil		"Missing user in session"
+//	@Failure	500	{object}	nil		"Failed to delete sessions"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/session [DELETE]
+func (s *APIV1Service) DeleteSessionList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	if err := s.Store.DeleteSession(ctx, &store.DeleteSession{
+		UserID: &userID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete sessions").SetInternal(err)
+	}
+	RemoveTokensAndCookies(c)
+	return c.JSON(http.StatusOK, true)
+}
+
+// authenticateSession returns the session of the access token. An expired access token is renewed with
+// the refresh token in the cookie, which is rotated.
+func (s *APIV1Service) authenticateSession(c echo.Context, token string) (*store.Session, error) {
+	ctx := c.Request().Context()
+	claims := &claimsMessage{}
+	_, err := jwt.ParseWithClaims(token, claims, SigningKeyFunc(ctx, s.Store))
+	if err == nil && !audienceContains(claims.Audience, auth.AccessTokenAudienceName) {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Invalid access token, audience mismatch, got %q, expected %q.", claims.Audience, auth.AccessTokenAudienceName))
+	}
+
+	var session *store.Session
+	if err == nil {
+		sessionID, err := util.ConvertStringToInt32(claims.ID)
+		if err != nil {
+			RemoveTokensAndCookies(c)
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, "Malformed session ID in the token.")
+		}
+		session, err = s.Store.GetSession(ctx, &store.FindSession{
+			ID: &sessionID,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Server error to find session ID: %d", sessionID)).SetInternal(err)
+		}
+		if session != nil && (session.ExpiresTs <= time.Now().Unix() || fmt.Sprintf("%d", session.UserID) != claims.Subject) {
+			session = nil
+		}
+	} else {
+		validationErr := &jwt.ValidationError{}
+		if token != "" && !(errors.As(err, &validationErr) && validationErr.Errors == jwt.ValidationErrorExpired) {
+			RemoveTokensAndCookies(c)
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, errors.Wrap(err, "Invalid or expired access token"))
+		}
+		// The access token is short-lived, renew it with the refresh token of the session.
+		var expiredClaims *claimsMessage
+		if token != "" && audienceContains(claims.Audience, auth.AccessTokenAudienceName) {
+			expiredClaims = claims
+		}
+		session, err = s.refreshSession(c, expiredClaims)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Server error to refresh session").SetInternal(err)
+		}
+	}
+	if session == nil {
+		RemoveTokensAndCookies(c)
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Session has been revoked or expired")
+	}
+	return session, nil
+}
+
+// refreshSession rotates the refresh token in the cookie and renews the access token of its session.
+// It returns nil if the refresh token doesn't exist or has expired.
+// The claims of the expired access token, whose signature is verified, are used to recognize the requests
+// which were sent concurrently with the previous refresh token. The previous refresh token which is reused
+// after the grace period may have been stolen, so the whole session is revoked.
+func (s *APIV1Service) refreshSession(c echo.Context, expiredClaims *claimsMessage) (*store.Session, error) {
+	ctx := c.Request().Context()
+	refreshToken := findRefreshToken(c)
+	if refreshToken == "" {
+		return nil, nil
+	}
+	refreshTokenHash := HashAccessToken(refreshToken)
+	session, err := s.Store.GetSession(ctx, &store.FindSession{
+		RefreshTokenHash: &refreshTokenHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find session")
+	}
+	if session == nil {
+		return s.findRotatedSession(ctx, refreshTokenHash, expiredClaims)
+	}
+	currentTs := time.Now().Unix()
+	if session.ExpiresTs <= currentTs {
+		return nil, nil
+	}
+
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &session.UserID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find user")
+	}
+	if user == nil || user.RowStatus == store.Archived {
+		return nil, nil
+	}
+
+	newRefreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate refresh token")
+	}
+	newRefreshTokenHash := HashAccessToken(newRefreshToken)
+	expiresTs := time.Now().Add(auth.RefreshTokenDuration).Unix()
+	userAgent, ipAddress := c.Request().UserAgent(), c.RealIP()
+	rotated, err := s.Store.RotateSessionRefreshToken(ctx, &store.RotateSessionRefreshToken{
+		ID:                  session.ID,
+		RefreshTokenHash:    refreshTokenHash,
+		NewRefreshTokenHash: newRefreshTokenHash,
+		UpdatedTs:           currentTs,
+		ExpiresTs:           expiresTs,
+		UserAgent:           userAgent,
+		IPAddress:           ipAddress,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update session")
+	}
+	if !rotated {
+		return s.findRotatedSession(ctx, refreshTokenHash, expiredClaims)
+	}
+	session.UpdatedTs = currentTs
+	session.ExpiresTs = expiresTs
+	session.RefreshTokenHash = newRefreshTokenHash
+	session.PreviousRefreshTokenHash = refreshTokenHash
+	session.UserAgent = userAgent
+	session.IPAddress = ipAddress
+
+	if err := setSessionCookies(c, s.Store, user, session.ID, newRefreshToken); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// findRotatedSession returns the session whose previous refresh token is the given one, if it has just been
+// refreshed by a concurrent request with the same expired access token. The session is revoked if the previous
+// refresh token is reused after the grace period.
+func (s *APIV1Service) findRotatedSession(ctx context.Context, refreshTokenHash string, expiredClaims *claimsMessage) (*store.Session, error) {
+	session, err := s.Store.GetSession(ctx, &store.FindSession{
+		PreviousRefreshTokenHash: &refreshTokenHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find session")
+	}
+	if session == nil {
+		return nil, nil
+	}
+	if time.Now().Unix()-session.UpdatedTs > sessionRefreshGracePeriod {
+		if err := s.Store.DeleteSession(ctx, &store.DeleteSession{
+			ID: &session.ID,
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to delete session")
+		}
+		return nil, nil
+	}
+	if expiredClaims == nil || expiredClaims.ID != fmt.Sprintf("%d", session.ID) || expiredClaims.Subject != fmt.Sprintf("%d", session.UserID) {
+		return nil, nil
+	}
+	// The session has just been refreshed by a concurrent request, which sets the new cookies.
+	return session, nil
+}
+
+// generateRefreshToken returns a new random refresh token.
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func convertSessionFromStore(session *store.Session) *Session {
+	return &Session{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		CreatedTs: session.CreatedTs,
+		UpdatedTs: session.UpdatedTs,
+		ExpiresTs: session.ExpiresTs,
+		UserAgent: session.UserAgent,
+		IPAddress: session.IPAddress,
+	}
+}
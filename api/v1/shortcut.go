@@ -0,0 +1,371 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/store"
+)
+
+// ShortcutPayload is the memo filter saved in a shortcut.
+type ShortcutPayload struct {
+	Tags            []string     `json:"tags"`
+	VisibilityList  []Visibility `json:"visibilityList"`
+	CreatedTsAfter  *int64       `json:"createdTsAfter"`
+	CreatedTsBefore *int64       `json:"createdTsBefore"`
+	Text            string       `json:"text"`
+	Pinned          bool         `json:"pinned"`
+}
+
+type Shortcut struct {
+	ID int32 `json:"id"`
+
+	// Standard fields
+	RowStatus RowStatus `json:"rowStatus"`
+	CreatorID int32     `json:"creatorId"`
+	CreatedTs int64     `json:"createdTs"`
+	UpdatedTs int64     `json:"updatedTs"`
+
+	// Domain specific fields
+	Title   string           `json:"title"`
+	Payload *ShortcutPayload `json:"payload"`
+}
+
+type CreateShortcutRequest struct {
+	Title   string           `json:"title"`
+	Payload *ShortcutPayload `json:"payload"`
+}
+
+type UpdateShortcutRequest struct {
+	RowStatus *RowStatus       `json:"rowStatus"`
+	Title     *string          `json:"title"`
+	Payload   *ShortcutPayload `json:"payload"`
+}
+
+func (s *APIV1Service) registerShortcutRoutes(g *echo.Group) {
+	g.GET("/shortcut", s.GetShortcutList)
+	g.POST("/shortcut", s.CreateShortcut)
+	g.GET("/shortcut/:shortcutId", s.GetShortcut)
+	g.PATCH("/shortcut/:shortcutId", s.UpdateShortcut)
+	g.DELETE("/shortcut/:shortcutId", s.DeleteShortcut)
+	g.GET("/shortcut/:shortcutId/memo", s.GetShortcutMemoList)
+}
+
+// GetShortcutList godoc
+//
+//	@Summary	Get a list of shortcuts of the current user
+//	@Tags		shortcut
+//	@Produce	json
+//	@Param		rowStatus	query		store.RowStatus		false	"Row status"
+//	@Success	200			{object}	[]store.Shortcut	"Shortcut list"
+//	@Failure	401			{object}	nil					"Missing user in session"
+//	@Failure	500			{object}	nil					"Failed to fetch shortcut list"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/shortcut [GET]
+func (s *APIV1Service) GetShortcutList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	find := &store.FindShortcut{
+		CreatorID: &userID,
+	}
+	if rowStatus := store.RowStatus(c.QueryParam("rowStatus")); rowStatus != "" {
+		find.RowStatus = &rowStatus
+	}
+	list, err := s.Store.ListShortcuts(ctx, find)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch shortcut list").SetInternal(err)
+	}
+
+	shortcutMessageList := []*Shortcut{}
+	for _, shortcut := range list {
+		shortcutMessageList = append(shortcutMessageList, convertShortcutFromStore(shortcut))
+	}
+	return c.JSON(http.StatusOK, shortcutMessageList)
+}
+
+// CreateShortcut godoc
+//
+//	@Summary	Create a shortcut
+//	@Tags		shortcut
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		CreateShortcutRequest	true	"Request object."
+//	@Success	200		{object}	store.Shortcut			"Created shortcut"
+//	@Failure	400		{object}	nil						"Malformatted post shortcut request | Shortcut title is required | Invalid shortcut payload"
+//	@Failure	401		{object}	nil						"Missing user in session"
+//	@Failure	500		{object}	nil						"Failed to create shortcut"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/shortcut [POST]
+func (s *APIV1Service) CreateShortcut(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	create := &CreateShortcutRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted post shortcut request").SetInternal(err)
+	}
+	if create.Title == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Shortcut title is required")
+	}
+	if err := create.Payload.validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid shortcut payload").SetInternal(err)
+	}
+
+	shortcut, err := s.Store.CreateShortcut(ctx, &store.Shortcut{
+		CreatorID: userID,
+		Title:     create.Title,
+		Payload:   convertShortcutPayloadToStore(create.Payload),
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create shortcut").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertShortcutFromStore(shortcut))
+}
+
+// GetShortcut godoc
+//
+//	@Summary	Get a shortcut
+//	@Tags		shortcut
+//	@Produce	json
+//	@Param		shortcutId	path		int				true	"Shortcut ID"
+//	@Success	200			{object}	store.Shortcut	"Shortcut"
+//	@Failure	400			{object}	nil				"ID is not a number: %s"
+//	@Failure	401			{object}	nil				"Missing user in session"
+//	@Failure	404			{object}	nil				"Shortcut not found: %d"
+//	@Failure	500			{object}	nil				"Failed to find shortcut"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/shortcut/{shortcutId} [GET]
+func (s *APIV1Service) GetShortcut(c echo.Context) error {
+	shortcut, err := s.getCurrentUserShortcut(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, convertShortcutFromStore(shortcut))
+}
+
+// UpdateShortcut godoc
+//
+//	@Summary	Update a shortcut
+//	@Tags		shortcut
+//	@Accept		json
+//	@Produce	json
+//	@Param		shortcutId	path		int						true	"Shortcut ID"
+//	@Param		patch		body		UpdateShortcutRequest	true	"Patch request"
+//	@Success	200			{object}	store.Shortcut			"Updated shortcut"
+//	@Failure	400			{object}	nil						"ID is not a number: %s | Malformatted patch shortcut request | Shortcut title is required | Invalid shortcut payload"
+//	@Failure	401			{object}	nil						"Missing user in session"
+//	@Failure	404			{object}	nil						"Shortcut not found: %d"
+//	@Failure	500			{object}	nil						"Failed to find shortcut | Failed to patch shortcut"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/shortcut/{shortcutId} [PATCH]
+func (s *APIV1Service) UpdateShortcut(c echo.Context) error {
+	ctx := c.Request().Context()
+	shortcut, err := s.getCurrentUserShortcut(c)
+	if err != nil {
+		return err
+	}
+
+	request := &UpdateShortcutRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted patch shortcut request").SetInternal(err)
+	}
+
+	currentTs := time.Now().Unix()
+	update := &store.UpdateShortcut{
+		ID:        shortcut.ID,
+		UpdatedTs: &currentTs,
+	}
+	if request.RowStatus != nil {
+		rowStatus := store.RowStatus(request.RowStatus.String())
+		update.RowStatus = &rowStatus
+	}
+	if request.Title != nil {
+		if *request.Title == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Shortcut title is required")
+		}
+		update.Title = request.Title
+	}
+	if request.Payload != nil {
+		if err := request.Payload.validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid shortcut payload").SetInternal(err)
+		}
+		update.Payload = convertShortcutPayloadToStore(request.Payload)
+	}
+
+	shortcut, err = s.Store.UpdateShortcut(ctx, update)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to patch shortcut").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertShortcutFromStore(shortcut))
+}
+
+// DeleteShortcut godoc
+//
+//	@Summary	Delete a shortcut
+//	@Tags		shortcut
+//	@Produce	json
+//	@Param		shortcutId	path		int		true	"Shortcut ID"
+//	@Success	200			{boolean}	true	"Shortcut deleted"
+//	@Failure	400			{object}	nil		"ID is not a number: %s"
+//	@Failure	401			{object}	nil		"Missing user in session"
+//	@Failure	404			{object}	nil		"Shortcut not found: %d"
+//	@Failure	500			{object}	nil		"Failed to find shortcut | Failed to delete shortcut"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/shortcut/{shortcutId} [DELETE]
+func (s *APIV1Service) DeleteShortcut(c echo.Context) error {
+	ctx := c.Request().Context()
+	shortcut, err := s.getCurrentUserShortcut(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Store.DeleteShortcut(ctx, &store.DeleteShortcut{
+		ID: shortcut.ID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete shortcut").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// GetShortcutMemoList godoc
+//
+//	@Summary	Get the memos matching the filter of a shortcut
+//	@Tags		shortcut
+//	@Produce	json
+//	@Param		shortcutId	path		int				true	"Shortcut ID"
+//	@Param		limit		query		int				false	"Limit"
+//	@Param		offset		query		int				false	"Offset"
+//	@Success	200			{object}	[]store.Memo	"Memo list"
+//	@Failure	400			{object}	nil				"ID is not a number: %s"
+//	@Failure	401			{object}	nil				"Missing user in session"
+//	@Failure	404			{object}	nil				"Shortcut not found: %d"
+//	@Failure	500			{object}	nil				"Failed to find shortcut | Failed to get memo display with updated ts setting value | Failed to fetch memo list | Failed to compose memo response"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/shortcut/{shortcutId}/memo [GET]
+func (s *APIV1Service) GetShortcutMemoList(c echo.Context) error {
+	ctx := c.Request().Context()
+	shortcut, err := s.getCurrentUserShortcut(c)
+	if err != nil {
+		return err
+	}
+
+	findMemoMessage := shortcut.Payload.FindMemo(shortcut.CreatorID)
+	if limit, err := strconv.Atoi(c.QueryParam("limit")); err == nil {
+		findMemoMessage.Limit = &limit
+	}
+	if offset, err := strconv.Atoi(c.QueryParam("offset")); err == nil {
+		findMemoMessage.Offset = &offset
+	}
+	memoDisplayWithUpdatedTs, err := s.getMemoDisplayWithUpdatedTsSettingValue(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get memo display with updated ts setting value").SetInternal(err)
+	}
+	if memoDisplayWithUpdatedTs {
+		findMemoMessage.OrderByUpdatedTs = true
+	}
+
+	list, err := s.Store.ListMemos(ctx, findMemoMessage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch memo list").SetInternal(err)
+	}
+	memoResponseList := []*Memo{}
+	for _, memo := range list {
+		memoResponse, err := s.convertMemoFromStore(ctx, memo)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose memo response").SetInternal(err)
+		}
+		memoResponseList = append(memoResponseList, memoResponse)
+	}
+	return c.JSON(http.StatusOK, memoResponseList)
+}
+
+// getCurrentUserShortcut returns the shortcut in path, which must belong to the current user.
+func (s *APIV1Service) getCurrentUserShortcut(c echo.Context) (*store.Shortcut, error) {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	shortcutID, err := util.ConvertStringToInt32(c.Param("shortcutId"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("shortcutId"))).SetInternal(err)
+	}
+
+	shortcut, err := s.Store.GetShortcut(ctx, &store.FindShortcut{
+		ID: &shortcutID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to find shortcut").SetInternal(err)
+	}
+	// Shortcuts are private, so others' shortcuts are reported as not found.
+	if shortcut == nil || shortcut.CreatorID != userID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Shortcut not found: %d", shortcutID))
+	}
+	return shortcut, nil
+}
+
+func (payload *ShortcutPayload) validate() error {
+	if payload == nil {
+		return nil
+	}
+	for _, visibility := range payload.VisibilityList {
+		if visibility != Public && visibility != Protected && visibility != Private {
+			return fmt.Errorf("invalid visibility %s", visibility)
+		}
+	}
+	return nil
+}
+
+func convertShortcutFromStore(shortcut *store.Shortcut) *Shortcut {
+	payload := &ShortcutPayload{
+		Tags:            []string{},
+		VisibilityList:  []Visibility{},
+		CreatedTsAfter:  shortcut.Payload.CreatedTsAfter,
+		CreatedTsBefore: shortcut.Payload.CreatedTsBefore,
+		Text:            shortcut.Payload.Text,
+		Pinned:          shortcut.Payload.Pinned,
+	}
+	payload.Tags = append(payload.Tags, shortcut.Payload.Tags...)
+	for _, visibility := range shortcut.Payload.VisibilityList {
+		payload.VisibilityList = append(payload.VisibilityList, Visibility(visibility.String()))
+	}
+	return &Shortcut{
+		ID:        shortcut.ID,
+		RowStatus: RowStatus(shortcut.RowStatus.String()),
+		CreatorID: shortcut.CreatorID,
+		CreatedTs: shortcut.CreatedTs,
+		UpdatedTs: shortcut.UpdatedTs,
+		Title:     shortcut.Title,
+		Payload:   payload,
+	}
+}
+
+func convertShortcutPayloadToStore(payload *ShortcutPayload) *store.ShortcutPayload {
+	if payload == nil {
+		return &store.ShortcutPayload{}
+	}
+	storePayload := &store.ShortcutPayload{
+		Tags:            payload.Tags,
+		CreatedTsAfter:  payload.CreatedTsAfter,
+		CreatedTsBefore: payload.CreatedTsBefore,
+		Text:            payload.Text,
+		Pinned:          payload.Pinned,
+	}
+	for _, visibility := range payload.VisibilityList {
+		storePayload.VisibilityList = append(storePayload.VisibilityList, store.Visibility(visibility.String()))
+	}
+	return storePayload
+}
@@ -0,0 +1,269 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/store"
+)
+
+// signingKey is a key of the keyring signing the access tokens, which is stored in the signing keys system setting.
+// The key ID is set to the kid header of the tokens it signs.
+type signingKey struct {
+	ID        string `json:"id"`
+	Secret    string `json:"secret"`
+	CreatedTs int64  `json:"createdTs"`
+}
+
+type SigningKey struct {
+	ID        string `json:"id"`
+	CreatedTs int64  `json:"createdTs"`
+	// Current is whether the key signs the new access tokens, which is the newest one.
+	Current bool `json:"current"`
+}
+
+func (s *APIV1Service) registerSigningKeyRoutes(g *echo.Group) {
+	g.GET("/system/signing-key", s.GetSigningKeyList)
+	g.POST("/system/signing-key", s.RotateSigningKey)
+	g.DELETE("/system/signing-key/:keyId", s.RetireSigningKey)
+}
+
+// GetSigningKeyList godoc
+//
+//	@Summary	Get the keyring signing the access tokens, without the secrets
+//	@Tags		system
+//	@Produce	json
+//	@Success	200	{object}	[]SigningKey	"Signing key list"
+//	@Failure	401	{object}	nil				"Missing user in session | Unauthorized"
+//	@Failure	500	{object}	nil				"Failed to find user | Failed to find signing keys"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/system/signing-key [GET]
+func (s *APIV1Service) GetSigningKeyList(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := s.checkHostUser(c); err != nil {
+		return err
+	}
+
+	keys, err := listSigningKeys(ctx, s.Store)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find signing keys").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertSigningKeysFromStore(keys))
+}
+
+// RotateSigningKey godoc
+//
+//	@Summary	Add a new signing key, which signs the new access tokens from now on
+//	@Tags		system
+//	@Produce	json
+//	@Success	200	{object}	[]SigningKey	"Signing key list"
+//	@Failure	401	{object}	nil				"Missing user in session | Unauthorized"
+//	@Failure	500	{object}	nil				"Failed to find user | Failed to find signing keys | Failed to generate signing key | Failed to update signing keys"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/system/signing-key [POST]
+func (s *APIV1Service) RotateSigningKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := s.checkHostUser(c); err != nil {
+		return err
+	}
+
+	keys, err := listSigningKeys(ctx, s.Store)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find signing keys").SetInternal(err)
+	}
+	secret, err := util.RandomString(64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate signing key").SetInternal(err)
+	}
+	keys = append(keys, &signingKey{
+		ID:        nextSigningKeyID(keys),
+		Secret:    secret,
+		CreatedTs: time.Now().Unix(),
+	})
+	if err := upsertSigningKeys(ctx, s.Store, keys); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update signing keys").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertSigningKeysFromStore(keys))
+}
+
+// RetireSigningKey godoc
+//
+//	@Summary	Retire a signing key, which invalidates all the access tokens signed with it
+//	@Tags		system
+//	@Produce	json
+//	@Param		keyId	path		string			true	"Signing key ID"
+//	@Success	200		{object}	[]SigningKey	"Signing key list"
+//	@Failure	400		{object}	nil				"The only signing key can't be retired"
+//	@Failure	401		{object}	nil				"Missing user in session | Unauthorized"
+//	@Failure	404		{object}	nil				"Signing key not found: %s"
+//	@Failure	500		{object}	nil				"Failed to find user | Failed to find signing keys | Failed to update signing keys"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/system/signing-key/{keyId} [DELETE]
+func (s *APIV1Service) RetireSigningKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := s.checkHostUser(c); err != nil {
+		return err
+	}
+
+	keys, err := listSigningKeys(ctx, s.Store)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find signing keys").SetInternal(err)
+	}
+	keyID := c.Param("keyId")
+	remainingKeys := []*signingKey{}
+	for _, key := range keys {
+		if key.ID != keyID {
+			remainingKeys = append(remainingKeys, key)
+		}
+	}
+	if len(remainingKeys) == len(keys) {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Signing key not found: %s", keyID))
+	}
+	if len(remainingKeys) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "The only signing key can't be retired")
+	}
+	if err := upsertSigningKeys(ctx, s.Store, remainingKeys); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update signing keys").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, convertSigningKeysFromStore(remainingKeys))
+}
+
+func (s *APIV1Service) checkHostUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil || user.Role != store.RoleHost {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+	}
+	return nil
+}
+
+// InitSigningKeys creates the keyring with the legacy secret if it doesn't exist yet,
+// so that the access tokens signed before the keyring remain valid.
+func InitSigningKeys(ctx context.Context, s *store.Store, secret string) error {
+	systemSetting, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingSigningKeysName.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if systemSetting != nil {
+		return nil
+	}
+	return upsertSigningKeys(ctx, s, []*signingKey{
+		{
+			ID:        auth.KeyID,
+			Secret:    secret,
+			CreatedTs: time.Now().Unix(),
+		},
+	})
+}
+
+// SigningKeyFunc returns the function looking up the key in the keyring to verify an access token with its kid header.
+func SigningKeyFunc(ctx context.Context, s *store.Store) jwt.Keyfunc {
+	return func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
+			return nil, errors.Errorf("unexpected access token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
+		}
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			return nil, errors.Errorf("unexpected access token kid=%v", t.Header["kid"])
+		}
+		keys, err := listSigningKeys(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if key.ID == kid {
+				return []byte(key.Secret), nil
+			}
+		}
+		return nil, errors.Errorf("unexpected access token kid=%v", kid)
+	}
+}
+
+// getCurrentSigningKey returns the newest key of the keyring, which signs the new access tokens.
+func getCurrentSigningKey(ctx context.Context, s *store.Store) (*signingKey, error) {
+	keys, err := listSigningKeys(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing key found")
+	}
+	return keys[len(keys)-1], nil
+}
+
+// listSigningKeys returns the keys of the keyring, from the oldest to the newest.
+func listSigningKeys(ctx context.Context, s *store.Store) ([]*signingKey, error) {
+	systemSetting, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingSigningKeysName.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find system setting")
+	}
+	keys := []*signingKey{}
+	if systemSetting == nil {
+		return keys, nil
+	}
+	if err := json.Unmarshal([]byte(systemSetting.Value), &keys); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal signing keys")
+	}
+	return keys, nil
+}
+
+func upsertSigningKeys(ctx context.Context, s *store.Store, keys []*signingKey) error {
+	value, err := json.Marshal(keys)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal signing keys")
+	}
+	if _, err := s.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  SystemSettingSigningKeysName.String(),
+		Value: string(value),
+	}); err != nil {
+		return errors.Wrap(err, "failed to upsert system setting")
+	}
+	return nil
+}
+
+// nextSigningKeyID returns the ID following the newest version of the keys, e.g. v2 after v1.
+func nextSigningKeyID(keys []*signingKey) string {
+	version := 0
+	for _, key := range keys {
+		if v, err := strconv.Atoi(strings.TrimPrefix(key.ID, "v")); err == nil && v > version {
+			version = v
+		}
+	}
+	return fmt.Sprintf("v%d", version+1)
+}
+
+func convertSigningKeysFromStore(keys []*signingKey) []*SigningKey {
+	signingKeyList := []*SigningKey{}
+	for i, key := range keys {
+		signingKeyList = append(signingKeyList, &SigningKey{
+			ID:        key.ID,
+			CreatedTs: key.CreatedTs,
+			Current:   i == len(keys)-1,
+		})
+	}
+	return signingKeyList
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/plugin/idp/oidc"
+	"github.com/usememos/memos/store"
+)
+
+type SSOAuthorize struct {
+	IdentityProviderID int32  `json:"identityProviderId"`
+	RedirectURI        string `json:"redirectUri"`
+}
+
+type SSOAuthorization struct {
+	// AuthorizationURL is where the user signs in with the identity provider, which redirects back with the code and the state.
+	AuthorizationURL string `json:"authorizationUrl"`
+}
+
+// ssoStateClaims is the authorization request of a SSO sign-in, which is kept in a cookie
+// until the identity provider redirects the user back with the code.
+type ssoStateClaims struct {
+	IdentityProviderID int32  `json:"identityProviderId"`
+	RedirectURI        string `json:"redirectUri"`
+	State              string `json:"state"`
+	Nonce              string `json:"nonce"`
+	CodeVerifier       string `json:"codeVerifier"`
+	jwt.RegisteredClaims
+}
+
+// AuthorizeSSO godoc
+//
+//	@Summary		Start a SSO sign-in with an OpenID Connect identity provider.
+//	@Description	The state, nonce and PKCE code verifier of the authorization request are kept in a cookie, which is checked by the SSO sign-in.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		SSOAuthorize		true	"SSO authorization object"
+//	@Success		200		{object}	SSOAuthorization	"Authorization URL"
+//	@Failure		400		{object}	nil					"Malformatted authorization request | Identity provider type %s doesn't support authorization requests"
+//	@Failure		404		{object}	nil					"Identity provider not found"
+//	@Failure		500		{object}	nil					"Failed to find identity provider | Failed to create identity provider instance | Failed to generate authorization request | Failed to get authorization URL"
+//	@Router			/api/v1/auth/sso/authorize [POST]
+func (s *APIV1Service) AuthorizeSSO(c echo.Context) error {
+	ctx := c.Request().Context()
+	authorize := &SSOAuthorize{}
+	if err := json.NewDecoder(c.Request().Body).Decode(authorize); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted authorization request").SetInternal(err)
+	}
+
+	identityProvider, err := s.Store.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &authorize.IdentityProviderID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find identity provider").SetInternal(err)
+	}
+	if identityProvider == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Identity provider not found")
+	}
+	if identityProvider.Type != store.IdentityProviderOIDCType {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Identity provider type %s doesn't support authorization requests", identityProvider.Type))
+	}
+
+	oidcIdentityProvider, err := oidc.NewIdentityProvider(identityProvider.Config.OIDCConfig)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create identity provider instance").SetInternal(err)
+	}
+	claims, err := generateSSOState(identityProvider, authorize.RedirectURI)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate authorization request").SetInternal(err)
+	}
+	authorizationURL, err := oidcIdentityProvider.AuthorizationURL(ctx, claims.RedirectURI, claims.State, claims.Nonce, claims.CodeVerifier)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get authorization URL").SetInternal(err)
+	}
+	ssoStateToken, err := signSSOState(ctx, s.Store, claims)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate authorization request").SetInternal(err)
+	}
+	setTokenCookie(c, auth.SSOStateCookieName, ssoStateToken, claims.ExpiresAt.Time)
+	return c.JSON(http.StatusOK, &SSOAuthorization{
+		AuthorizationURL: authorizationURL,
+	})
+}
+
+// generateSSOState generates the authorization request of a SSO sign-in with the identity provider.
+func generateSSOState(identityProvider *store.IdentityProvider, redirectURI string) (*ssoStateClaims, error) {
+	random, err := util.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := util.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+	return &ssoStateClaims{
+		IdentityProviderID: identityProvider.ID,
+		RedirectURI:        redirectURI,
+		// The state ends with the identity provider ID, which the auth callback page signs in with.
+		State:        fmt.Sprintf("auth.signin.%s-%d", random, identityProvider.ID),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{auth.SSOStateAudienceName},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(auth.SSOStateDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    auth.Issuer,
+		},
+	}, nil
+}
+
+func signSSOState(ctx context.Context, s *store.Store, claims *ssoStateClaims) (string, error) {
+	key, err := getCurrentSigningKey(ctx, s)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signing key")
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString([]byte(key.Secret))
+}
+
+// consumeSSOState returns the authorization request of the SSO sign-in kept in the cookie, if its state is the given one.
+// The cookie is removed once used, as the authorization code can only be exchanged once anyway.
+func consumeSSOState(c echo.Context, s *store.Store, identityProviderID int32, state string) (*ssoStateClaims, error) {
+	cookie, err := c.Cookie(auth.SSOStateCookieName)
+	if err != nil {
+		return nil, errors.New("missing sso state cookie")
+	}
+	setTokenCookie(c, auth.SSOStateCookieName, "", time.Now().Add(-1*time.Hour))
+
+	claims := &ssoStateClaims{}
+	if _, err := jwt.ParseWithClaims(cookie.Value, claims, SigningKeyFunc(c.Request().Context(), s)); err != nil {
+		return nil, errors.Wrap(err, "invalid sso state token")
+	}
+	if !audienceContains(claims.Audience, auth.SSOStateAudienceName) {
+		return nil, errors.Errorf("unexpected sso state token audience %v", claims.Audience)
+	}
+	if claims.IdentityProviderID != identityProviderID || subtle.ConstantTimeCompare([]byte(claims.State), []byte(state)) != 1 {
+		return nil, errors.New("unexpected sso state")
+	}
+	return claims, nil
+}
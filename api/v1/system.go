@@ -22,6 +22,12 @@ type SystemStatus struct {
 	AllowSignUp bool `json:"allowSignUp"`
 	// Disable password login.
 	DisablePasswordLogin bool `json:"disablePasswordLogin"`
+	// Require two-factor authentication for hosts and admins.
+	RequireAdminTwoFactor bool `json:"requireAdminTwoFactor"`
+	// Require verified email for the users signing up.
+	RequireEmailVerification bool `json:"requireEmailVerification"`
+	// Mail enabled, which is true if the SMTP server is configured.
+	MailEnabled bool `json:"mailEnabled"`
 	// Disable public memos.
 	DisablePublicMemos bool `json:"disablePublicMemos"`
 	// Max upload size.
@@ -66,21 +72,24 @@ func (*APIV1Service) PingSystem(c echo.Context) error {
 //	@Produce	json
 //	@Success	200	{object}	SystemStatus	"System GetSystemStatus"
 //	@Failure	401	{object}	nil				"Missing user in session | Unauthorized"
-//	@Failure	500	{object}	nil				"Failed to find host user | Failed to find system setting list | Failed to unmarshal system setting customized profile value"
+//	@Failure	500	{object}	nil				"Failed to find host user | Failed to find system setting list | Failed to unmarshal system setting customized profile value | Failed to unmarshal system setting smtp value"
 //	@Router		/api/v1/status [GET]
 func (s *APIV1Service) GetSystemStatus(c echo.Context) error {
 	ctx := c.Request().Context()
 
 	systemStatus := SystemStatus{
-		Profile:              *s.Profile,
-		DBSize:               0,
-		AllowSignUp:          false,
-		DisablePasswordLogin: false,
-		DisablePublicMemos:   false,
-		MaxUploadSizeMiB:     32,
-		AutoBackupInterval:   0,
-		AdditionalStyle:      "",
-		AdditionalScript:     "",
+		Profile:                  *s.Profile,
+		DBSize:                   0,
+		AllowSignUp:              false,
+		DisablePasswordLogin:     false,
+		RequireAdminTwoFactor:    false,
+		RequireEmailVerification: false,
+		MailEnabled:              false,
+		DisablePublicMemos:       false,
+		MaxUploadSizeMiB:         32,
+		AutoBackupInterval:       0,
+		AdditionalStyle:          "",
+		AdditionalScript:         "",
 		CustomizedProfile: CustomizedProfile{
 			Name:        "memos",
 			LogoURL:     "",
@@ -110,7 +119,7 @@ func (s *APIV1Service) GetSystemStatus(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting list").SetInternal(err)
 	}
 	for _, systemSetting := range systemSettingList {
-		if systemSetting.Name == SystemSettingServerIDName.String() || systemSetting.Name == SystemSettingSecretSessionName.String() || systemSetting.Name == SystemSettingTelegramBotTokenName.String() {
+		if systemSetting.Name == SystemSettingServerIDName.String() || systemSetting.Name == SystemSettingSecretSessionName.String() || systemSetting.Name == SystemSettingTelegramBotTokenName.String() || systemSetting.Name == SystemSettingSigningKeysName.String() || systemSetting.Name == SystemSettingMemoTagsBackfilledName.String() || systemSetting.Name == SystemSettingMemoTrashRetentionDaysName.String() {
 			continue
 		}
 
@@ -126,6 +135,17 @@ func (s *APIV1Service) GetSystemStatus(c echo.Context) error {
 			systemStatus.AllowSignUp = baseValue.(bool)
 		case SystemSettingDisablePasswordLoginName.String():
 			systemStatus.DisablePasswordLogin = baseValue.(bool)
+		case SystemSettingRequireAdminTwoFactorName.String():
+			systemStatus.RequireAdminTwoFactor = baseValue.(bool)
+		case SystemSettingRequireEmailVerificationName.String():
+			systemStatus.RequireEmailVerification = baseValue.(bool)
+		case SystemSettingSMTPName.String():
+			// Only tell whether the emails are sent, the credentials of the SMTP server are secret.
+			smtpConfig := SMTPConfig{}
+			if err := json.Unmarshal([]byte(systemSetting.Value), &smtpConfig); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal system setting smtp value").SetInternal(err)
+			}
+			systemStatus.MailEnabled = smtpConfig.Host != ""
 		case SystemSettingDisablePublicMemosName.String():
 			systemStatus.DisablePublicMemos = baseValue.(bool)
 		case SystemSettingMaxUploadSizeMiBName.String():
@@ -152,6 +172,10 @@ func (s *APIV1Service) GetSystemStatus(c echo.Context) error {
 			log.Warn("Unknown system setting name", zap.String("setting name", systemSetting.Name))
 		}
 	}
+	// The emails aren't sent without the external URL, which the links are built from.
+	if systemStatus.CustomizedProfile.ExternalURL == "" {
+		systemStatus.MailEnabled = false
+	}
 
 	return c.JSON(http.StatusOK, systemStatus)
 }
@@ -42,6 +42,18 @@ const (
 	SystemSettingMemoDisplayWithUpdatedTsName SystemSettingName = "memo-display-with-updated-ts"
 	// SystemSettingAutoBackupIntervalName is the name of auto backup interval as seconds.
 	SystemSettingAutoBackupIntervalName SystemSettingName = "auto-backup-interval"
+	// SystemSettingMemoTrashRetentionDaysName is the name of days to keep memos in trash before purging them, 0 means forever.
+	SystemSettingMemoTrashRetentionDaysName SystemSettingName = "memo-trash-retention-days"
+	// SystemSettingSigningKeysName is the name of the keyring signing the access tokens.
+	SystemSettingSigningKeysName SystemSettingName = "signing-keys"
+	// SystemSettingMemoTagsBackfilledName is the name of the marker of the memo tags indexed once for the existing memos.
+	SystemSettingMemoTagsBackfilledName SystemSettingName = "memo-tags-backfilled"
+	// SystemSettingRequireAdminTwoFactorName is the name of require two-factor authentication for hosts and admins setting.
+	SystemSettingRequireAdminTwoFactorName SystemSettingName = "require-admin-two-factor"
+	// SystemSettingSMTPName is the name of the SMTP server sending the emails.
+	SystemSettingSMTPName SystemSettingName = "smtp"
+	// SystemSettingRequireEmailVerificationName is the name of require verified email for the users signing up setting.
+	SystemSettingRequireEmailVerificationName SystemSettingName = "require-email-verification"
 )
 const systemSettingUnmarshalError = `failed to unmarshal value from system setting "%v"`
 
@@ -61,6 +73,24 @@ type CustomizedProfile struct {
 	ExternalURL string `json:"externalUrl"`
 }
 
+// SMTPConfig is the struct definition for SystemSettingSMTPName system setting item.
+type SMTPConfig struct {
+	// Host is the host of the SMTP server, the emails are not sent if it's empty.
+	Host string `json:"host"`
+	// Port is the port of the SMTP server.
+	Port int `json:"port"`
+	// Username is the username of the SMTP server, the emails are sent without authentication if it's empty.
+	Username string `json:"username"`
+	// Password is the password of the SMTP server.
+	Password string `json:"password"`
+	// Security is NONE, STARTTLS or TLS.
+	Security string `json:"security"`
+	// FromEmail is the address the emails are sent from.
+	FromEmail string `json:"fromEmail"`
+	// FromName is the name the emails are sent from.
+	FromName string `json:"fromName"`
+}
+
 func (key SystemSettingName) String() string {
 	return string(key)
 }
@@ -117,6 +147,10 @@ func (s *APIV1Service) GetSystemSettingList(c echo.Context) error {
 
 	systemSettingList := make([]*SystemSetting, 0, len(list))
 	for _, systemSetting := range list {
+		// The signing keys are managed with the keyring endpoints, which never expose their secrets.
+		if systemSetting.Name == SystemSettingSigningKeysName.String() {
+			continue
+		}
 		systemSettingList = append(systemSettingList, convertSystemSettingFromStore(systemSetting))
 	}
 	return c.JSON(http.StatusOK, systemSettingList)
@@ -132,8 +166,8 @@ func (s *APIV1Service) GetSystemSettingList(c echo.Context) error {
 //	@Success	200		{object}	store.SystemSetting			"Created system setting"
 //	@Failure	400		{object}	nil							"Malformatted post system setting request | invalid system setting"
 //	@Failure	401		{object}	nil							"Missing user in session | Unauthorized"
-//	@Failure	403		{object}	nil							"Cannot disable passwords if no SSO identity provider is configured."
-//	@Failure	500		{object}	nil							"Failed to find user | Failed to upsert system setting"
+//	@Failure	403		{object}	nil							"Cannot disable passwords if no SSO identity provider or passkey is configured. | Cannot require two-factor authentication before enabling it for yourself. | Cannot require email verification if SMTP is not configured. | Cannot disable SMTP while email verification is required. | Cannot enable SMTP if the external URL is not set. | Cannot unset the external URL while SMTP is enabled."
+//	@Failure	500		{object}	nil							"Failed to find user | Failed to find identity providers | Failed to find user credentials | Failed to find two-factor authentication | Failed to find system setting | Failed to upsert system setting"
 //	@Security	ApiKeyAuth
 //	@Router		/api/v1/system/setting [POST]
 func (s *APIV1Service) CreateSystemSetting(c echo.Context) error {
@@ -168,10 +202,88 @@ func (s *APIV1Service) CreateSystemSetting(c echo.Context) error {
 
 		identityProviderList, err := s.Store.ListIdentityProviders(ctx, &store.FindIdentityProvider{})
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to upsert system setting").SetInternal(err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find identity providers").SetInternal(err)
+		}
+		// The host keeps signing in with its passkeys on passkey-only instances.
+		userCredentialList, err := s.Store.ListUserCredentials(ctx, &store.FindUserCredential{
+			UserID: &user.ID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user credentials").SetInternal(err)
+		}
+		if disablePasswordLogin && len(identityProviderList) == 0 && len(userCredentialList) == 0 {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot disable passwords if no SSO identity provider or passkey is configured.")
+		}
+	}
+
+	if systemSettingUpsert.Name == SystemSettingRequireAdminTwoFactorName {
+		var requireAdminTwoFactor bool
+		if err := json.Unmarshal([]byte(systemSettingUpsert.Value), &requireAdminTwoFactor); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid system setting").SetInternal(err)
+		}
+
+		userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+			UserID: &user.ID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find two-factor authentication").SetInternal(err)
+		}
+		if requireAdminTwoFactor && (userTOTP == nil || !userTOTP.Enabled) {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot require two-factor authentication before enabling it for yourself.")
+		}
+	}
+
+	if systemSettingUpsert.Name == SystemSettingRequireEmailVerificationName {
+		var requireEmailVerification bool
+		if err := json.Unmarshal([]byte(systemSettingUpsert.Value), &requireEmailVerification); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid system setting").SetInternal(err)
+		}
+
+		mailConfig, err := s.getMailConfig(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+		}
+		if requireEmailVerification && mailConfig == nil {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot require email verification if SMTP is not configured.")
+		}
+	}
+
+	if systemSettingUpsert.Name == SystemSettingSMTPName {
+		smtpConfig := &SMTPConfig{}
+		if err := json.Unmarshal([]byte(systemSettingUpsert.Value), smtpConfig); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid system setting").SetInternal(err)
+		}
+
+		// The users signing up would never be able to verify their email without SMTP.
+		requireEmailVerification, err := s.isEmailVerificationRequired(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+		}
+		if smtpConfig.Host == "" && requireEmailVerification {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot disable SMTP while email verification is required.")
+		}
+		// The links sent by email are built from the external URL.
+		customizedProfile, err := s.getSystemCustomizedProfile(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
 		}
-		if disablePasswordLogin && len(identityProviderList) == 0 {
-			return echo.NewHTTPError(http.StatusForbidden, "Cannot disable passwords if no SSO identity provider is configured.")
+		if smtpConfig.Host != "" && customizedProfile.ExternalURL == "" {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot enable SMTP if the external URL is not set.")
+		}
+	}
+
+	if systemSettingUpsert.Name == SystemSettingCustomizedProfileName {
+		customizedProfile := &CustomizedProfile{}
+		if err := json.Unmarshal([]byte(systemSettingUpsert.Value), customizedProfile); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid system setting").SetInternal(err)
+		}
+
+		mailConfig, err := s.getMailConfig(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find system setting").SetInternal(err)
+		}
+		if customizedProfile.ExternalURL == "" && mailConfig != nil {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot unset the external URL while SMTP is enabled.")
 		}
 	}
 
@@ -188,7 +300,7 @@ func (s *APIV1Service) CreateSystemSetting(c echo.Context) error {
 
 func (upsert UpsertSystemSettingRequest) Validate() error {
 	switch settingName := upsert.Name; settingName {
-	case SystemSettingServerIDName:
+	case SystemSettingServerIDName, SystemSettingSigningKeysName, SystemSettingMemoTagsBackfilledName:
 		return fmt.Errorf("updating %v is not allowed", settingName)
 	case SystemSettingAllowSignUpName:
 		var value bool
@@ -200,6 +312,27 @@ func (upsert UpsertSystemSettingRequest) Validate() error {
 		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
 			return fmt.Errorf(systemSettingUnmarshalError, settingName)
 		}
+	case SystemSettingRequireAdminTwoFactorName:
+		var value bool
+		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
+			return fmt.Errorf(systemSettingUnmarshalError, settingName)
+		}
+	case SystemSettingRequireEmailVerificationName:
+		var value bool
+		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
+			return fmt.Errorf(systemSettingUnmarshalError, settingName)
+		}
+	case SystemSettingSMTPName:
+		value := &SMTPConfig{}
+		if err := json.Unmarshal([]byte(upsert.Value), value); err != nil {
+			return fmt.Errorf(systemSettingUnmarshalError, settingName)
+		}
+		if value.Host == "" {
+			return nil
+		}
+		if err := convertMailConfigFromSMTPConfig(value).Validate(); err != nil {
+			return err
+		}
 	case SystemSettingDisablePublicMemosName:
 		var value bool
 		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
@@ -252,6 +385,14 @@ func (upsert UpsertSystemSettingRequest) Validate() error {
 		if value < 0 {
 			return fmt.Errorf("must be positive")
 		}
+	case SystemSettingMemoTrashRetentionDaysName:
+		var value int
+		if err := json.Unmarshal([]byte(upsert.Value), &value); err != nil {
+			return fmt.Errorf(systemSettingUnmarshalError, settingName)
+		}
+		if value < 0 {
+			return fmt.Errorf("must be positive")
+		}
 	case SystemSettingTelegramBotTokenName:
 		if upsert.Value == "" {
 			return nil
@@ -1,15 +1,15 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
-	"sort"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/plugin/gomark/parser"
 	"github.com/usememos/memos/store"
 	"golang.org/x/exp/slices"
 )
@@ -19,6 +19,12 @@ type Tag struct {
 	CreatorID int32
 }
 
+// TagCount is the number of memos with the tag.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
 type UpsertTagRequest struct {
 	Name string `json:"name"`
 }
@@ -27,11 +33,29 @@ type DeleteTagRequest struct {
 	Name string `json:"name"`
 }
 
+type RenameTagRequest struct {
+	Name    string `json:"name"`
+	NewName string `json:"newName"`
+}
+
+type MergeTagsRequest struct {
+	NameList []string `json:"nameList"`
+	NewName  string   `json:"newName"`
+}
+
+type RenameTagResponse struct {
+	// MemoCount is the number of memos changed.
+	MemoCount int `json:"memoCount"`
+}
+
 func (s *APIV1Service) registerTagRoutes(g *echo.Group) {
 	g.GET("/tag", s.GetTagList)
 	g.POST("/tag", s.CreateTag)
 	g.GET("/tag/suggestion", s.GetTagSuggestion)
+	g.GET("/tag/count", s.GetTagCountList)
 	g.POST("/tag/delete", s.DeleteTag)
+	g.POST("/tag/rename", s.RenameTag)
+	g.POST("/tag/merge", s.MergeTags)
 }
 
 // GetTagList godoc
@@ -145,6 +169,94 @@ func (s *APIV1Service) DeleteTag(c echo.Context) error {
 	return c.JSON(http.StatusOK, true)
 }
 
+// RenameTag godoc
+//
+//	@Summary	Rename a tag in all memos of the current user
+//	@Tags		tag
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		RenameTagRequest	true	"Request object."
+//	@Success	200		{object}	RenameTagResponse	"Number of memos changed"
+//	@Failure	400		{object}	nil					"Malformatted rename tag request | Invalid tag name: %s"
+//	@Failure	401		{object}	nil					"Missing user in session"
+//	@Failure	500		{object}	nil					"Failed to rename tag"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/tag/rename [POST]
+func (s *APIV1Service) RenameTag(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	request := &RenameTagRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted rename tag request").SetInternal(err)
+	}
+	for _, name := range []string{request.Name, request.NewName} {
+		if !IsValidTagName(name) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid tag name: %s", name))
+		}
+	}
+
+	count, err := RenameTags(ctx, s.Store, &store.RenameTag{
+		CreatorID: userID,
+		NameList:  []string{request.Name},
+		NewName:   request.NewName,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to rename tag").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, &RenameTagResponse{MemoCount: count})
+}
+
+// MergeTags godoc
+//
+//	@Summary	Merge tags into one in all memos of the current user
+//	@Tags		tag
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		MergeTagsRequest	true	"Request object."
+//	@Success	200		{object}	RenameTagResponse	"Number of memos changed"
+//	@Failure	400		{object}	nil					"Malformatted merge tags request | Tag name list shouldn't be empty | Invalid tag name: %s"
+//	@Failure	401		{object}	nil					"Missing user in session"
+//	@Failure	500		{object}	nil					"Failed to merge tags"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/tag/merge [POST]
+func (s *APIV1Service) MergeTags(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	request := &MergeTagsRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted merge tags request").SetInternal(err)
+	}
+	if len(request.NameList) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tag name list shouldn't be empty")
+	}
+	for _, name := range request.NameList {
+		if !IsValidTagName(name) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid tag name: %s", name))
+		}
+	}
+	if !IsValidTagName(request.NewName) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid tag name: %s", request.NewName))
+	}
+
+	count, err := RenameTags(ctx, s.Store, &store.RenameTag{
+		CreatorID: userID,
+		NameList:  request.NameList,
+		NewName:   request.NewName,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to merge tags").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, &RenameTagResponse{MemoCount: count})
+}
+
 // GetTagSuggestion godoc
 //
 //	@Summary	Get a list of tags suggested from other memos contents
@@ -152,7 +264,7 @@ func (s *APIV1Service) DeleteTag(c echo.Context) error {
 //	@Produce	json
 //	@Success	200	{object}	[]string	"Tag list"
 //	@Failure	400	{object}	nil			"Missing user session"
-//	@Failure	500	{object}	nil			"Failed to find memo list | Failed to find tag list"
+//	@Failure	500	{object}	nil			"Failed to find tag count list | Failed to find tag list"
 //	@Security	ApiKeyAuth
 //	@Router		/api/v1/tag/suggestion [GET]
 func (s *APIV1Service) GetTagSuggestion(c echo.Context) error {
@@ -162,15 +274,12 @@ func (s *APIV1Service) GetTagSuggestion(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing user session")
 	}
 	normalRowStatus := store.Normal
-	memoFind := &store.FindMemo{
-		CreatorID:     &userID,
-		ContentSearch: []string{"#"},
-		RowStatus:     &normalRowStatus,
-	}
-
-	memoMessageList, err := s.Store.ListMemos(ctx, memoFind)
+	tagCountList, err := s.Store.ListTagCounts(ctx, &store.FindMemoTag{
+		CreatorID: &userID,
+		RowStatus: &normalRowStatus,
+	})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find memo list").SetInternal(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find tag count list").SetInternal(err)
 	}
 
 	list, err := s.Store.ListTags(ctx, &store.FindTag{
@@ -184,22 +293,51 @@ func (s *APIV1Service) GetTagSuggestion(c echo.Context) error {
 		tagNameList = append(tagNameList, tag.Name)
 	}
 
-	tagMapSet := make(map[string]bool)
-	for _, memo := range memoMessageList {
-		for _, tag := range findTagListFromMemoContent(memo.Content) {
-			if !slices.Contains(tagNameList, tag) {
-				tagMapSet[tag] = true
-			}
-		}
-	}
+	// The tag counts are ordered by name already.
 	tagList := []string{}
-	for tag := range tagMapSet {
-		tagList = append(tagList, tag)
+	for _, tagCount := range tagCountList {
+		if !slices.Contains(tagNameList, tagCount.Name) {
+			tagList = append(tagList, tagCount.Name)
+		}
 	}
-	sort.Strings(tagList)
 	return c.JSON(http.StatusOK, tagList)
 }
 
+// GetTagCountList godoc
+//
+//	@Summary	Get the tags in the memos of the current user with the number of memos of each
+//	@Tags		tag
+//	@Produce	json
+//	@Success	200	{object}	[]TagCount	"Tag count list"
+//	@Failure	400	{object}	nil			"Missing user session"
+//	@Failure	500	{object}	nil			"Failed to find tag count list"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/tag/count [GET]
+func (s *APIV1Service) GetTagCountList(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing user session")
+	}
+	normalRowStatus := store.Normal
+	list, err := s.Store.ListTagCounts(ctx, &store.FindMemoTag{
+		CreatorID: &userID,
+		RowStatus: &normalRowStatus,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find tag count list").SetInternal(err)
+	}
+
+	tagCountList := []*TagCount{}
+	for _, tagCount := range list {
+		tagCountList = append(tagCountList, &TagCount{
+			Name:  tagCount.Name,
+			Count: tagCount.Count,
+		})
+	}
+	return c.JSON(http.StatusOK, tagCountList)
+}
+
 func (s *APIV1Service) createTagCreateActivity(c echo.Context, tag *Tag) error {
 	ctx := c.Request().Context()
 	payload := ActivityTagCreatePayload{
@@ -221,27 +359,38 @@ func (s *APIV1Service) createTagCreateActivity(c echo.Context, tag *Tag) error {
 	return err
 }
 
-func convertTagFromStore(tag *store.Tag) *Tag {
-	return &Tag{
-		Name:      tag.Name,
-		CreatorID: tag.CreatorID,
+// RenameTags renames or merges tags in the memos of the creator, and records the change as a single activity
+// in the same transaction.
+func RenameTags(ctx context.Context, s *store.Store, rename *store.RenameTag) (int, error) {
+	rename.Activity = func(memoCount int) (*store.Activity, error) {
+		payload := ActivityTagRenamePayload{
+			TagNameList: rename.NameList,
+			NewTagName:  rename.NewName,
+			MemoCount:   memoCount,
+		}
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal activity payload")
+		}
+		return &store.Activity{
+			CreatorID: rename.CreatorID,
+			Type:      ActivityTagRename.String(),
+			Level:     ActivityInfo.String(),
+			Payload:   string(payloadBytes),
+		}, nil
 	}
+	return s.RenameTag(ctx, rename)
 }
 
-var tagRegexp = regexp.MustCompile(`#([^\s#,]+)`)
-
-func findTagListFromMemoContent(memoContent string) []string {
-	tagMapSet := make(map[string]bool)
-	matches := tagRegexp.FindAllStringSubmatch(memoContent, -1)
-	for _, v := range matches {
-		tagName := v[1]
-		tagMapSet[tagName] = true
-	}
+// IsValidTagName returns whether the name is the whole name of a tag when written in a memo.
+func IsValidTagName(name string) bool {
+	tagList := parser.FindTagList("#" + name)
+	return len(tagList) == 1 && tagList[0] == name
+}
 
-	tagList := []string{}
-	for tag := range tagMapSet {
-		tagList = append(tagList, tag)
+func convertTagFromStore(tag *store.Tag) *Tag {
+	return &Tag{
+		Name:      tag.Name,
+		CreatorID: tag.CreatorID,
 	}
-	sort.Strings(tagList)
-	return tagList
 }
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -42,6 +43,7 @@ type User struct {
 	Username        string         `json:"username"`
 	Role            Role           `json:"role"`
 	Email           string         `json:"email"`
+	EmailVerified   bool           `json:"emailVerified"`
 	Nickname        string         `json:"nickname"`
 	PasswordHash    string         `json:"-"`
 	OpenID          string         `json:"openId"`
@@ -328,7 +330,7 @@ func (s *APIV1Service) DeleteUser(c echo.Context) error {
 //	@Failure	400		{object}	nil					"ID is not a number: %s | Current session user not found with ID: %d | Malformatted patch user request | Invalid update user request"
 //	@Failure	401		{object}	nil					"Missing user in session"
 //	@Failure	403		{object}	nil					"Unauthorized to update user"
-//	@Failure	500		{object}	nil					"Failed to find user | Failed to generate password hash | Failed to patch user | Failed to find userSettingList"
+//	@Failure	500		{object}	nil					"Failed to find user | Failed to generate password hash | Failed to patch user | Failed to revoke user credentials | Failed to find userSettingList"
 //	@Router		/api/v1/user/{id} [PATCH]
 func (s *APIV1Service) UpdateUser(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -398,6 +400,11 @@ func (s *APIV1Service) UpdateUser(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to patch user").SetInternal(err)
 	}
+	if user.RowStatus == store.Archived && userUpdate.RowStatus != nil {
+		if err := RevokeUserCredentials(ctx, s.Store, user.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke user credentials").SetInternal(err)
+		}
+	}
 
 	list, err := s.Store.ListUserSettings(ctx, &store.FindUserSetting{
 		UserID: &userID,
@@ -498,18 +505,42 @@ func (s *APIV1Service) createUserCreateActivity(c echo.Context, user *User) erro
 	return err
 }
 
+// RevokeUserCredentials signs the user out of all its sessions, deletes its personal access tokens
+// and rotates its open ID, so that the archived users are locked out right away.
+func RevokeUserCredentials(ctx context.Context, s *store.Store, userID int32) error {
+	if err := s.DeleteSession(ctx, &store.DeleteSession{
+		UserID: &userID,
+	}); err != nil {
+		return errors.Wrap(err, "failed to delete sessions")
+	}
+	if err := s.DeleteAccessToken(ctx, &store.DeleteAccessToken{
+		UserID: &userID,
+	}); err != nil {
+		return errors.Wrap(err, "failed to delete access tokens")
+	}
+	openID := util.GenUUID()
+	if _, err := s.UpdateUser(ctx, &store.UpdateUser{
+		ID:     userID,
+		OpenID: &openID,
+	}); err != nil {
+		return errors.Wrap(err, "failed to rotate open ID")
+	}
+	return nil
+}
+
 func convertUserFromStore(user *store.User) *User {
 	return &User{
-		ID:           user.ID,
-		RowStatus:    RowStatus(user.RowStatus),
-		CreatedTs:    user.CreatedTs,
-		UpdatedTs:    user.UpdatedTs,
-		Username:     user.Username,
-		Role:         Role(user.Role),
-		Email:        user.Email,
-		Nickname:     user.Nickname,
-		PasswordHash: user.PasswordHash,
-		OpenID:       user.OpenID,
-		AvatarURL:    user.AvatarURL,
+		ID:            user.ID,
+		RowStatus:     RowStatus(user.RowStatus),
+		CreatedTs:     user.CreatedTs,
+		UpdatedTs:     user.UpdatedTs,
+		Username:      user.Username,
+		Role:          Role(user.Role),
+		Email:         user.Email,
+		EmailVerified: user.Email != "" && user.VerifiedEmail == user.Email,
+		Nickname:      user.Nickname,
+		PasswordHash:  user.PasswordHash,
+		OpenID:        user.OpenID,
+		AvatarURL:     user.AvatarURL,
 	}
 }
@@ -0,0 +1,448 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/api/auth"
+	"github.com/usememos/memos/common/util"
+	"github.com/usememos/memos/plugin/totp"
+	"github.com/usememos/memos/store"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes generated when enabling two-factor authentication.
+	recoveryCodeCount = 10
+	// maxTOTPFailedAttempts is the number of incorrect codes after which the codes of the user are refused,
+	// until no code has been tried for totpLockoutDuration.
+	maxTOTPFailedAttempts = 5
+	totpLockoutDuration   = 15 * time.Minute
+)
+
+var errTooManyTOTPAttempts = errors.New("too many failed two-factor authentication attempts")
+
+type UserTOTP struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+}
+
+type UserTOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth URI to be shown as a QR code, which is scanned by authenticator apps.
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type UserTOTPCodeRequest struct {
+	// Code is a code of the authenticator app, or a recovery code.
+	Code string `json:"code"`
+}
+
+type UserTOTPRecoveryCodes struct {
+	// RecoveryCodes are only returned once, each of them can be used instead of a code once.
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TwoFactorChallenge is returned by the password sign-in of users with two-factor authentication,
+// whose token is exchanged for a session along with a code.
+type TwoFactorChallenge struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+}
+
+type TOTPSignIn struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	Code           string `json:"code"`
+}
+
+func (s *APIV1Service) registerUserTOTPRoutes(g *echo.Group) {
+	g.GET("/user/me/totp", s.GetUserTOTP)
+	g.POST("/user/me/totp", s.EnrollUserTOTP)
+	g.POST("/user/me/totp/enable", s.EnableUserTOTP)
+	g.POST("/user/me/totp/disable", s.DisableUserTOTP)
+}
+
+// GetUserTOTP godoc
+//
+//	@Summary	Get the two-factor authentication status of the current user
+//	@Tags		user
+//	@Produce	json
+//	@Success	200	{object}	UserTOTP	"Two-factor authentication status"
+//	@Failure	401	{object}	nil			"Missing user in session"
+//	@Failure	500	{object}	nil			"Failed to find two-factor authentication"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/totp [GET]
+func (s *APIV1Service) GetUserTOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+
+	userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find two-factor authentication").SetInternal(err)
+	}
+	userTOTPMessage := &UserTOTP{}
+	if userTOTP != nil && userTOTP.Enabled {
+		userTOTPMessage.Enabled = true
+		userTOTPMessage.RecoveryCodesLeft = len(userTOTP.RecoveryCodes)
+	}
+	return c.JSON(http.StatusOK, userTOTPMessage)
+}
+
+// EnrollUserTOTP godoc
+//
+//	@Summary	Generate a new TOTP secret for the current user, which is enabled once a code of it is confirmed
+//	@Tags		user
+//	@Produce	json
+//	@Success	200	{object}	UserTOTPEnrollment	"TOTP secret and its provisioning URI"
+//	@Failure	401	{object}	nil					"Missing user in session"
+//	@Failure	409	{object}	nil					"Two-factor authentication is already enabled"
+//	@Failure	500	{object}	nil					"Failed to find user | Failed to find two-factor authentication | Failed to generate TOTP secret | Failed to upsert two-factor authentication"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/totp [POST]
+func (s *APIV1Service) EnrollUserTOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+
+	userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find two-factor authentication").SetInternal(err)
+	}
+	if userTOTP != nil && userTOTP.Enabled {
+		return echo.NewHTTPError(http.StatusConflict, "Two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate TOTP secret").SetInternal(err)
+	}
+	if _, err := s.Store.UpsertUserTOTP(ctx, &store.UserTOTP{
+		UserID: userID,
+		Secret: secret,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to upsert two-factor authentication").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, &UserTOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(auth.Issuer, user.Username, secret),
+	})
+}
+
+// EnableUserTOTP godoc
+//
+//	@Summary	Enable two-factor authentication of the current user by confirming a code of the enrolled secret
+//	@Tags		user
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		UserTOTPCodeRequest		true	"Code of the authenticator app"
+//	@Success	200		{object}	UserTOTPRecoveryCodes	"Recovery codes, which are only returned once"
+//	@Failure	400		{object}	nil						"Malformatted enable TOTP request | Two-factor authentication is not enrolled | Incorrect two-factor authentication code"
+//	@Failure	401		{object}	nil						"Missing user in session"
+//	@Failure	409		{object}	nil						"Two-factor authentication is already enabled"
+//	@Failure	429		{object}	nil						"Too many incorrect two-factor authentication codes, please try again later"
+//	@Failure	500		{object}	nil						"Failed to find two-factor authentication | Failed to verify two-factor authentication code | Failed to generate recovery codes | Failed to update two-factor authentication"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/totp/enable [POST]
+func (s *APIV1Service) EnableUserTOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	request := &UserTOTPCodeRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted enable TOTP request").SetInternal(err)
+	}
+
+	userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find two-factor authentication").SetInternal(err)
+	}
+	if userTOTP == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is not enrolled")
+	}
+	if userTOTP.Enabled {
+		return echo.NewHTTPError(http.StatusConflict, "Two-factor authentication is already enabled")
+	}
+	verified, err := s.verifyTOTPCode(ctx, userTOTP, request.Code)
+	if errors.Is(err, errTooManyTOTPAttempts) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "Too many incorrect two-factor authentication codes, please try again later")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify two-factor authentication code").SetInternal(err)
+	}
+	if !verified {
+		return echo.NewHTTPError(http.StatusBadRequest, "Incorrect two-factor authentication code")
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := generateRecoveryCodes()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate recovery codes").SetInternal(err)
+	}
+	enabled, currentTs := true, time.Now().Unix()
+	if err := s.Store.UpdateUserTOTP(ctx, &store.UpdateUserTOTP{
+		UserID:        userID,
+		UpdatedTs:     &currentTs,
+		Enabled:       &enabled,
+		RecoveryCodes: &recoveryCodeHashes,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update two-factor authentication").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, &UserTOTPRecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// DisableUserTOTP godoc
+//
+//	@Summary	Disable two-factor authentication of the current user with a code or a recovery code
+//	@Tags		user
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		UserTOTPCodeRequest	true	"Code of the authenticator app, or a recovery code"
+//	@Success	200		{boolean}	true				"Two-factor authentication disabled"
+//	@Failure	400		{object}	nil					"Malformatted disable TOTP request | Two-factor authentication is not enabled | Incorrect two-factor authentication code"
+//	@Failure	401		{object}	nil					"Missing user in session"
+//	@Failure	429		{object}	nil					"Too many incorrect two-factor authentication codes, please try again later"
+//	@Failure	500		{object}	nil					"Failed to find two-factor authentication | Failed to verify two-factor authentication code | Failed to delete two-factor authentication"
+//	@Security	ApiKeyAuth
+//	@Router		/api/v1/user/me/totp/disable [POST]
+func (s *APIV1Service) DisableUserTOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, ok := c.Get(auth.UserIDContextKey).(int32)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing user in session")
+	}
+	request := &UserTOTPCodeRequest{}
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted disable TOTP request").SetInternal(err)
+	}
+
+	userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find two-factor authentication").SetInternal(err)
+	}
+	if userTOTP == nil || !userTOTP.Enabled {
+		return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is not enabled")
+	}
+	verified, err := s.verifyTOTPCode(ctx, userTOTP, request.Code)
+	if errors.Is(err, errTooManyTOTPAttempts) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "Too many incorrect two-factor authentication codes, please try again later")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify two-factor authentication code").SetInternal(err)
+	}
+	if !verified {
+		return echo.NewHTTPError(http.StatusBadRequest, "Incorrect two-factor authentication code")
+	}
+
+	if err := s.Store.DeleteUserTOTP(ctx, &store.DeleteUserTOTP{
+		UserID: userID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete two-factor authentication").SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, true)
+}
+
+// SignInTOTP godoc
+//
+//	@Summary	Finish the two-step sign-in with a code of the authenticator app or a recovery code.
+//	@Tags		auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		body	body		TOTPSignIn	true	"Two-factor token of the password sign-in and the code"
+//	@Success	200		{object}	store.User	"User information"
+//	@Failure	400		{object}	nil			"Malformatted signin request"
+//	@Failure	401		{object}	nil			"Invalid or expired two-factor token, please sign in again | Incorrect two-factor authentication code"
+//	@Failure	403		{object}	nil			"User has been archived with username %s"
+//	@Failure	429		{object}	nil			"Too many incorrect two-factor authentication codes, please try again later"
+//	@Failure	500		{object}	nil			"Failed to find user | Failed to find two-factor authentication | Failed to verify two-factor authentication code | Failed to generate tokens | Failed to create activity"
+//	@Router		/api/v1/auth/signin/totp [POST]
+func (s *APIV1Service) SignInTOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	signin := &TOTPSignIn{}
+	if err := json.NewDecoder(c.Request().Body).Decode(signin); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted signin request").SetInternal(err)
+	}
+
+	claims := &claimsMessage{}
+	if _, err := jwt.ParseWithClaims(signin.TwoFactorToken, claims, SigningKeyFunc(ctx, s.Store)); err != nil || !audienceContains(claims.Audience, auth.TwoFactorTokenAudienceName) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired two-factor token, please sign in again").SetInternal(err)
+	}
+	userID, err := util.ConvertStringToInt32(claims.Subject)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired two-factor token, please sign in again").SetInternal(err)
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find user").SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired two-factor token, please sign in again")
+	} else if user.RowStatus == store.Archived {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("User has been archived with username %s", user.Username))
+	}
+
+	userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find two-factor authentication").SetInternal(err)
+	}
+	if userTOTP == nil || !userTOTP.Enabled {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired two-factor token, please sign in again")
+	}
+	verified, err := s.verifyTOTPCode(ctx, userTOTP, signin.Code)
+	if errors.Is(err, errTooManyTOTPAttempts) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "Too many incorrect two-factor authentication codes, please try again later")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify two-factor authentication code").SetInternal(err)
+	}
+	if !verified {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect two-factor authentication code")
+	}
+
+	if err := GenerateTokensAndSetCookies(c, s.Store, user); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate tokens").SetInternal(err)
+	}
+	if err := s.createAuthSignInActivity(c, user); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+	}
+	userMessage := convertUserFromStore(user)
+	return c.JSON(http.StatusOK, userMessage)
+}
+
+// IsTwoFactorEnrollmentRequired returns whether the user has to enable two-factor authentication before using the APIs,
+// as the system setting requires it for hosts and admins.
+func IsTwoFactorEnrollmentRequired(ctx context.Context, s *store.Store, user *store.User) (bool, error) {
+	if user.Role != store.RoleHost && user.Role != store.RoleAdmin {
+		return false, nil
+	}
+	requireAdminTwoFactorSetting, err := s.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: SystemSettingRequireAdminTwoFactorName.String(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to find system setting")
+	}
+	if requireAdminTwoFactorSetting == nil {
+		return false, nil
+	}
+	requireAdminTwoFactor := false
+	if err := json.Unmarshal([]byte(requireAdminTwoFactorSetting.Value), &requireAdminTwoFactor); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal system setting")
+	}
+	if !requireAdminTwoFactor {
+		return false, nil
+	}
+
+	userTOTP, err := s.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to find two-factor authentication")
+	}
+	return userTOTP == nil || !userTOTP.Enabled, nil
+}
+
+// getTwoFactorChallenge returns the challenge of the two-step sign-in if the user has enabled two-factor authentication.
+func (s *APIV1Service) getTwoFactorChallenge(ctx context.Context, user *store.User) (*TwoFactorChallenge, error) {
+	userTOTP, err := s.Store.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find two-factor authentication")
+	}
+	if userTOTP == nil || !userTOTP.Enabled {
+		return nil, nil
+	}
+
+	key, err := getCurrentSigningKey(ctx, s.Store)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get signing key")
+	}
+	// The two-factor token isn't bound to any session yet.
+	expirationTime := time.Now().Add(auth.TwoFactorTokenDuration)
+	twoFactorToken, err := generateToken(user.Username, user.ID, 0, auth.TwoFactorTokenAudienceName, expirationTime, key.ID, []byte(key.Secret))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate two-factor token")
+	}
+	return &TwoFactorChallenge{
+		TwoFactorToken: twoFactorToken,
+	}, nil
+}
+
+// verifyTOTPCode returns whether the code is a valid code of the TOTP secret, or one of the recovery codes.
+// The code is consumed, so that it can't be used again, and every attempt is counted to limit the guesses.
+func (s *APIV1Service) verifyTOTPCode(ctx context.Context, userTOTP *store.UserTOTP, code string) (bool, error) {
+	now := time.Now()
+	attempted, err := s.Store.AttemptUserTOTP(ctx, &store.AttemptUserTOTP{
+		UserID:            userTOTP.UserID,
+		AttemptedTs:       now.Unix(),
+		MaxFailedAttempts: maxTOTPFailedAttempts,
+		ResetTs:           now.Add(-totpLockoutDuration).Unix(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if !attempted {
+		return false, errTooManyTOTPAttempts
+	}
+
+	if step, ok := totp.Validate(userTOTP.Secret, code, now); ok {
+		// The update is conditional on the last used step, so that concurrent uses of a code are told apart.
+		return s.Store.ConsumeUserTOTPStep(ctx, userTOTP.UserID, step)
+	}
+	index := slices.Index(userTOTP.RecoveryCodes, HashAccessToken(normalizeRecoveryCode(code)))
+	if index < 0 {
+		return false, nil
+	}
+	recoveryCodes := slices.Delete(slices.Clone(userTOTP.RecoveryCodes), index, index+1)
+	return s.Store.ConsumeUserTOTPRecoveryCode(ctx, userTOTP.UserID, userTOTP.RecoveryCodes, recoveryCodes)
+}
+
+// generateRecoveryCodes returns new recovery codes formatted as xxxxx-xxxxx, and their hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes, recoveryCodeHashes := []string{}, []string{}
+	for i := 0; i < recoveryCodeCount; i++ {
+		randomString, err := util.RandomString(10)
+		if err != nil {
+			return nil, nil, err
+		}
+		recoveryCode := normalizeRecoveryCode(randomString[:5] + "-" + randomString[5:])
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		recoveryCodeHashes = append(recoveryCodeHashes, HashAccessToken(recoveryCode))
+	}
+	return recoveryCodes, recoveryCodeHashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
@@ -7,14 +7,12 @@ import (
 )
 
 type APIV1Service struct {
-	Secret  string
 	Profile *profile.Profile
 	Store   *store.Store
 }
 
-func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store) *APIV1Service {
+func NewAPIV1Service(profile *profile.Profile, store *store.Store) *APIV1Service {
 	return &APIV1Service{
-		Secret:  secret,
 		Profile: profile,
 		Store:   store,
 	}
@@ -27,14 +25,20 @@ func (s *APIV1Service) Register(rootGroup *echo.Group) {
 	// Register API v1 routes.
 	apiV1Group := rootGroup.Group("/api/v1")
 	apiV1Group.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return JWTMiddleware(s, next, s.Secret)
+		return JWTMiddleware(s, next)
 	})
 	s.registerSystemRoutes(apiV1Group)
 	s.registerSystemSettingRoutes(apiV1Group)
+	s.registerSigningKeyRoutes(apiV1Group)
 	s.registerAuthRoutes(apiV1Group)
 	s.registerIdentityProviderRoutes(apiV1Group)
 	s.registerUserRoutes(apiV1Group)
 	s.registerUserSettingRoutes(apiV1Group)
+	s.registerUserTOTPRoutes(apiV1Group)
+	s.registerUserPasskeyRoutes(apiV1Group)
+	s.registerMailRoutes(apiV1Group)
+	s.registerAccessTokenRoutes(apiV1Group)
+	s.registerSessionRoutes(apiV1Group)
 	s.registerTagRoutes(apiV1Group)
 	s.registerStorageRoutes(apiV1Group)
 	s.registerResourceRoutes(apiV1Group)
@@ -42,11 +46,22 @@ func (s *APIV1Service) Register(rootGroup *echo.Group) {
 	s.registerMemoOrganizerRoutes(apiV1Group)
 	s.registerMemoResourceRoutes(apiV1Group)
 	s.registerMemoRelationRoutes(apiV1Group)
+	s.registerMemoRevisionRoutes(apiV1Group)
+	s.registerMemoTrashRoutes(apiV1Group)
+	s.registerMemoImportRoutes(apiV1Group)
+	s.registerShortcutRoutes(apiV1Group)
+
+	// Register SCIM routes.
+	scimGroup := rootGroup.Group("/scim/v2")
+	scimGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return SCIMMiddleware(s, next)
+	})
+	s.registerSCIMRoutes(scimGroup)
 
 	// Register public routes.
 	publicGroup := rootGroup.Group("/o")
 	publicGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return JWTMiddleware(s, next, s.Secret)
+		return JWTMiddleware(s, next)
 	})
 	s.registerGetterPublicRoutes(publicGroup)
 	s.registerResourcePublicRoutes(publicGroup)
@@ -3,13 +3,13 @@ package v2
 import (
 	"context"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/pkg/errors"
 	"github.com/usememos/memos/api/auth"
+	apiv1 "github.com/usememos/memos/api/v1"
 	"github.com/usememos/memos/common/util"
 	"github.com/usememos/memos/store"
 	"google.golang.org/grpc"
@@ -25,24 +25,56 @@ const (
 	// The key name used to store user id in the context
 	// user id is extracted from the jwt token subject field.
 	UserIDContextKey ContextKey = iota
+	// The key name used to store the scopes of the personal access token in the context.
+	ScopesContextKey
 )
 
 // GRPCAuthInterceptor is the auth interceptor for gRPC server.
 type GRPCAuthInterceptor struct {
-	Store  *store.Store
-	secret string
+	Store *store.Store
 }
 
 // NewGRPCAuthInterceptor returns a new API auth interceptor.
-func NewGRPCAuthInterceptor(store *store.Store, secret string) *GRPCAuthInterceptor {
+func NewGRPCAuthInterceptor(store *store.Store) *GRPCAuthInterceptor {
 	return &GRPCAuthInterceptor{
-		Store:  store,
-		secret: secret,
+		Store: store,
 	}
 }
 
 // AuthenticationInterceptor is the unary interceptor for gRPC API.
 func (in *GRPCAuthInterceptor) AuthenticationInterceptor(ctx context.Context, request any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	childCtx, err := in.authenticateContext(ctx, serverInfo.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(childCtx, request)
+}
+
+// AuthenticationStreamInterceptor is the stream interceptor for gRPC API.
+func (in *GRPCAuthInterceptor) AuthenticationStreamInterceptor(server any, stream grpc.ServerStream, serverInfo *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	childCtx, err := in.authenticateContext(stream.Context(), serverInfo.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(server, &authenticatedServerStream{
+		ServerStream: stream,
+		ctx:          childCtx,
+	})
+}
+
+// authenticatedServerStream is a server stream with the context of the authenticated user.
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticateContext returns the context storing the ID of the authenticated user, or the context itself
+// for the methods allowed without authentication.
+func (in *GRPCAuthInterceptor) authenticateContext(ctx context.Context, fullMethod string) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Errorf(codes.Unauthenticated, "failed to parse metadata from incoming context")
@@ -52,12 +84,28 @@ func (in *GRPCAuthInterceptor) AuthenticationInterceptor(ctx context.Context, re
 		return nil, status.Errorf(codes.Unauthenticated, err.Error())
 	}
 
-	userID, err := in.authenticate(ctx, accessTokenStr)
-	if err != nil {
-		if isUnauthorizeAllowedMethod(serverInfo.FullMethod) {
-			return handler(ctx, request)
+	var userID int32
+	if strings.HasPrefix(accessTokenStr, auth.PersonalAccessTokenPrefix) {
+		accessToken, err := apiv1.AuthenticatePersonalAccessToken(ctx, in.Store, accessTokenStr)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to authenticate access token: %v", err)
+		}
+		if accessToken == nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired access token")
+		}
+		if scope := getRequiredScope(fullMethod); !apiv1.HasScope(accessToken.Scopes, scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "access token lacks the %s scope", scope)
+		}
+		userID = accessToken.UserID
+		ctx = context.WithValue(ctx, ScopesContextKey, accessToken.Scopes)
+	} else {
+		userID, err = in.authenticate(ctx, accessTokenStr)
+		if err != nil {
+			if isUnauthorizeAllowedMethod(fullMethod) {
+				return ctx, nil
+			}
+			return nil, err
 		}
-		return nil, err
 	}
 	user, err := in.Store.GetUser(ctx, &store.FindUser{
 		ID: &userID,
@@ -68,13 +116,32 @@ func (in *GRPCAuthInterceptor) AuthenticationInterceptor(ctx context.Context, re
 	if user == nil {
 		return nil, status.Errorf(codes.Unauthenticated, "user ID %q not exists in the access token", userID)
 	}
-	if isOnlyForAdminAllowedMethod(serverInfo.FullMethod) && user.Role != store.RoleHost && user.Role != store.RoleAdmin {
+	// The archived users are refused whatever the credentials, including the access tokens created before.
+	if user.RowStatus == store.Archived {
+		return nil, status.Errorf(codes.Unauthenticated, "user ID %q has been deactivated by administrators", userID)
+	}
+	if isOnlyForAdminAllowedMethod(fullMethod) && user.Role != store.RoleHost && user.Role != store.RoleAdmin {
 		return nil, status.Errorf(codes.PermissionDenied, "user ID %q is not admin", userID)
 	}
+	if _, ok := ctx.Value(ScopesContextKey).([]string); !ok {
+		required, err := apiv1.IsTwoFactorEnrollmentRequired(ctx, in.Store, user)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check two-factor authentication: %v", err)
+		}
+		if required {
+			return nil, status.Errorf(codes.PermissionDenied, "two-factor authentication is required, please enable it first")
+		}
+		pending, err := apiv1.IsEmailVerificationPending(ctx, in.Store, user)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check email verification: %v", err)
+		}
+		if pending {
+			return nil, status.Errorf(codes.PermissionDenied, "email address is not verified, please open the link sent to it")
+		}
+	}
 
 	// Stores userID into context.
-	childCtx := context.WithValue(ctx, UserIDContextKey, userID)
-	return handler(childCtx, request)
+	return context.WithValue(ctx, UserIDContextKey, userID), nil
 }
 
 func (in *GRPCAuthInterceptor) authenticate(ctx context.Context, accessTokenStr string) (int32, error) {
@@ -82,17 +149,7 @@ func (in *GRPCAuthInterceptor) authenticate(ctx context.Context, accessTokenStr
 		return 0, status.Errorf(codes.Unauthenticated, "access token not found")
 	}
 	claims := &claimsMessage{}
-	_, err := jwt.ParseWithClaims(accessTokenStr, claims, func(t *jwt.Token) (any, error) {
-		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
-			return nil, status.Errorf(codes.Unauthenticated, "unexpected access token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
-		}
-		if kid, ok := t.Header["kid"].(string); ok {
-			if kid == "v1" {
-				return []byte(in.secret), nil
-			}
-		}
-		return nil, status.Errorf(codes.Unauthenticated, "unexpected access token kid=%v", t.Header["kid"])
-	})
+	_, err := jwt.ParseWithClaims(accessTokenStr, claims, apiv1.SigningKeyFunc(ctx, in.Store))
 	if err != nil {
 		return 0, status.Errorf(codes.Unauthenticated, "Invalid or expired access token")
 	}
@@ -108,6 +165,19 @@ func (in *GRPCAuthInterceptor) authenticate(ctx context.Context, accessTokenStr
 	if err != nil {
 		return 0, status.Errorf(codes.Unauthenticated, "malformed ID %q in the access token", claims.Subject)
 	}
+	sessionID, err := util.ConvertStringToInt32(claims.ID)
+	if err != nil {
+		return 0, status.Errorf(codes.Unauthenticated, "malformed session ID %q in the access token", claims.ID)
+	}
+	session, err := in.Store.GetSession(ctx, &store.FindSession{
+		ID: &sessionID,
+	})
+	if err != nil {
+		return 0, status.Errorf(codes.Unauthenticated, "failed to find session ID %q in the access token", sessionID)
+	}
+	if session == nil || session.UserID != userID || session.ExpiresTs <= time.Now().Unix() {
+		return 0, status.Errorf(codes.Unauthenticated, "session ID %q has been revoked or expired", sessionID)
+	}
 	user, err := in.Store.GetUser(ctx, &store.FindUser{
 		ID: &userID,
 	})
@@ -159,36 +229,3 @@ type claimsMessage struct {
 	Name string `json:"name"`
 	jwt.RegisteredClaims
 }
-
-// GenerateAccessToken generates an access token for web.
-func GenerateAccessToken(username string, userID int, secret string) (string, error) {
-	expirationTime := time.Now().Add(auth.AccessTokenDuration)
-	return generateToken(username, userID, auth.AccessTokenAudienceName, expirationTime, []byte(secret))
-}
-
-func generateToken(username string, userID int, aud string, expirationTime time.Time, secret []byte) (string, error) {
-	// Create the JWT claims, which includes the username and expiry time.
-	claims := &claimsMessage{
-		Name: username,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Audience: jwt.ClaimStrings{aud},
-			// In JWT, the expiry time is expressed as unix milliseconds.
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    auth.Issuer,
-			Subject:   strconv.Itoa(userID),
-		},
-	}
-
-	// Declare the token with the HS256 algorithm used for signing, and the claims.
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token.Header["kid"] = auth.KeyID
-
-	// Create the JWT string.
-	tokenString, err := token.SignedString(secret)
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
-}
@@ -1,11 +1,21 @@
 package v2
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/usememos/memos/api/auth"
+)
 
 var authenticationAllowlistMethods = map[string]bool{
-	"/memos.api.v2.SystemService/GetSystemInfo": true,
-	"/memos.api.v2.UserService/GetUser":         true,
-	"/memos.api.v2.MemoService/ListMemos":       true,
+	"/memos.api.v2.SystemService/GetSystemInfo":      true,
+	"/memos.api.v2.UserService/GetUser":              true,
+	"/memos.api.v2.MemoService/ListMemos":            true,
+	"/memos.api.v2.MemoService/GetMemo":              true,
+	"/memos.api.v2.MemoService/ListMemoResources":    true,
+	"/memos.api.v2.MemoService/ListMemoRelations":    true,
+	"/memos.api.v2.TagService/GetTagTree":            true,
+	"/memos.api.v2.ResourceService/GetResource":      true,
+	"/memos.api.v2.ResourceService/DownloadResource": true,
 }
 
 // isUnauthorizeAllowedMethod returns whether the method is exempted from authentication.
@@ -18,9 +28,30 @@ func isUnauthorizeAllowedMethod(fullMethodName string) bool {
 
 var allowedMethodsOnlyForAdmin = map[string]bool{
 	"/memos.api.v2.UserService/CreateUser": true,
+	"/memos.api.v2.UserService/DeleteUser": true,
 }
 
 // isOnlyForAdminAllowedMethod returns true if the method is allowed to be called only by admin.
 func isOnlyForAdminAllowedMethod(methodName string) bool {
 	return allowedMethodsOnlyForAdmin[methodName]
 }
+
+// getRequiredScope returns the scope of personal access tokens required to call the method.
+// The methods of the services which aren't about memos require the admin scope.
+func getRequiredScope(fullMethodName string) auth.Scope {
+	serviceName, methodName, _ := strings.Cut(strings.TrimPrefix(fullMethodName, "/memos.api.v2."), "/")
+	isRead := strings.HasPrefix(methodName, "Get") || strings.HasPrefix(methodName, "List")
+	switch serviceName {
+	case "MemoService", "TagService", "ShortcutService":
+		if isRead {
+			return auth.ScopeMemoRead
+		}
+		return auth.ScopeMemoWrite
+	case "ResourceService":
+		if isRead || methodName == "DownloadResource" {
+			return auth.ScopeMemoRead
+		}
+		return auth.ScopeResourceUpload
+	}
+	return auth.ScopeAdmin
+}
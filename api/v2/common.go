@@ -1,6 +1,9 @@
 package v2
 
 import (
+	"encoding/base64"
+	"encoding/json"
+
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
 )
@@ -15,3 +18,31 @@ func convertRowStatusFromStore(rowStatus store.RowStatus) apiv2pb.RowStatus {
 		return apiv2pb.RowStatus_ROW_STATUS_UNSPECIFIED
 	}
 }
+
+func convertRowStatusToStore(rowStatus apiv2pb.RowStatus) store.RowStatus {
+	switch rowStatus {
+	case apiv2pb.RowStatus_ACTIVE:
+		return store.Normal
+	case apiv2pb.RowStatus_ARCHIVED:
+		return store.Archived
+	default:
+		return store.Normal
+	}
+}
+
+// marshalPageToken encodes the position of a page into an opaque page token.
+func marshalPageToken(pageToken any) (string, error) {
+	data, err := json.Marshal(pageToken)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func unmarshalPageToken(token string, pageToken any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, pageToken)
+}
@@ -0,0 +1,238 @@
+package v2
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/store"
+	exprv1 "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// memoFilterVariables are the variables of the memo filter, which is a CEL expression such as
+// `tag in ["work", "ideas"] && !pinned && created_ts > 1690000000 && content.contains("meeting")`.
+var memoFilterVariables = map[string]*cel.Type{
+	"creator":      cel.StringType,
+	"tag":          cel.StringType,
+	"visibility":   cel.StringType,
+	"row_status":   cel.StringType,
+	"pinned":       cel.BoolType,
+	"created_ts":   cel.IntType,
+	"updated_ts":   cel.IntType,
+	"content":      cel.StringType,
+	"has_resource": cel.BoolType,
+}
+
+var memoFilterComparisonOperators = map[string]store.MemoFilterOperator{
+	"_==_": store.MemoFilterEqual,
+	"_<_":  store.MemoFilterLess,
+	"_<=_": store.MemoFilterLessOrEqual,
+	"_>_":  store.MemoFilterGreater,
+	"_>=_": store.MemoFilterGreaterOrEqual,
+}
+
+// memoFilterFieldOperators are the operators supported on each field, the others are refused as invalid filters.
+var memoFilterFieldOperators = map[store.MemoFilterField][]store.MemoFilterOperator{
+	store.MemoFilterCreator:     {store.MemoFilterEqual, store.MemoFilterIn},
+	store.MemoFilterTag:         {store.MemoFilterEqual, store.MemoFilterIn},
+	store.MemoFilterVisibility:  {store.MemoFilterEqual, store.MemoFilterIn},
+	store.MemoFilterRowStatus:   {store.MemoFilterEqual, store.MemoFilterIn},
+	store.MemoFilterPinned:      {store.MemoFilterEqual},
+	store.MemoFilterCreatedTs:   {store.MemoFilterEqual, store.MemoFilterIn, store.MemoFilterLess, store.MemoFilterLessOrEqual, store.MemoFilterGreater, store.MemoFilterGreaterOrEqual},
+	store.MemoFilterUpdatedTs:   {store.MemoFilterEqual, store.MemoFilterIn, store.MemoFilterLess, store.MemoFilterLessOrEqual, store.MemoFilterGreater, store.MemoFilterGreaterOrEqual},
+	store.MemoFilterContent:     {store.MemoFilterContains},
+	store.MemoFilterHasResource: {store.MemoFilterEqual},
+}
+
+// memoFilterRowStatuses maps the row statuses in the API to the ones in the store.
+var memoFilterRowStatuses = map[string]store.RowStatus{
+	"ACTIVE":   store.Normal,
+	"ARCHIVED": store.Archived,
+}
+
+// parseMemoFilter parses the CEL filter of memos into the filter of the store.
+func parseMemoFilter(filter string) (*store.MemoFilter, error) {
+	options := []cel.EnvOption{}
+	for name, variableType := range memoFilterVariables {
+		options = append(options, cel.Variable(name, variableType))
+	}
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(filter)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, errors.Errorf("filter must be a boolean expression")
+	}
+	return convertMemoFilterExpr(ast.Expr())
+}
+
+func convertMemoFilterExpr(expr *exprv1.Expr) (*store.MemoFilter, error) {
+	// A boolean variable alone, such as `pinned`.
+	if identExpr := expr.GetIdentExpr(); identExpr != nil {
+		if memoFilterVariables[identExpr.Name] != cel.BoolType {
+			return nil, errors.Errorf("%s isn't a boolean", identExpr.Name)
+		}
+		return &store.MemoFilter{
+			Operator: store.MemoFilterEqual,
+			Field:    store.MemoFilterField(identExpr.Name),
+			Values:   []any{true},
+		}, nil
+	}
+
+	callExpr := expr.GetCallExpr()
+	if callExpr == nil {
+		return nil, errors.Errorf("unsupported expression")
+	}
+	switch callExpr.Function {
+	case "_&&_", "_||_":
+		operator := store.MemoFilterAnd
+		if callExpr.Function == "_||_" {
+			operator = store.MemoFilterOr
+		}
+		filter := &store.MemoFilter{
+			Operator: operator,
+		}
+		for _, arg := range callExpr.Args {
+			child, err := convertMemoFilterExpr(arg)
+			if err != nil {
+				return nil, err
+			}
+			filter.Children = append(filter.Children, child)
+		}
+		return filter, nil
+	case "!_":
+		child, err := convertMemoFilterExpr(callExpr.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &store.MemoFilter{
+			Operator: store.MemoFilterNot,
+			Children: []*store.MemoFilter{child},
+		}, nil
+	case "_!=_":
+		filter, err := convertMemoFilterComparison(store.MemoFilterEqual, callExpr.Args)
+		if err != nil {
+			return nil, err
+		}
+		return &store.MemoFilter{
+			Operator: store.MemoFilterNot,
+			Children: []*store.MemoFilter{filter},
+		}, nil
+	case "@in":
+		field, err := getMemoFilterField(callExpr.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMemoFilterOperator(field, store.MemoFilterIn); err != nil {
+			return nil, err
+		}
+		listExpr := callExpr.Args[1].GetListExpr()
+		if listExpr == nil {
+			return nil, errors.Errorf("in requires a list of constants")
+		}
+		filter := &store.MemoFilter{
+			Operator: store.MemoFilterIn,
+			Field:    field,
+		}
+		for _, element := range listExpr.Elements {
+			value, err := getMemoFilterValue(field, element)
+			if err != nil {
+				return nil, err
+			}
+			filter.Values = append(filter.Values, value)
+		}
+		if len(filter.Values) == 0 {
+			return nil, errors.Errorf("in requires a non-empty list")
+		}
+		return filter, nil
+	case "contains":
+		field, err := getMemoFilterField(callExpr.Target)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMemoFilterOperator(field, store.MemoFilterContains); err != nil {
+			return nil, err
+		}
+		value, err := getMemoFilterValue(field, callExpr.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &store.MemoFilter{
+			Operator: store.MemoFilterContains,
+			Field:    field,
+			Values:   []any{value},
+		}, nil
+	}
+	if operator, ok := memoFilterComparisonOperators[callExpr.Function]; ok {
+		return convertMemoFilterComparison(operator, callExpr.Args)
+	}
+	return nil, errors.Errorf("unsupported function %s", callExpr.Function)
+}
+
+// convertMemoFilterComparison converts the comparison of a variable with a constant, such as `created_ts > 1690000000`.
+func convertMemoFilterComparison(operator store.MemoFilterOperator, args []*exprv1.Expr) (*store.MemoFilter, error) {
+	field, err := getMemoFilterField(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMemoFilterOperator(field, operator); err != nil {
+		return nil, err
+	}
+	value, err := getMemoFilterValue(field, args[1])
+	if err != nil {
+		return nil, err
+	}
+	return &store.MemoFilter{
+		Operator: operator,
+		Field:    field,
+		Values:   []any{value},
+	}, nil
+}
+
+func getMemoFilterField(expr *exprv1.Expr) (store.MemoFilterField, error) {
+	identExpr := expr.GetIdentExpr()
+	if identExpr == nil {
+		return "", errors.Errorf("the left operand must be a variable")
+	}
+	return store.MemoFilterField(identExpr.Name), nil
+}
+
+// checkMemoFilterOperator returns an error unless the operator is supported on the field.
+func checkMemoFilterOperator(field store.MemoFilterField, operator store.MemoFilterOperator) error {
+	for _, supported := range memoFilterFieldOperators[field] {
+		if supported == operator {
+			return nil
+		}
+	}
+	return errors.Errorf("operator %s isn't supported on %s", operator, field)
+}
+
+// getMemoFilterValue returns the constant of the field as the value in the store.
+func getMemoFilterValue(field store.MemoFilterField, expr *exprv1.Expr) (any, error) {
+	constExpr := expr.GetConstExpr()
+	if constExpr == nil {
+		return nil, errors.Errorf("the right operand must be a constant")
+	}
+	switch field {
+	case store.MemoFilterVisibility:
+		visibility := store.Visibility(constExpr.GetStringValue())
+		if visibility != store.Public && visibility != store.Protected && visibility != store.Private {
+			return nil, errors.Errorf("invalid visibility %q", visibility)
+		}
+		return visibility, nil
+	case store.MemoFilterRowStatus:
+		rowStatus, ok := memoFilterRowStatuses[constExpr.GetStringValue()]
+		if !ok {
+			return nil, errors.Errorf("invalid row status %q", constExpr.GetStringValue())
+		}
+		return rowStatus, nil
+	case store.MemoFilterCreatedTs, store.MemoFilterUpdatedTs:
+		return constExpr.GetInt64Value(), nil
+	case store.MemoFilterPinned, store.MemoFilterHasResource:
+		return constExpr.GetBoolValue(), nil
+	default:
+		return constExpr.GetStringValue(), nil
+	}
+}
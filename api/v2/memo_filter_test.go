@@ -0,0 +1,76 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestParseMemoFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   *store.MemoFilter
+	}{
+		{
+			filter: `visibility == "PUBLIC"`,
+			want: &store.MemoFilter{
+				Operator: store.MemoFilterEqual,
+				Field:    store.MemoFilterVisibility,
+				Values:   []any{store.Public},
+			},
+		},
+		{
+			filter: `tag in ["work", "ideas"] && !pinned`,
+			want: &store.MemoFilter{
+				Operator: store.MemoFilterAnd,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterIn, Field: store.MemoFilterTag, Values: []any{"work", "ideas"}},
+					{Operator: store.MemoFilterNot, Children: []*store.MemoFilter{
+						{Operator: store.MemoFilterEqual, Field: store.MemoFilterPinned, Values: []any{true}},
+					}},
+				},
+			},
+		},
+		{
+			filter: `created_ts >= 1690000000 || content.contains("meeting")`,
+			want: &store.MemoFilter{
+				Operator: store.MemoFilterOr,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterGreaterOrEqual, Field: store.MemoFilterCreatedTs, Values: []any{int64(1690000000)}},
+					{Operator: store.MemoFilterContains, Field: store.MemoFilterContent, Values: []any{"meeting"}},
+				},
+			},
+		},
+		{
+			filter: `row_status != "ARCHIVED"`,
+			want: &store.MemoFilter{
+				Operator: store.MemoFilterNot,
+				Children: []*store.MemoFilter{
+					{Operator: store.MemoFilterEqual, Field: store.MemoFilterRowStatus, Values: []any{store.Archived}},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		filter, err := parseMemoFilter(test.filter)
+		require.NoError(t, err, test.filter)
+		require.Equal(t, test.want, filter, test.filter)
+	}
+
+	for _, filter := range []string{
+		`visibility == "SECRET"`,
+		`tag > "work"`,
+		`created_ts`,
+		`unknown == 1`,
+		`created_ts > "yesterday"`,
+		`tag.contains("work")`,
+		`content == "meeting"`,
+		`content in ["meeting"]`,
+		`pinned in [true]`,
+		`has_resource != false && creator > "a"`,
+	} {
+		_, err := parseMemoFilter(filter)
+		require.Error(t, err, filter)
+	}
+}
@@ -2,15 +2,26 @@ package v2
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
-	"github.com/google/cel-go/cel"
-	"github.com/pkg/errors"
+	apiv1 "github.com/usememos/memos/api/v1"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const maxContentLength = 1 << 30
+
+// memoPageToken is the position of the next page of memos, which is opaque to clients.
+type memoPageToken struct {
+	Cursor *store.MemoCursor `json:"cursor,omitempty"`
+	// Offset is used instead of the cursor when the memos are ordered by rank.
+	Offset int `json:"offset,omitempty"`
+}
+
 type MemoService struct {
 	apiv2pb.UnimplementedMemoServiceServer
 
@@ -26,43 +37,315 @@ func NewMemoService(store *store.Store) *MemoService {
 
 func (s *MemoService) ListMemos(ctx context.Context, request *apiv2pb.ListMemosRequest) (*apiv2pb.ListMemosResponse, error) {
 	memoFind := &store.FindMemo{}
-	if request.PageSize != 0 {
-		offset := int(request.Page * request.PageSize)
-		limit := int(request.PageSize)
-		memoFind.Offset = &offset
-		memoFind.Limit = &limit
+	// Anonymous users can only see public memos, while signed-in users can see protected memos and their own private ones as well.
+	if userIDPtr := ctx.Value(UserIDContextKey); userIDPtr == nil {
+		memoFind.VisibilityList = []store.Visibility{store.Public}
+	} else {
+		userID := userIDPtr.(int32)
+		memoFind.ViewerID = &userID
 	}
 	if request.Filter != "" {
-		visibilityString, err := getVisibilityFilter(request.Filter)
+		filter, err := parseMemoFilter(request.Filter)
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
 		}
-		memoFind.VisibilityList = []store.Visibility{store.Visibility(visibilityString)}
+		memoFind.Filter = filter
+	}
+	if request.Search != "" {
+		memoFind.ContentSearch = []string{request.Search}
+		memoFind.OrderByRank = true
+	}
+	total, err := s.Store.CountMemos(ctx, memoFind)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count memos: %v", err)
+	}
+
+	pageToken := &memoPageToken{}
+	if request.PageToken != "" {
+		if err := unmarshalPageToken(request.PageToken, pageToken); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+		// The offset past the last memo is refused, rather than scanning the memos for nothing.
+		if pageToken.Offset < 0 || pageToken.Offset > total {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: offset %d out of range", pageToken.Offset)
+		}
+	}
+	if request.PageSize > 0 {
+		// One more memo is fetched to know whether there is a next page.
+		limit := int(request.PageSize) + 1
+		memoFind.Limit = &limit
+		memoFind.Cursor = pageToken.Cursor
+		memoFind.Offset = &pageToken.Offset
 	}
 	memos, err := s.Store.ListMemos(ctx, memoFind)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+	}
+
+	response := &apiv2pb.ListMemosResponse{
+		Total: int32(total),
+	}
+	if request.PageSize > 0 && len(memos) > int(request.PageSize) {
+		memos = memos[:request.PageSize]
+		nextPageToken := &memoPageToken{}
+		// The relevance isn't a stable position, so ranked memos are paginated by offset.
+		if memoFind.OrderByRank {
+			nextPageToken.Offset = pageToken.Offset + len(memos)
+		} else {
+			nextPageToken.Cursor = store.NewMemoCursor(memos[len(memos)-1], memoFind)
+		}
+		token, err := marshalPageToken(nextPageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal page token: %v", err)
+		}
+		response.NextPageToken = token
+	}
+	for _, memo := range memos {
+		response.Memos = append(response.Memos, convertMemoFromStore(memo))
+	}
+	return response, nil
+}
+
+func (s *MemoService) CreateMemo(ctx context.Context, request *apiv2pb.CreateMemoRequest) (*apiv2pb.CreateMemoResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	if len(request.Content) > maxContentLength {
+		return nil, status.Errorf(codes.InvalidArgument, "content size overflow, up to 1MB")
+	}
+
+	visibility := convertVisibilityToStore(request.Visibility)
+	if request.Visibility == apiv2pb.Visibility_VISIBILITY_UNSPECIFIED {
+		defaultVisibility, err := s.getDefaultMemoVisibility(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		visibility = defaultVisibility
+	}
+	visibility, err := s.enforceMemoVisibility(ctx, userID, visibility)
 	if err != nil {
 		return nil, err
 	}
 
-	memoMessages := make([]*apiv2pb.Memo, len(memos))
-	for i, memo := range memos {
-		memoMessages[i] = convertMemoFromStore(memo)
+	resourceIDs, err := s.findMemoResources(ctx, userID, request.ResourceIds)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.findMemoRelations(ctx, 0, request.Relations)
+	if err != nil {
+		return nil, err
+	}
+	memo, err := s.Store.CreateMemo(ctx, &store.Memo{
+		CreatorID:      userID,
+		Content:        request.Content,
+		Visibility:     visibility,
+		ResourceIDList: resourceIDs,
+		RelationList:   relations,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create memo: %v", err)
 	}
 
-	// TODO(steven): Add privalige checks.
-	response := &apiv2pb.ListMemosResponse{
-		Memos: nil,
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+	}
+
+	response := &apiv2pb.CreateMemoResponse{
+		Memo: convertMemoFromStore(memo),
 	}
 	return response, nil
 }
 
 func (s *MemoService) GetMemo(ctx context.Context, request *apiv2pb.GetMemoRequest) (*apiv2pb.GetMemoResponse, error) {
-	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
-		ID: &request.Id,
+	memo, err := s.getVisibleMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &apiv2pb.GetMemoResponse{
+		Memo: convertMemoFromStore(memo),
+	}
+	return response, nil
+}
+
+func (s *MemoService) UpdateMemo(ctx context.Context, request *apiv2pb.UpdateMemoRequest) (*apiv2pb.UpdateMemoResponse, error) {
+	if request.Memo == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "memo is required")
+	}
+	if request.UpdateMask == nil || len(request.UpdateMask.Paths) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "update mask is required")
+	}
+	memo, err := s.getCurrentUserMemo(ctx, request.Memo.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTs := time.Now().Unix()
+	update := &store.UpdateMemo{
+		ID:        memo.ID,
+		UpdatedTs: &currentTs,
+	}
+	for _, path := range request.UpdateMask.Paths {
+		switch path {
+		case "content":
+			if len(request.Memo.Content) > maxContentLength {
+				return nil, status.Errorf(codes.InvalidArgument, "content size overflow, up to 1MB")
+			}
+			update.Content = &request.Memo.Content
+		case "visibility":
+			visibility, err := s.enforceMemoVisibility(ctx, memo.CreatorID, convertVisibilityToStore(request.Memo.Visibility))
+			if err != nil {
+				return nil, err
+			}
+			update.Visibility = &visibility
+		case "row_status":
+			rowStatus := convertRowStatusToStore(request.Memo.RowStatus)
+			update.RowStatus = &rowStatus
+		case "created_ts":
+			update.CreatedTs = &request.Memo.CreatedTs
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid update path: %s", path)
+		}
+	}
+
+	if err := s.Store.UpdateMemo(ctx, update); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update memo: %v", err)
+	}
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
 	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+	}
+
+	response := &apiv2pb.UpdateMemoResponse{
+		Memo: convertMemoFromStore(memo),
+	}
+	return response, nil
+}
+
+func (s *MemoService) DeleteMemo(ctx context.Context, request *apiv2pb.DeleteMemoRequest) (*apiv2pb.DeleteMemoResponse, error) {
+	memo, err := s.getCurrentUserMemo(ctx, request.Id)
 	if err != nil {
 		return nil, err
 	}
+
+	deletedTs := time.Now().Unix()
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete memo: %v", err)
+	}
+	return &apiv2pb.DeleteMemoResponse{}, nil
+}
+
+func (s *MemoService) ListMemoResources(ctx context.Context, request *apiv2pb.ListMemoResourcesRequest) (*apiv2pb.ListMemoResourcesResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &apiv2pb.ListMemoResourcesResponse{
+		ResourceIds: memo.ResourceIDList,
+	}
+	return response, nil
+}
+
+func (s *MemoService) SetMemoResources(ctx context.Context, request *apiv2pb.SetMemoResourcesRequest) (*apiv2pb.SetMemoResourcesResponse, error) {
+	memo, err := s.getCurrentUserMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setMemoResources(ctx, memo, request.ResourceIds); err != nil {
+		return nil, err
+	}
+	return &apiv2pb.SetMemoResourcesResponse{}, nil
+}
+
+func (s *MemoService) ListMemoRelations(ctx context.Context, request *apiv2pb.ListMemoRelationsRequest) (*apiv2pb.ListMemoRelationsResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &apiv2pb.ListMemoRelationsResponse{}
+	for _, relation := range memo.RelationList {
+		response.Relations = append(response.Relations, convertMemoRelationFromStore(relation))
+	}
+	return response, nil
+}
+
+func (s *MemoService) SetMemoRelations(ctx context.Context, request *apiv2pb.SetMemoRelationsRequest) (*apiv2pb.SetMemoRelationsResponse, error) {
+	memo, err := s.getCurrentUserMemo(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setMemoRelations(ctx, memo, request.Relations); err != nil {
+		return nil, err
+	}
+	return &apiv2pb.SetMemoRelationsResponse{}, nil
+}
+
+func (s *MemoService) ListTrashMemos(ctx context.Context, _ *apiv2pb.ListTrashMemosRequest) (*apiv2pb.ListTrashMemosResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	memos, err := s.Store.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &userID,
+		InTrash:   true,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+	}
+
+	response := &apiv2pb.ListTrashMemosResponse{}
+	for _, memo := range memos {
+		response.Memos = append(response.Memos, convertMemoFromStore(memo))
+	}
+	return response, nil
+}
+
+func (s *MemoService) RestoreMemo(ctx context.Context, request *apiv2pb.RestoreMemoRequest) (*apiv2pb.RestoreMemoResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+		ID:      &request.Id,
+		InTrash: true,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+	}
+	if memo == nil || memo.CreatorID != userID {
+		return nil, status.Errorf(codes.NotFound, "memo not found in trash")
+	}
+
+	deletedTs := int64(0)
+	if err := s.Store.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to restore memo: %v", err)
+	}
+	memo, err = s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+	}
+
+	response := &apiv2pb.RestoreMemoResponse{
+		Memo: convertMemoFromStore(memo),
+	}
+	return response, nil
+}
+
+// getVisibleMemo returns the memo if it's visible to the current user.
+func (s *MemoService) getVisibleMemo(ctx context.Context, id int32) (*store.Memo, error) {
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
+	}
 	if memo == nil {
 		return nil, status.Errorf(codes.NotFound, "memo not found")
 	}
@@ -76,50 +359,197 @@ func (s *MemoService) GetMemo(ctx context.Context, request *apiv2pb.GetMemoReque
 			return nil, status.Errorf(codes.PermissionDenied, "permission denied")
 		}
 	}
+	return memo, nil
+}
 
-	response := &apiv2pb.GetMemoResponse{
-		Memo: convertMemoFromStore(memo),
+// getCurrentUserMemo returns the memo if it's created by the current user, as only the creator can change a memo.
+func (s *MemoService) getCurrentUserMemo(ctx context.Context, id int32) (*store.Memo, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get memo: %v", err)
 	}
-	return response, nil
+	if memo == nil {
+		return nil, status.Errorf(codes.NotFound, "memo not found")
+	}
+	if memo.CreatorID != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	return memo, nil
 }
 
-// getVisibilityFilter will parse the simple filter such as `visibility = "PRIVATE"` to "PRIVATE" .
-func getVisibilityFilter(filter string) (string, error) {
-	formatInvalidErr := errors.Errorf("invalid filter %q", filter)
-	e, err := cel.NewEnv(cel.Variable("visibility", cel.StringType))
+// getDefaultMemoVisibility returns the memo visibility in the settings of the user, or private if it isn't set.
+func (s *MemoService) getDefaultMemoVisibility(ctx context.Context, userID int32) (store.Visibility, error) {
+	userSetting, err := s.Store.GetUserSetting(ctx, &store.FindUserSetting{
+		UserID: &userID,
+		Key:    apiv1.UserSettingMemoVisibilityKey.String(),
+	})
 	if err != nil {
-		return "", err
+		return "", status.Errorf(codes.Internal, "failed to get user setting: %v", err)
 	}
-	ast, issues := e.Compile(filter)
-	if issues != nil {
-		return "", status.Errorf(codes.InvalidArgument, issues.String())
+	if userSetting == nil {
+		return store.Private, nil
 	}
-	expr := ast.Expr()
-	if expr == nil {
-		return "", formatInvalidErr
+	visibility := store.Private
+	if err := json.Unmarshal([]byte(userSetting.Value), &visibility); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to unmarshal user setting: %v", err)
 	}
-	callExpr := expr.GetCallExpr()
-	if callExpr == nil {
-		return "", formatInvalidErr
+	return visibility, nil
+}
+
+// enforceMemoVisibility returns private instead of the visibility for normal users if public memos are disabled.
+func (s *MemoService) enforceMemoVisibility(ctx context.Context, userID int32, visibility store.Visibility) (store.Visibility, error) {
+	systemSetting, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: apiv1.SystemSettingDisablePublicMemosName.String(),
+	})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get system setting: %v", err)
+	}
+	if systemSetting == nil {
+		return visibility, nil
+	}
+	disablePublicMemos := false
+	if err := json.Unmarshal([]byte(systemSetting.Value), &disablePublicMemos); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to unmarshal system setting: %v", err)
+	}
+	if !disablePublicMemos {
+		return visibility, nil
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return "", status.Errorf(codes.NotFound, "user not found")
+	}
+	if user.Role == store.RoleUser {
+		return store.Private, nil
+	}
+	return visibility, nil
+}
+
+// findMemoResources returns the distinct resources, which must belong to the creator of the memo.
+func (s *MemoService) findMemoResources(ctx context.Context, creatorID int32, resourceIDs []int32) ([]int32, error) {
+	resourceIDSet := map[int32]bool{}
+	list := []int32{}
+	for _, resourceID := range resourceIDs {
+		if resourceIDSet[resourceID] {
+			continue
+		}
+		resourceID := resourceID
+		resource, err := s.Store.GetResource(ctx, &store.FindResource{
+			ID: &resourceID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get resource: %v", err)
+		}
+		if resource == nil || resource.CreatorID != creatorID {
+			return nil, status.Errorf(codes.InvalidArgument, "resource %d not found", resourceID)
+		}
+		resourceIDSet[resourceID] = true
+		list = append(list, resourceID)
+	}
+	return list, nil
+}
+
+// findMemoRelations returns the distinct relations from the memo, whose related memos must be visible to the current user.
+// The memo ID is 0 for the memos being created.
+func (s *MemoService) findMemoRelations(ctx context.Context, memoID int32, relations []*apiv2pb.MemoRelation) ([]*store.MemoRelation, error) {
+	relationSet := map[string]bool{}
+	list := []*store.MemoRelation{}
+	for _, relation := range relations {
+		if memoID != 0 && relation.RelatedMemoId == memoID {
+			return nil, status.Errorf(codes.InvalidArgument, "memo can't relate to itself")
+		}
+		relationType := convertMemoRelationTypeToStore(relation.Type)
+		key := fmt.Sprintf("%d-%s", relation.RelatedMemoId, relationType)
+		if relationSet[key] {
+			continue
+		}
+		// The memos which aren't visible are not found, so that whether they exist isn't told.
+		if _, err := s.getVisibleMemo(ctx, relation.RelatedMemoId); err != nil {
+			if status.Code(err) == codes.Internal {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "related memo %d not found", relation.RelatedMemoId)
+		}
+		relationSet[key] = true
+		list = append(list, &store.MemoRelation{
+			MemoID:        memoID,
+			RelatedMemoID: relation.RelatedMemoId,
+			Type:          relationType,
+		})
+	}
+	return list, nil
+}
+
+// setMemoResources replaces the resources of the memo, which must belong to the creator of the memo.
+func (s *MemoService) setMemoResources(ctx context.Context, memo *store.Memo, resourceIDs []int32) error {
+	resourceIDs, err := s.findMemoResources(ctx, memo.CreatorID, resourceIDs)
+	if err != nil {
+		return err
 	}
-	if callExpr.Function != "_==_" {
-		return "", formatInvalidErr
+	resourceIDSet := map[int32]bool{}
+	for _, resourceID := range resourceIDs {
+		resourceIDSet[resourceID] = true
+		if _, err := s.Store.UpsertMemoResource(ctx, &store.UpsertMemoResource{
+			MemoID:     memo.ID,
+			ResourceID: resourceID,
+			CreatedTs:  time.Now().Unix(),
+		}); err != nil {
+			return status.Errorf(codes.Internal, "failed to upsert memo resource: %v", err)
+		}
 	}
-	if len(callExpr.Args) != 2 {
-		return "", formatInvalidErr
+	for _, resourceID := range memo.ResourceIDList {
+		if resourceIDSet[resourceID] {
+			continue
+		}
+		resourceID := resourceID
+		if err := s.Store.DeleteMemoResource(ctx, &store.DeleteMemoResource{
+			MemoID:     &memo.ID,
+			ResourceID: &resourceID,
+		}); err != nil {
+			return status.Errorf(codes.Internal, "failed to delete memo resource: %v", err)
+		}
 	}
-	if callExpr.Args[0].GetIdentExpr() == nil || callExpr.Args[0].GetIdentExpr().Name != "visibility" {
-		return "", formatInvalidErr
+	return nil
+}
+
+// setMemoRelations replaces the relations from the memo to other memos.
+func (s *MemoService) setMemoRelations(ctx context.Context, memo *store.Memo, relations []*apiv2pb.MemoRelation) error {
+	relationList, err := s.findMemoRelations(ctx, memo.ID, relations)
+	if err != nil {
+		return err
 	}
-	constExpr := callExpr.Args[1].GetConstExpr()
-	if constExpr == nil {
-		return "", formatInvalidErr
+	relationSet := map[string]bool{}
+	for _, relation := range relationList {
+		relationSet[fmt.Sprintf("%d-%s", relation.RelatedMemoID, relation.Type)] = true
+		if _, err := s.Store.UpsertMemoRelation(ctx, relation); err != nil {
+			return status.Errorf(codes.Internal, "failed to upsert memo relation: %v", err)
+		}
+	}
+	for _, relation := range memo.RelationList {
+		if relationSet[fmt.Sprintf("%d-%s", relation.RelatedMemoID, relation.Type)] {
+			continue
+		}
+		relation := relation
+		if err := s.Store.DeleteMemoRelation(ctx, &store.DeleteMemoRelation{
+			MemoID:        &memo.ID,
+			RelatedMemoID: &relation.RelatedMemoID,
+			Type:          &relation.Type,
+		}); err != nil {
+			return status.Errorf(codes.Internal, "failed to delete memo relation: %v", err)
+		}
 	}
-	return constExpr.GetStringValue(), nil
+	return nil
 }
 
 func convertMemoFromStore(memo *store.Memo) *apiv2pb.Memo {
-	return &apiv2pb.Memo{
+	memoMessage := &apiv2pb.Memo{
 		Id:         int32(memo.ID),
 		RowStatus:  convertRowStatusFromStore(memo.RowStatus),
 		CreatedTs:  memo.CreatedTs,
@@ -128,6 +558,37 @@ func convertMemoFromStore(memo *store.Memo) *apiv2pb.Memo {
 		Content:    memo.Content,
 		Visibility: convertVisibilityFromStore(memo.Visibility),
 		Pinned:     memo.Pinned,
+		Snippet:    memo.Snippet,
+		DeletedTs:  memo.DeletedTs,
+	}
+	memoMessage.ResourceIds = memo.ResourceIDList
+	for _, relation := range memo.RelationList {
+		memoMessage.Relations = append(memoMessage.Relations, convertMemoRelationFromStore(relation))
+	}
+	return memoMessage
+}
+
+func convertMemoRelationFromStore(relation *store.MemoRelation) *apiv2pb.MemoRelation {
+	relationType := apiv2pb.MemoRelation_TYPE_UNSPECIFIED
+	switch relation.Type {
+	case store.MemoRelationReference:
+		relationType = apiv2pb.MemoRelation_REFERENCE
+	case store.MemoRelationAdditional:
+		relationType = apiv2pb.MemoRelation_ADDITIONAL
+	}
+	return &apiv2pb.MemoRelation{
+		MemoId:        relation.MemoID,
+		RelatedMemoId: relation.RelatedMemoID,
+		Type:          relationType,
+	}
+}
+
+func convertMemoRelationTypeToStore(relationType apiv2pb.MemoRelation_Type) store.MemoRelationType {
+	switch relationType {
+	case apiv2pb.MemoRelation_ADDITIONAL:
+		return store.MemoRelationAdditional
+	default:
+		return store.MemoRelationReference
 	}
 }
 
@@ -143,3 +604,14 @@ func convertVisibilityFromStore(visibility store.Visibility) apiv2pb.Visibility
 		return apiv2pb.Visibility_VISIBILITY_UNSPECIFIED
 	}
 }
+
+func convertVisibilityToStore(visibility apiv2pb.Visibility) store.Visibility {
+	switch visibility {
+	case apiv2pb.Visibility_PUBLIC:
+		return store.Public
+	case apiv2pb.Visibility_PROTECTED:
+		return store.Protected
+	default:
+		return store.Private
+	}
+}
@@ -0,0 +1,307 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resourceChunkSize is the size of the chunks of resource content in download streams.
+const resourceChunkSize = 64 << 10
+
+type ResourceService struct {
+	apiv2pb.UnimplementedResourceServiceServer
+
+	Store *store.Store
+}
+
+// NewResourceService creates a new ResourceService.
+func NewResourceService(store *store.Store) *ResourceService {
+	return &ResourceService{
+		Store: store,
+	}
+}
+
+func (s *ResourceService) ListResources(ctx context.Context, _ *apiv2pb.ListResourcesRequest) (*apiv2pb.ListResourcesResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	resources, err := s.Store.ListResources(ctx, &store.FindResource{
+		CreatorID: &userID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list resources: %v", err)
+	}
+
+	response := &apiv2pb.ListResourcesResponse{}
+	for _, resource := range resources {
+		response.Resources = append(response.Resources, convertResourceFromStore(resource))
+	}
+	return response, nil
+}
+
+func (s *ResourceService) GetResource(ctx context.Context, request *apiv2pb.GetResourceRequest) (*apiv2pb.GetResourceResponse, error) {
+	resource, err := s.getVisibleResource(ctx, request.Id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &apiv2pb.GetResourceResponse{
+		Resource: convertResourceFromStore(resource),
+	}
+	return response, nil
+}
+
+func (s *ResourceService) UpdateResource(ctx context.Context, request *apiv2pb.UpdateResourceRequest) (*apiv2pb.UpdateResourceResponse, error) {
+	if request.Resource == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "resource is required")
+	}
+	if request.UpdateMask == nil || len(request.UpdateMask.Paths) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "update mask is required")
+	}
+	resource, err := s.getCurrentUserResource(ctx, request.Resource.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTs := time.Now().Unix()
+	update := &store.UpdateResource{
+		ID:        resource.ID,
+		UpdatedTs: &currentTs,
+	}
+	for _, path := range request.UpdateMask.Paths {
+		switch path {
+		case "filename":
+			if err := validateResourceFilename(request.Resource.Filename); err != nil {
+				return nil, err
+			}
+			update.Filename = &request.Resource.Filename
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid update path: %s", path)
+		}
+	}
+
+	resource, err = s.Store.UpdateResource(ctx, update)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update resource: %v", err)
+	}
+
+	response := &apiv2pb.UpdateResourceResponse{
+		Resource: convertResourceFromStore(resource),
+	}
+	return response, nil
+}
+
+func (s *ResourceService) DeleteResource(ctx context.Context, request *apiv2pb.DeleteResourceRequest) (*apiv2pb.DeleteResourceResponse, error) {
+	resource, err := s.getCurrentUserResource(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiv1.DeleteResourceFiles(s.Store, resource)
+	if err := s.Store.DeleteResource(ctx, &store.DeleteResource{
+		ID: resource.ID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete resource: %v", err)
+	}
+	return &apiv2pb.DeleteResourceResponse{}, nil
+}
+
+func (s *ResourceService) UploadResource(stream apiv2pb.ResourceService_UploadResourceServer) error {
+	ctx := stream.Context()
+	userID := ctx.Value(UserIDContextKey).(int32)
+	request, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to receive metadata: %v", err)
+	}
+	metadata := request.GetMetadata()
+	if metadata == nil {
+		return status.Errorf(codes.InvalidArgument, "the first message must be the metadata")
+	}
+	if err := validateResourceFilename(metadata.Filename); err != nil {
+		return err
+	}
+
+	maxUploadSetting := s.Store.GetSystemSettingValueWithDefault(&ctx, apiv1.SystemSettingMaxUploadSizeMiBName.String(), "32")
+	maxUploadSizeMiB, err := strconv.Atoi(maxUploadSetting)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to parse max upload size: %v", err)
+	}
+	reader := &uploadResourceReader{
+		stream:  stream,
+		maxSize: int64(maxUploadSizeMiB) * apiv1.MebiByte,
+	}
+	create := &store.Resource{
+		CreatorID: userID,
+		Filename:  metadata.Filename,
+		Type:      metadata.Type,
+	}
+	// The content is passed to the storage as it's received, so it's never buffered whole except for the database storage.
+	if err := apiv1.SaveResourceBlob(ctx, s.Store, create, reader); err != nil {
+		if reader.size > reader.maxSize {
+			return status.Errorf(codes.InvalidArgument, "file size exceeds allowed limit of %d MiB", maxUploadSizeMiB)
+		}
+		return status.Errorf(codes.Internal, "failed to save resource: %v", err)
+	}
+	create.Size = reader.size
+
+	resource, err := s.Store.CreateResource(ctx, create)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create resource: %v", err)
+	}
+	return stream.SendAndClose(&apiv2pb.UploadResourceResponse{
+		Resource: convertResourceFromStore(resource),
+	})
+}
+
+func (s *ResourceService) DownloadResource(request *apiv2pb.DownloadResourceRequest, stream apiv2pb.ResourceService_DownloadResourceServer) error {
+	resource, err := s.getVisibleResource(stream.Context(), request.Id, true)
+	if err != nil {
+		return err
+	}
+	if resource.ExternalLink != "" {
+		return status.Errorf(codes.FailedPrecondition, "resource is stored externally at %s", resource.ExternalLink)
+	}
+
+	var reader io.Reader
+	if resource.InternalPath != "" {
+		file, err := os.Open(resource.InternalPath)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to open resource file: %v", err)
+		}
+		defer file.Close()
+		reader = file
+	} else {
+		reader = bytes.NewReader(resource.Blob)
+	}
+
+	if err := stream.Send(&apiv2pb.DownloadResourceResponse{
+		Resource: convertResourceFromStore(resource),
+	}); err != nil {
+		return err
+	}
+	buf := make([]byte, resourceChunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&apiv2pb.DownloadResourceResponse{
+				Chunk: buf[:n],
+			}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read resource: %v", err)
+		}
+	}
+}
+
+// getVisibleResource returns the resource if it's visible to the current user, following the visibility of its memos.
+func (s *ResourceService) getVisibleResource(ctx context.Context, id int32, getBlob bool) (*store.Resource, error) {
+	resource, err := s.Store.GetResource(ctx, &store.FindResource{
+		ID:      &id,
+		GetBlob: getBlob,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get resource: %v", err)
+	}
+	if resource == nil {
+		return nil, status.Errorf(codes.NotFound, "resource not found")
+	}
+
+	visibility, err := apiv1.CheckResourceVisibility(ctx, s.Store, resource.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check resource visibility: %v", err)
+	}
+	if visibility != store.Public {
+		userIDPtr := ctx.Value(UserIDContextKey)
+		if userIDPtr == nil {
+			return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+		}
+		if visibility == store.Private && resource.CreatorID != userIDPtr.(int32) {
+			return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+		}
+	}
+	return resource, nil
+}
+
+// getCurrentUserResource returns the resource if it's created by the current user.
+func (s *ResourceService) getCurrentUserResource(ctx context.Context, id int32) (*store.Resource, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	resource, err := s.Store.GetResource(ctx, &store.FindResource{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get resource: %v", err)
+	}
+	if resource == nil || resource.CreatorID != userID {
+		return nil, status.Errorf(codes.NotFound, "resource not found")
+	}
+	return resource, nil
+}
+
+// validateResourceFilename returns an error unless the filename is a single path element, as the local storage
+// puts it in the path of the file.
+func validateResourceFilename(filename string) error {
+	if filename == "" {
+		return status.Errorf(codes.InvalidArgument, "resource filename is required")
+	}
+	if strings.ContainsAny(filename, `/\`) || strings.Contains(filename, "..") {
+		return status.Errorf(codes.InvalidArgument, "invalid resource filename %q", filename)
+	}
+	return nil
+}
+
+// uploadResourceReader reads the content of a resource from the chunks of an upload stream.
+type uploadResourceReader struct {
+	stream  apiv2pb.ResourceService_UploadResourceServer
+	chunk   []byte
+	size    int64
+	maxSize int64
+}
+
+func (r *uploadResourceReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		request, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if request.GetMetadata() != nil {
+			return 0, fmt.Errorf("unexpected metadata after the first message")
+		}
+		r.chunk = request.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	r.size += int64(n)
+	if r.size > r.maxSize {
+		return n, fmt.Errorf("file size exceeds the limit of %d bytes", r.maxSize)
+	}
+	return n, nil
+}
+
+func convertResourceFromStore(resource *store.Resource) *apiv2pb.Resource {
+	return &apiv2pb.Resource{
+		Id:           resource.ID,
+		CreatorId:    resource.CreatorID,
+		CreatedTs:    resource.CreatedTs,
+		UpdatedTs:    resource.UpdatedTs,
+		Filename:     resource.Filename,
+		ExternalLink: resource.ExternalLink,
+		Type:         resource.Type,
+		Size:         resource.Size,
+	}
+}
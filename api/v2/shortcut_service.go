@@ -0,0 +1,221 @@
+package v2
+
+import (
+	"context"
+	"time"
+
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ShortcutService struct {
+	apiv2pb.UnimplementedShortcutServiceServer
+
+	Store *store.Store
+}
+
+// NewShortcutService creates a new ShortcutService.
+func NewShortcutService(store *store.Store) *ShortcutService {
+	return &ShortcutService{
+		Store: store,
+	}
+}
+
+func (s *ShortcutService) ListShortcuts(ctx context.Context, _ *apiv2pb.ListShortcutsRequest) (*apiv2pb.ListShortcutsResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	shortcuts, err := s.Store.ListShortcuts(ctx, &store.FindShortcut{
+		CreatorID: &userID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list shortcuts: %v", err)
+	}
+
+	response := &apiv2pb.ListShortcutsResponse{}
+	for _, shortcut := range shortcuts {
+		response.Shortcuts = append(response.Shortcuts, convertShortcutFromStore(shortcut))
+	}
+	return response, nil
+}
+
+func (s *ShortcutService) GetShortcut(ctx context.Context, request *apiv2pb.GetShortcutRequest) (*apiv2pb.GetShortcutResponse, error) {
+	shortcut, err := s.getCurrentUserShortcut(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &apiv2pb.GetShortcutResponse{
+		Shortcut: convertShortcutFromStore(shortcut),
+	}
+	return response, nil
+}
+
+func (s *ShortcutService) CreateShortcut(ctx context.Context, request *apiv2pb.CreateShortcutRequest) (*apiv2pb.CreateShortcutResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	if request.Shortcut == nil || request.Shortcut.Title == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "shortcut title is required")
+	}
+
+	shortcut, err := s.Store.CreateShortcut(ctx, &store.Shortcut{
+		CreatorID: userID,
+		Title:     request.Shortcut.Title,
+		Payload:   convertShortcutFilterToStore(request.Shortcut.Filter),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create shortcut: %v", err)
+	}
+
+	response := &apiv2pb.CreateShortcutResponse{
+		Shortcut: convertShortcutFromStore(shortcut),
+	}
+	return response, nil
+}
+
+func (s *ShortcutService) UpdateShortcut(ctx context.Context, request *apiv2pb.UpdateShortcutRequest) (*apiv2pb.UpdateShortcutResponse, error) {
+	if request.Shortcut == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "shortcut is required")
+	}
+	if request.UpdateMask == nil || len(request.UpdateMask.Paths) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "update mask is required")
+	}
+	shortcut, err := s.getCurrentUserShortcut(ctx, request.Shortcut.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTs := time.Now().Unix()
+	update := &store.UpdateShortcut{
+		ID:        shortcut.ID,
+		UpdatedTs: &currentTs,
+	}
+	for _, path := range request.UpdateMask.Paths {
+		switch path {
+		case "row_status":
+			rowStatus := convertRowStatusToStore(request.Shortcut.RowStatus)
+			update.RowStatus = &rowStatus
+		case "title":
+			if request.Shortcut.Title == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "shortcut title is required")
+			}
+			update.Title = &request.Shortcut.Title
+		case "filter":
+			update.Payload = convertShortcutFilterToStore(request.Shortcut.Filter)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid update path: %s", path)
+		}
+	}
+
+	shortcut, err = s.Store.UpdateShortcut(ctx, update)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update shortcut: %v", err)
+	}
+
+	response := &apiv2pb.UpdateShortcutResponse{
+		Shortcut: convertShortcutFromStore(shortcut),
+	}
+	return response, nil
+}
+
+func (s *ShortcutService) DeleteShortcut(ctx context.Context, request *apiv2pb.DeleteShortcutRequest) (*apiv2pb.DeleteShortcutResponse, error) {
+	shortcut, err := s.getCurrentUserShortcut(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Store.DeleteShortcut(ctx, &store.DeleteShortcut{
+		ID: shortcut.ID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete shortcut: %v", err)
+	}
+	return &apiv2pb.DeleteShortcutResponse{}, nil
+}
+
+func (s *ShortcutService) ListShortcutMemos(ctx context.Context, request *apiv2pb.ListShortcutMemosRequest) (*apiv2pb.ListShortcutMemosResponse, error) {
+	shortcut, err := s.getCurrentUserShortcut(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	memoFind := shortcut.Payload.FindMemo(shortcut.CreatorID)
+	if request.PageSize != 0 {
+		offset := int(request.Page * request.PageSize)
+		limit := int(request.PageSize)
+		memoFind.Offset = &offset
+		memoFind.Limit = &limit
+	}
+	memos, err := s.Store.ListMemos(ctx, memoFind)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
+	}
+
+	response := &apiv2pb.ListShortcutMemosResponse{}
+	for _, memo := range memos {
+		response.Memos = append(response.Memos, convertMemoFromStore(memo))
+	}
+	return response, nil
+}
+
+// getCurrentUserShortcut returns the shortcut with id, which must belong to the current user.
+func (s *ShortcutService) getCurrentUserShortcut(ctx context.Context, id int32) (*store.Shortcut, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	shortcut, err := s.Store.GetShortcut(ctx, &store.FindShortcut{
+		ID: &id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get shortcut: %v", err)
+	}
+	// Shortcuts are private, so others' shortcuts are reported as not found.
+	if shortcut == nil || shortcut.CreatorID != userID {
+		return nil, status.Errorf(codes.NotFound, "shortcut not found")
+	}
+	return shortcut, nil
+}
+
+func convertShortcutFromStore(shortcut *store.Shortcut) *apiv2pb.Shortcut {
+	filter := &apiv2pb.ShortcutFilter{
+		Tags:   shortcut.Payload.Tags,
+		Text:   shortcut.Payload.Text,
+		Pinned: shortcut.Payload.Pinned,
+	}
+	for _, visibility := range shortcut.Payload.VisibilityList {
+		filter.Visibilities = append(filter.Visibilities, convertVisibilityFromStore(visibility))
+	}
+	if shortcut.Payload.CreatedTsAfter != nil {
+		filter.CreatedTsAfter = *shortcut.Payload.CreatedTsAfter
+	}
+	if shortcut.Payload.CreatedTsBefore != nil {
+		filter.CreatedTsBefore = *shortcut.Payload.CreatedTsBefore
+	}
+
+	return &apiv2pb.Shortcut{
+		Id:        shortcut.ID,
+		RowStatus: convertRowStatusFromStore(shortcut.RowStatus),
+		CreatorId: shortcut.CreatorID,
+		CreatedTs: shortcut.CreatedTs,
+		UpdatedTs: shortcut.UpdatedTs,
+		Title:     shortcut.Title,
+		Filter:    filter,
+	}
+}
+
+func convertShortcutFilterToStore(filter *apiv2pb.ShortcutFilter) *store.ShortcutPayload {
+	payload := &store.ShortcutPayload{}
+	if filter == nil {
+		return payload
+	}
+
+	payload.Tags = filter.Tags
+	payload.Text = filter.Text
+	payload.Pinned = filter.Pinned
+	for _, visibility := range filter.Visibilities {
+		payload.VisibilityList = append(payload.VisibilityList, convertVisibilityToStore(visibility))
+	}
+	if filter.CreatedTsAfter != 0 {
+		payload.CreatedTsAfter = &filter.CreatedTsAfter
+	}
+	if filter.CreatedTsBefore != 0 {
+		payload.CreatedTsBefore = &filter.CreatedTsBefore
+	}
+	return payload
+}
@@ -39,7 +39,8 @@ func (s *SystemService) GetSystemInfo(ctx context.Context, _ *apiv2pb.GetSystemI
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
 		}
-		if user != nil && user.Role == store.RoleHost {
+		// The database size is only known for the sqlite file.
+		if user != nil && user.Role == store.RoleHost && s.Profile.Driver == "sqlite" {
 			fi, err := os.Stat(s.Profile.DSN)
 			if err != nil {
 				return nil, status.Errorf(codes.Internal, "failed to get file info: %v", err)
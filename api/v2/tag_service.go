@@ -3,6 +3,7 @@ package v2
 import (
 	"context"
 
+	apiv1 "github.com/usememos/memos/api/v1"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
 	"google.golang.org/grpc/codes"
@@ -38,6 +39,95 @@ func (s *TagService) ListTags(ctx context.Context, request *apiv2pb.ListTagsRequ
 	return response, nil
 }
 
+func (s *TagService) GetTagTree(ctx context.Context, request *apiv2pb.GetTagTreeRequest) (*apiv2pb.GetTagTreeResponse, error) {
+	normalStatus := store.Normal
+	find := &store.FindMemoTag{
+		CreatorID: &request.CreatorId,
+		RowStatus: &normalStatus,
+	}
+	// Only the memos visible to the current user are counted.
+	userIDPtr := ctx.Value(UserIDContextKey)
+	if userIDPtr == nil {
+		find.VisibilityList = []store.Visibility{store.Public}
+	} else if userIDPtr.(int32) != request.CreatorId {
+		find.VisibilityList = []store.Visibility{store.Public, store.Protected}
+	}
+	nodes, err := s.Store.ListTagTree(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list tag tree: %v", err)
+	}
+
+	response := &apiv2pb.GetTagTreeResponse{}
+	for _, node := range nodes {
+		response.Nodes = append(response.Nodes, convertTagNodeFromStore(node))
+	}
+	return response, nil
+}
+
+func (s *TagService) RenameTag(ctx context.Context, request *apiv2pb.RenameTagRequest) (*apiv2pb.RenameTagResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	for _, name := range []string{request.Name, request.NewName} {
+		if !apiv1.IsValidTagName(name) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid tag name: %s", name)
+		}
+	}
+
+	count, err := apiv1.RenameTags(ctx, s.Store, &store.RenameTag{
+		CreatorID: userID,
+		NameList:  []string{request.Name},
+		NewName:   request.NewName,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rename tag: %v", err)
+	}
+
+	response := &apiv2pb.RenameTagResponse{
+		MemoCount: int32(count),
+	}
+	return response, nil
+}
+
+func (s *TagService) MergeTags(ctx context.Context, request *apiv2pb.MergeTagsRequest) (*apiv2pb.MergeTagsResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	if len(request.Names) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "names are required")
+	}
+	for _, name := range request.Names {
+		if !apiv1.IsValidTagName(name) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid tag name: %s", name)
+		}
+	}
+	if !apiv1.IsValidTagName(request.NewName) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid tag name: %s", request.NewName)
+	}
+
+	count, err := apiv1.RenameTags(ctx, s.Store, &store.RenameTag{
+		CreatorID: userID,
+		NameList:  request.Names,
+		NewName:   request.NewName,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to merge tags: %v", err)
+	}
+
+	response := &apiv2pb.MergeTagsResponse{
+		MemoCount: int32(count),
+	}
+	return response, nil
+}
+
+func convertTagNodeFromStore(node *store.TagNode) *apiv2pb.TagNode {
+	tagNode := &apiv2pb.TagNode{
+		Name:      node.Name,
+		Path:      node.Path,
+		MemoCount: int32(node.MemoCount),
+	}
+	for _, child := range node.Children {
+		tagNode.Children = append(tagNode.Children, convertTagNodeFromStore(child))
+	}
+	return tagNode
+}
+
 func convertTagFromStore(tag *store.Tag) *apiv2pb.Tag {
 	return &apiv2pb.Tag{
 		Name:      tag.Name,
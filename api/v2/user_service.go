@@ -2,9 +2,14 @@ package v2
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/common/util"
 	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
 	"github.com/usememos/memos/store"
+	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -22,6 +27,31 @@ func NewUserService(store *store.Store) *UserService {
 	}
 }
 
+func (s *UserService) ListUsers(ctx context.Context, _ *apiv2pb.ListUsersRequest) (*apiv2pb.ListUsersResponse, error) {
+	currentUser, err := s.getCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.Store.ListUsers(ctx, &store.FindUser{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+
+	response := &apiv2pb.ListUsersResponse{}
+	for _, user := range users {
+		userMessage := convertUserFromStore(user)
+		// Data desensitization, only admins can see the emails of other users.
+		if user.ID != currentUser.ID {
+			userMessage.OpenId = ""
+			if !isAdmin(currentUser) {
+				userMessage.Email = ""
+			}
+		}
+		response.Users = append(response.Users, userMessage)
+	}
+	return response, nil
+}
+
 func (s *UserService) GetUser(ctx context.Context, request *apiv2pb.GetUserRequest) (*apiv2pb.GetUserResponse, error) {
 	user, err := s.Store.GetUser(ctx, &store.FindUser{
 		Username: &request.Name,
@@ -43,6 +73,277 @@ func (s *UserService) GetUser(ctx context.Context, request *apiv2pb.GetUserReque
 	return response, nil
 }
 
+func (s *UserService) CreateUser(ctx context.Context, request *apiv2pb.CreateUserRequest) (*apiv2pb.CreateUserResponse, error) {
+	if request.User == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user is required")
+	}
+	currentUser, err := s.getCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the host can create users, as in API v1.
+	if currentUser.Role != store.RoleHost {
+		return nil, status.Errorf(codes.PermissionDenied, "only host can create users")
+	}
+	role := convertUserRoleToStore(request.User.Role)
+	if role == store.RoleHost {
+		return nil, status.Errorf(codes.PermissionDenied, "could not create host user")
+	}
+	create := apiv1.CreateUserRequest{
+		Username: request.User.Username,
+		Role:     apiv1.Role(role),
+		Email:    request.User.Email,
+		Nickname: request.User.Nickname,
+		Password: request.User.Password,
+	}
+	if err := create.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user: %v", err)
+	}
+	if err := s.checkUsernameAvailable(ctx, create.Username); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(create.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate password hash: %v", err)
+	}
+	user, err := s.Store.CreateUser(ctx, &store.User{
+		Username:     create.Username,
+		Role:         role,
+		Email:        create.Email,
+		Nickname:     create.Nickname,
+		PasswordHash: string(passwordHash),
+		OpenID:       util.GenUUID(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+	}
+
+	userMessage := convertUserFromStore(user)
+	userMessage.OpenId = ""
+	response := &apiv2pb.CreateUserResponse{
+		User: userMessage,
+	}
+	return response, nil
+}
+
+func (s *UserService) UpdateUser(ctx context.Context, request *apiv2pb.UpdateUserRequest) (*apiv2pb.UpdateUserResponse, error) {
+	if request.User == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user is required")
+	}
+	if request.UpdateMask == nil || len(request.UpdateMask.Paths) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "update mask is required")
+	}
+	currentUser, err := s.getCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &request.User.Id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+	if !canManageUser(currentUser, user) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	currentTs := time.Now().Unix()
+	update := &store.UpdateUser{
+		ID:        user.ID,
+		UpdatedTs: &currentTs,
+	}
+	validation := apiv1.UpdateUserRequest{}
+	for _, path := range request.UpdateMask.Paths {
+		switch path {
+		case "username":
+			if request.User.Username != user.Username {
+				if err := s.checkUsernameAvailable(ctx, request.User.Username); err != nil {
+					return nil, err
+				}
+			}
+			update.Username = &request.User.Username
+			validation.Username = update.Username
+		case "nickname":
+			update.Nickname = &request.User.Nickname
+			validation.Nickname = update.Nickname
+		case "email":
+			update.Email = &request.User.Email
+			validation.Email = update.Email
+		case "avatar_url":
+			update.AvatarURL = &request.User.AvatarUrl
+			validation.AvatarURL = update.AvatarURL
+		case "password":
+			validation.Password = &request.User.Password
+			passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.User.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to generate password hash: %v", err)
+			}
+			passwordHashStr := string(passwordHash)
+			update.PasswordHash = &passwordHashStr
+		case "role":
+			role := convertUserRoleToStore(request.User.Role)
+			if currentUser.Role != store.RoleHost || user.Role == store.RoleHost || role == store.RoleHost {
+				return nil, status.Errorf(codes.PermissionDenied, "only host can change the role of admins and users")
+			}
+			update.Role = &role
+		case "row_status":
+			if currentUser.Role != store.RoleHost || user.ID == currentUser.ID {
+				return nil, status.Errorf(codes.PermissionDenied, "only host can archive other users")
+			}
+			rowStatus := convertRowStatusToStore(request.User.RowStatus)
+			update.RowStatus = &rowStatus
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid update path: %s", path)
+		}
+	}
+	if err := validation.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user: %v", err)
+	}
+
+	user, err = s.Store.UpdateUser(ctx, update)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+	if user.RowStatus == store.Archived && update.RowStatus != nil {
+		if err := apiv1.RevokeUserCredentials(ctx, s.Store, user.ID); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke user credentials: %v", err)
+		}
+	}
+
+	userMessage := convertUserFromStore(user)
+	if user.ID != currentUser.ID {
+		userMessage.OpenId = ""
+	}
+	response := &apiv2pb.UpdateUserResponse{
+		User: userMessage,
+	}
+	return response, nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, request *apiv2pb.DeleteUserRequest) (*apiv2pb.DeleteUserResponse, error) {
+	currentUser, err := s.getCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &request.Id,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+	if user.ID == currentUser.ID || !canManageUser(currentUser, user) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	if err := s.Store.DeleteUser(ctx, &store.DeleteUser{
+		ID: user.ID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete user: %v", err)
+	}
+	return &apiv2pb.DeleteUserResponse{}, nil
+}
+
+func (s *UserService) GetUserSettings(ctx context.Context, request *apiv2pb.GetUserSettingsRequest) (*apiv2pb.GetUserSettingsResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	if request.Id != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	userSettings, err := s.Store.ListUserSettings(ctx, &store.FindUserSetting{
+		UserID: &userID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list user settings: %v", err)
+	}
+	response := &apiv2pb.GetUserSettingsResponse{}
+	for _, userSetting := range userSettings {
+		userSettingMessage, err := ConvertUserSettingFromStore(userSetting)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert user setting: %v", err)
+		}
+		response.Settings = append(response.Settings, userSettingMessage)
+	}
+	return response, nil
+}
+
+func (s *UserService) UpdateUserSettings(ctx context.Context, request *apiv2pb.UpdateUserSettingsRequest) (*apiv2pb.UpdateUserSettingsResponse, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	if request.Id != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+
+	// All the settings are validated before any of them is saved.
+	upserts := []*store.UserSetting{}
+	for _, userSetting := range request.Settings {
+		upsert, err := convertUserSettingToStore(userSetting)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid user setting: %v", err)
+		}
+		upsert.UserID = userID
+		upserts = append(upserts, upsert)
+	}
+
+	response := &apiv2pb.UpdateUserSettingsResponse{}
+	for _, upsert := range upserts {
+		userSetting, err := s.Store.UpsertUserSetting(ctx, upsert)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to upsert user setting: %v", err)
+		}
+		userSettingMessage, err := ConvertUserSettingFromStore(userSetting)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert user setting: %v", err)
+		}
+		response.Settings = append(response.Settings, userSettingMessage)
+	}
+	return response, nil
+}
+
+// getCurrentUser returns the authenticated user.
+func (s *UserService) getCurrentUser(ctx context.Context) (*store.User, error) {
+	userID := ctx.Value(UserIDContextKey).(int32)
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		ID: &userID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user not found")
+	}
+	return user, nil
+}
+
+func (s *UserService) checkUsernameAvailable(ctx context.Context, username string) error {
+	user, err := s.Store.GetUser(ctx, &store.FindUser{
+		Username: &username,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user != nil {
+		return status.Errorf(codes.AlreadyExists, "username %s is already taken", username)
+	}
+	return nil
+}
+
+func isAdmin(user *store.User) bool {
+	return user.Role == store.RoleHost || user.Role == store.RoleAdmin
+}
+
+// canManageUser returns whether the current user can update or delete the user.
+// Users can manage themselves, and only the host can manage the others, as in API v1.
+func canManageUser(currentUser *store.User, user *store.User) bool {
+	return currentUser.ID == user.ID || currentUser.Role == store.RoleHost
+}
+
 func convertUserFromStore(user *store.User) *apiv2pb.User {
 	return &apiv2pb.User{
 		Id:        int32(user.ID),
@@ -71,35 +372,82 @@ func convertUserRoleFromStore(role store.Role) apiv2pb.Role {
 	}
 }
 
+func convertUserRoleToStore(role apiv2pb.Role) store.Role {
+	switch role {
+	case apiv2pb.Role_HOST:
+		return store.RoleHost
+	case apiv2pb.Role_ADMIN:
+		return store.RoleAdmin
+	default:
+		return store.RoleUser
+	}
+}
+
 // ConvertUserSettingFromStore converts a user setting from store to protobuf.
-func ConvertUserSettingFromStore(userSetting *store.UserSetting) *apiv2pb.UserSetting {
+// The values in the store are JSON encoded strings.
+func ConvertUserSettingFromStore(userSetting *store.UserSetting) (*apiv2pb.UserSetting, error) {
+	value := ""
+	if err := json.Unmarshal([]byte(userSetting.Value), &value); err != nil {
+		return nil, err
+	}
 	userSettingKey := apiv2pb.UserSetting_KEY_UNSPECIFIED
 	userSettingValue := &apiv2pb.UserSettingValue{}
-	switch userSetting.Key {
-	case "locale":
+	switch apiv1.UserSettingKey(userSetting.Key) {
+	case apiv1.UserSettingLocaleKey:
 		userSettingKey = apiv2pb.UserSetting_LOCALE
 		userSettingValue.Value = &apiv2pb.UserSettingValue_StringValue{
-			StringValue: userSetting.Value,
+			StringValue: value,
 		}
-	case "appearance":
+	case apiv1.UserSettingAppearanceKey:
 		userSettingKey = apiv2pb.UserSetting_APPEARANCE
 		userSettingValue.Value = &apiv2pb.UserSettingValue_StringValue{
-			StringValue: userSetting.Value,
+			StringValue: value,
 		}
-	case "memo-visibility":
+	case apiv1.UserSettingMemoVisibilityKey:
 		userSettingKey = apiv2pb.UserSetting_MEMO_VISIBILITY
 		userSettingValue.Value = &apiv2pb.UserSettingValue_VisibilityValue{
-			VisibilityValue: convertVisibilityFromStore(store.Visibility(userSetting.Value)),
+			VisibilityValue: convertVisibilityFromStore(store.Visibility(value)),
 		}
-	case "telegram-user-id":
+	case apiv1.UserSettingTelegramUserIDKey:
 		userSettingKey = apiv2pb.UserSetting_TELEGRAM_USER_ID
 		userSettingValue.Value = &apiv2pb.UserSettingValue_StringValue{
-			StringValue: userSetting.Value,
+			StringValue: value,
 		}
 	}
 	return &apiv2pb.UserSetting{
 		UserId: int32(userSetting.UserID),
 		Key:    userSettingKey,
 		Value:  userSettingValue,
+	}, nil
+}
+
+// convertUserSettingToStore converts a user setting from protobuf to store, and validates its value.
+func convertUserSettingToStore(userSetting *apiv2pb.UserSetting) (*store.UserSetting, error) {
+	key := apiv1.UserSettingKey("")
+	value := ""
+	switch userSetting.Key {
+	case apiv2pb.UserSetting_LOCALE:
+		key, value = apiv1.UserSettingLocaleKey, userSetting.Value.GetStringValue()
+	case apiv2pb.UserSetting_APPEARANCE:
+		key, value = apiv1.UserSettingAppearanceKey, userSetting.Value.GetStringValue()
+	case apiv2pb.UserSetting_MEMO_VISIBILITY:
+		key, value = apiv1.UserSettingMemoVisibilityKey, string(convertVisibilityToStore(userSetting.Value.GetVisibilityValue()))
+	case apiv2pb.UserSetting_TELEGRAM_USER_ID:
+		key, value = apiv1.UserSettingTelegramUserIDKey, userSetting.Value.GetStringValue()
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	upsert := apiv1.UpsertUserSettingRequest{
+		Key:   key,
+		Value: string(valueBytes),
+	}
+	if err := upsert.Validate(); err != nil {
+		return nil, err
 	}
+	return &store.UserSetting{
+		Key:   key.String(),
+		Value: upsert.Value,
+	}, nil
 }
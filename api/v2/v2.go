@@ -14,7 +14,6 @@ import (
 )
 
 type APIV2Service struct {
-	Secret  string
 	Profile *profile.Profile
 	Store   *store.Store
 
@@ -22,20 +21,24 @@ type APIV2Service struct {
 	grpcServerPort int
 }
 
-func NewAPIV2Service(secret string, profile *profile.Profile, store *store.Store, grpcServerPort int) *APIV2Service {
-	authProvider := NewGRPCAuthInterceptor(store, secret)
+func NewAPIV2Service(profile *profile.Profile, store *store.Store, grpcServerPort int) *APIV2Service {
+	authProvider := NewGRPCAuthInterceptor(store)
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			authProvider.AuthenticationInterceptor,
 		),
+		grpc.ChainStreamInterceptor(
+			authProvider.AuthenticationStreamInterceptor,
+		),
 	)
 	apiv2pb.RegisterSystemServiceServer(grpcServer, NewSystemService(profile, store))
 	apiv2pb.RegisterUserServiceServer(grpcServer, NewUserService(store))
 	apiv2pb.RegisterMemoServiceServer(grpcServer, NewMemoService(store))
 	apiv2pb.RegisterTagServiceServer(grpcServer, NewTagService(store))
+	apiv2pb.RegisterShortcutServiceServer(grpcServer, NewShortcutService(store))
+	apiv2pb.RegisterResourceServiceServer(grpcServer, NewResourceService(store))
 
 	return &APIV2Service{
-		Secret:         secret,
 		Profile:        profile,
 		Store:          store,
 		grpcServer:     grpcServer,
@@ -73,6 +76,12 @@ func (s *APIV2Service) RegisterGateway(ctx context.Context, e *echo.Echo) error
 	if err := apiv2pb.RegisterTagServiceHandler(context.Background(), gwMux, conn); err != nil {
 		return err
 	}
+	if err := apiv2pb.RegisterShortcutServiceHandler(context.Background(), gwMux, conn); err != nil {
+		return err
+	}
+	if err := apiv2pb.RegisterResourceServiceHandler(context.Background(), gwMux, conn); err != nil {
+		return err
+	}
 	e.Any("/api/v2/*", echo.WrapHandler(gwMux))
 
 	return nil
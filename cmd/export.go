@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/usememos/memos/server/archive"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+)
+
+var (
+	exportCmdFlagUsername = "username"
+	exportCmdFlagOutput   = "output"
+	exportCmd             = &cobra.Command{
+		Use:   "export",
+		Short: "Export memos and resources to a zip archive",
+		Run: func(cmd *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			username, err := cmd.Flags().GetString(exportCmdFlagUsername)
+			if err != nil {
+				fmt.Printf("failed to get username, error: %+v\n", err)
+				return
+			}
+
+			output, err := cmd.Flags().GetString(exportCmdFlagOutput)
+			if err != nil {
+				fmt.Printf("failed to get output, error: %+v\n", err)
+				return
+			}
+
+			db := db.NewDB(profile)
+			if err := db.Open(ctx); err != nil {
+				fmt.Printf("failed to open db, error: %+v\n", err)
+				return
+			}
+
+			s := store.New(db.DBInstance, profile)
+			find := &store.FindUser{}
+			if username != "" {
+				find.Username = &username
+			}
+			users, err := s.ListUsers(ctx, find)
+			if err != nil {
+				fmt.Printf("failed to list users, error: %+v\n", err)
+				return
+			}
+			if len(users) == 0 {
+				fmt.Printf("no user to export\n")
+				return
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				fmt.Printf("failed to create %s, error: %+v\n", output, err)
+				return
+			}
+			defer file.Close()
+
+			result, err := archive.Export(ctx, s, file, users)
+			if err != nil {
+				fmt.Printf("failed to export, error: %+v\n", err)
+				return
+			}
+			fmt.Printf("Exported %d memos and %d resources of %d users to %s\n", result.MemoCount, result.ResourceCount, result.UserCount, output)
+		},
+	}
+)
+
+func init() {
+	exportCmd.Flags().String(exportCmdFlagUsername, "", "Username of the user to export, all users are exported if it's empty")
+	exportCmd.Flags().String(exportCmdFlagOutput, "memos-export.zip", "Path of the output archive")
+
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/plugin/importer"
+	"github.com/usememos/memos/server/archive"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/store/db"
+)
+
+// importSourceMemos is the source of the archives made by export.
+const importSourceMemos = "memos"
+
+var (
+	importCmdFlagUsername = "username"
+	importCmdFlagInput    = "input"
+	importCmdFlagSource   = "source"
+	importCmd             = &cobra.Command{
+		Use:   "import",
+		Short: "Import memos and resources from a zip archive made by export, or the export of another tool",
+		Run: func(cmd *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			username, err := cmd.Flags().GetString(importCmdFlagUsername)
+			if err != nil {
+				fmt.Printf("failed to get username, error: %+v\n", err)
+				return
+			}
+
+			input, err := cmd.Flags().GetString(importCmdFlagInput)
+			if err != nil {
+				fmt.Printf("failed to get input, error: %+v\n", err)
+				return
+			}
+
+			source, err := cmd.Flags().GetString(importCmdFlagSource)
+			if err != nil {
+				fmt.Printf("failed to get source, error: %+v\n", err)
+				return
+			}
+			if source != importSourceMemos && username == "" {
+				fmt.Printf("username is required to import from %s\n", source)
+				return
+			}
+
+			file, err := os.Open(input)
+			if err != nil {
+				fmt.Printf("failed to open %s, error: %+v\n", input, err)
+				return
+			}
+			defer file.Close()
+			stat, err := file.Stat()
+			if err != nil {
+				fmt.Printf("failed to stat %s, error: %+v\n", input, err)
+				return
+			}
+
+			db := db.NewDB(profile)
+			if err := db.Open(ctx); err != nil {
+				fmt.Printf("failed to open db, error: %+v\n", err)
+				return
+			}
+
+			s := store.New(db.DBInstance, profile)
+			if source == importSourceMemos {
+				result, err := archive.Import(ctx, s, file, stat.Size(), &archive.ImportOptions{
+					Username: username,
+				})
+				if err != nil {
+					fmt.Printf("failed to import, error: %+v\n", err)
+					return
+				}
+				fmt.Printf("Imported %d memos, %d resources and %d relations from %s\n", result.MemoCount, result.ResourceCount, result.RelationCount, input)
+				return
+			}
+
+			user, err := s.GetUser(ctx, &store.FindUser{Username: &username})
+			if err != nil {
+				fmt.Printf("failed to find user, error: %+v\n", err)
+				return
+			}
+			if user == nil {
+				fmt.Printf("user %s not found\n", username)
+				return
+			}
+
+			// The export of another tool can be the extracted folder as well.
+			var fsys fs.FS
+			if stat.IsDir() {
+				fsys = os.DirFS(input)
+			} else {
+				fsys, err = zip.NewReader(file, stat.Size())
+				if err != nil {
+					fmt.Printf("failed to open %s, error: %+v\n", input, err)
+					return
+				}
+			}
+			notes, err := importer.Parse(importer.Source(source), fsys)
+			if err != nil {
+				fmt.Printf("failed to parse %s, error: %+v\n", input, err)
+				return
+			}
+			result, err := apiv1.ImportNotes(ctx, s, user.ID, notes)
+			if err != nil {
+				fmt.Printf("failed to import, error: %+v\n", err)
+				return
+			}
+			fmt.Printf("Imported %d memos and %d resources from %s\n", result.MemoCount, result.ResourceCount, input)
+		},
+	}
+)
+
+func init() {
+	importCmd.Flags().String(importCmdFlagUsername, "", "Username of the user to import all memos into, memos of an archive are imported into the users with the same usernames if it's empty")
+	importCmd.Flags().String(importCmdFlagInput, "memos-export.zip", "Path of the input archive, or the folder of an export of another tool")
+	importCmd.Flags().String(importCmdFlagSource, importSourceMemos, `Source of the input, can be "memos" or "keep" or "flomo" or "obsidian"`)
+
+	rootCmd.AddCommand(importCmd)
+}
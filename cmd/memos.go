@@ -33,6 +33,8 @@ var (
 	mode    string
 	port    int
 	data    string
+	driver  string
+	dsn     string
 
 	rootCmd = &cobra.Command{
 		Use:   "memos",
@@ -91,6 +93,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&mode, "mode", "m", "demo", `mode of server, can be "prod" or "dev" or "demo"`)
 	rootCmd.PersistentFlags().IntVarP(&port, "port", "p", 8081, "port of server")
 	rootCmd.PersistentFlags().StringVarP(&data, "data", "d", "", "data directory")
+	rootCmd.PersistentFlags().StringVarP(&driver, "driver", "", "sqlite", `database driver, can be "sqlite" or "postgres" or "mysql"`)
+	rootCmd.PersistentFlags().StringVarP(&dsn, "dsn", "", "", "database source name, defaults to the sqlite file in data directory")
 
 	err := viper.BindPFlag("mode", rootCmd.PersistentFlags().Lookup("mode"))
 	if err != nil {
@@ -104,9 +108,18 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	err = viper.BindPFlag("driver", rootCmd.PersistentFlags().Lookup("driver"))
+	if err != nil {
+		panic(err)
+	}
+	err = viper.BindPFlag("dsn", rootCmd.PersistentFlags().Lookup("dsn"))
+	if err != nil {
+		panic(err)
+	}
 
 	viper.SetDefault("mode", "demo")
 	viper.SetDefault("port", 8081)
+	viper.SetDefault("driver", "sqlite")
 	viper.SetEnvPrefix("memos")
 }
 
@@ -121,7 +134,11 @@ func initConfig() {
 
 	println("---")
 	println("Server profile")
-	println("dsn:", profile.DSN)
+	println("driver:", profile.Driver)
+	// The dsn of other drivers may contain the password.
+	if profile.Driver == "sqlite" {
+		println("dsn:", profile.DSN)
+	}
 	println("port:", profile.Port)
 	println("mode:", profile.Mode)
 	println("version:", profile.Version)
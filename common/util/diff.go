@@ -0,0 +1,122 @@
+package util
+
+import "strings"
+
+// DiffOperation is the operation applied to a line in a diff.
+type DiffOperation string
+
+const (
+	// DiffEqual means the line is in both texts.
+	DiffEqual DiffOperation = "EQUAL"
+	// DiffInsert means the line is only in the new text.
+	DiffInsert DiffOperation = "INSERT"
+	// DiffDelete means the line is only in the old text.
+	DiffDelete DiffOperation = "DELETE"
+)
+
+// DiffLine is a line of a diff.
+type DiffLine struct {
+	Operation DiffOperation
+	Text      string
+}
+
+// DiffLines returns the line based diff that turns text from into text to.
+// It implements the Myers diff algorithm, so the diff has the fewest inserted and deleted lines.
+func DiffLines(from, to string) []*DiffLine {
+	a, b := splitLines(from), splitLines(to)
+
+	// Trim the common prefix and suffix, which are usually most of the text.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := []*DiffLine{}
+	for _, line := range a[:prefix] {
+		diff = append(diff, &DiffLine{Operation: DiffEqual, Text: line})
+	}
+	diff = append(diff, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, &DiffLine{Operation: DiffEqual, Text: line})
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(text, "\n")
+}
+
+func myersDiff(a, b []string) []*DiffLine {
+	n, m := len(a), len(b)
+	maxSteps := n + m
+	if maxSteps == 0 {
+		return []*DiffLine{}
+	}
+
+	// v[offset+k] is the furthest x reached on diagonal k, trace keeps v before every step.
+	offset := maxSteps + 1
+	v := make([]int, 2*maxSteps+3)
+	trace := [][]int{}
+	for d := 0; d <= maxSteps; d++ {
+		trace = append(trace, append([]int{}, v...))
+		for k := -d; k <= d; k += 2 {
+			x := 0
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackMyersDiff(a, b, trace, offset)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrackMyersDiff(a, b []string, trace [][]int, offset int) []*DiffLine {
+	reversed := []*DiffLine{}
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, &DiffLine{Operation: DiffEqual, Text: a[x-1]})
+			x, y = x-1, y-1
+		}
+		if prevK == k+1 {
+			reversed = append(reversed, &DiffLine{Operation: DiffInsert, Text: b[prevY]})
+		} else {
+			reversed = append(reversed, &DiffLine{Operation: DiffDelete, Text: a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, &DiffLine{Operation: DiffEqual, Text: a[x-1]})
+		x, y = x-1, y-1
+	}
+
+	diff := make([]*DiffLine, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		diff = append(diff, reversed[i])
+	}
+	return diff
+}
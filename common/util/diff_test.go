@@ -0,0 +1,62 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want string
+	}{
+		{
+			from: "",
+			to:   "",
+			want: "",
+		},
+		{
+			from: "",
+			to:   "a\nb",
+			want: "+a +b",
+		},
+		{
+			from: "a\nb\nc",
+			to:   "a\nb\nc",
+			want: " a  b  c",
+		},
+		{
+			from: "a\nb\nc",
+			to:   "a\nc",
+			want: " a -b  c",
+		},
+		{
+			from: "#todo\n- buy milk\n- call mom",
+			to:   "#done\n- buy milk\n- call mom\n- read",
+			want: "-#todo +#done  - buy milk  - call mom +- read",
+		},
+		{
+			from: "a\nb\nc\na\nb\nb\na",
+			to:   "c\nb\na\nb\na\nc",
+			want: "-a -b  c +b  a  b -b  a +c",
+		},
+	}
+	for _, test := range tests {
+		lines := []string{}
+		for _, line := range DiffLines(test.from, test.to) {
+			switch line.Operation {
+			case DiffEqual:
+				lines = append(lines, " "+line.Text)
+			case DiffInsert:
+				lines = append(lines, "+"+line.Text)
+			case DiffDelete:
+				lines = append(lines, "-"+line.Text)
+			}
+		}
+		result := strings.Join(lines, " ")
+		if result != test.want {
+			t.Errorf("DiffLines(%q, %q): got result %q, want %q.", test.from, test.to, result, test.want)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package parser
 
-import "github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+import (
+	"sort"
+	"strings"
+
+	"github.com/usememos/memos/plugin/gomark/parser/tokenizer"
+)
 
 type TagParser struct {
 	ContentTokens []*tokenizer.Token
@@ -32,3 +37,48 @@ func (*TagParser) Match(tokens []*tokenizer.Token) *TagParser {
 		ContentTokens: contentTokens,
 	}
 }
+
+// Name returns the name of the tag, which is a path separated by slashes for a hierarchical tag, e.g. `work/clientA`.
+func (p *TagParser) Name() string {
+	name := ""
+	for _, token := range p.ContentTokens {
+		name += token.Value
+	}
+	return name
+}
+
+// FindTagList returns the sorted unique names of the tags in text.
+func FindTagList(text string) []string {
+	tokens := tokenizer.Tokenize(text)
+	tagMapSet := make(map[string]bool)
+	for i := range tokens {
+		tag := NewTagParser().Match(tokens[i:])
+		if tag == nil {
+			continue
+		}
+		tagMapSet[tag.Name()] = true
+	}
+
+	tagList := []string{}
+	for tag := range tagMapSet {
+		tagList = append(tagList, tag)
+	}
+	sort.Strings(tagList)
+	return tagList
+}
+
+// ReplaceTags returns text in which the name of every tag is replaced with the result of replace.
+func ReplaceTags(text string, replace func(name string) string) string {
+	tokens := tokenizer.Tokenize(text)
+	builder := strings.Builder{}
+	for i := 0; i < len(tokens); i++ {
+		tag := NewTagParser().Match(tokens[i:])
+		if tag == nil {
+			builder.WriteString(tokens[i].Value)
+			continue
+		}
+		builder.WriteString("#" + replace(tag.Name()))
+		i += len(tag.ContentTokens)
+	}
+	return builder.String()
+}
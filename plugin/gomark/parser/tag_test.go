@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -49,3 +50,79 @@ func TestTagParser(t *testing.T) {
 		require.Equal(t, test.tag, NewTagParser().Match(tokens))
 	}
 }
+
+func TestFindTagList(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{
+			text: "#tag1 ",
+			want: []string{"tag1"},
+		},
+		{
+			text: "#tag1 #tag2 ",
+			want: []string{"tag1", "tag2"},
+		},
+		{
+			text: "#tag1 #tag2 \n#tag3 ",
+			want: []string{"tag1", "tag2", "tag3"},
+		},
+		{
+			text: "#tag1 #tag2 \n#tag3 #tag4 ",
+			want: []string{"tag1", "tag2", "tag3", "tag4"},
+		},
+		{
+			text: "#tag1 #tag2 \n#tag3  #tag4 ",
+			want: []string{"tag1", "tag2", "tag3", "tag4"},
+		},
+		{
+			text: "#tag1 123123#tag2 \n#tag3  #tag4 ",
+			want: []string{"tag1", "tag2", "tag3", "tag4"},
+		},
+		{
+			text: "#tag1 http://123123.com?123123#tag2 \n#tag3  #tag4 http://123123.com?123123#tag2) ",
+			want: []string{"tag1", "tag2", "tag2)", "tag3", "tag4"},
+		},
+		{
+			text: "# Heading\n#tag_name #tag/subtag #tag1#tag2",
+			want: []string{"tag/subtag", "tag1", "tag2", "tag_name"},
+		},
+		{
+			text: "no tags",
+			want: []string{},
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, FindTagList(test.text))
+	}
+}
+
+func TestReplaceTags(t *testing.T) {
+	rename := func(name string) string {
+		if name == "work" || strings.HasPrefix(name, "work/") {
+			return "job" + strings.TrimPrefix(name, "work")
+		}
+		return name
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{
+			text: "#work meeting",
+			want: "#job meeting",
+		},
+		{
+			text: "# Heading\n#work/clientA #working #ideas\n**bold** #work",
+			want: "# Heading\n#job/clientA #working #ideas\n**bold** #job",
+		},
+		{
+			text: "no tags",
+			want: "no tags",
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, ReplaceTags(test.text, rename))
+	}
+}
@@ -1,7 +1,52 @@
 package idp
 
+import (
+	"fmt"
+)
+
 type IdentityProviderUserInfo struct {
 	Identifier  string
 	DisplayName string
 	Email       string
+	// EmailVerified is whether the identity provider asserts that the email is owned by the user.
+	EmailVerified bool
+	AvatarURL     string
+	// Groups are the groups of the user, if the identity provider reports them.
+	Groups []string
+	// Claims are all the claims of the user, which the provisioning rules match.
+	Claims map[string][]string
+}
+
+// ConvertClaims converts the claims decoded from JSON to their string values,
+// e.g. an array of groups to the groups, and a boolean to "true" or "false".
+func ConvertClaims(claims map[string]any) map[string][]string {
+	values := map[string][]string{}
+	for name, claim := range claims {
+		list, ok := claim.([]any)
+		if !ok {
+			list = []any{claim}
+		}
+		for _, item := range list {
+			switch v := item.(type) {
+			case nil:
+			case string:
+				values[name] = append(values[name], v)
+			case bool, float64, int64:
+				values[name] = append(values[name], fmt.Sprint(v))
+			}
+		}
+	}
+	return values
+}
+
+// IsEmailVerified returns true if the claims assert that the email is verified with the standard email_verified claim,
+// which some identity providers send as a string.
+func IsEmailVerified(claims map[string]any) bool {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
 }
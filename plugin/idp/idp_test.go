@@ -0,0 +1,45 @@
+package idp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertClaims(t *testing.T) {
+	claims := map[string]any{}
+	err := json.Unmarshal([]byte(`{
+		"sub": "248289761001",
+		"email": "john@example.com",
+		"email_verified": true,
+		"groups": ["admins", "staff"],
+		"age": 42,
+		"address": {"country": "FR"},
+		"picture": null
+	}`), &claims)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"sub":            {"248289761001"},
+		"email":          {"john@example.com"},
+		"email_verified": {"true"},
+		"groups":         {"admins", "staff"},
+		"age":            {"42"},
+	}, ConvertClaims(claims))
+}
+
+func TestIsEmailVerified(t *testing.T) {
+	tests := []struct {
+		claims map[string]any
+		want   bool
+	}{
+		{claims: map[string]any{"email_verified": true}, want: true},
+		{claims: map[string]any{"email_verified": "true"}, want: true},
+		{claims: map[string]any{"email_verified": false}, want: false},
+		{claims: map[string]any{"email_verified": "yes"}, want: false},
+		{claims: map[string]any{}, want: false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, IsEmailVerified(test.claims))
+	}
+}
@@ -0,0 +1,186 @@
+// Package ldap is the plugin for LDAP Identity Provider.
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/plugin/idp"
+	"github.com/usememos/memos/store"
+)
+
+// timeout is the timeout of connecting to the directory, and of each request.
+const timeout = 10 * time.Second
+
+// ErrInvalidCredentials is returned when the directory doesn't know the user, or the password is incorrect.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// IdentityProvider represents a LDAP Identity Provider.
+type IdentityProvider struct {
+	config *store.IdentityProviderLDAPConfig
+}
+
+// NewIdentityProvider initializes a new LDAP Identity Provider with the given configuration.
+func NewIdentityProvider(config *store.IdentityProviderLDAPConfig) (*IdentityProvider, error) {
+	if config.FieldMapping == nil {
+		return nil, errors.New(`the field "fieldMapping" is empty but required`)
+	}
+	for v, field := range map[string]string{
+		config.URL:                     "url",
+		config.BaseDN:                  "baseDn",
+		config.UserFilter:              "userFilter",
+		config.FieldMapping.Identifier: "fieldMapping.identifier",
+	} {
+		if v == "" {
+			return nil, errors.Errorf(`the field "%s" is empty but required`, field)
+		}
+	}
+	if strings.Count(config.UserFilter, "%s") != 1 {
+		return nil, errors.Errorf(`the field "userFilter" must contain %%s once`)
+	}
+
+	return &IdentityProvider{
+		config: config,
+	}, nil
+}
+
+// Authenticate searches the user with the service account, then verifies the password with a bind as the user.
+// It returns the user information mapped from the attributes of the user.
+func (p *IdentityProvider) Authenticate(username, password string) (*idp.IdentityProviderUserInfo, error) {
+	// An empty password would be an unauthenticated bind, which always succeeds.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if p.config.BindDN != "" {
+		if err := conn.Bind(p.config.BindDN, p.config.BindPassword); err != nil {
+			return nil, errors.Wrap(err, "failed to bind with the service account")
+		}
+	}
+
+	// The group attribute is usually an operational attribute, which isn't one of all the user attributes.
+	attributes := []string{"*"}
+	if p.config.GroupAttribute != "" {
+		attributes = append(attributes, p.config.GroupAttribute)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(timeout.Seconds()),
+		false,
+		fmt.Sprintf(p.config.UserFilter, ldap.EscapeFilter(username)),
+		attributes,
+		nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, errors.Wrap(err, "failed to search user")
+	}
+	if result == nil || len(result.Entries) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	if len(result.Entries) > 1 {
+		return nil, errors.Errorf("multiple users found with username %q", username)
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, errors.Wrap(err, "failed to bind with the user")
+	}
+
+	userInfo := &idp.IdentityProviderUserInfo{
+		Identifier: entry.GetAttributeValue(p.config.FieldMapping.Identifier),
+	}
+	if userInfo.Identifier == "" {
+		return nil, errors.Errorf("the attribute %q is not found in user or has empty value", p.config.FieldMapping.Identifier)
+	}
+
+	// Best effort to map optional fields
+	if p.config.FieldMapping.DisplayName != "" {
+		userInfo.DisplayName = entry.GetAttributeValue(p.config.FieldMapping.DisplayName)
+	}
+	if userInfo.DisplayName == "" {
+		userInfo.DisplayName = userInfo.Identifier
+	}
+	if p.config.FieldMapping.Email != "" {
+		userInfo.Email = entry.GetAttributeValue(p.config.FieldMapping.Email)
+	}
+	if p.config.FieldMapping.AvatarURL != "" {
+		userInfo.AvatarURL = entry.GetAttributeValue(p.config.FieldMapping.AvatarURL)
+	}
+	if p.config.GroupAttribute != "" {
+		userInfo.Groups = entry.GetAttributeValues(p.config.GroupAttribute)
+	}
+	userInfo.Claims = map[string][]string{}
+	for _, attribute := range entry.Attributes {
+		userInfo.Claims[attribute.Name] = attribute.Values
+	}
+	return userInfo, nil
+}
+
+// Role returns the role mapped from the groups of the user, the host groups taking precedence over the admin groups.
+// It returns false if the role mapping isn't enabled.
+func (p *IdentityProvider) Role(groups []string) (store.Role, bool) {
+	if p.config.GroupAttribute == "" {
+		return "", false
+	}
+
+	// The groups are usually DNs, which are case-insensitive.
+	containsGroup := func(list []string) bool {
+		for _, group := range groups {
+			for _, item := range list {
+				if strings.EqualFold(group, item) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if containsGroup(p.config.HostGroups) {
+		return store.RoleHost, true
+	}
+	if containsGroup(p.config.AdminGroups) {
+		return store.RoleAdmin, true
+	}
+	return store.RoleUser, true
+}
+
+func (p *IdentityProvider) dial() (*ldap.Conn, error) {
+	u, err := url.Parse(p.config.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse url")
+	}
+	tlsConfig := &tls.Config{
+		ServerName: u.Hostname(),
+		MinVersion: tls.VersionTLS12,
+	}
+	conn, err := ldap.DialURL(p.config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the directory")
+	}
+	conn.SetTimeout(timeout)
+
+	if p.config.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to start tls")
+		}
+	}
+	return conn, nil
+}
@@ -0,0 +1,150 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/plugin/idp"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestNewIdentityProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *store.IdentityProviderLDAPConfig
+		containsErr string
+	}{
+		{
+			name: "no url",
+			config: &store.IdentityProviderLDAPConfig{
+				BaseDN:     "dc=example,dc=com",
+				UserFilter: "(uid=%s)",
+				FieldMapping: &store.FieldMapping{
+					Identifier: "uid",
+				},
+			},
+			containsErr: `the field "url" is empty but required`,
+		},
+		{
+			name: "no field mapping identifier",
+			config: &store.IdentityProviderLDAPConfig{
+				URL:          "ldap://localhost:389",
+				BaseDN:       "dc=example,dc=com",
+				UserFilter:   "(uid=%s)",
+				FieldMapping: &store.FieldMapping{},
+			},
+			containsErr: `the field "fieldMapping.identifier" is empty but required`,
+		},
+		{
+			name: "user filter without username",
+			config: &store.IdentityProviderLDAPConfig{
+				URL:        "ldap://localhost:389",
+				BaseDN:     "dc=example,dc=com",
+				UserFilter: "(uid=john)",
+				FieldMapping: &store.FieldMapping{
+					Identifier: "uid",
+				},
+			},
+			containsErr: `the field "userFilter" must contain %s once`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewIdentityProvider(test.config)
+			assert.ErrorContains(t, err, test.containsErr)
+		})
+	}
+}
+
+func TestIdentityProvider(t *testing.T) {
+	server := test.NewLDAPServer(t,
+		&test.LDAPEntry{
+			DN:       "cn=memos,ou=services,dc=example,dc=com",
+			Password: "service-password",
+		},
+		&test.LDAPEntry{
+			DN:       "uid=john,ou=people,dc=example,dc=com",
+			Password: "john-password",
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {"john"},
+				"cn":          {"John Doe"},
+				"mail":        {"john@example.com"},
+				"memberOf":    {"cn=Admins,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"},
+			},
+		},
+		&test.LDAPEntry{
+			DN:       "uid=jane,ou=people,dc=example,dc=com",
+			Password: "jane-password",
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {"jane"},
+			},
+		},
+	)
+	config := &store.IdentityProviderLDAPConfig{
+		URL:          server.URL,
+		BindDN:       "cn=memos,ou=services,dc=example,dc=com",
+		BindPassword: "service-password",
+		BaseDN:       "ou=people,dc=example,dc=com",
+		UserFilter:   "(&(objectClass=inetOrgPerson)(uid=%s))",
+		FieldMapping: &store.FieldMapping{
+			Identifier:  "uid",
+			DisplayName: "cn",
+			Email:       "mail",
+		},
+		GroupAttribute: "memberOf",
+		HostGroups:     []string{"cn=hosts,ou=groups,dc=example,dc=com"},
+		AdminGroups:    []string{"cn=admins,ou=groups,dc=example,dc=com"},
+	}
+	ldap, err := NewIdentityProvider(config)
+	require.NoError(t, err)
+
+	userInfo, err := ldap.Authenticate("john", "john-password")
+	require.NoError(t, err)
+	require.Equal(t, &idp.IdentityProviderUserInfo{
+		Identifier:  "john",
+		DisplayName: "John Doe",
+		Email:       "john@example.com",
+		Groups:      []string{"cn=Admins,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"},
+		Claims: map[string][]string{
+			"objectClass": {"inetOrgPerson"},
+			"uid":         {"john"},
+			"cn":          {"John Doe"},
+			"mail":        {"john@example.com"},
+			"memberOf":    {"cn=Admins,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"},
+		},
+	}, userInfo)
+	role, ok := ldap.Role(userInfo.Groups)
+	require.True(t, ok)
+	require.Equal(t, store.RoleAdmin, role)
+
+	userInfo, err = ldap.Authenticate("jane", "jane-password")
+	require.NoError(t, err)
+	require.Equal(t, "jane", userInfo.DisplayName)
+	role, ok = ldap.Role(userInfo.Groups)
+	require.True(t, ok)
+	require.Equal(t, store.RoleUser, role)
+
+	_, err = ldap.Authenticate("john", "jane-password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = ldap.Authenticate("john", "")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = ldap.Authenticate("unknown", "john-password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	// The username can't inject a filter matching another user.
+	_, err = ldap.Authenticate("*", "john-password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// The service account must be valid.
+	config.BindPassword = "wrong-password"
+	_, err = ldap.Authenticate("john", "john-password")
+	require.ErrorContains(t, err, "failed to bind with the service account")
+
+	// The role isn't mapped without the group attribute.
+	config.GroupAttribute = ""
+	_, ok = ldap.Role(userInfo.Groups)
+	require.False(t, ok)
+}
@@ -111,5 +111,14 @@ func (p *IdentityProvider) UserInfo(token string) (*idp.IdentityProviderUserInfo
 			userInfo.Email = v
 		}
 	}
+	if userInfo.Email != "" {
+		userInfo.EmailVerified = idp.IsEmailVerified(claims)
+	}
+	if p.config.FieldMapping.AvatarURL != "" {
+		if v, ok := claims[p.config.FieldMapping.AvatarURL].(string); ok {
+			userInfo.AvatarURL = v
+		}
+	}
+	userInfo.Claims = idp.ConvertClaims(claims)
 	return userInfo, nil
 }
@@ -0,0 +1,467 @@
+// Package oidc is the plugin for OpenID Connect Identity Provider.
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/usememos/memos/plugin/idp"
+	"github.com/usememos/memos/store"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// discoveryPath is the path of the OpenID Provider Metadata relative to the issuer.
+	discoveryPath = "/.well-known/openid-configuration"
+	// cacheDuration is how long the metadata and the JWKS of an issuer are cached.
+	cacheDuration = time.Hour
+	// keySetRefetchInterval is the minimum interval between the fetches of a JWKS for the unknown kids,
+	// so that the ID tokens with random kids don't flood the issuer.
+	keySetRefetchInterval = time.Minute
+)
+
+// supportedSigningMethods are the asymmetric algorithms accepted for ID tokens.
+// The symmetric ones are rejected, as the client secret isn't required.
+var supportedSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+var (
+	metadataCache sync.Map // map[string]*cachedMetadata
+	keySetCache   sync.Map // map[string]*cachedKeySet
+	// keySetMutex serializes the fetches of the JWKS, so that concurrent unknown kids trigger a single fetch.
+	keySetMutex sync.Mutex
+)
+
+// providerMetadata is the part of the OpenID Provider Metadata used to sign in.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type cachedMetadata struct {
+	metadata  *providerMetadata
+	expiresAt time.Time
+}
+
+// jsonWebKey is a public key of the JWKS, see RFC 7517.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type publicKey struct {
+	kid string
+	key any
+}
+
+type cachedKeySet struct {
+	keys      []*publicKey
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// IdentityProvider represents an OpenID Connect Identity Provider.
+type IdentityProvider struct {
+	config *store.IdentityProviderOIDCConfig
+}
+
+// NewIdentityProvider initializes a new OpenID Connect Identity Provider with the given configuration.
+func NewIdentityProvider(config *store.IdentityProviderOIDCConfig) (*IdentityProvider, error) {
+	if config.FieldMapping == nil {
+		return nil, errors.New(`the field "fieldMapping" is empty but required`)
+	}
+	for v, field := range map[string]string{
+		config.Issuer:                  "issuer",
+		config.ClientID:                "clientId",
+		config.FieldMapping.Identifier: "fieldMapping.identifier",
+	} {
+		if v == "" {
+			return nil, errors.Errorf(`the field "%s" is empty but required`, field)
+		}
+	}
+
+	return &IdentityProvider{
+		config: config,
+	}, nil
+}
+
+// AuthorizationURL returns the URL redirecting the user to the identity provider, with the nonce bound to the ID token
+// and the S256 challenge of the PKCE code verifier.
+func (p *IdentityProvider) AuthorizationURL(ctx context.Context, redirectURL, state, nonce, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	conf := p.oauth2Config(metadata, redirectURL)
+	return conf.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// ExchangeToken returns the raw ID token exchanged with the given authorization code and PKCE code verifier.
+func (p *IdentityProvider) ExchangeToken(ctx context.Context, redirectURL, code, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	conf := p.oauth2Config(metadata, redirectURL)
+	token, err := conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, httpClient), code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to exchange token")
+	}
+
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return "", errors.New(`missing "id_token" from token response`)
+	}
+
+	return idToken, nil
+}
+
+// VerifyIDToken verifies the signature of the ID token against the JWKS of the issuer,
+// then checks its issuer, audience, expiration and nonce. It returns the claims of the ID token.
+func (p *IdentityProvider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(supportedSigningMethods))
+	if _, err := parser.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return getPublicKey(ctx, metadata.JWKSURI, kid)
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to verify id token")
+	}
+
+	if !claims.VerifyIssuer(metadata.Issuer, true) {
+		return nil, errors.Errorf("unexpected id token issuer %v", claims["iss"])
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.Errorf("unexpected id token audience %v", claims["aud"])
+	}
+	// The authorized party is the client when the ID token has several audiences.
+	if azp, ok := claims["azp"]; ok && azp != p.config.ClientID {
+		return nil, errors.Errorf("unexpected id token authorized party %v", azp)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("id token is expired")
+	}
+	if v, ok := claims["nonce"].(string); !ok || subtle.ConstantTimeCompare([]byte(v), []byte(nonce)) != 1 {
+		return nil, errors.New("unexpected id token nonce")
+	}
+
+	return claims, nil
+}
+
+// UserInfo returns the user information mapped from the claims of the ID token.
+func (p *IdentityProvider) UserInfo(claims jwt.MapClaims) (*idp.IdentityProviderUserInfo, error) {
+	userInfo := &idp.IdentityProviderUserInfo{}
+	if v, ok := claims[p.config.FieldMapping.Identifier].(string); ok {
+		userInfo.Identifier = v
+	}
+	if userInfo.Identifier == "" {
+		return nil, errors.Errorf("the field %q is not found in claims or has empty value", p.config.FieldMapping.Identifier)
+	}
+
+	// Best effort to map optional fields
+	if p.config.FieldMapping.DisplayName != "" {
+		if v, ok := claims[p.config.FieldMapping.DisplayName].(string); ok {
+			userInfo.DisplayName = v
+		}
+	}
+	if userInfo.DisplayName == "" {
+		userInfo.DisplayName = userInfo.Identifier
+	}
+	if p.config.FieldMapping.Email != "" {
+		if v, ok := claims[p.config.FieldMapping.Email].(string); ok {
+			userInfo.Email = v
+		}
+	}
+	if userInfo.Email != "" {
+		userInfo.EmailVerified = idp.IsEmailVerified(claims)
+	}
+	if p.config.FieldMapping.AvatarURL != "" {
+		if v, ok := claims[p.config.FieldMapping.AvatarURL].(string); ok {
+			userInfo.AvatarURL = v
+		}
+	}
+	userInfo.Claims = idp.ConvertClaims(claims)
+	return userInfo, nil
+}
+
+// GenerateCodeVerifier returns a random PKCE code verifier, see RFC 7636.
+func GenerateCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge returns the S256 code challenge of the PKCE code verifier.
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (p *IdentityProvider) oauth2Config(metadata *providerMetadata, redirectURL string) *oauth2.Config {
+	scopes := p.config.Scopes
+	hasOpenIDScope := false
+	for _, scope := range scopes {
+		if scope == "openid" {
+			hasOpenIDScope = true
+		}
+	}
+	if !hasOpenIDScope {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  metadata.AuthorizationEndpoint,
+			TokenURL: metadata.TokenEndpoint,
+		},
+	}
+}
+
+// discover returns the metadata of the issuer, which is cached for a while.
+func (p *IdentityProvider) discover(ctx context.Context) (*providerMetadata, error) {
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	if cache, ok := metadataCache.Load(issuer); ok && time.Now().Before(cache.(*cachedMetadata).expiresAt) {
+		return cache.(*cachedMetadata).metadata, nil
+	}
+
+	metadata := &providerMetadata{}
+	if err := getJSON(ctx, issuer+discoveryPath, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to discover provider metadata")
+	}
+	// The issuer of the metadata must be the configured one, as it's the issuer of the ID tokens.
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return nil, errors.Errorf("unexpected issuer %q of provider metadata, expect %q", metadata.Issuer, p.config.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("incomplete provider metadata")
+	}
+
+	metadataCache.Store(issuer, &cachedMetadata{
+		metadata:  metadata,
+		expiresAt: time.Now().Add(cacheDuration),
+	})
+	return metadata, nil
+}
+
+// getPublicKey returns the key of the JWKS with the given kid. The JWKS is cached for a while,
+// and refetched when the kid isn't found, in case the issuer has rotated its keys.
+// The JWKS is refetched at most once per interval, the unknown kids fail fast in between.
+func getPublicKey(ctx context.Context, jwksURI, kid string) (any, error) {
+	if key, ok := findCachedPublicKey(jwksURI, kid); ok {
+		return key, nil
+	}
+
+	keySetMutex.Lock()
+	defer keySetMutex.Unlock()
+	// The JWKS may have been refetched by a concurrent request.
+	if key, ok := findCachedPublicKey(jwksURI, kid); ok {
+		return key, nil
+	}
+	if cache, ok := keySetCache.Load(jwksURI); ok {
+		keySet := cache.(*cachedKeySet)
+		if time.Now().Before(keySet.expiresAt) && time.Since(keySet.fetchedAt) < keySetRefetchInterval {
+			return nil, errors.Errorf("unexpected id token kid=%v", kid)
+		}
+	}
+
+	keys, err := fetchKeySet(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	keySetCache.Store(jwksURI, &cachedKeySet{
+		keys:      keys,
+		fetchedAt: time.Now(),
+		expiresAt: time.Now().Add(cacheDuration),
+	})
+	if key := findPublicKey(keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, errors.Errorf("unexpected id token kid=%v", kid)
+}
+
+// findCachedPublicKey returns the key with the given kid from the cached JWKS, if it hasn't expired.
+func findCachedPublicKey(jwksURI, kid string) (any, bool) {
+	cache, ok := keySetCache.Load(jwksURI)
+	if !ok || !time.Now().Before(cache.(*cachedKeySet).expiresAt) {
+		return nil, false
+	}
+	key := findPublicKey(cache.(*cachedKeySet).keys, kid)
+	return key, key != nil
+}
+
+// findPublicKey returns the key with the given kid, or the only key if the ID token has no kid.
+func findPublicKey(keys []*publicKey, kid string) any {
+	if kid == "" {
+		if len(keys) == 1 {
+			return keys[0].key
+		}
+		return nil
+	}
+	for _, key := range keys {
+		if key.kid == kid {
+			return key.key
+		}
+	}
+	return nil
+}
+
+func fetchKeySet(ctx context.Context, jwksURI string) ([]*publicKey, error) {
+	keySet := struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}{}
+	if err := getJSON(ctx, jwksURI, &keySet); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch jwks")
+	}
+
+	keys := []*publicKey{}
+	for _, jwk := range keySet.Keys {
+		// Skip the keys which aren't for signatures.
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse jwk kid=%v", jwk.Kid)
+		}
+		if key != nil {
+			keys = append(keys, &publicKey{
+				kid: jwk.Kid,
+				key: key,
+			})
+		}
+	}
+	return keys, nil
+}
+
+// publicKey returns the RSA or EC public key of the JWK, or nil if the key type isn't supported.
+func (jwk *jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{
+			N: n,
+			E: int(e.Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported ec curve %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid ec point")
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     x,
+			Y:     y,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to new http request")
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d of %s", resp.StatusCode, url)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response body")
+	}
+	return nil
+}
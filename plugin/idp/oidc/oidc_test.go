@@ -0,0 +1,354 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestNewIdentityProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *store.IdentityProviderOIDCConfig
+		containsErr string
+	}{
+		{
+			name: "no issuer",
+			config: &store.IdentityProviderOIDCConfig{
+				ClientID: "test-client-id",
+				FieldMapping: &store.FieldMapping{
+					Identifier: "preferred_username",
+				},
+			},
+			containsErr: `the field "issuer" is empty but required`,
+		},
+		{
+			name: "no clientId",
+			config: &store.IdentityProviderOIDCConfig{
+				Issuer: "https://example.com",
+				FieldMapping: &store.FieldMapping{
+					Identifier: "preferred_username",
+				},
+			},
+			containsErr: `the field "clientId" is empty but required`,
+		},
+		{
+			name: "no field mapping identifier",
+			config: &store.IdentityProviderOIDCConfig{
+				Issuer:       "https://example.com",
+				ClientID:     "test-client-id",
+				FieldMapping: &store.FieldMapping{},
+			},
+			containsErr: `the field "fieldMapping.identifier" is empty but required`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewIdentityProvider(test.config)
+			assert.ErrorContains(t, err, test.containsErr)
+		})
+	}
+}
+
+// mockIssuer is a local stand-in OpenID Provider.
+type mockIssuer struct {
+	*httptest.Server
+	clientID string
+	kid      string
+	key      any
+	// claims are added to the ID tokens issued by the token endpoint.
+	claims jwt.MapClaims
+	// codeChallenge and nonce are the ones of the last authorization request.
+	codeChallenge string
+	nonce         string
+	jwksRequests  atomic.Int32
+}
+
+func newMockIssuer(t *testing.T, clientID string) *mockIssuer {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m := &mockIssuer{
+		clientID: clientID,
+		kid:      "key-1",
+		key:      privateKey,
+		claims:   jwt.MapClaims{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		}))
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.jwksRequests.Add(1)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{m.jwk()},
+		}))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.Form.Get("code") != "test-code" || codeChallenge(r.Form.Get("code_verifier")) != m.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"error": "invalid_grant",
+			}))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "test-access-token",
+			"token_type":   "Bearer",
+			"id_token":     m.signIDToken(t, jwt.MapClaims{"nonce": m.nonce}),
+		}))
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize records the authorization request, as the authorization endpoint would do.
+func (m *mockIssuer) authorize(t *testing.T, authorizationURL string) {
+	u, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	require.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	m.codeChallenge = u.Query().Get("code_challenge")
+	m.nonce = u.Query().Get("nonce")
+}
+
+func (m *mockIssuer) signIDToken(t *testing.T, claims jwt.MapClaims) string {
+	idTokenClaims := jwt.MapClaims{
+		"iss": m.URL,
+		"sub": "248289761001",
+		"aud": m.clientID,
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}
+	for k, v := range m.claims {
+		idTokenClaims[k] = v
+	}
+	for k, v := range claims {
+		idTokenClaims[k] = v
+	}
+
+	var method jwt.SigningMethod = jwt.SigningMethodRS256
+	if _, ok := m.key.(*ecdsa.PrivateKey); ok {
+		method = jwt.SigningMethodES256
+	}
+	token := jwt.NewWithClaims(method, idTokenClaims)
+	token.Header["kid"] = m.kid
+	idToken, err := token.SignedString(m.key)
+	require.NoError(t, err)
+	return idToken
+}
+
+func (m *mockIssuer) jwk() map[string]string {
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	switch key := m.key.(type) {
+	case *rsa.PrivateKey:
+		return map[string]string{
+			"kty": "RSA",
+			"kid": m.kid,
+			"use": "sig",
+			"n":   encode(key.N),
+			"e":   encode(big.NewInt(int64(key.E))),
+		}
+	case *ecdsa.PrivateKey:
+		return map[string]string{
+			"kty": "EC",
+			"kid": m.kid,
+			"use": "sig",
+			"crv": "P-256",
+			"x":   encode(key.X),
+			"y":   encode(key.Y),
+		}
+	}
+	return nil
+}
+
+func TestIdentityProvider(t *testing.T) {
+	ctx := context.Background()
+	const (
+		testClientID    = "test-client-id"
+		testRedirectURL = "https://memos.example.com/auth/callback"
+	)
+	issuer := newMockIssuer(t, testClientID)
+	issuer.claims = jwt.MapClaims{
+		"preferred_username": "john",
+		"name":               "John Doe",
+		"email":              "john@example.com",
+	}
+	oidc, err := NewIdentityProvider(&store.IdentityProviderOIDCConfig{
+		Issuer:   issuer.URL,
+		ClientID: testClientID,
+		Scopes:   []string{"profile", "email"},
+		FieldMapping: &store.FieldMapping{
+			Identifier:  "preferred_username",
+			DisplayName: "name",
+			Email:       "email",
+		},
+	})
+	require.NoError(t, err)
+
+	codeVerifier, err := GenerateCodeVerifier()
+	require.NoError(t, err)
+	authorizationURL, err := oidc.AuthorizationURL(ctx, testRedirectURL, "test-state", "test-nonce", codeVerifier)
+	require.NoError(t, err)
+	u, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	assert.Equal(t, issuer.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+	assert.Equal(t, "test-state", u.Query().Get("state"))
+	assert.Equal(t, "test-nonce", u.Query().Get("nonce"))
+	assert.Equal(t, testRedirectURL, u.Query().Get("redirect_uri"))
+	issuer.authorize(t, authorizationURL)
+
+	// The code can't be exchanged without the code verifier of the authorization request.
+	otherCodeVerifier, err := GenerateCodeVerifier()
+	require.NoError(t, err)
+	_, err = oidc.ExchangeToken(ctx, testRedirectURL, "test-code", otherCodeVerifier)
+	require.ErrorContains(t, err, "invalid_grant")
+	idToken, err := oidc.ExchangeToken(ctx, testRedirectURL, "test-code", codeVerifier)
+	require.NoError(t, err)
+
+	_, err = oidc.VerifyIDToken(ctx, idToken, "other-nonce")
+	require.ErrorContains(t, err, "nonce")
+	claims, err := oidc.VerifyIDToken(ctx, idToken, "test-nonce")
+	require.NoError(t, err)
+	userInfo, err := oidc.UserInfo(claims)
+	require.NoError(t, err)
+	require.Equal(t, "john", userInfo.Identifier)
+	require.Equal(t, "John Doe", userInfo.DisplayName)
+	require.Equal(t, "john@example.com", userInfo.Email)
+	// The email isn't asserted to be verified without the email_verified claim.
+	require.False(t, userInfo.EmailVerified)
+	require.Equal(t, []string{"john@example.com"}, userInfo.Claims["email"])
+	// The JWKS is cached.
+	require.Equal(t, int32(1), issuer.jwksRequests.Load())
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	const testClientID = "test-client-id"
+	issuer := newMockIssuer(t, testClientID)
+	oidc, err := NewIdentityProvider(&store.IdentityProviderOIDCConfig{
+		Issuer:   issuer.URL,
+		ClientID: testClientID,
+		FieldMapping: &store.FieldMapping{
+			Identifier: "sub",
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		claims      jwt.MapClaims
+		containsErr string
+	}{
+		{
+			name:        "other issuer",
+			claims:      jwt.MapClaims{"iss": "https://evil.example.com"},
+			containsErr: "unexpected id token issuer",
+		},
+		{
+			name:        "other audience",
+			claims:      jwt.MapClaims{"aud": "other-client-id"},
+			containsErr: "unexpected id token audience",
+		},
+		{
+			name:        "other authorized party",
+			claims:      jwt.MapClaims{"aud": []string{testClientID, "other-client-id"}, "azp": "other-client-id"},
+			containsErr: "unexpected id token authorized party",
+		},
+		{
+			name:        "expired",
+			claims:      jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()},
+			containsErr: "expired",
+		},
+		{
+			name:        "no nonce",
+			claims:      jwt.MapClaims{"nonce": nil},
+			containsErr: "unexpected id token nonce",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := jwt.MapClaims{"nonce": "test-nonce"}
+			for k, v := range test.claims {
+				claims[k] = v
+			}
+			_, err := oidc.VerifyIDToken(ctx, issuer.signIDToken(t, claims), "test-nonce")
+			require.ErrorContains(t, err, test.containsErr)
+		})
+	}
+
+	// The tokens signed with a symmetric key are rejected.
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":   issuer.URL,
+		"sub":   "248289761001",
+		"aud":   testClientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "test-nonce",
+	})
+	token.Header["kid"] = issuer.kid
+	hs256IDToken, err := token.SignedString([]byte("test-client-secret"))
+	require.NoError(t, err)
+	_, err = oidc.VerifyIDToken(ctx, hs256IDToken, "test-nonce")
+	require.ErrorContains(t, err, "signing method HS256 is invalid")
+
+	// The JWKS is refetched when the issuer rotates its keys.
+	idToken := issuer.signIDToken(t, jwt.MapClaims{"nonce": "test-nonce"})
+	_, err = oidc.VerifyIDToken(ctx, idToken, "test-nonce")
+	require.NoError(t, err)
+	jwksRequests := issuer.jwksRequests.Load()
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	oldKid, oldKey := issuer.kid, issuer.key
+	issuer.kid, issuer.key = "key-2", ecKey
+	newIDToken := issuer.signIDToken(t, jwt.MapClaims{"nonce": "test-nonce"})
+	// The JWKS isn't refetched again until the interval has passed since the last fetch.
+	_, err = oidc.VerifyIDToken(ctx, newIDToken, "test-nonce")
+	require.ErrorContains(t, err, "unexpected id token kid=key-2")
+	require.Equal(t, jwksRequests, issuer.jwksRequests.Load())
+	cache, ok := keySetCache.Load(issuer.URL + "/jwks")
+	require.True(t, ok)
+	cache.(*cachedKeySet).fetchedAt = time.Now().Add(-keySetRefetchInterval)
+	_, err = oidc.VerifyIDToken(ctx, newIDToken, "test-nonce")
+	require.NoError(t, err)
+	require.Equal(t, jwksRequests+1, issuer.jwksRequests.Load())
+	// The tokens signed with the retired key are rejected, and don't refetch the JWKS.
+	_, err = oidc.VerifyIDToken(ctx, idToken, "test-nonce")
+	require.ErrorContains(t, err, "unexpected id token kid=key-1")
+	require.Equal(t, jwksRequests+1, issuer.jwksRequests.Load())
+
+	// The tokens signed with an unknown key are rejected, even with a known kid.
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer.kid, issuer.key = oldKid, otherKey
+	forgedIDToken := issuer.signIDToken(t, jwt.MapClaims{"nonce": "test-nonce"})
+	issuer.key = oldKey
+	_, err = oidc.VerifyIDToken(ctx, forgedIDToken, "test-nonce")
+	require.Error(t, err)
+}
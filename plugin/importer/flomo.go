@@ -0,0 +1,228 @@
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// flomoTimeLayout is the layout of the creation times of Flomo, which are in the time zone of the exporter.
+const flomoTimeLayout = "2006-01-02 15:04:05"
+
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+
+// parseFlomo parses the HTML files of a Flomo export, in which each memo is a `<div class="memo">`.
+// The attachments are referred by `src` or `href` relative to the HTML file.
+// Creation times are read in the local time zone.
+func parseFlomo(fsys fs.FS) ([]*Note, error) {
+	names, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	// The export is usually a single folder in the zip file.
+	nested, err := fs.Glob(fsys, "*/*.html")
+	if err != nil {
+		return nil, err
+	}
+	names = append(names, nested...)
+
+	notes := []*Note{}
+	for _, name := range names {
+		file, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		document, err := html.Parse(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		for _, memo := range findElements(document, "memo") {
+			note, err := convertFlomoMemo(fsys, path.Dir(name), memo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			notes = append(notes, note)
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedTs < notes[j].CreatedTs
+	})
+	return notes, nil
+}
+
+func convertFlomoMemo(fsys fs.FS, dir string, memo *html.Node) (*Note, error) {
+	note := &Note{}
+	for _, node := range findElements(memo, "time") {
+		createdTime, err := time.ParseInLocation(flomoTimeLayout, strings.TrimSpace(textContent(node)), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time: %w", err)
+		}
+		note.CreatedTs = createdTime.Unix()
+	}
+	for _, node := range findElements(memo, "content") {
+		builder := &strings.Builder{}
+		renderMarkdown(builder, node, "")
+		note.Content = cleanMarkdown(builder.String())
+	}
+	for _, node := range findElements(memo, "files") {
+		for _, link := range findLinks(node) {
+			name, err := url.PathUnescape(link)
+			if err != nil || strings.Contains(name, "://") {
+				continue
+			}
+			attachment, err := readAttachment(fsys, path.Join(dir, name), "")
+			if err != nil {
+				return nil, err
+			}
+			note.Attachments = append(note.Attachments, attachment)
+		}
+	}
+	return note, nil
+}
+
+// findElements returns the elements under node with class, excluding the ones nested in a found one.
+func findElements(node *html.Node, class string) []*html.Node {
+	elements := []*html.Node{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && hasClass(child, class) {
+			elements = append(elements, child)
+			continue
+		}
+		elements = append(elements, findElements(child, class)...)
+	}
+	return elements
+}
+
+func hasClass(node *html.Node, class string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == "class" {
+			for _, field := range strings.Fields(attr.Val) {
+				if field == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func getAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// findLinks returns the `src` and `href` of the elements under node.
+func findLinks(node *html.Node) []string {
+	links := []string{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			if link := getAttr(child, "src"); link != "" {
+				links = append(links, link)
+			} else if link := getAttr(child, "href"); link != "" {
+				links = append(links, link)
+			}
+		}
+		links = append(links, findLinks(child)...)
+	}
+	return links
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	builder := strings.Builder{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		builder.WriteString(textContent(child))
+	}
+	return builder.String()
+}
+
+// renderMarkdown writes the Markdown of the children of node, in which linePrefix begins every new line.
+func renderMarkdown(builder *strings.Builder, node *html.Node, linePrefix string) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			builder.WriteString(strings.ReplaceAll(child.Data, "\n", "\n"+linePrefix))
+			continue
+		}
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		switch child.DataAtom {
+		case atom.P, atom.Div:
+			renderMarkdown(builder, child, linePrefix)
+			builder.WriteString("\n\n" + linePrefix)
+		case atom.Br:
+			builder.WriteString("\n" + linePrefix)
+		case atom.Strong, atom.B:
+			builder.WriteString("**")
+			renderMarkdown(builder, child, linePrefix)
+			builder.WriteString("**")
+		case atom.Em, atom.I:
+			builder.WriteString("*")
+			renderMarkdown(builder, child, linePrefix)
+			builder.WriteString("*")
+		case atom.S, atom.Del:
+			builder.WriteString("~~")
+			renderMarkdown(builder, child, linePrefix)
+			builder.WriteString("~~")
+		case atom.Code:
+			builder.WriteString("`" + textContent(child) + "`")
+		case atom.A:
+			builder.WriteString("[")
+			renderMarkdown(builder, child, linePrefix)
+			builder.WriteString("](" + getAttr(child, "href") + ")")
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			builder.WriteString(strings.Repeat("#", int(child.Data[1]-'0')) + " ")
+			renderMarkdown(builder, child, linePrefix)
+			builder.WriteString("\n\n" + linePrefix)
+		case atom.Blockquote:
+			builder.WriteString("> ")
+			renderMarkdown(builder, child, linePrefix+"> ")
+			builder.WriteString("\n\n" + linePrefix)
+		case atom.Ul, atom.Ol:
+			index := 0
+			for item := child.FirstChild; item != nil; item = item.NextSibling {
+				if item.Type != html.ElementNode || item.DataAtom != atom.Li {
+					continue
+				}
+				index++
+				marker := "- "
+				if child.DataAtom == atom.Ol {
+					marker = fmt.Sprintf("%d. ", index)
+				}
+				itemBuilder := &strings.Builder{}
+				renderMarkdown(itemBuilder, item, linePrefix+strings.Repeat(" ", len(marker)))
+				builder.WriteString(marker + cleanMarkdown(itemBuilder.String()) + "\n" + linePrefix)
+			}
+			builder.WriteString("\n" + linePrefix)
+		default:
+			renderMarkdown(builder, child, linePrefix)
+		}
+	}
+}
+
+// cleanMarkdown trims the trailing spaces of lines and the blank lines more than one in rendered Markdown.
+func cleanMarkdown(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	content = blankLinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(content)
+}
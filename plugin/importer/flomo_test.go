@@ -0,0 +1,39 @@
+package importer
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlomo(t *testing.T) {
+	fsys := fstest.MapFS{
+		"flomo@user-20230801/user的笔记.html": &fstest.MapFile{Data: []byte(`<html><body><div class="memos">
+<div class="memo">
+  <div class="time">2023-07-02 08:30:00</div>
+  <div class="content"><p>Second memo with <strong>bold</strong> text</p><ul><li><p>one</p></li><li><p>two &amp; three</p></li></ul><p>#reading/books</p></div>
+  <div class="files"><img src="file/2023-07-02/1/cover%20image.png" /></div>
+</div>
+<div class="memo">
+  <div class="time">2023-07-01 20:00:00</div>
+  <div class="content"><p>First memo<br>next line</p><ol><li><p>a</p></li><li><p>b</p></li></ol></div>
+  <div class="files"></div>
+</div>
+</div></body></html>`)},
+		"flomo@user-20230801/file/2023-07-02/1/cover image.png": &fstest.MapFile{Data: []byte("png")},
+	}
+
+	notes, err := Parse(SourceFlomo, fsys)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	createdTime := time.Date(2023, 7, 1, 20, 0, 0, 0, time.Local)
+	require.Equal(t, createdTime.Unix(), notes[0].CreatedTs)
+	require.Equal(t, "First memo\nnext line\n\n1. a\n2. b", notes[0].Content)
+	require.Empty(t, notes[0].Attachments)
+
+	require.Equal(t, "Second memo with **bold** text\n\n- one\n- two & three\n\n#reading/books", notes[1].Content)
+	require.Equal(t, []*Attachment{{Filename: "cover image.png", Type: "image/png", Blob: []byte("png")}}, notes[1].Attachments)
+}
@@ -0,0 +1,116 @@
+// Package importer parses the exports of other note-taking tools into notes which can be saved as memos.
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"mime"
+	"path"
+	"strings"
+)
+
+// Source is the tool an export comes from.
+type Source string
+
+const (
+	// SourceKeep is the Google Keep export of Google Takeout.
+	SourceKeep Source = "keep"
+	// SourceFlomo is the HTML export of Flomo.
+	SourceFlomo Source = "flomo"
+	// SourceObsidian is an Obsidian vault.
+	SourceObsidian Source = "obsidian"
+)
+
+// Note is a note parsed from an export.
+type Note struct {
+	// Content is the Markdown content, in which tags are written as `#tag`.
+	Content   string
+	CreatedTs int64
+	// UpdatedTs is 0 if the export doesn't record it.
+	UpdatedTs   int64
+	Archived    bool
+	Pinned      bool
+	Attachments []*Attachment
+}
+
+// Attachment is a file attached to a note.
+type Attachment struct {
+	Filename string
+	Type     string
+	Blob     []byte
+}
+
+// Parse parses the notes of an export of source in fsys, which is the extracted folder or zip file of the export.
+func Parse(source Source, fsys fs.FS) ([]*Note, error) {
+	switch source {
+	case SourceKeep:
+		return parseKeep(fsys)
+	case SourceFlomo:
+		return parseFlomo(fsys)
+	case SourceObsidian:
+		return parseObsidian(fsys)
+	default:
+		return nil, fmt.Errorf("unsupported source %q", source)
+	}
+}
+
+// readAttachment reads the file of name in fsys as an attachment.
+func readAttachment(fsys fs.FS, name, mimeType string) (*Attachment, error) {
+	blob, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %s: %w", name, err)
+	}
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(path.Ext(name))
+	}
+	return &Attachment{
+		Filename: path.Base(name),
+		Type:     mimeType,
+		Blob:     blob,
+	}, nil
+}
+
+// formatTag returns the `#tag` syntax of a tag name, in which the characters ending a tag are replaced.
+func formatTag(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	name = strings.Map(func(r rune) rune {
+		if r == '#' || r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return '-'
+		}
+		return r
+	}, name)
+	if name == "" {
+		return ""
+	}
+	return "#" + name
+}
+
+// appendTags appends the tags which aren't written in content yet to the end of it.
+func appendTags(content string, names []string) string {
+	tags := []string{}
+	for _, name := range names {
+		tag := formatTag(name)
+		if tag == "" || containsTag(content, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		return content
+	}
+
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		content += "\n\n"
+	}
+	return content + strings.Join(tags, " ")
+}
+
+func containsTag(content, tag string) bool {
+	for _, field := range strings.Fields(content) {
+		if field == tag {
+			return true
+		}
+	}
+	return false
+}
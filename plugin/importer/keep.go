@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+type keepNote struct {
+	Title                   string `json:"title"`
+	TextContent             string `json:"textContent"`
+	IsTrashed               bool   `json:"isTrashed"`
+	IsPinned                bool   `json:"isPinned"`
+	IsArchived              bool   `json:"isArchived"`
+	CreatedTimestampUsec    int64  `json:"createdTimestampUsec"`
+	UserEditedTimestampUsec int64  `json:"userEditedTimestampUsec"`
+	ListContent             []struct {
+		Text      string `json:"text"`
+		IsChecked bool   `json:"isChecked"`
+	} `json:"listContent"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Annotations []struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	} `json:"annotations"`
+	Attachments []struct {
+		FilePath string `json:"filePath"`
+		Mimetype string `json:"mimetype"`
+	} `json:"attachments"`
+}
+
+// parseKeep parses the JSON files of notes in a Google Takeout export, e.g. `Takeout/Keep/*.json`.
+// The attachments are the files next to them. Notes in trash are skipped.
+func parseKeep(fsys fs.FS) ([]*Note, error) {
+	names := []string{}
+	if err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(path.Ext(name), ".json") {
+			names = append(names, name)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	notes := []*Note{}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		keepNote := &keepNote{}
+		// Other JSON files of Takeout aren't notes.
+		if err := json.Unmarshal(data, keepNote); err != nil || keepNote.UserEditedTimestampUsec == 0 {
+			continue
+		}
+		if keepNote.IsTrashed {
+			continue
+		}
+
+		note, err := convertKeepNote(fsys, path.Dir(name), keepNote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		notes = append(notes, note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedTs < notes[j].CreatedTs
+	})
+	return notes, nil
+}
+
+func convertKeepNote(fsys fs.FS, dir string, keepNote *keepNote) (*Note, error) {
+	parts := []string{}
+	if title := strings.TrimSpace(keepNote.Title); title != "" {
+		parts = append(parts, "# "+title)
+	}
+	if text := strings.TrimSpace(keepNote.TextContent); text != "" {
+		parts = append(parts, text)
+	}
+	if len(keepNote.ListContent) > 0 {
+		items := []string{}
+		for _, item := range keepNote.ListContent {
+			checkbox := "[ ]"
+			if item.IsChecked {
+				checkbox = "[x]"
+			}
+			items = append(items, fmt.Sprintf("- %s %s", checkbox, item.Text))
+		}
+		parts = append(parts, strings.Join(items, "\n"))
+	}
+	if len(keepNote.Annotations) > 0 {
+		links := []string{}
+		for _, annotation := range keepNote.Annotations {
+			if annotation.URL == "" {
+				continue
+			}
+			title := annotation.Title
+			if title == "" {
+				title = annotation.URL
+			}
+			links = append(links, fmt.Sprintf("- [%s](%s)", title, annotation.URL))
+		}
+		if len(links) > 0 {
+			parts = append(parts, strings.Join(links, "\n"))
+		}
+	}
+	labels := []string{}
+	for _, label := range keepNote.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	note := &Note{
+		Content:   appendTags(strings.Join(parts, "\n\n"), labels),
+		CreatedTs: keepNote.CreatedTimestampUsec / 1e6,
+		UpdatedTs: keepNote.UserEditedTimestampUsec / 1e6,
+		Archived:  keepNote.IsArchived,
+		Pinned:    keepNote.IsPinned,
+	}
+	if note.CreatedTs == 0 {
+		note.CreatedTs = note.UpdatedTs
+	}
+	for _, attachment := range keepNote.Attachments {
+		name := path.Join(dir, attachment.FilePath)
+		// Takeout names some JPEG files with another extension than the one in filePath.
+		if _, err := fs.Stat(fsys, name); err != nil {
+			ext := path.Ext(name)
+			for _, alternative := range []string{".jpg", ".jpeg", ".png"} {
+				if _, err := fs.Stat(fsys, strings.TrimSuffix(name, ext)+alternative); err == nil {
+					name = strings.TrimSuffix(name, ext) + alternative
+					break
+				}
+			}
+		}
+		file, err := readAttachment(fsys, name, attachment.Mimetype)
+		if err != nil {
+			return nil, err
+		}
+		note.Attachments = append(note.Attachments, file)
+	}
+	return note, nil
+}
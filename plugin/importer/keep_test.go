@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeep(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Takeout/Keep/Groceries.json": &fstest.MapFile{Data: []byte(`{
+			"title": "Groceries",
+			"isPinned": true,
+			"isArchived": false,
+			"isTrashed": false,
+			"createdTimestampUsec": 1690000000000000,
+			"userEditedTimestampUsec": 1690000100000000,
+			"listContent": [{"text": "Milk", "isChecked": true}, {"text": "Eggs", "isChecked": false}],
+			"labels": [{"name": "home"}, {"name": "weekly shopping"}],
+			"attachments": [{"filePath": "photo.jpeg", "mimetype": "image/jpeg"}]
+		}`)},
+		"Takeout/Keep/photo.jpg": &fstest.MapFile{Data: []byte("jpeg")},
+		"Takeout/Keep/Idea.json": &fstest.MapFile{Data: []byte(`{
+			"title": "",
+			"textContent": "An idea #home",
+			"isArchived": true,
+			"userEditedTimestampUsec": 1680000000000000,
+			"labels": [{"name": "home"}]
+		}`)},
+		"Takeout/Keep/Trashed.json": &fstest.MapFile{Data: []byte(`{
+			"textContent": "Trashed",
+			"isTrashed": true,
+			"userEditedTimestampUsec": 1680000000000000
+		}`)},
+		"Takeout/Keep/Labels.txt":      &fstest.MapFile{Data: []byte("home\n")},
+		"Takeout/archive_browser.json": &fstest.MapFile{Data: []byte(`{"files": []}`)},
+	}
+
+	notes, err := Parse(SourceKeep, fsys)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	require.Equal(t, "An idea #home", notes[0].Content)
+	require.Equal(t, int64(1680000000), notes[0].CreatedTs)
+	require.True(t, notes[0].Archived)
+
+	require.Equal(t, "# Groceries\n\n- [x] Milk\n- [ ] Eggs\n\n#home #weekly-shopping", notes[1].Content)
+	require.Equal(t, int64(1690000000), notes[1].CreatedTs)
+	require.Equal(t, int64(1690000100), notes[1].UpdatedTs)
+	require.True(t, notes[1].Pinned)
+	require.Equal(t, []*Attachment{{Filename: "photo.jpg", Type: "image/jpeg", Blob: []byte("jpeg")}}, notes[1].Attachments)
+}
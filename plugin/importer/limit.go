@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// ErrTooLarge is returned when reading more than the limits of a LimitFS.
+var ErrTooLarge = errors.New("export is too large")
+
+// LimitFS returns fsys, whose files are read up to maxFileSize bytes each and up to maxTotalSize bytes altogether.
+// The compressed size of a zip file tells nothing about how large it gets once inflated in memory.
+func LimitFS(fsys fs.FS, maxFileSize, maxTotalSize int64) fs.FS {
+	return &limitFS{
+		fsys:        fsys,
+		maxFileSize: maxFileSize,
+		remaining:   maxTotalSize,
+	}
+}
+
+type limitFS struct {
+	fsys        fs.FS
+	maxFileSize int64
+
+	mutex     sync.Mutex
+	remaining int64
+}
+
+func (l *limitFS) Open(name string) (fs.File, error) {
+	file, err := l.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		return file, nil
+	}
+	// The size of the files of zip archives is the uncompressed size declared in their headers,
+	// which is checked before reading so that no buffer is allocated for it.
+	if info.Size() > l.maxFileSize {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", name, ErrTooLarge)
+	}
+	return &limitFile{
+		File:   file,
+		fs:     l,
+		name:   name,
+		reader: &io.LimitedReader{R: file, N: l.maxFileSize + 1},
+	}, nil
+}
+
+func (l *limitFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(l.fsys, name)
+}
+
+func (l *limitFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(l.fsys, name)
+}
+
+// consume counts n more bytes read, returning false once the total is over the limit.
+func (l *limitFS) consume(n int64) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.remaining -= n
+	return l.remaining >= 0
+}
+
+type limitFile struct {
+	fs.File
+	fs   *limitFS
+	name string
+	// reader reads one byte more than the limit, so that the files lying about their size are caught.
+	reader *io.LimitedReader
+}
+
+func (f *limitFile) Read(p []byte) (int, error) {
+	n, err := f.reader.Read(p)
+	if f.reader.N == 0 || !f.fs.consume(int64(n)) {
+		return n, fmt.Errorf("%s: %w", f.name, ErrTooLarge)
+	}
+	return n, err
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Notes/Note.md": &fstest.MapFile{Data: []byte("![[image.png]]\n")},
+		"Notes/image.png": &fstest.MapFile{
+			Data: bytes.Repeat([]byte("a"), 100),
+		},
+	}
+
+	notes, err := Parse(SourceObsidian, LimitFS(fsys, 100, 200))
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Len(t, notes[0].Attachments, 1)
+
+	// A file is larger than the limit.
+	_, err = Parse(SourceObsidian, LimitFS(fsys, 99, 200))
+	require.ErrorIs(t, err, ErrTooLarge)
+	// All the files are larger than the limit.
+	_, err = Parse(SourceObsidian, LimitFS(fsys, 100, 110))
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	// The file declaring a smaller size than its content is caught while reading.
+	_, err = fs.ReadFile(LimitFS(&lyingFS{fsys}, 10, 200), "Notes/image.png")
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+// lyingFS declares the files to be 1 byte large, like the headers of forged zip files.
+type lyingFS struct {
+	fs.FS
+}
+
+func (l *lyingFS) Open(name string) (fs.File, error) {
+	file, err := l.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &lyingFile{file}, nil
+}
+
+type lyingFile struct {
+	fs.File
+}
+
+func (f *lyingFile) Stat() (fs.FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return &lyingFileInfo{info}, nil
+}
+
+type lyingFileInfo struct {
+	fs.FileInfo
+}
+
+func (*lyingFileInfo) Size() int64 {
+	return 1
+}
@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// obsidianEmbedRegexp matches `![[file]]` and `![[file|size]]`.
+	obsidianEmbedRegexp = regexp.MustCompile(`!\[\[([^\]|]+)(\|[^\]]*)?\]\]`)
+	// obsidianWikiLinkRegexp matches `[[note]]` and `[[note|alias]]`.
+	obsidianWikiLinkRegexp = regexp.MustCompile(`\[\[([^\]|]+)(\|([^\]]*))?\]\]`)
+	// markdownImageRegexp matches `![alt](path)`.
+	markdownImageRegexp = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)\)`)
+
+	obsidianTimeLayouts = []string{
+		time.RFC3339,
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02",
+	}
+)
+
+// parseObsidian parses the Markdown notes of an Obsidian vault. Hidden folders such as `.obsidian` are skipped.
+// Embedded files become attachments, and wiki links become the text of them.
+// The creation time is read from `created` or `date` of the frontmatter, or else the modification time of the file.
+func parseObsidian(fsys fs.FS) ([]*Note, error) {
+	names := []string{}
+	// files are the attachable files of the vault by their base names, as embeds usually refer to the shortest path.
+	files := map[string]string{}
+	if err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.EqualFold(path.Ext(name), ".md") {
+			names = append(names, name)
+		} else if _, ok := files[d.Name()]; !ok {
+			files[d.Name()] = name
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	notes := []*Note{}
+	for _, name := range names {
+		note, err := convertObsidianNote(fsys, name, files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		notes = append(notes, note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedTs < notes[j].CreatedTs
+	})
+	return notes, nil
+}
+
+func convertObsidianNote(fsys fs.FS, name string, files map[string]string) (*Note, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	frontmatter, body, err := splitObsidianFrontmatter(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	note := &Note{
+		CreatedTs: info.ModTime().Unix(),
+		UpdatedTs: info.ModTime().Unix(),
+	}
+	for _, key := range []string{"created", "date"} {
+		if createdTs, ok := parseObsidianTime(frontmatter[key]); ok {
+			note.CreatedTs = createdTs
+			break
+		}
+	}
+
+	// Embedded files become attachments, other embeds are notes which become their names.
+	var attachErr error
+	dir := path.Dir(name)
+	body = obsidianEmbedRegexp.ReplaceAllStringFunc(body, func(match string) string {
+		target := strings.TrimSpace(obsidianEmbedRegexp.FindStringSubmatch(match)[1])
+		filePath := findObsidianFile(fsys, dir, target, files)
+		if filePath == "" {
+			return target
+		}
+		attachment, err := readAttachment(fsys, filePath, "")
+		if err != nil {
+			attachErr = err
+			return match
+		}
+		note.Attachments = append(note.Attachments, attachment)
+		return ""
+	})
+	body = markdownImageRegexp.ReplaceAllStringFunc(body, func(match string) string {
+		target, err := url.PathUnescape(markdownImageRegexp.FindStringSubmatch(match)[1])
+		if err != nil || strings.Contains(target, "://") {
+			return match
+		}
+		filePath := findObsidianFile(fsys, dir, target, files)
+		if filePath == "" {
+			return match
+		}
+		attachment, err := readAttachment(fsys, filePath, "")
+		if err != nil {
+			attachErr = err
+			return match
+		}
+		note.Attachments = append(note.Attachments, attachment)
+		return ""
+	})
+	if attachErr != nil {
+		return nil, attachErr
+	}
+	body = obsidianWikiLinkRegexp.ReplaceAllStringFunc(body, func(match string) string {
+		submatch := obsidianWikiLinkRegexp.FindStringSubmatch(match)
+		if alias := strings.TrimSpace(submatch[3]); alias != "" {
+			return alias
+		}
+		return strings.TrimSpace(submatch[1])
+	})
+
+	body = strings.TrimSpace(blankLinesRegexp.ReplaceAllString(body, "\n\n"))
+	title := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if !strings.HasPrefix(body, "# ") {
+		body = strings.TrimSpace("# " + title + "\n\n" + body)
+	}
+	note.Content = appendTags(body, parseObsidianTags(frontmatter["tags"]))
+	return note, nil
+}
+
+// splitObsidianFrontmatter returns the YAML frontmatter of a note and the body after it.
+func splitObsidianFrontmatter(text string) (map[string]any, string, error) {
+	frontmatter := map[string]any{}
+	if !strings.HasPrefix(text, "---\n") {
+		return frontmatter, text, nil
+	}
+	end := strings.Index(text[3:], "\n---")
+	if end < 0 {
+		return frontmatter, text, nil
+	}
+	if err := yaml.Unmarshal([]byte(text[4:end+4]), &frontmatter); err != nil {
+		return nil, "", fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	body := text[end+7:]
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 && strings.TrimSpace(body[:newline]) == "" {
+		body = body[newline+1:]
+	}
+	return frontmatter, body, nil
+}
+
+// parseObsidianTags returns the tags of the frontmatter, which is a list or a string separated by commas or spaces.
+func parseObsidianTags(value any) []string {
+	tags := []string{}
+	switch v := value.(type) {
+	case string:
+		tags = append(tags, strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })...)
+	case []any:
+		for _, item := range v {
+			if tag, ok := item.(string); ok {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+func parseObsidianTime(value any) (int64, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Unix(), true
+	case string:
+		for _, layout := range obsidianTimeLayouts {
+			if t, err := time.ParseInLocation(layout, strings.TrimSpace(v), time.Local); err == nil {
+				return t.Unix(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// findObsidianFile returns the path in the vault of a file referred by target in a note of dir,
+// or an empty string if it's not an attachable file.
+func findObsidianFile(fsys fs.FS, dir, target string, files map[string]string) string {
+	target = strings.TrimPrefix(target, "/")
+	if strings.EqualFold(path.Ext(target), ".md") || path.Ext(target) == "" {
+		return ""
+	}
+	for _, name := range []string{path.Join(dir, target), path.Clean(target)} {
+		if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+			return name
+		}
+	}
+	return files[path.Base(target)]
+}
@@ -0,0 +1,40 @@
+package importer
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseObsidian(t *testing.T) {
+	modTime := time.Unix(1690000000, 0)
+	fsys := fstest.MapFS{
+		"Daily/2023-07-01.md": &fstest.MapFile{
+			Data: []byte("---\ncreated: 2023-07-01 09:00\ntags: [journal, work log]\n---\nMet with [[Alice Smith|Alice]] about [[Project X]].\n\n![[diagram.png|300]]\n![chart](assets/chart.svg)\n"),
+		},
+		"Notes/Ideas.md": &fstest.MapFile{
+			Data:    []byte("# My ideas\n\nSomething #idea\n![[Other note]]\n"),
+			ModTime: modTime,
+		},
+		"Notes/diagram.png":        &fstest.MapFile{Data: []byte("png")},
+		"Daily/assets/chart.svg":   &fstest.MapFile{Data: []byte("svg")},
+		".obsidian/workspace.json": &fstest.MapFile{Data: []byte("{}")},
+		".trash/Deleted.md":        &fstest.MapFile{Data: []byte("deleted")},
+	}
+
+	notes, err := Parse(SourceObsidian, fsys)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	createdTime := time.Date(2023, 7, 1, 9, 0, 0, 0, time.Local)
+	require.Equal(t, createdTime.Unix(), notes[0].CreatedTs)
+	require.Equal(t, "# 2023-07-01\n\nMet with Alice about Project X.\n\n#journal #work-log", notes[0].Content)
+	require.Len(t, notes[0].Attachments, 2)
+	require.Equal(t, "diagram.png", notes[0].Attachments[0].Filename)
+	require.Equal(t, "chart.svg", notes[0].Attachments[1].Filename)
+
+	require.Equal(t, modTime.Unix(), notes[1].CreatedTs)
+	require.Equal(t, "# My ideas\n\nSomething #idea\nOther note", notes[1].Content)
+}
@@ -0,0 +1,175 @@
+// Package mail is the plugin sending plain text emails with a SMTP server.
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// timeout is the timeout of sending an email, from connecting to the server to quitting.
+const timeout = 30 * time.Second
+
+// Security is how the connection to the SMTP server is secured.
+type Security string
+
+const (
+	// SecurityNone doesn't encrypt the connection, the credentials are only sent to local servers then.
+	SecurityNone Security = "NONE"
+	// SecurityStartTLS upgrades the connection with the STARTTLS command, usually on the port 587.
+	SecurityStartTLS Security = "STARTTLS"
+	// SecurityTLS connects with implicit TLS, usually on the port 465.
+	SecurityTLS Security = "TLS"
+)
+
+// Config is the configuration of the SMTP server sending the emails.
+type Config struct {
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	Security  Security
+	FromEmail string
+	FromName  string
+}
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Validate checks the configuration is complete.
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return errors.New(`the field "host" is empty but required`)
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return errors.Errorf("invalid port %d", c.Port)
+	}
+	switch c.Security {
+	case SecurityNone, SecurityStartTLS, SecurityTLS:
+	default:
+		return errors.Errorf("invalid security %q", c.Security)
+	}
+	if _, err := mail.ParseAddress(c.FromEmail); err != nil {
+		return errors.Wrapf(err, "invalid from email %q", c.FromEmail)
+	}
+	if strings.ContainsAny(c.FromName, "\r\n") {
+		return errors.New("invalid from name")
+	}
+	return nil
+}
+
+// Send sends the message with the SMTP server.
+func Send(config *Config, message *Message) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	from := &mail.Address{Name: config.FromName, Address: config.FromEmail}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return errors.Wrapf(err, "invalid recipient %q", message.To)
+	}
+	data, err := buildMessage(from, to, message)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	tlsConfig := &tls.Config{
+		ServerName: config.Host,
+		MinVersion: tls.VersionTLS12,
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if config.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the smtp server")
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to greet the smtp server")
+	}
+	defer client.Close()
+
+	if config.Security == SecurityStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return errors.Wrap(err, "failed to start tls")
+		}
+	}
+	if config.Username != "" {
+		// The plain authentication refuses to send the credentials over unencrypted connections to remote servers.
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return errors.Wrap(err, "failed to authenticate")
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return errors.Wrap(err, "failed to set the sender")
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return errors.Wrap(err, "failed to set the recipient")
+	}
+	w, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to start the message")
+	}
+	if _, err := w.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write the message")
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "failed to send the message")
+	}
+	return client.Quit()
+}
+
+// buildMessage returns the message with its headers, whose body is encoded in quoted-printable.
+func buildMessage(from, to *mail.Address, message *Message) ([]byte, error) {
+	if strings.ContainsAny(message.Subject, "\r\n") {
+		return nil, errors.New("invalid subject")
+	}
+
+	buf := &bytes.Buffer{}
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%d.%s>", time.Now().UnixNano(), from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/test"
+)
+
+func TestSend(t *testing.T) {
+	server := test.NewSMTPServer(t, "memos", "secret")
+	config := &Config{
+		Host:      server.Host,
+		Port:      server.Port,
+		Username:  "memos",
+		Password:  "secret",
+		Security:  SecurityNone,
+		FromEmail: "noreply@example.com",
+		FromName:  "Memos",
+	}
+	err := Send(config, &Message{
+		To:      "Steven <steven@example.com>",
+		Subject: "Réinitialiser le mot de passe",
+		Body:    "Hello,\nthe quick brown fox jumps over the lazy dog, then jumps over the lazy dog again and again and again.\n.\n",
+	})
+	require.NoError(t, err)
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, "noreply@example.com", messages[0].From)
+	require.Equal(t, []string{"steven@example.com"}, messages[0].To)
+	require.Equal(t, `"Memos" <noreply@example.com>`, messages[0].Header("From"))
+	require.Equal(t, "=?utf-8?q?R=C3=A9initialiser_le_mot_de_passe?=", messages[0].Header("Subject"))
+	require.Equal(t, "Hello,\nthe quick brown fox jumps over the lazy dog, then jumps over the lazy dog again and again and again.\n.\n", messages[0].Body())
+}
+
+func TestSendErrors(t *testing.T) {
+	server := test.NewSMTPServer(t, "memos", "secret")
+	config := &Config{
+		Host:      server.Host,
+		Port:      server.Port,
+		Username:  "memos",
+		Password:  "wrong",
+		Security:  SecurityNone,
+		FromEmail: "noreply@example.com",
+	}
+	message := &Message{
+		To:      "steven@example.com",
+		Subject: "Hello",
+		Body:    "Hello",
+	}
+
+	// The wrong password is rejected.
+	require.Error(t, Send(config, message))
+
+	// The headers can't be injected.
+	config.Password = "secret"
+	require.Error(t, Send(config, &Message{To: "steven@example.com", Subject: "Hello\r\nBcc: eve@example.com", Body: "Hello"}))
+	require.Error(t, Send(config, &Message{To: "steven@example.com\r\nBcc: eve@example.com", Subject: "Hello", Body: "Hello"}))
+
+	// The configuration is validated.
+	config.Security = "SSL"
+	require.Error(t, Send(config, message))
+	require.Empty(t, server.Messages())
+
+	config.Security = SecurityNone
+	require.NoError(t, Send(config, message))
+	require.Len(t, server.Messages(), 1)
+}
@@ -0,0 +1,148 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// maxCBORDepth is the maximum nesting of the decoded items, the attestation objects and COSE keys being shallow.
+const maxCBORDepth = 16
+
+// decodeCBOR decodes the first CBOR item of the data as described by RFC 8949, returning the item
+// and the number of bytes it is encoded in. Only the definite lengths used by authenticators are supported.
+// The integers are decoded into int64, the maps into map[any]any keyed by int64 or string.
+func decodeCBOR(data []byte) (any, int, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, int, error) {
+	if depth > maxCBORDepth {
+		return nil, 0, errors.New("cbor item is nested too deeply")
+	}
+	if len(data) == 0 {
+		return nil, 0, errors.New("unexpected end of cbor data")
+	}
+	majorType, additionalInfo := data[0]>>5, data[0]&0x1f
+
+	// The simple values and floats use the additional information differently.
+	if majorType == 7 {
+		switch additionalInfo {
+		case 20:
+			return false, 1, nil
+		case 21:
+			return true, 1, nil
+		case 22, 23:
+			return nil, 1, nil
+		case 26:
+			if len(data) < 5 {
+				return nil, 0, errors.New("unexpected end of cbor data")
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:5]))), 5, nil
+		case 27:
+			if len(data) < 9 {
+				return nil, 0, errors.New("unexpected end of cbor data")
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(data[1:9])), 9, nil
+		default:
+			return nil, 0, errors.Errorf("unsupported cbor simple value %d", additionalInfo)
+		}
+	}
+
+	argument, offset, err := decodeCBORArgument(data, additionalInfo)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch majorType {
+	case 0:
+		if argument > math.MaxInt64 {
+			return nil, 0, errors.New("cbor integer overflows")
+		}
+		return int64(argument), offset, nil
+	case 1:
+		if argument > math.MaxInt64 {
+			return nil, 0, errors.New("cbor integer overflows")
+		}
+		return -1 - int64(argument), offset, nil
+	case 2, 3:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, errors.New("unexpected end of cbor data")
+		}
+		end := offset + int(argument)
+		if majorType == 2 {
+			return append([]byte{}, data[offset:end]...), end, nil
+		}
+		return string(data[offset:end]), end, nil
+	case 4:
+		// Each item is encoded in one byte at least.
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, errors.New("unexpected end of cbor data")
+		}
+		list := make([]any, 0, int(argument))
+		for i := uint64(0); i < argument; i++ {
+			item, n, err := decodeCBORItem(data[offset:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			list = append(list, item)
+			offset += n
+		}
+		return list, offset, nil
+	case 5:
+		if argument > uint64(len(data)-offset)/2 {
+			return nil, 0, errors.New("unexpected end of cbor data")
+		}
+		m := make(map[any]any, int(argument))
+		for i := uint64(0); i < argument; i++ {
+			key, n, err := decodeCBORItem(data[offset:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			offset += n
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, 0, errors.Errorf("unsupported cbor map key type %T", key)
+			}
+			value, n, err := decodeCBORItem(data[offset:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			offset += n
+			m[key] = value
+		}
+		return m, offset, nil
+	case 6:
+		// The semantics of the tags don't matter to the WebAuthn structures.
+		item, n, err := decodeCBORItem(data[offset:], depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		return item, offset + n, nil
+	default:
+		return nil, 0, errors.Errorf("unsupported cbor major type %d", majorType)
+	}
+}
+
+// decodeCBORArgument decodes the argument following the initial byte, returning it and the offset of the content.
+func decodeCBORArgument(data []byte, additionalInfo byte) (uint64, int, error) {
+	switch {
+	case additionalInfo < 24:
+		return uint64(additionalInfo), 1, nil
+	case additionalInfo <= 27:
+		size := 1 << (additionalInfo - 24)
+		if len(data) < 1+size {
+			return 0, 0, errors.New("unexpected end of cbor data")
+		}
+		var argument uint64
+		for _, b := range data[1 : 1+size] {
+			argument = argument<<8 | uint64(b)
+		}
+		return argument, 1 + size, nil
+	case additionalInfo == 31:
+		return 0, 0, errors.New("indefinite length cbor items are not supported")
+	default:
+		return 0, 0, errors.Errorf("invalid cbor additional information %d", additionalInfo)
+	}
+}
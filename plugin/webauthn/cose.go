@@ -0,0 +1,128 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// The COSE algorithms of the supported credential public keys.
+const (
+	AlgorithmES256 = -7
+	AlgorithmEdDSA = -8
+	AlgorithmRS256 = -257
+)
+
+// SupportedAlgorithms are the COSE algorithms of the credential public keys, in order of preference.
+var SupportedAlgorithms = []int{AlgorithmES256, AlgorithmEdDSA, AlgorithmRS256}
+
+// The COSE key parameters of RFC 9053.
+const (
+	coseKeyType      = 1
+	coseAlgorithm    = 3
+	coseCurve        = -1
+	coseX            = -2
+	coseY            = -3
+	coseRSAModulus   = -1
+	coseRSAExponent  = -2
+	coseKeyTypeOKP   = 1
+	coseKeyTypeEC2   = 2
+	coseKeyTypeRSA   = 3
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// publicKey is a credential public key, which verifies the signatures of its algorithm.
+type publicKey struct {
+	algorithm int64
+	key       crypto.PublicKey
+}
+
+// parsePublicKey parses the credential public key encoded as a COSE key.
+func parsePublicKey(coseKey []byte) (*publicKey, error) {
+	item, n, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cose key")
+	}
+	if n != len(coseKey) {
+		return nil, errors.New("unexpected data after cose key")
+	}
+	m, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("cose key is not a map")
+	}
+	keyType, _ := m[int64(coseKeyType)].(int64)
+	algorithm, _ := m[int64(coseAlgorithm)].(int64)
+
+	switch {
+	case keyType == coseKeyTypeEC2 && algorithm == AlgorithmES256:
+		curve, _ := m[int64(coseCurve)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+		y, _ := m[int64(coseY)].([]byte)
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid ec2 cose key")
+		}
+		// Unmarshal checks the point is on the curve.
+		px, py := elliptic.Unmarshal(elliptic.P256(), append(append([]byte{0x04}, x...), y...))
+		if px == nil {
+			return nil, errors.New("invalid ec2 cose key point")
+		}
+		return &publicKey{
+			algorithm: algorithm,
+			key:       &ecdsa.PublicKey{Curve: elliptic.P256(), X: px, Y: py},
+		}, nil
+	case keyType == coseKeyTypeOKP && algorithm == AlgorithmEdDSA:
+		curve, _ := m[int64(coseCurve)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+		if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid okp cose key")
+		}
+		return &publicKey{
+			algorithm: algorithm,
+			key:       ed25519.PublicKey(x),
+		}, nil
+	case keyType == coseKeyTypeRSA && algorithm == AlgorithmRS256:
+		modulus, _ := m[int64(coseRSAModulus)].([]byte)
+		exponent, _ := m[int64(coseRSAExponent)].([]byte)
+		n := new(big.Int).SetBytes(modulus)
+		e := new(big.Int).SetBytes(exponent)
+		if n.BitLen() < 2048 || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa cose key")
+		}
+		return &publicKey{
+			algorithm: algorithm,
+			key:       &rsa.PublicKey{N: n, E: int(e.Int64())},
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported cose key type %d with algorithm %d", keyType, algorithm)
+	}
+}
+
+// verify verifies the signature of the data.
+func (k *publicKey) verify(data, signature []byte) error {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid es256 signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return errors.New("invalid eddsa signature")
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrap(err, "invalid rs256 signature")
+		}
+	default:
+		return errors.Errorf("unsupported algorithm %d", k.algorithm)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// Package webauthn implements the relying party verification of WebAuthn registrations and assertions,
+// with which users sign in with passkeys. See https://www.w3.org/TR/webauthn-2/.
+//
+// The attestation statements aren't verified, as the registrations request the "none" attestation conveyance.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The flags of the authenticator data.
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+)
+
+// ErrSignCountRegression is returned when the signature counter of the authenticator didn't increase,
+// which suggests the credential has been cloned.
+var ErrSignCountRegression = errors.New("the signature counter of the authenticator didn't increase")
+
+// RelyingParty is the relying party of the passkeys, which are scoped to its ID.
+type RelyingParty struct {
+	// ID is the domain of the origin.
+	ID string
+	// Origin is the origin of the pages calling the WebAuthn API.
+	Origin string
+}
+
+// AttestationResponse is the response of the authenticator to a registration, with base64url encoded fields.
+type AttestationResponse struct {
+	ClientDataJSON    string
+	AttestationObject string
+}
+
+// AssertionResponse is the response of the authenticator to an authentication, with base64url encoded fields.
+type AssertionResponse struct {
+	ClientDataJSON    string
+	AuthenticatorData string
+	Signature         string
+	UserHandle        string
+}
+
+// Credential is a registered public key credential, with base64url encoded ID and COSE public key.
+type Credential struct {
+	ID        string
+	PublicKey string
+	SignCount uint32
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	coseKey      []byte
+}
+
+// NewRelyingParty returns the relying party of the origin, such as https://memos.example.com.
+func NewRelyingParty(origin string) (*RelyingParty, error) {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse origin")
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return nil, errors.Errorf("invalid origin %q", origin)
+	}
+	return &RelyingParty{
+		ID:     u.Hostname(),
+		Origin: u.Scheme + "://" + u.Host,
+	}, nil
+}
+
+// GenerateChallenge returns a new random challenge encoded in base64url, which is signed by the authenticator.
+func GenerateChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// VerifyRegistration verifies the response of the authenticator to the registration with the challenge,
+// returning the created credential. The user must have been verified by the authenticator.
+func (rp *RelyingParty) VerifyRegistration(challenge, credentialID string, response *AttestationResponse) (*Credential, error) {
+	if _, err := rp.verifyClientData(response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	attestationObject, err := decodeBase64URL(response.AttestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode attestation object")
+	}
+	item, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode attestation object")
+	}
+	m, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("attestation object is not a map")
+	}
+	rawAuthenticatorData, ok := m["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("missing authenticator data in attestation object")
+	}
+	authenticatorData, err := rp.verifyAuthenticatorData(rawAuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	if authenticatorData.flags&flagAttestedCredentialData == 0 {
+		return nil, errors.New("missing attested credential data")
+	}
+	if encodedID := base64.RawURLEncoding.EncodeToString(authenticatorData.credentialID); encodedID != strings.TrimRight(credentialID, "=") {
+		return nil, errors.New("unexpected credential id")
+	}
+	if _, err := parsePublicKey(authenticatorData.coseKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:        base64.RawURLEncoding.EncodeToString(authenticatorData.credentialID),
+		PublicKey: base64.RawURLEncoding.EncodeToString(authenticatorData.coseKey),
+		SignCount: authenticatorData.signCount,
+	}, nil
+}
+
+// VerifyAssertion verifies the response of the authenticator to the authentication with the challenge,
+// signed with the credential. It returns the new signature counter to be stored with the credential.
+// The user must have been verified by the authenticator.
+func (rp *RelyingParty) VerifyAssertion(challenge string, credential *Credential, response *AssertionResponse) (uint32, error) {
+	rawClientData, err := rp.verifyClientData(response.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return 0, err
+	}
+	rawAuthenticatorData, err := decodeBase64URL(response.AuthenticatorData)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode authenticator data")
+	}
+	authenticatorData, err := rp.verifyAuthenticatorData(rawAuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	coseKey, err := decodeBase64URL(credential.PublicKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode public key")
+	}
+	publicKey, err := parsePublicKey(coseKey)
+	if err != nil {
+		return 0, err
+	}
+	signature, err := decodeBase64URL(response.Signature)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode signature")
+	}
+	// The signature is over the authenticator data followed by the hash of the client data.
+	clientDataHash := sha256.Sum256(rawClientData)
+	signedData := append(append([]byte{}, rawAuthenticatorData...), clientDataHash[:]...)
+	if err := publicKey.verify(signedData, signature); err != nil {
+		return 0, err
+	}
+
+	// The authenticators without signature counter always return zero.
+	if (authenticatorData.signCount != 0 || credential.SignCount != 0) && authenticatorData.signCount <= credential.SignCount {
+		return 0, ErrSignCountRegression
+	}
+	return authenticatorData.signCount, nil
+}
+
+// verifyClientData verifies the client data collected by the browser, returning its raw JSON.
+func (rp *RelyingParty) verifyClientData(encoded, ceremonyType, challenge string) ([]byte, error) {
+	rawClientData, err := decodeBase64URL(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode client data")
+	}
+	clientData := &clientData{}
+	if err := json.Unmarshal(rawClientData, clientData); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal client data")
+	}
+	if clientData.Type != ceremonyType {
+		return nil, errors.Errorf("unexpected client data type %q", clientData.Type)
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(strings.TrimRight(clientData.Challenge, "=")), []byte(challenge)) != 1 {
+		return nil, errors.New("unexpected challenge")
+	}
+	if clientData.Origin != rp.Origin {
+		return nil, errors.Errorf("unexpected origin %q", clientData.Origin)
+	}
+	return rawClientData, nil
+}
+
+// verifyAuthenticatorData parses the authenticator data, and verifies it is scoped to the relying party
+// and the user has been verified.
+func (rp *RelyingParty) verifyAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+	authenticatorData := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authenticatorData.rpIDHash, rpIDHash[:]) {
+		return nil, errors.New("unexpected relying party id hash")
+	}
+	if authenticatorData.flags&flagUserPresent == 0 {
+		return nil, errors.New("user is not present")
+	}
+	if authenticatorData.flags&flagUserVerified == 0 {
+		return nil, errors.New("user is not verified")
+	}
+
+	if authenticatorData.flags&flagAttestedCredentialData != 0 {
+		// The attested credential data is the AAGUID, the length of the credential ID, the credential ID and the COSE key.
+		rest := data[37:]
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data is too short")
+		}
+		credentialIDLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if len(rest) < credentialIDLength {
+			return nil, errors.New("attested credential data is too short")
+		}
+		authenticatorData.credentialID = rest[:credentialIDLength]
+		rest = rest[credentialIDLength:]
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode credential public key")
+		}
+		authenticatorData.coseKey = rest[:n]
+	}
+	return authenticatorData, nil
+}
+
+// decodeBase64URL decodes the base64url encoded data, with or without padding.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
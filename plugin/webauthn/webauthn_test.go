@@ -0,0 +1,115 @@
+package webauthn
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/test"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	// The examples of RFC 8949 Appendix A.
+	tests := []struct {
+		encoded string
+		decoded any
+	}{
+		{encoded: "17", decoded: int64(23)},
+		{encoded: "1903e8", decoded: int64(1000)},
+		{encoded: "3903e7", decoded: int64(-1000)},
+		{encoded: "4401020304", decoded: []byte{1, 2, 3, 4}},
+		{encoded: "6449455446", decoded: "IETF"},
+		{encoded: "83010203", decoded: []any{int64(1), int64(2), int64(3)}},
+		{encoded: "a201020304", decoded: map[any]any{int64(1): int64(2), int64(3): int64(4)}},
+		{encoded: "a26161016162820203", decoded: map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+		{encoded: "f5", decoded: true},
+		{encoded: "c11a514b67b0", decoded: int64(1363896240)},
+	}
+	for _, test := range tests {
+		data, err := hex.DecodeString(test.encoded)
+		require.NoError(t, err)
+		decoded, n, err := decodeCBOR(data)
+		require.NoError(t, err)
+		require.Equal(t, test.decoded, decoded)
+		require.Equal(t, len(data), n)
+	}
+
+	for _, encoded := range []string{"", "5f", "44010203", "9bffffffffffffffff", "a1400102"} {
+		data, err := hex.DecodeString(encoded)
+		require.NoError(t, err)
+		_, _, err = decodeCBOR(data)
+		require.Error(t, err, encoded)
+	}
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	for _, algorithm := range SupportedAlgorithms {
+		rp, err := NewRelyingParty("https://memos.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "memos.example.com", rp.ID)
+		authenticator := test.NewWebAuthnAuthenticator("https://memos.example.com")
+		authenticator.Algorithm = algorithm
+
+		challenge, err := GenerateChallenge()
+		require.NoError(t, err)
+		attestation, err := authenticator.Register(challenge, "user-handle")
+		require.NoError(t, err)
+		response := &AttestationResponse{
+			ClientDataJSON:    attestation.ClientDataJSON,
+			AttestationObject: attestation.AttestationObject,
+		}
+		_, err = rp.VerifyRegistration("another-challenge", attestation.CredentialID, response)
+		require.ErrorContains(t, err, "unexpected challenge")
+		credential, err := rp.VerifyRegistration(challenge, attestation.CredentialID, response)
+		require.NoError(t, err)
+		require.Equal(t, attestation.CredentialID, credential.ID)
+		require.Equal(t, uint32(0), credential.SignCount)
+
+		challenge, err = GenerateChallenge()
+		require.NoError(t, err)
+		assertion, err := authenticator.Assert(challenge)
+		require.NoError(t, err)
+		require.Equal(t, credential.ID, assertion.CredentialID)
+		require.Equal(t, "user-handle", assertion.UserHandle)
+		assertionResponse := &AssertionResponse{
+			ClientDataJSON:    assertion.ClientDataJSON,
+			AuthenticatorData: assertion.AuthenticatorData,
+			Signature:         assertion.Signature,
+			UserHandle:        assertion.UserHandle,
+		}
+		signCount, err := rp.VerifyAssertion(challenge, credential, assertionResponse)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), signCount)
+
+		// The replayed assertion doesn't increase the signature counter.
+		credential.SignCount = signCount
+		_, err = rp.VerifyAssertion(challenge, credential, assertionResponse)
+		require.ErrorIs(t, err, ErrSignCountRegression)
+
+		// The tampered signature is rejected.
+		tampered := *assertionResponse
+		tampered.Signature = assertion.Signature[:len(assertion.Signature)-4] + "AAAA"
+		_, err = rp.VerifyAssertion(challenge, credential, &tampered)
+		require.Error(t, err)
+	}
+}
+
+func TestVerifyOrigin(t *testing.T) {
+	rp, err := NewRelyingParty("https://memos.example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "https://memos.example.com", rp.Origin)
+	_, err = NewRelyingParty("memos.example.com")
+	require.Error(t, err)
+
+	challenge, err := GenerateChallenge()
+	require.NoError(t, err)
+	// The passkeys of another site are scoped to its relying party ID.
+	authenticator := test.NewWebAuthnAuthenticator("https://evil.example.com")
+	attestation, err := authenticator.Register(challenge, "user-handle")
+	require.NoError(t, err)
+	_, err = rp.VerifyRegistration(challenge, attestation.CredentialID, &AttestationResponse{
+		ClientDataJSON:    attestation.ClientDataJSON,
+		AttestationObject: attestation.AttestationObject,
+	})
+	require.ErrorContains(t, err, "unexpected origin")
+}
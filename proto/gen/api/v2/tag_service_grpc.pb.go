@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TagService_ListTags_FullMethodName = "/memos.api.v2.TagService/ListTags"
+	TagService_ListTags_FullMethodName   = "/memos.api.v2.TagService/ListTags"
+	TagService_GetTagTree_FullMethodName = "/memos.api.v2.TagService/GetTagTree"
+	TagService_RenameTag_FullMethodName  = "/memos.api.v2.TagService/RenameTag"
+	TagService_MergeTags_FullMethodName  = "/memos.api.v2.TagService/MergeTags"
 )
 
 // TagServiceClient is the client API for TagService service.
@@ -27,6 +30,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TagServiceClient interface {
 	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	// GetTagTree gets the tree of the tags in the memos of a creator, where `work/clientA` is a child of `work`.
+	GetTagTree(ctx context.Context, in *GetTagTreeRequest, opts ...grpc.CallOption) (*GetTagTreeResponse, error)
+	// RenameTag renames a tag of the current user in the content of its memos, and moves its children under the new name.
+	RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*RenameTagResponse, error)
+	// MergeTags merges tags of the current user into one in the content of its memos, with their children.
+	MergeTags(ctx context.Context, in *MergeTagsRequest, opts ...grpc.CallOption) (*MergeTagsResponse, error)
 }
 
 type tagServiceClient struct {
@@ -46,11 +55,44 @@ func (c *tagServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, op
 	return out, nil
 }
 
+func (c *tagServiceClient) GetTagTree(ctx context.Context, in *GetTagTreeRequest, opts ...grpc.CallOption) (*GetTagTreeResponse, error) {
+	out := new(GetTagTreeResponse)
+	err := c.cc.Invoke(ctx, TagService_GetTagTree_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) RenameTag(ctx context.Context, in *RenameTagRequest, opts ...grpc.CallOption) (*RenameTagResponse, error) {
+	out := new(RenameTagResponse)
+	err := c.cc.Invoke(ctx, TagService_RenameTag_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) MergeTags(ctx context.Context, in *MergeTagsRequest, opts ...grpc.CallOption) (*MergeTagsResponse, error) {
+	out := new(MergeTagsResponse)
+	err := c.cc.Invoke(ctx, TagService_MergeTags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TagServiceServer is the server API for TagService service.
 // All implementations must embed UnimplementedTagServiceServer
 // for forward compatibility
 type TagServiceServer interface {
 	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	// GetTagTree gets the tree of the tags in the memos of a creator, where `work/clientA` is a child of `work`.
+	GetTagTree(context.Context, *GetTagTreeRequest) (*GetTagTreeResponse, error)
+	// RenameTag renames a tag of the current user in the content of its memos, and moves its children under the new name.
+	RenameTag(context.Context, *RenameTagRequest) (*RenameTagResponse, error)
+	// MergeTags merges tags of the current user into one in the content of its memos, with their children.
+	MergeTags(context.Context, *MergeTagsRequest) (*MergeTagsResponse, error)
 	mustEmbedUnimplementedTagServiceServer()
 }
 
@@ -61,6 +103,15 @@ type UnimplementedTagServiceServer struct {
 func (UnimplementedTagServiceServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
 }
+func (UnimplementedTagServiceServer) GetTagTree(context.Context, *GetTagTreeRequest) (*GetTagTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTagTree not implemented")
+}
+func (UnimplementedTagServiceServer) RenameTag(context.Context, *RenameTagRequest) (*RenameTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameTag not implemented")
+}
+func (UnimplementedTagServiceServer) MergeTags(context.Context, *MergeTagsRequest) (*MergeTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeTags not implemented")
+}
 func (UnimplementedTagServiceServer) mustEmbedUnimplementedTagServiceServer() {}
 
 // UnsafeTagServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +143,60 @@ func _TagService_ListTags_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TagService_GetTagTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).GetTagTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_GetTagTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).GetTagTree(ctx, req.(*GetTagTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_RenameTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).RenameTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_RenameTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).RenameTag(ctx, req.(*RenameTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_MergeTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).MergeTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_MergeTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).MergeTags(ctx, req.(*MergeTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TagService_ServiceDesc is the grpc.ServiceDesc for TagService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +208,18 @@ var TagService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListTags",
 			Handler:    _TagService_ListTags_Handler,
 		},
+		{
+			MethodName: "GetTagTree",
+			Handler:    _TagService_GetTagTree_Handler,
+		},
+		{
+			MethodName: "RenameTag",
+			Handler:    _TagService_RenameTag_Handler,
+		},
+		{
+			MethodName: "MergeTags",
+			Handler:    _TagService_MergeTags_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v2/tag_service.proto",
@@ -0,0 +1,103 @@
+// Package archive exports the memos of users into a portable zip archive and imports them back.
+//
+// An archive contains a directory for each exported user:
+//
+//	<username>/memos/<id>.md              one Markdown file for each memo, with a YAML frontmatter
+//	<username>/resources.yaml             the list of resources
+//	<username>/resources/<id>/<filename>  the files of the resources
+//
+// IDs in the archive are the ones of the exporting instance, they are mapped to new ones on import.
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/usememos/memos/store"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	memoDir          = "memos"
+	resourceDir      = "resources"
+	resourceListFile = "resources.yaml"
+
+	frontmatterDelimiter = "---"
+)
+
+// Frontmatter is the metadata of a memo in the archive.
+type Frontmatter struct {
+	ID         int32            `yaml:"id"`
+	Created    time.Time        `yaml:"created"`
+	Updated    time.Time        `yaml:"updated"`
+	Visibility store.Visibility `yaml:"visibility"`
+	Archived   bool             `yaml:"archived,omitempty"`
+	Pinned     bool             `yaml:"pinned,omitempty"`
+	Tags       []string         `yaml:"tags,omitempty"`
+	Relations  []Relation       `yaml:"relations,omitempty"`
+	// Resources are the IDs of the resources in resources.yaml.
+	Resources []int32 `yaml:"resources,omitempty"`
+}
+
+// Relation is a relation from a memo to the memo of ID.
+type Relation struct {
+	ID   int32                  `yaml:"id"`
+	Type store.MemoRelationType `yaml:"type"`
+}
+
+// Resource is a resource in resources.yaml.
+type Resource struct {
+	ID       int32  `yaml:"id"`
+	Filename string `yaml:"filename"`
+	Type     string `yaml:"type,omitempty"`
+	Size     int64  `yaml:"size,omitempty"`
+	// Path is the path of the file in the archive, relative to the directory of the user.
+	// It's empty if the resource is an external link.
+	Path         string `yaml:"path,omitempty"`
+	ExternalLink string `yaml:"externalLink,omitempty"`
+}
+
+// marshalMemo returns the Markdown file of a memo with its frontmatter.
+func marshalMemo(frontmatter *Frontmatter, content string) ([]byte, error) {
+	data, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString(frontmatterDelimiter + "\n")
+	buf.Write(data)
+	buf.WriteString(frontmatterDelimiter + "\n")
+	buf.WriteString(content)
+	return buf.Bytes(), nil
+}
+
+// unmarshalMemo parses the Markdown file of a memo into its frontmatter and content.
+func unmarshalMemo(data []byte) (*Frontmatter, string, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, frontmatterDelimiter+"\n") {
+		return nil, "", fmt.Errorf("frontmatter not found")
+	}
+	text = strings.TrimPrefix(text, frontmatterDelimiter+"\n")
+	end := strings.Index(text, "\n"+frontmatterDelimiter+"\n")
+	if end < 0 {
+		return nil, "", fmt.Errorf("frontmatter is not closed")
+	}
+
+	frontmatter := &Frontmatter{}
+	if err := yaml.Unmarshal([]byte(text[:end+1]), frontmatter); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal frontmatter: %w", err)
+	}
+	return frontmatter, text[end+len(frontmatterDelimiter)+2:], nil
+}
+
+// resourcePath returns the path of the file of a resource, relative to the directory of the user.
+func resourcePath(resource *store.Resource) string {
+	filename := strings.NewReplacer("/", "_", "\\", "_").Replace(resource.Filename)
+	if filename == "" || filename == "." || filename == ".." {
+		filename = "file"
+	}
+	return fmt.Sprintf("%s/%d/%s", resourceDir, resource.ID, filename)
+}
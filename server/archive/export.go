@@ -0,0 +1,134 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/usememos/memos/plugin/gomark/parser"
+	"github.com/usememos/memos/store"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportResult is the summary of an export.
+type ExportResult struct {
+	UserCount     int
+	MemoCount     int
+	ResourceCount int
+}
+
+// Export writes the memos and resources of users into a zip archive.
+// Memos in trash aren't exported.
+func Export(ctx context.Context, s *store.Store, w io.Writer, users []*store.User) (*ExportResult, error) {
+	result := &ExportResult{}
+	zipWriter := zip.NewWriter(w)
+	for _, user := range users {
+		memoCount, resourceCount, err := exportUser(ctx, s, zipWriter, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export user %s: %w", user.Username, err)
+		}
+		result.UserCount++
+		result.MemoCount += memoCount
+		result.ResourceCount += resourceCount
+	}
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func exportUser(ctx context.Context, s *store.Store, zipWriter *zip.Writer, user *store.User) (int, int, error) {
+	resources, err := s.ListResources(ctx, &store.FindResource{
+		CreatorID: &user.ID,
+		GetBlob:   true,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list resources: %w", err)
+	}
+	resourceList := []*Resource{}
+	for _, resource := range resources {
+		item := &Resource{
+			ID:           resource.ID,
+			Filename:     resource.Filename,
+			Type:         resource.Type,
+			Size:         resource.Size,
+			ExternalLink: resource.ExternalLink,
+		}
+		if resource.ExternalLink == "" {
+			blob := resource.Blob
+			if resource.InternalPath != "" {
+				blob, err = os.ReadFile(resource.InternalPath)
+				if err != nil {
+					return 0, 0, fmt.Errorf("failed to read resource %d: %w", resource.ID, err)
+				}
+			}
+			item.Path = resourcePath(resource)
+			if err := writeFile(zipWriter, path.Join(user.Username, item.Path), time.Unix(resource.CreatedTs, 0), blob); err != nil {
+				return 0, 0, err
+			}
+		}
+		resourceList = append(resourceList, item)
+	}
+	data, err := yaml.Marshal(resourceList)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := writeFile(zipWriter, path.Join(user.Username, resourceListFile), time.Now(), data); err != nil {
+		return 0, 0, err
+	}
+
+	memos, err := s.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &user.ID,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list memos: %w", err)
+	}
+	for _, memo := range memos {
+		frontmatter := &Frontmatter{
+			ID:         memo.ID,
+			Created:    time.Unix(memo.CreatedTs, 0).UTC(),
+			Updated:    time.Unix(memo.UpdatedTs, 0).UTC(),
+			Visibility: memo.Visibility,
+			Archived:   memo.RowStatus == store.Archived,
+			Pinned:     memo.Pinned,
+			Resources:  memo.ResourceIDList,
+		}
+		if tagList := parser.FindTagList(memo.Content); len(tagList) > 0 {
+			frontmatter.Tags = tagList
+		}
+		for _, relation := range memo.RelationList {
+			frontmatter.Relations = append(frontmatter.Relations, Relation{
+				ID:   relation.RelatedMemoID,
+				Type: relation.Type,
+			})
+		}
+		data, err := marshalMemo(frontmatter, memo.Content)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to marshal memo %d: %w", memo.ID, err)
+		}
+		if err := writeFile(zipWriter, path.Join(user.Username, memoDir, fmt.Sprintf("%d.md", memo.ID)), frontmatter.Updated, data); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return len(memos), len(resourceList), nil
+}
+
+func writeFile(zipWriter *zip.Writer, name string, modified time.Time, data []byte) error {
+	writer, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
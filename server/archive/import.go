@@ -0,0 +1,273 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"gopkg.in/yaml.v3"
+)
+
+// ImportOptions are the options of an import.
+type ImportOptions struct {
+	// Username is the user to import all the memos into.
+	// If it's empty, the memos of each user in the archive are imported into the user with the same username.
+	Username string
+}
+
+// ImportResult is the summary of an import.
+type ImportResult struct {
+	MemoCount     int
+	ResourceCount int
+	RelationCount int
+	// MemoIDMap maps the IDs of memos in the archive to the IDs of the imported ones.
+	MemoIDMap map[int32]int32
+}
+
+type archiveMemo struct {
+	frontmatter *Frontmatter
+	content     string
+	creatorID   int32
+}
+
+// Import recreates the memos, resources and relations of a zip archive written by Export.
+// Relations to memos which aren't in the archive are skipped.
+func Import(ctx context.Context, s *store.Store, r io.ReaderAt, size int64, options *ImportOptions) (*ImportResult, error) {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	files := map[string]*zip.File{}
+	usernames := []string{}
+	for _, file := range zipReader.File {
+		name := path.Clean(file.Name)
+		files[name] = file
+		if username, rest, ok := strings.Cut(name, "/"); ok && rest == resourceListFile {
+			usernames = append(usernames, username)
+		}
+	}
+	sort.Strings(usernames)
+
+	result := &ImportResult{
+		MemoIDMap: map[int32]int32{},
+	}
+	memos := []*archiveMemo{}
+	for _, username := range usernames {
+		targetUsername := username
+		if options.Username != "" {
+			targetUsername = options.Username
+		}
+		user, err := s.GetUser(ctx, &store.FindUser{Username: &targetUsername})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user %s: %w", targetUsername, err)
+		}
+		if user == nil {
+			return nil, fmt.Errorf("user %s not found", targetUsername)
+		}
+
+		resourceIDMap, err := importResources(ctx, s, files, username, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import resources of user %s: %w", username, err)
+		}
+		result.ResourceCount += len(resourceIDMap)
+
+		userMemos, err := readMemos(files, username, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read memos of user %s: %w", username, err)
+		}
+		for _, memo := range userMemos {
+			id, err := importMemo(ctx, s, memo, resourceIDMap)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import memo %d: %w", memo.frontmatter.ID, err)
+			}
+			result.MemoIDMap[memo.frontmatter.ID] = id
+			result.MemoCount++
+		}
+		memos = append(memos, userMemos...)
+	}
+
+	// Relations are created after all the memos, as they may refer to memos of other users.
+	for _, memo := range memos {
+		for _, relation := range memo.frontmatter.Relations {
+			relatedMemoID, ok := result.MemoIDMap[relation.ID]
+			if !ok {
+				continue
+			}
+			if _, err := s.UpsertMemoRelation(ctx, &store.MemoRelation{
+				MemoID:        result.MemoIDMap[memo.frontmatter.ID],
+				RelatedMemoID: relatedMemoID,
+				Type:          relation.Type,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to import relations of memo %d: %w", memo.frontmatter.ID, err)
+			}
+			result.RelationCount++
+		}
+	}
+
+	return result, nil
+}
+
+// importResources creates the resources of a user in the archive, and returns the map of their IDs.
+func importResources(ctx context.Context, s *store.Store, files map[string]*zip.File, username string, creatorID int32) (map[int32]int32, error) {
+	data, err := readFile(files[path.Join(username, resourceListFile)])
+	if err != nil {
+		return nil, err
+	}
+	resourceList := []*Resource{}
+	if err := yaml.Unmarshal(data, &resourceList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", resourceListFile, err)
+	}
+
+	resourceIDMap := map[int32]int32{}
+	for _, resource := range resourceList {
+		create := &store.Resource{
+			CreatorID:    creatorID,
+			Filename:     filepath.Base(resource.Filename),
+			Type:         resource.Type,
+			ExternalLink: resource.ExternalLink,
+		}
+		if resource.Path != "" {
+			file, ok := files[path.Join(username, resource.Path)]
+			if !ok {
+				return nil, fmt.Errorf("file of resource %d not found", resource.ID)
+			}
+			blob, err := readFile(file)
+			if err != nil {
+				return nil, err
+			}
+			create.Size = int64(len(blob))
+			if err := apiv1.SaveResourceBlob(ctx, s, create, bytes.NewReader(blob)); err != nil {
+				return nil, fmt.Errorf("failed to save resource %d: %w", resource.ID, err)
+			}
+		}
+		created, err := s.CreateResource(ctx, create)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource %d: %w", resource.ID, err)
+		}
+		resourceIDMap[resource.ID] = created.ID
+	}
+	return resourceIDMap, nil
+}
+
+// readMemos reads the memos of a user in the archive, ordered by their IDs.
+func readMemos(files map[string]*zip.File, username string, creatorID int32) ([]*archiveMemo, error) {
+	prefix := path.Join(username, memoDir) + "/"
+	memos := []*archiveMemo{}
+	for name, file := range files {
+		if !strings.HasPrefix(name, prefix) || path.Ext(name) != ".md" {
+			continue
+		}
+		data, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		frontmatter, content, err := unmarshalMemo(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memo file %s: %w", name, err)
+		}
+		memos = append(memos, &archiveMemo{
+			frontmatter: frontmatter,
+			content:     content,
+			creatorID:   creatorID,
+		})
+	}
+	sort.Slice(memos, func(i, j int) bool {
+		return memos[i].frontmatter.ID < memos[j].frontmatter.ID
+	})
+	return memos, nil
+}
+
+// importMemo creates a memo in the archive, and returns its ID.
+func importMemo(ctx context.Context, s *store.Store, memo *archiveMemo, resourceIDMap map[int32]int32) (int32, error) {
+	frontmatter := memo.frontmatter
+	visibility := frontmatter.Visibility
+	if visibility != store.Public && visibility != store.Protected {
+		visibility = store.Private
+	}
+	create := &store.Memo{
+		CreatorID:  memo.creatorID,
+		Content:    memo.content,
+		Visibility: visibility,
+	}
+	if !frontmatter.Created.IsZero() {
+		create.CreatedTs = frontmatter.Created.Unix()
+	}
+	created, err := s.CreateMemo(ctx, create)
+	if err != nil {
+		return 0, err
+	}
+
+	update := &store.UpdateMemo{
+		ID: created.ID,
+	}
+	if !frontmatter.Updated.IsZero() {
+		updatedTs := frontmatter.Updated.Unix()
+		update.UpdatedTs = &updatedTs
+	}
+	if frontmatter.Archived {
+		rowStatus := store.Archived
+		update.RowStatus = &rowStatus
+	}
+	if update.UpdatedTs != nil || update.RowStatus != nil {
+		if err := s.UpdateMemo(ctx, update); err != nil {
+			return 0, err
+		}
+	}
+
+	if frontmatter.Pinned {
+		if _, err := s.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+			MemoID: created.ID,
+			UserID: memo.creatorID,
+			Pinned: true,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	for _, resourceID := range frontmatter.Resources {
+		id, ok := resourceIDMap[resourceID]
+		if !ok {
+			continue
+		}
+		if _, err := s.UpsertMemoResource(ctx, &store.UpsertMemoResource{
+			MemoID:     created.ID,
+			ResourceID: id,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	for _, tag := range frontmatter.Tags {
+		if _, err := s.UpsertTag(ctx, &store.Tag{
+			Name:      tag,
+			CreatorID: memo.creatorID,
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	return created.ID, nil
+}
+
+func readFile(file *zip.File) ([]byte, error) {
+	if file == nil {
+		return nil, fmt.Errorf("file not found")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	return data, nil
+}
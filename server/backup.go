@@ -34,6 +34,10 @@ func (r *BackupRunner) Run(ctx context.Context) {
 		log.Error(fmt.Sprintf("invalid SystemSettingAutoBackupIntervalName value %s, disable auto backup", intervalStr), zap.Error(err))
 		return
 	}
+	if r.Store.Profile.Driver != "sqlite" {
+		log.Info("auto backup only supports sqlite, disable auto backup for " + r.Store.Profile.Driver)
+		return
+	}
 
 	log.Info("enable auto backup every " + intervalStr + " seconds")
 	ticker := time.NewTicker(time.Duration(interval) * time.Second)
@@ -19,6 +19,8 @@ type Profile struct {
 	Port int `json:"-"`
 	// Data is the data directory
 	Data string `json:"-"`
+	// Driver is the database driver, can be "sqlite" or "postgres" or "mysql"
+	Driver string `json:"-"`
 	// DSN points to where Memos stores its own data
 	DSN string `json:"-"`
 	// Version is the current version of server
@@ -84,8 +86,19 @@ func GetProfile() (*Profile, error) {
 	}
 
 	profile.Data = dataDir
-	dbFile := fmt.Sprintf("memos_%s.db", profile.Mode)
-	profile.DSN = filepath.Join(dataDir, dbFile)
+	if profile.Driver == "" {
+		profile.Driver = "sqlite"
+	}
+	if profile.Driver != "sqlite" && profile.Driver != "postgres" && profile.Driver != "mysql" {
+		return nil, fmt.Errorf("unsupported driver %q", profile.Driver)
+	}
+	if profile.DSN == "" {
+		if profile.Driver != "sqlite" {
+			return nil, fmt.Errorf("dsn is required for driver %q", profile.Driver)
+		}
+		dbFile := fmt.Sprintf("memos_%s.db", profile.Mode)
+		profile.DSN = filepath.Join(dataDir, dbFile)
+	}
 	profile.Version = version.GetCurrentVersion(profile.Mode)
 
 	return &profile, nil
@@ -27,7 +27,6 @@ type Server struct {
 	e *echo.Echo
 
 	ID      string
-	Secret  string
 	Profile *profile.Profile
 	Store   *store.Store
 
@@ -35,8 +34,9 @@ type Server struct {
 	apiV2Service *apiv2.APIV2Service
 
 	// Asynchronous runners.
-	backupRunner *BackupRunner
-	telegramBot  *telegram.Bot
+	backupRunner     *BackupRunner
+	trashPurgeRunner *TrashPurgeRunner
+	telegramBot      *telegram.Bot
 }
 
 // @title						memos API
@@ -70,8 +70,9 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 		Profile: profile,
 
 		// Asynchronous runners.
-		backupRunner: NewBackupRunner(store),
-		telegramBot:  telegram.NewBotWithHandler(newTelegramHandler(store)),
+		backupRunner:     NewBackupRunner(store),
+		trashPurgeRunner: NewTrashPurgeRunner(store),
+		telegramBot:      telegram.NewBotWithHandler(newTelegramHandler(store)),
 	}
 
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
@@ -103,6 +104,10 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 	}
 	s.ID = serverID
 
+	if err := s.backfillMemoTags(ctx); err != nil {
+		return nil, fmt.Errorf("failed to backfill memo tags: %w", err)
+	}
+
 	embedFrontend(e)
 
 	// This will serve Swagger UI at /api/index.html and Swagger 2.0 spec at /api/doc.json
@@ -115,17 +120,16 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 			return nil, fmt.Errorf("failed to retrieve system secret session name: %w", err)
 		}
 	}
-	s.Secret = secret
+	// The secret is kept as the first key of the keyring, so that the access tokens signed with it remain valid.
+	if err := apiv1.InitSigningKeys(ctx, store, secret); err != nil {
+		return nil, fmt.Errorf("failed to initialize signing keys: %w", err)
+	}
 
 	rootGroup := e.Group("")
-	apiV1Service := apiv1.NewAPIV1Service(s.Secret, profile, store)
+	apiV1Service := apiv1.NewAPIV1Service(profile, store)
 	apiV1Service.Register(rootGroup)
 
-	s.apiV2Service = apiv2.NewAPIV2Service(s.Secret, profile, store, s.Profile.Port+1)
-	// Register gRPC gateway as api v2.
-	if err := s.apiV2Service.RegisterGateway(ctx, e); err != nil {
-		return nil, fmt.Errorf("failed to register gRPC gateway: %w", err)
-	}
+	s.apiV2Service = apiv2.NewAPIV2Service(profile, store, s.Profile.Port+1)
 
 	return s, nil
 }
@@ -137,6 +141,7 @@ func (s *Server) Start(ctx context.Context) error {
 
 	go s.telegramBot.Start(ctx)
 	go s.backupRunner.Run(ctx)
+	go s.trashPurgeRunner.Run(ctx)
 
 	// Start gRPC server.
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", s.Profile.Port+1))
@@ -148,6 +153,10 @@ func (s *Server) Start(ctx context.Context) error {
 			log.Error("grpc server listen error", zap.Error(err))
 		}
 	}()
+	// Register gRPC gateway as api v2 once the gRPC server is listening, so that its first connection doesn't fail.
+	if err := s.apiV2Service.RegisterGateway(ctx, s.e); err != nil {
+		return errors.Wrap(err, "failed to register gRPC gateway")
+	}
 
 	// programmatically set API version same as the server version
 	apiv1.SwaggerInfo.Version = s.Profile.Version
@@ -163,6 +172,8 @@ func (s *Server) Shutdown(ctx context.Context) {
 	if err := s.e.Shutdown(ctx); err != nil {
 		fmt.Printf("failed to shutdown server, error: %v\n", err)
 	}
+	// Shutdown gRPC server
+	s.apiV2Service.GetGRPCServer().GracefulStop()
 
 	// Close database connection
 	if err := s.Store.GetDB().Close(); err != nil {
@@ -195,6 +206,30 @@ func (s *Server) getSystemServerID(ctx context.Context) (string, error) {
 	return serverIDSetting.Value, nil
 }
 
+// backfillMemoTags indexes the tags of the memos created before the memo_tag table once,
+// which is recorded with a system setting so that the memos aren't parsed again on the next startups.
+func (s *Server) backfillMemoTags(ctx context.Context) error {
+	backfilledSetting, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: apiv1.SystemSettingMemoTagsBackfilledName.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if backfilledSetting != nil {
+		return nil
+	}
+	if err := s.Store.BackfillMemoTags(ctx); err != nil {
+		return err
+	}
+	if _, err := s.Store.UpsertSystemSetting(ctx, &store.SystemSetting{
+		Name:  apiv1.SystemSettingMemoTagsBackfilledName.String(),
+		Value: "true",
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *Server) getSystemSecretSessionName(ctx context.Context) (string, error) {
 	secretSessionNameValue, err := s.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
 		Name: apiv1.SystemSettingSecretSessionName.String(),
@@ -241,5 +276,5 @@ func defaultGetRequestSkipper(c echo.Context) bool {
 
 func defaultAPIRequestSkipper(c echo.Context) bool {
 	path := c.Request().URL.Path
-	return util.HasPrefixes(path, "/api", "/api/v1", "api/v2")
+	return util.HasPrefixes(path, "/api", "/api/v1", "api/v2", "/scim")
 }
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/common/log"
+	"github.com/usememos/memos/store"
+	"go.uber.org/zap"
+)
+
+// trashPurgeInterval is the interval to purge the memos in trash after the retention period.
+const trashPurgeInterval = time.Hour
+
+type TrashPurgeRunner struct {
+	Store *store.Store
+}
+
+func NewTrashPurgeRunner(store *store.Store) *TrashPurgeRunner {
+	return &TrashPurgeRunner{
+		Store: store,
+	}
+}
+
+func (r *TrashPurgeRunner) Run(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Purge(ctx, time.Now()); err != nil {
+			log.Error("fail to purge memos in trash", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("stop purging memos in trash graceful.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes the memos which have been in trash for longer than the retention period at now for good.
+func (r *TrashPurgeRunner) Purge(ctx context.Context, now time.Time) error {
+	retentionDaysStr := r.Store.GetSystemSettingValueWithDefault(&ctx, apiv1.SystemSettingMemoTrashRetentionDaysName.String(), strconv.Itoa(apiv1.DefaultMemoTrashRetentionDays))
+	retentionDays, err := strconv.Atoi(retentionDaysStr)
+	if err != nil || retentionDays < 0 {
+		return fmt.Errorf("invalid SystemSettingMemoTrashRetentionDaysName value %s", retentionDaysStr)
+	}
+	if retentionDays == 0 {
+		return nil
+	}
+
+	deletedTsBefore := now.AddDate(0, 0, -retentionDays).Unix()
+	memos, err := r.Store.ListMemos(ctx, &store.FindMemo{
+		InTrash:         true,
+		DeletedTsBefore: &deletedTsBefore,
+	})
+	if err != nil {
+		return err
+	}
+	for _, memo := range memos {
+		if err := r.Store.DeleteMemo(ctx, &store.DeleteMemo{
+			ID: memo.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	if len(memos) > 0 {
+		log.Info(fmt.Sprintf("purged %d memos in trash", len(memos)))
+	}
+	return nil
+}
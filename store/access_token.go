@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// AccessToken is a personal access token of a user, only the hash of the token is stored.
+type AccessToken struct {
+	ID int32
+
+	// Standard fields
+	UserID    int32
+	CreatedTs int64
+
+	// Domain specific fields
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresTs  int64
+	LastUsedTs int64
+}
+
+type FindAccessToken struct {
+	ID        *int32
+	UserID    *int32
+	TokenHash *string
+}
+
+type UpdateAccessToken struct {
+	ID         int32
+	LastUsedTs *int64
+}
+
+type DeleteAccessToken struct {
+	ID     *int32
+	UserID *int32
+}
+
+func (s *Store) CreateAccessToken(ctx context.Context, create *AccessToken) (*AccessToken, error) {
+	stmt := `
+		INSERT INTO access_token (
+			user_id,
+			name,
+			token_hash,
+			scopes,
+			expires_ts
+		)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	args := []any{create.UserID, create.Name, create.TokenHash, strings.Join(create.Scopes, ","), create.ExpiresTs}
+	if err := s.insertReturning(ctx, s.db, "access_token", stmt, args, []string{"id", "created_ts", "last_used_ts"},
+		&create.ID,
+		&create.CreatedTs,
+		&create.LastUsedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	accessToken := create
+	return accessToken, nil
+}
+
+func (s *Store) ListAccessTokens(ctx context.Context, find *FindAccessToken) ([]*AccessToken, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if v := find.TokenHash; v != nil {
+		where, args = append(where, "token_hash = ?"), append(args, *v)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			user_id,
+			created_ts,
+			name,
+			token_hash,
+			scopes,
+			expires_ts,
+			last_used_ts
+		FROM access_token
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*AccessToken{}
+	for rows.Next() {
+		accessToken := &AccessToken{}
+		var scopes string
+		if err := rows.Scan(
+			&accessToken.ID,
+			&accessToken.UserID,
+			&accessToken.CreatedTs,
+			&accessToken.Name,
+			&accessToken.TokenHash,
+			&scopes,
+			&accessToken.ExpiresTs,
+			&accessToken.LastUsedTs,
+		); err != nil {
+			return nil, err
+		}
+		accessToken.Scopes = []string{}
+		if scopes != "" {
+			accessToken.Scopes = strings.Split(scopes, ",")
+		}
+		list = append(list, accessToken)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetAccessToken(ctx context.Context, find *FindAccessToken) (*AccessToken, error) {
+	list, err := s.ListAccessTokens(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	accessToken := list[0]
+	return accessToken, nil
+}
+
+func (s *Store) UpdateAccessToken(ctx context.Context, update *UpdateAccessToken) error {
+	set, args := []string{}, []any{}
+	if v := update.LastUsedTs; v != nil {
+		set, args = append(set, "last_used_ts = ?"), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	args = append(args, update.ID)
+
+	stmt := `UPDATE access_token SET ` + strings.Join(set, ", ") + ` WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Store) DeleteAccessToken(ctx context.Context, delete *DeleteAccessToken) error {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := delete.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if len(args) == 0 {
+		// Prevent deleting all the access tokens by accident.
+		return nil
+	}
+
+	stmt := `DELETE FROM access_token WHERE ` + strings.Join(where, " AND ")
+	result, err := s.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	if _, err := result.RowsAffected(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumAccessToken(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		access_token
+	WHERE
+		user_id NOT IN (
+			SELECT
+				id
+			FROM
+				"user"
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -18,6 +18,10 @@ type Activity struct {
 }
 
 func (s *Store) CreateActivity(ctx context.Context, create *Activity) (*Activity, error) {
+	return s.createActivity(ctx, s.db, create)
+}
+
+func (s *Store) createActivity(ctx context.Context, q queryer, create *Activity) (*Activity, error) {
 	stmt := `
 		INSERT INTO activity (
 			creator_id, 
@@ -26,9 +30,9 @@ func (s *Store) CreateActivity(ctx context.Context, create *Activity) (*Activity
 			payload
 		)
 		VALUES (?, ?, ?, ?)
-		RETURNING id, created_ts
 	`
-	if err := s.db.QueryRowContext(ctx, stmt, create.CreatorID, create.Type, create.Level, create.Payload).Scan(
+	args := []any{create.CreatorID, create.Type, create.Level, create.Payload}
+	if err := s.insertReturning(ctx, q, "activity", stmt, args, []string{"id", "created_ts"},
 		&create.ID,
 		&create.CreatedTs,
 	); err != nil {
@@ -23,7 +23,7 @@ var migrationFS embed.FS
 var seedFS embed.FS
 
 type DB struct {
-	// sqlite db connection instance
+	// db connection instance of the driver in profile
 	DBInstance *sql.DB
 	profile    *profile.Profile
 }
@@ -42,39 +42,32 @@ func (db *DB) Open(ctx context.Context) (err error) {
 		return fmt.Errorf("dsn required")
 	}
 
-	// Connect to the database with some sane settings:
-	// - No shared-cache: it's obsolete; WAL journal mode is a better solution.
-	// - No foreign key constraints: it's currently disabled by default, but it's a
-	// good practice to be explicit and prevent future surprises on SQLite upgrades.
-	// - Journal mode set to WAL: it's the recommended journal mode for most applications
-	// as it prevents locking issues.
-	//
-	// Notes:
-	// - When using the `modernc.org/sqlite` driver, each pragma must be prefixed with `_pragma=`.
-	//
-	// References:
-	// - https://pkg.go.dev/modernc.org/sqlite#Driver.Open
-	// - https://www.sqlite.org/sharedcache.html
-	// - https://www.sqlite.org/pragma.html
-	sqliteDB, err := sql.Open("sqlite", db.profile.DSN+"?_pragma=foreign_keys(0)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)")
+	switch db.profile.Driver {
+	case "sqlite":
+		db.DBInstance, err = openSQLite(db.profile.DSN)
+	case "postgres":
+		db.DBInstance, err = openPostgres(db.profile.DSN)
+	case "mysql":
+		db.DBInstance, err = openMySQL(db.profile.DSN)
+	default:
+		return fmt.Errorf("unsupported driver %q", db.profile.Driver)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to open db with dsn: %s, err: %w", db.profile.DSN, err)
+		return fmt.Errorf("failed to open %s db, err: %w", db.profile.Driver, err)
 	}
-	db.DBInstance = sqliteDB
 
+	isNew, err := db.isNew(ctx)
+	if err != nil {
+		return err
+	}
 	if db.profile.Mode == "prod" {
-		_, err := os.Stat(db.profile.DSN)
-		if err != nil {
-			// If db file not exists, we should create a new one with latest schema.
-			if errors.Is(err, os.ErrNotExist) {
-				if err := db.applyLatestSchema(ctx); err != nil {
-					return fmt.Errorf("failed to apply latest schema, err: %w", err)
-				}
-			} else {
-				return fmt.Errorf("failed to get db file stat, err: %w", err)
+		if isNew {
+			// If db is new, we should create it with latest schema.
+			if err := db.applyLatestSchema(ctx); err != nil {
+				return fmt.Errorf("failed to apply latest schema, err: %w", err)
 			}
 		} else {
-			// If db file exists, we should check if we need to migrate the database.
+			// If db exists, we should check if we need to migrate the database.
 			currentVersion := version.GetCurrentVersion(db.profile.Mode)
 			migrationHistoryList, err := db.FindMigrationHistoryList(ctx, &MigrationHistoryFind{})
 			if err != nil {
@@ -98,18 +91,21 @@ func (db *DB) Open(ctx context.Context) (err error) {
 			latestMigrationHistoryVersion := migrationHistoryVersionList[len(migrationHistoryVersionList)-1]
 
 			if version.IsVersionGreaterThan(version.GetSchemaVersion(currentVersion), latestMigrationHistoryVersion) {
-				minorVersionList := getMinorVersionList()
+				minorVersionList := getMinorVersionList(db.profile.Driver)
 
 				// backup the raw database file before migration
-				rawBytes, err := os.ReadFile(db.profile.DSN)
-				if err != nil {
-					return fmt.Errorf("failed to read raw database file, err: %w", err)
-				}
-				backupDBFilePath := fmt.Sprintf("%s/memos_%s_%d_backup.db", db.profile.Data, db.profile.Version, time.Now().Unix())
-				if err := os.WriteFile(backupDBFilePath, rawBytes, 0644); err != nil {
-					return fmt.Errorf("failed to write raw database file, err: %w", err)
+				backupDBFilePath := ""
+				if db.profile.Driver == "sqlite" {
+					rawBytes, err := os.ReadFile(db.profile.DSN)
+					if err != nil {
+						return fmt.Errorf("failed to read raw database file, err: %w", err)
+					}
+					backupDBFilePath = fmt.Sprintf("%s/memos_%s_%d_backup.db", db.profile.Data, db.profile.Version, time.Now().Unix())
+					if err := os.WriteFile(backupDBFilePath, rawBytes, 0644); err != nil {
+						return fmt.Errorf("failed to write raw database file, err: %w", err)
+					}
+					println("succeed to copy a backup database file")
 				}
-				println("succeed to copy a backup database file")
 
 				println("start migrate")
 				for _, minorVersion := range minorVersionList {
@@ -124,19 +120,21 @@ func (db *DB) Open(ctx context.Context) (err error) {
 				println("end migrate")
 
 				// remove the created backup db file after migrate succeed
-				if err := os.Remove(backupDBFilePath); err != nil {
-					println(fmt.Sprintf("Failed to remove temp database file, err %v", err))
+				if backupDBFilePath != "" {
+					if err := os.Remove(backupDBFilePath); err != nil {
+						println(fmt.Sprintf("Failed to remove temp database file, err %v", err))
+					}
 				}
 			}
 		}
 	} else {
 		// In non-prod mode, we should always migrate the database.
-		if _, err := os.Stat(db.profile.DSN); errors.Is(err, os.ErrNotExist) {
+		if isNew {
 			if err := db.applyLatestSchema(ctx); err != nil {
 				return fmt.Errorf("failed to apply latest schema: %w", err)
 			}
-			// In demo mode, we should seed the database.
-			if db.profile.Mode == "demo" {
+			// In demo mode, we should seed the database, the seed data is written for SQLite.
+			if db.profile.Mode == "demo" && db.profile.Driver == "sqlite" {
 				if err := db.seed(ctx); err != nil {
 					return fmt.Errorf("failed to seed: %w", err)
 				}
@@ -147,6 +145,31 @@ func (db *DB) Open(ctx context.Context) (err error) {
 	return nil
 }
 
+// isNew returns true if the schema hasn't been applied to the database.
+func (db *DB) isNew(ctx context.Context) (bool, error) {
+	query := ""
+	switch db.profile.Driver {
+	case "sqlite":
+		if _, err := os.Stat(db.profile.DSN); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return true, nil
+			}
+			return false, fmt.Errorf("failed to get db file stat, err: %w", err)
+		}
+		return false, nil
+	case "postgres":
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = 'migration_history'`
+	case "mysql":
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'migration_history'`
+	}
+
+	count := 0
+	if err := db.DBInstance.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to find migration_history table, err: %w", err)
+	}
+	return count == 0, nil
+}
+
 const (
 	latestSchemaFileName = "LATEST__SCHEMA.sql"
 )
@@ -156,7 +179,7 @@ func (db *DB) applyLatestSchema(ctx context.Context) error {
 	if db.profile.Mode == "prod" {
 		schemaMode = "prod"
 	}
-	latestSchemaPath := fmt.Sprintf("%s/%s/%s", "migration", schemaMode, latestSchemaFileName)
+	latestSchemaPath := fmt.Sprintf("%s/%s/%s/%s", "migration", db.profile.Driver, schemaMode, latestSchemaFileName)
 	buf, err := migrationFS.ReadFile(latestSchemaPath)
 	if err != nil {
 		return fmt.Errorf("failed to read latest schema %q, error %w", latestSchemaPath, err)
@@ -169,7 +192,7 @@ func (db *DB) applyLatestSchema(ctx context.Context) error {
 }
 
 func (db *DB) applyMigrationForMinorVersion(ctx context.Context, minorVersion string) error {
-	filenames, err := fs.Glob(migrationFS, fmt.Sprintf("%s/%s/*.sql", "migration/prod", minorVersion))
+	filenames, err := fs.Glob(migrationFS, fmt.Sprintf("%s/%s/prod/%s/*.sql", "migration", db.profile.Driver, minorVersion))
 	if err != nil {
 		return fmt.Errorf("failed to read ddl files, err: %w", err)
 	}
@@ -238,11 +261,10 @@ func (db *DB) execute(ctx context.Context, stmt string) error {
 	return tx.Commit()
 }
 
-// minorDirRegexp is a regular expression for minor version directory.
-var minorDirRegexp = regexp.MustCompile(`^migration/prod/[0-9]+\.[0-9]+$`)
-
-func getMinorVersionList() []string {
+func getMinorVersionList(driver string) []string {
 	minorVersionList := []string{}
+	// minorDirRegexp is a regular expression for minor version directory of the driver.
+	minorDirRegexp := regexp.MustCompile(fmt.Sprintf(`^migration/%s/prod/[0-9]+\.[0-9]+$`, regexp.QuoteMeta(driver)))
 
 	if err := fs.WalkDir(migrationFS, "migration", func(path string, file fs.DirEntry, err error) error {
 		if err != nil {
@@ -0,0 +1,246 @@
+-- migration_history
+CREATE TABLE migration_history (
+  version VARCHAR(256) NOT NULL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP())
+);
+
+-- system_setting
+CREATE TABLE system_setting (
+  name VARCHAR(256) NOT NULL,
+  value LONGTEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT (''),
+  UNIQUE(name)
+);
+
+-- user
+CREATE TABLE "user" (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  row_status VARCHAR(256) NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  username VARCHAR(256) NOT NULL UNIQUE,
+  role VARCHAR(256) NOT NULL CHECK (role IN ('HOST', 'ADMIN', 'USER')) DEFAULT 'USER',
+  email VARCHAR(256) NOT NULL DEFAULT '',
+  nickname VARCHAR(256) NOT NULL DEFAULT '',
+  password_hash VARCHAR(256) NOT NULL,
+  open_id VARCHAR(256) NOT NULL UNIQUE,
+  avatar_url LONGTEXT NOT NULL DEFAULT (''),
+  idp_id INT NOT NULL DEFAULT 0,
+  idp_identifier VARCHAR(256) NOT NULL DEFAULT '',
+  verified_email VARCHAR(256) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_user_username ON "user" (username);
+
+CREATE INDEX idx_user_idp_identifier ON "user" (idp_id, idp_identifier);
+
+-- user_setting
+CREATE TABLE user_setting (
+  user_id INT NOT NULL,
+  "key" VARCHAR(256) NOT NULL,
+  value LONGTEXT NOT NULL,
+  UNIQUE(user_id, "key")
+);
+
+-- memo
+CREATE TABLE memo (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  creator_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  row_status VARCHAR(256) NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  content LONGTEXT NOT NULL DEFAULT (''),
+  visibility VARCHAR(256) NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE',
+  deleted_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_memo_creator_id ON memo (creator_id);
+CREATE INDEX idx_memo_visibility ON memo (visibility);
+CREATE INDEX idx_memo_deleted_ts ON memo (deleted_ts);
+
+-- memo_revision
+CREATE TABLE memo_revision (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  memo_id INT NOT NULL,
+  content LONGTEXT NOT NULL DEFAULT (''),
+  visibility VARCHAR(256) NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE'
+);
+
+CREATE INDEX idx_memo_revision_memo_id ON memo_revision (memo_id);
+
+-- memo_organizer
+CREATE TABLE memo_organizer (
+  memo_id INT NOT NULL,
+  user_id INT NOT NULL,
+  pinned INT NOT NULL CHECK (pinned IN (0, 1)) DEFAULT 0,
+  UNIQUE(memo_id, user_id)
+);
+
+-- shortcut
+CREATE TABLE shortcut (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  creator_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  row_status VARCHAR(256) NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  title TEXT NOT NULL DEFAULT (''),
+  payload LONGTEXT NOT NULL DEFAULT ('{}')
+);
+
+-- resource
+CREATE TABLE resource (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  creator_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  filename TEXT NOT NULL DEFAULT (''),
+  "blob" LONGBLOB DEFAULT NULL,
+  external_link TEXT NOT NULL DEFAULT (''),
+  type VARCHAR(256) NOT NULL DEFAULT '',
+  size BIGINT NOT NULL DEFAULT 0,
+  internal_path TEXT NOT NULL DEFAULT ('')
+);
+
+CREATE INDEX idx_resource_creator_id ON resource (creator_id);
+
+-- memo_resource
+CREATE TABLE memo_resource (
+  memo_id INT NOT NULL,
+  resource_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  UNIQUE(memo_id, resource_id)
+);
+
+-- tag
+CREATE TABLE tag (
+  name VARCHAR(256) NOT NULL,
+  creator_id INT NOT NULL,
+  UNIQUE(name, creator_id)
+);
+
+-- memo_tag
+CREATE TABLE memo_tag (
+  memo_id INT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  UNIQUE(memo_id, name)
+);
+
+CREATE INDEX idx_memo_tag_name ON memo_tag (name);
+
+-- activity
+CREATE TABLE activity (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  creator_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  type VARCHAR(256) NOT NULL DEFAULT '',
+  level VARCHAR(256) NOT NULL CHECK (level IN ('INFO', 'WARN', 'ERROR')) DEFAULT 'INFO',
+  payload LONGTEXT NOT NULL DEFAULT ('{}')
+);
+
+-- storage
+CREATE TABLE storage (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name TEXT NOT NULL,
+  type VARCHAR(256) NOT NULL,
+  config LONGTEXT NOT NULL DEFAULT ('{}')
+);
+
+-- idp
+CREATE TABLE idp (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name TEXT NOT NULL,
+  type VARCHAR(256) NOT NULL,
+  identifier_filter VARCHAR(256) NOT NULL DEFAULT '',
+  config LONGTEXT NOT NULL DEFAULT ('{}'),
+  provisioning LONGTEXT NOT NULL DEFAULT ('{}')
+);
+
+-- memo_relation
+CREATE TABLE memo_relation (
+  memo_id INT NOT NULL,
+  related_memo_id INT NOT NULL,
+  type VARCHAR(256) NOT NULL,
+  UNIQUE(memo_id, related_memo_id, type)
+);
+
+-- access_token
+CREATE TABLE access_token (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  name TEXT NOT NULL,
+  token_hash VARCHAR(256) NOT NULL UNIQUE,
+  scopes TEXT NOT NULL DEFAULT (''),
+  expires_ts BIGINT NOT NULL,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_access_token_user_id ON access_token (user_id);
+
+-- session
+CREATE TABLE session (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  expires_ts BIGINT NOT NULL,
+  refresh_token_hash VARCHAR(256) NOT NULL UNIQUE,
+  previous_refresh_token_hash VARCHAR(256) NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT (''),
+  ip_address VARCHAR(256) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_session_user_id ON session (user_id);
+
+CREATE INDEX idx_session_previous_refresh_token_hash ON session (previous_refresh_token_hash);
+
+-- user_totp
+CREATE TABLE user_totp (
+  user_id INT NOT NULL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  secret TEXT NOT NULL,
+  enabled INT NOT NULL CHECK (enabled IN (0, 1)) DEFAULT 0,
+  recovery_codes TEXT NOT NULL DEFAULT (''),
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  failed_attempts INT NOT NULL DEFAULT 0,
+  last_attempted_ts BIGINT NOT NULL DEFAULT 0
+);
+
+-- user_credential
+CREATE TABLE user_credential (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  updated_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  name TEXT NOT NULL DEFAULT (''),
+  credential_id VARCHAR(512) NOT NULL UNIQUE,
+  public_key TEXT NOT NULL,
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_user_credential_user_id ON user_credential (user_id);
+
+-- mail_token
+CREATE TABLE mail_token (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id INT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  type VARCHAR(256) NOT NULL CHECK (type IN ('PASSWORD_RESET', 'EMAIL_VERIFICATION')),
+  token_hash VARCHAR(256) NOT NULL UNIQUE,
+  email VARCHAR(256) NOT NULL,
+  expires_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_mail_token_user_id ON mail_token (user_id);
+
+-- passkey_challenge
+CREATE TABLE passkey_challenge (
+  id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (UNIX_TIMESTAMP()),
+  challenge VARCHAR(256) NOT NULL UNIQUE,
+  expires_ts BIGINT NOT NULL
+);
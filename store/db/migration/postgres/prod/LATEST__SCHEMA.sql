@@ -0,0 +1,246 @@
+-- migration_history
+CREATE TABLE migration_history (
+  version TEXT NOT NULL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())
+);
+
+-- system_setting
+CREATE TABLE system_setting (
+  name TEXT NOT NULL,
+  value TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  UNIQUE(name)
+);
+
+-- user
+CREATE TABLE "user" (
+  id SERIAL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  row_status TEXT NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  username TEXT NOT NULL UNIQUE,
+  role TEXT NOT NULL CHECK (role IN ('HOST', 'ADMIN', 'USER')) DEFAULT 'USER',
+  email TEXT NOT NULL DEFAULT '',
+  nickname TEXT NOT NULL DEFAULT '',
+  password_hash TEXT NOT NULL,
+  open_id TEXT NOT NULL UNIQUE,
+  avatar_url TEXT NOT NULL DEFAULT '',
+  idp_id INTEGER NOT NULL DEFAULT 0,
+  idp_identifier TEXT NOT NULL DEFAULT '',
+  verified_email TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_user_username ON "user" (username);
+
+CREATE INDEX idx_user_idp_identifier ON "user" (idp_id, idp_identifier);
+
+-- user_setting
+CREATE TABLE user_setting (
+  user_id INTEGER NOT NULL,
+  key TEXT NOT NULL,
+  value TEXT NOT NULL,
+  UNIQUE(user_id, key)
+);
+
+-- memo
+CREATE TABLE memo (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  row_status TEXT NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  content TEXT NOT NULL DEFAULT '',
+  visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE',
+  deleted_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_memo_creator_id ON memo (creator_id);
+CREATE INDEX idx_memo_visibility ON memo (visibility);
+CREATE INDEX idx_memo_deleted_ts ON memo (deleted_ts);
+
+-- memo_revision
+CREATE TABLE memo_revision (
+  id SERIAL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  memo_id INTEGER NOT NULL,
+  content TEXT NOT NULL DEFAULT '',
+  visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE'
+);
+
+CREATE INDEX idx_memo_revision_memo_id ON memo_revision (memo_id);
+
+-- memo_organizer
+CREATE TABLE memo_organizer (
+  memo_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  pinned INTEGER NOT NULL CHECK (pinned IN (0, 1)) DEFAULT 0,
+  UNIQUE(memo_id, user_id)
+);
+
+-- shortcut
+CREATE TABLE shortcut (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  row_status TEXT NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
+  title TEXT NOT NULL DEFAULT '',
+  payload TEXT NOT NULL DEFAULT '{}'
+);
+
+-- resource
+CREATE TABLE resource (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  filename TEXT NOT NULL DEFAULT '',
+  blob BYTEA DEFAULT NULL,
+  external_link TEXT NOT NULL DEFAULT '',
+  type TEXT NOT NULL DEFAULT '',
+  size BIGINT NOT NULL DEFAULT 0,
+  internal_path TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_resource_creator_id ON resource (creator_id);
+
+-- memo_resource
+CREATE TABLE memo_resource (
+  memo_id INTEGER NOT NULL,
+  resource_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  UNIQUE(memo_id, resource_id)
+);
+
+-- tag
+CREATE TABLE tag (
+  name TEXT NOT NULL,
+  creator_id INTEGER NOT NULL,
+  UNIQUE(name, creator_id)
+);
+
+-- memo_tag
+CREATE TABLE memo_tag (
+  memo_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  UNIQUE(memo_id, name)
+);
+
+CREATE INDEX idx_memo_tag_name ON memo_tag (name);
+
+-- activity
+CREATE TABLE activity (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  type TEXT NOT NULL DEFAULT '',
+  level TEXT NOT NULL CHECK (level IN ('INFO', 'WARN', 'ERROR')) DEFAULT 'INFO',
+  payload TEXT NOT NULL DEFAULT '{}'
+);
+
+-- storage
+CREATE TABLE storage (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  config TEXT NOT NULL DEFAULT '{}'
+);
+
+-- idp
+CREATE TABLE idp (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  identifier_filter TEXT NOT NULL DEFAULT '',
+  config TEXT NOT NULL DEFAULT '{}',
+  provisioning TEXT NOT NULL DEFAULT '{}'
+);
+
+-- memo_relation
+CREATE TABLE memo_relation (
+  memo_id INTEGER NOT NULL,
+  related_memo_id INTEGER NOT NULL,
+  type TEXT NOT NULL,
+  UNIQUE(memo_id, related_memo_id, type)
+);
+
+-- access_token
+CREATE TABLE access_token (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  name TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT NOT NULL DEFAULT '',
+  expires_ts BIGINT NOT NULL,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_access_token_user_id ON access_token (user_id);
+
+-- session
+CREATE TABLE session (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  expires_ts BIGINT NOT NULL,
+  refresh_token_hash TEXT NOT NULL UNIQUE,
+  previous_refresh_token_hash TEXT NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  ip_address TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_session_user_id ON session (user_id);
+
+CREATE INDEX idx_session_previous_refresh_token_hash ON session (previous_refresh_token_hash);
+
+-- user_totp
+CREATE TABLE user_totp (
+  user_id INTEGER PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  secret TEXT NOT NULL,
+  enabled INTEGER NOT NULL CHECK (enabled IN (0, 1)) DEFAULT 0,
+  recovery_codes TEXT NOT NULL DEFAULT '',
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  failed_attempts INTEGER NOT NULL DEFAULT 0,
+  last_attempted_ts BIGINT NOT NULL DEFAULT 0
+);
+
+-- user_credential
+CREATE TABLE user_credential (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  name TEXT NOT NULL DEFAULT '',
+  credential_id TEXT NOT NULL UNIQUE,
+  public_key TEXT NOT NULL,
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_user_credential_user_id ON user_credential (user_id);
+
+-- mail_token
+CREATE TABLE mail_token (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  type TEXT NOT NULL CHECK (type IN ('PASSWORD_RESET', 'EMAIL_VERIFICATION')),
+  token_hash TEXT NOT NULL UNIQUE,
+  email TEXT NOT NULL,
+  expires_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_mail_token_user_id ON mail_token (user_id);
+
+-- passkey_challenge
+CREATE TABLE passkey_challenge (
+  id SERIAL PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
+  challenge TEXT NOT NULL UNIQUE,
+  expires_ts BIGINT NOT NULL
+);
@@ -24,11 +24,16 @@ CREATE TABLE user (
   nickname TEXT NOT NULL DEFAULT '',
   password_hash TEXT NOT NULL,
   open_id TEXT NOT NULL UNIQUE,
-  avatar_url TEXT NOT NULL DEFAULT ''
+  avatar_url TEXT NOT NULL DEFAULT '',
+  idp_id INTEGER NOT NULL DEFAULT 0,
+  idp_identifier TEXT NOT NULL DEFAULT '',
+  verified_email TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_user_username ON user (username);
 
+CREATE INDEX idx_user_idp_identifier ON user (idp_id, idp_identifier);
+
 -- user_setting
 CREATE TABLE user_setting (
   user_id INTEGER NOT NULL,
@@ -45,12 +50,28 @@ CREATE TABLE memo (
   updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
   row_status TEXT NOT NULL CHECK (row_status IN ('NORMAL', 'ARCHIVED')) DEFAULT 'NORMAL',
   content TEXT NOT NULL DEFAULT '',
-  visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE'
+  visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE',
+  deleted_ts BIGINT NOT NULL DEFAULT 0
 );
 
 CREATE INDEX idx_memo_creator_id ON memo (creator_id);
 CREATE INDEX idx_memo_content ON memo (content);
 CREATE INDEX idx_memo_visibility ON memo (visibility);
+CREATE INDEX idx_memo_deleted_ts ON memo (deleted_ts);
+
+-- memo_fts
+CREATE VIRTUAL TABLE memo_fts USING fts5(content, tokenize = 'trigram');
+
+-- memo_revision
+CREATE TABLE memo_revision (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  memo_id INTEGER NOT NULL,
+  content TEXT NOT NULL DEFAULT '',
+  visibility TEXT NOT NULL CHECK (visibility IN ('PUBLIC', 'PROTECTED', 'PRIVATE')) DEFAULT 'PRIVATE'
+);
+
+CREATE INDEX idx_memo_revision_memo_id ON memo_revision (memo_id);
 
 -- memo_organizer
 CREATE TABLE memo_organizer (
@@ -103,6 +124,15 @@ CREATE TABLE tag (
   UNIQUE(name, creator_id)
 );
 
+-- memo_tag
+CREATE TABLE memo_tag (
+  memo_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  UNIQUE(memo_id, name)
+);
+
+CREATE INDEX idx_memo_tag_name ON memo_tag (name);
+
 -- activity
 CREATE TABLE activity (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -127,7 +157,8 @@ CREATE TABLE idp (
   name TEXT NOT NULL,
   type TEXT NOT NULL,
   identifier_filter TEXT NOT NULL DEFAULT '',
-  config TEXT NOT NULL DEFAULT '{}'
+  config TEXT NOT NULL DEFAULT '{}',
+  provisioning TEXT NOT NULL DEFAULT '{}'
 );
 
 -- memo_relation
@@ -137,3 +168,83 @@ CREATE TABLE memo_relation (
   type TEXT NOT NULL,
   UNIQUE(memo_id, related_memo_id, type)
 );
+
+-- access_token
+CREATE TABLE access_token (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  name TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT NOT NULL DEFAULT '',
+  expires_ts BIGINT NOT NULL,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_access_token_user_id ON access_token (user_id);
+
+-- session
+CREATE TABLE session (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  expires_ts BIGINT NOT NULL,
+  refresh_token_hash TEXT NOT NULL UNIQUE,
+  previous_refresh_token_hash TEXT NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  ip_address TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_session_user_id ON session (user_id);
+
+CREATE INDEX idx_session_previous_refresh_token_hash ON session (previous_refresh_token_hash);
+
+-- user_totp
+CREATE TABLE user_totp (
+  user_id INTEGER PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  secret TEXT NOT NULL,
+  enabled INTEGER NOT NULL CHECK (enabled IN (0, 1)) DEFAULT 0,
+  recovery_codes TEXT NOT NULL DEFAULT '',
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  failed_attempts INTEGER NOT NULL DEFAULT 0,
+  last_attempted_ts BIGINT NOT NULL DEFAULT 0
+);
+
+-- user_credential
+CREATE TABLE user_credential (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  name TEXT NOT NULL DEFAULT '',
+  credential_id TEXT NOT NULL UNIQUE,
+  public_key TEXT NOT NULL,
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_user_credential_user_id ON user_credential (user_id);
+
+-- mail_token
+CREATE TABLE mail_token (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  type TEXT NOT NULL CHECK (type IN ('PASSWORD_RESET', 'EMAIL_VERIFICATION')),
+  token_hash TEXT NOT NULL UNIQUE,
+  email TEXT NOT NULL,
+  expires_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_mail_token_user_id ON mail_token (user_id);
+
+-- passkey_challenge
+CREATE TABLE passkey_challenge (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  challenge TEXT NOT NULL UNIQUE,
+  expires_ts BIGINT NOT NULL
+);
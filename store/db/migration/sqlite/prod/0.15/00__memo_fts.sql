@@ -0,0 +1,3 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS memo_fts USING fts5(content, tokenize = 'trigram');
+
+INSERT INTO memo_fts (rowid, content) SELECT id, content FROM memo;
@@ -0,0 +1,7 @@
+CREATE TABLE memo_tag (
+  memo_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  UNIQUE(memo_id, name)
+);
+
+CREATE INDEX idx_memo_tag_name ON memo_tag (name);
@@ -0,0 +1,15 @@
+CREATE TABLE session (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  expires_ts BIGINT NOT NULL,
+  refresh_token_hash TEXT NOT NULL UNIQUE,
+  previous_refresh_token_hash TEXT NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  ip_address TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_session_user_id ON session (user_id);
+
+CREATE INDEX idx_session_previous_refresh_token_hash ON session (previous_refresh_token_hash);
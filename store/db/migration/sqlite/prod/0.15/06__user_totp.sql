@@ -0,0 +1,11 @@
+CREATE TABLE user_totp (
+  user_id INTEGER PRIMARY KEY,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  secret TEXT NOT NULL,
+  enabled INTEGER NOT NULL CHECK (enabled IN (0, 1)) DEFAULT 0,
+  recovery_codes TEXT NOT NULL DEFAULT '',
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  failed_attempts INTEGER NOT NULL DEFAULT 0,
+  last_attempted_ts BIGINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,7 @@
+ALTER TABLE idp ADD COLUMN provisioning TEXT NOT NULL DEFAULT '{}';
+
+ALTER TABLE user ADD COLUMN idp_id INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE user ADD COLUMN idp_identifier TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_user_idp_identifier ON user (idp_id, idp_identifier);
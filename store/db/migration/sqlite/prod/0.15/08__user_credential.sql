@@ -0,0 +1,13 @@
+CREATE TABLE user_credential (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  name TEXT NOT NULL DEFAULT '',
+  credential_id TEXT NOT NULL UNIQUE,
+  public_key TEXT NOT NULL,
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_user_credential_user_id ON user_credential (user_id);
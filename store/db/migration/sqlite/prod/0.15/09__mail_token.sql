@@ -0,0 +1,13 @@
+ALTER TABLE user ADD COLUMN verified_email TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE mail_token (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  type TEXT NOT NULL CHECK (type IN ('PASSWORD_RESET', 'EMAIL_VERIFICATION')),
+  token_hash TEXT NOT NULL UNIQUE,
+  email TEXT NOT NULL,
+  expires_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_mail_token_user_id ON mail_token (user_id);
@@ -0,0 +1,6 @@
+CREATE TABLE passkey_challenge (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+  challenge TEXT NOT NULL UNIQUE,
+  expires_ts BIGINT NOT NULL
+);
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"strings"
 )
 
@@ -61,23 +62,34 @@ func (db *DB) FindMigrationHistoryList(ctx context.Context, find *MigrationHisto
 }
 
 func (db *DB) UpsertMigrationHistory(ctx context.Context, upsert *MigrationHistoryUpsert) (*MigrationHistory, error) {
-	stmt := `
+	// The upsert is done by find and insert, which is the same for all drivers.
+	list, err := db.FindMigrationHistoryList(ctx, &MigrationHistoryFind{
+		Version: &upsert.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		stmt := `
 		INSERT INTO migration_history (
 			version
 		)
 		VALUES (?)
-		ON CONFLICT(version) DO UPDATE
-		SET
-			version=EXCLUDED.version
-		RETURNING version, created_ts
 	`
-	var migrationHistory MigrationHistory
-	if err := db.DBInstance.QueryRowContext(ctx, stmt, upsert.Version).Scan(
-		&migrationHistory.Version,
-		&migrationHistory.CreatedTs,
-	); err != nil {
-		return nil, err
+		if _, err := db.DBInstance.ExecContext(ctx, stmt, upsert.Version); err != nil {
+			return nil, err
+		}
+		list, err = db.FindMigrationHistoryList(ctx, &MigrationHistoryFind{
+			Version: &upsert.Version,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("migration history %s not found", upsert.Version)
 	}
 
-	return &migrationHistory, nil
+	migrationHistory := list[0]
+	return migrationHistory, nil
 }
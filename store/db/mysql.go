@@ -0,0 +1,27 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openMySQL(dsn string) (*sql.DB, error) {
+	config, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	// The schema and migration files contain multiple statements.
+	config.MultiStatements = true
+	// The SQL shared with other drivers double-quotes identifiers and concatenates strings with `||`.
+	if config.Params == nil {
+		config.Params = map[string]string{}
+	}
+	config.Params["sql_mode"] = "CONCAT(@@sql_mode, ',ANSI_QUOTES,PIPES_AS_CONCAT')"
+
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func openPostgres(dsn string) (*sql.DB, error) {
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(&postgresConnector{Connector: stdlib.GetConnector(*config)}), nil
+}
+
+// postgresConnector makes the PostgreSQL connections accept the `?` placeholders of other drivers,
+// so that the SQL of store is shared by all of them.
+type postgresConnector struct {
+	driver.Connector
+}
+
+func (c *postgresConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresConn{Conn: conn.(*stdlib.Conn)}, nil
+}
+
+type postgresConn struct {
+	*stdlib.Conn
+}
+
+func (c *postgresConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rebindPostgres(query))
+}
+
+func (c *postgresConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.PrepareContext(ctx, rebindPostgres(query))
+}
+
+func (c *postgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.ExecContext(ctx, rebindPostgres(query), args)
+}
+
+func (c *postgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.QueryContext(ctx, rebindPostgres(query), args)
+}
+
+// rebindPostgres replaces the `?` placeholders in query with the `$n` ones of PostgreSQL.
+// Question marks in string literals, quoted identifiers and line comments are kept.
+func rebindPostgres(query string) string {
+	builder := strings.Builder{}
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"':
+			// A doubled quote inside is read as two adjacent quoted parts, which is kept as well.
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				builder.WriteString(query[i:])
+				return builder.String()
+			}
+			builder.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				builder.WriteString(query[i:])
+				return builder.String()
+			}
+			builder.WriteString(query[i : i+end+1])
+			i += end
+		case c == '?':
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebindPostgres(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "SELECT id FROM memo WHERE creator_id = ? AND visibility IN (?, ?)",
+			want:  "SELECT id FROM memo WHERE creator_id = $1 AND visibility IN ($2, $3)",
+		},
+		{
+			query: `SELECT id FROM "user" WHERE username = ? AND nickname != 'who?'`,
+			want:  `SELECT id FROM "user" WHERE username = $1 AND nickname != 'who?'`,
+		},
+		{
+			query: "SELECT 'it''s ?' -- why?\nFROM memo WHERE id = ?",
+			want:  "SELECT 'it''s ?' -- why?\nFROM memo WHERE id = $1",
+		},
+		{
+			query: `SELECT "a?b" FROM memo`,
+			want:  `SELECT "a?b" FROM memo`,
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, rebindPostgres(test.query))
+	}
+}
@@ -6,7 +6,7 @@ INSERT INTO
   )
 VALUES
   (
-    'inbox',
+    'TODO',
     101,
-    '[{"type":"TYPE","value":{"operator":"IS","value":"NOT_TAGGED"},"relation":"AND"}]'
-  );
\ No newline at end of file
+    '{"tags":["TODO"]}'
+  );
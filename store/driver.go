@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// The SQL of store is shared by all the drivers in profile.Driver, the helpers below
+// compose the parts which differ among them. Placeholders are always `?`,
+// as the PostgreSQL connections of store/db rebind them.
+// Identifiers reserved by any driver, such as "user", "key" and "blob", are double-quoted,
+// which MySQL accepts with the ANSI_QUOTES mode set by store/db.
+
+// queryer is the common interface of *sql.DB and *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertReturning executes the INSERT stmt into table and scans the columns of the inserted row into dest.
+// MySQL doesn't support RETURNING, so the row is selected by the last insert ID there.
+func (s *Store) insertReturning(ctx context.Context, q queryer, table, stmt string, args []any, columns []string, dest ...any) error {
+	if s.Profile.Driver != "mysql" {
+		return q.QueryRowContext(ctx, stmt+" RETURNING "+strings.Join(columns, ", "), args...).Scan(dest...)
+	}
+
+	result, err := q.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", strings.Join(columns, ", "), table)
+	return q.QueryRowContext(ctx, query, id).Scan(dest...)
+}
+
+// execReturning executes stmt, which changes the row of table matching where, and scans the columns of the row into dest.
+// MySQL doesn't support RETURNING, so the row is selected by where there.
+func (s *Store) execReturning(ctx context.Context, q queryer, table, stmt string, args []any, where string, whereArgs []any, columns []string, dest ...any) error {
+	if s.Profile.Driver != "mysql" {
+		return q.QueryRowContext(ctx, stmt+" RETURNING "+strings.Join(columns, ", "), args...).Scan(dest...)
+	}
+
+	if _, err := q.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(columns, ", "), table, where)
+	return q.QueryRowContext(ctx, query, whereArgs...).Scan(dest...)
+}
+
+// onConflictUpdate returns the clause of an INSERT which updates columns of the existing row
+// when the inserted one conflicts with it on the unique conflictColumns.
+func (s *Store) onConflictUpdate(conflictColumns []string, columns ...string) string {
+	set := []string{}
+	if s.Profile.Driver == "mysql" {
+		for _, column := range columns {
+			set = append(set, fmt.Sprintf("%s = VALUES(%s)", column, column))
+		}
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+	}
+
+	for _, column := range columns {
+		set = append(set, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+	}
+	return fmt.Sprintf("ON CONFLICT(%s) DO UPDATE SET %s", strings.Join(conflictColumns, ", "), strings.Join(set, ", "))
+}
+
+// groupConcat returns the aggregate expression joining the values of expr with commas.
+func (s *Store) groupConcat(expr string) string {
+	if s.Profile.Driver == "postgres" {
+		return fmt.Sprintf("STRING_AGG(CAST(%s AS TEXT), ',')", expr)
+	}
+	return fmt.Sprintf("GROUP_CONCAT(%s)", expr)
+}
+
+// like returns the condition that expr contains the text of a placeholder case-insensitively, whose value is
+// made by containsPattern. It matches LIKE of SQLite and MySQL, which ignore the case of ASCII letters by default.
+func (s *Store) like(expr string) string {
+	switch s.Profile.Driver {
+	case "postgres":
+		return expr + ` ILIKE ? ESCAPE '\'`
+	case "mysql":
+		// The backslash is the default escape character of MySQL, where it escapes string literals as well.
+		return expr + " LIKE ?"
+	}
+	return expr + ` LIKE ? ESCAPE '\'`
+}
+
+// containsPattern returns the LIKE pattern matching the text anywhere, with its wildcards escaped.
+func containsPattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(text) + "%"
+}
@@ -11,6 +11,8 @@ type IdentityProviderType string
 
 const (
 	IdentityProviderOAuth2Type IdentityProviderType = "OAUTH2"
+	IdentityProviderOIDCType   IdentityProviderType = "OIDC"
+	IdentityProviderLDAPType   IdentityProviderType = "LDAP"
 )
 
 func (t IdentityProviderType) String() string {
@@ -19,6 +21,8 @@ func (t IdentityProviderType) String() string {
 
 type IdentityProviderConfig struct {
 	OAuth2Config *IdentityProviderOAuth2Config
+	OIDCConfig   *IdentityProviderOIDCConfig
+	LDAPConfig   *IdentityProviderLDAPConfig
 }
 
 type IdentityProviderOAuth2Config struct {
@@ -31,10 +35,67 @@ type IdentityProviderOAuth2Config struct {
 	FieldMapping *FieldMapping `json:"fieldMapping"`
 }
 
+// IdentityProviderOIDCConfig is the config of an OpenID Connect provider, whose endpoints are discovered from the issuer.
+type IdentityProviderOIDCConfig struct {
+	Issuer       string        `json:"issuer"`
+	ClientID     string        `json:"clientId"`
+	ClientSecret string        `json:"clientSecret"`
+	Scopes       []string      `json:"scopes"`
+	FieldMapping *FieldMapping `json:"fieldMapping"`
+}
+
+// IdentityProviderLDAPConfig is the config of a LDAP directory, whose users sign in with their directory password.
+type IdentityProviderLDAPConfig struct {
+	// URL is the address of the directory, e.g. ldaps://ldap.example.com:636.
+	URL      string `json:"url"`
+	StartTLS bool   `json:"startTls"`
+	// BindDN and BindPassword are the credentials of the service account searching the users.
+	BindDN       string `json:"bindDn"`
+	BindPassword string `json:"bindPassword"`
+	BaseDN       string `json:"baseDn"`
+	// UserFilter is the filter searching the user, where %s is replaced with the username, e.g. (uid=%s).
+	UserFilter   string        `json:"userFilter"`
+	FieldMapping *FieldMapping `json:"fieldMapping"`
+	// GroupAttribute is the attribute of the user listing its groups, e.g. memberOf.
+	// The role of the user is mapped from its groups on each sign-in if it's set.
+	GroupAttribute string   `json:"groupAttribute"`
+	HostGroups     []string `json:"hostGroups"`
+	AdminGroups    []string `json:"adminGroups"`
+}
+
 type FieldMapping struct {
 	Identifier  string `json:"identifier"`
 	DisplayName string `json:"displayName"`
 	Email       string `json:"email"`
+	AvatarURL   string `json:"avatarUrl"`
+}
+
+// IdentityProviderProvisioning is how the users signing in with an identity provider are provisioned.
+type IdentityProviderProvisioning struct {
+	// DisableSignUp prevents the unknown users from being created on their first sign-in.
+	DisableSignUp bool `json:"disableSignUp"`
+	// SyncProfile updates the nickname, email and avatar of the user from the claims on each sign-in.
+	// The email is only synced if the identity provider asserts that it's verified.
+	SyncProfile bool `json:"syncProfile"`
+	// RoleMappings map the claims to the role of the user on each sign-in, the first matching one wins.
+	// The user gets the USER role if none matches, and keeps its role if there is no mapping.
+	// The claims carrying the email are only matched if the identity provider asserts that it's verified.
+	RoleMappings []*IdentityProviderRoleMapping `json:"roleMappings"`
+	// ArchiveRule archives the user when it matches, e.g. when the identity provider reports the user as disabled.
+	ArchiveRule *IdentityProviderClaimRule `json:"archiveRule"`
+}
+
+// IdentityProviderClaimRule matches the user if any value of the claim matches the pattern.
+type IdentityProviderClaimRule struct {
+	Claim string `json:"claim"`
+	// Pattern is a regular expression, e.g. @example\.com$ matching the email domain.
+	Pattern string `json:"pattern"`
+}
+
+type IdentityProviderRoleMapping struct {
+	Claim   string `json:"claim"`
+	Pattern string `json:"pattern"`
+	Role    Role   `json:"role"`
 }
 
 type IdentityProvider struct {
@@ -43,6 +104,7 @@ type IdentityProvider struct {
 	Type             IdentityProviderType
 	IdentifierFilter string
 	Config           *IdentityProviderConfig
+	Provisioning     *IdentityProviderProvisioning
 }
 
 type FindIdentityProvider struct {
@@ -55,6 +117,7 @@ type UpdateIdentityProvider struct {
 	Name             *string
 	IdentifierFilter *string
 	Config           *IdentityProviderConfig
+	Provisioning     *IdentityProviderProvisioning
 }
 
 type DeleteIdentityProvider struct {
@@ -62,15 +125,16 @@ type DeleteIdentityProvider struct {
 }
 
 func (s *Store) CreateIdentityProvider(ctx context.Context, create *IdentityProvider) (*IdentityProvider, error) {
-	var configBytes []byte
-	if create.Type == IdentityProviderOAuth2Type {
-		bytes, err := json.Marshal(create.Config.OAuth2Config)
-		if err != nil {
-			return nil, err
-		}
-		configBytes = bytes
-	} else {
-		return nil, fmt.Errorf("unsupported idp type %s", string(create.Type))
+	configBytes, err := marshalIdentityProviderConfig(create.Type, create.Config)
+	if err != nil {
+		return nil, err
+	}
+	if create.Provisioning == nil {
+		create.Provisioning = &IdentityProviderProvisioning{}
+	}
+	provisioningBytes, err := json.Marshal(create.Provisioning)
+	if err != nil {
+		return nil, err
 	}
 
 	stmt := `
@@ -78,21 +142,13 @@ func (s *Store) CreateIdentityProvider(ctx context.Context, create *IdentityProv
 			name,
 			type,
 			identifier_filter,
-			config
+			config,
+			provisioning
 		)
-		VALUES (?, ?, ?, ?)
-		RETURNING id
+		VALUES (?, ?, ?, ?, ?)
 	`
-	if err := s.db.QueryRowContext(
-		ctx,
-		stmt,
-		create.Name,
-		create.Type,
-		create.IdentifierFilter,
-		string(configBytes),
-	).Scan(
-		&create.ID,
-	); err != nil {
+	args := []any{create.Name, create.Type, create.IdentifierFilter, string(configBytes), string(provisioningBytes)}
+	if err := s.insertReturning(ctx, s.db, "idp", stmt, args, []string{"id"}, &create.ID); err != nil {
 		return nil, err
 	}
 
@@ -104,7 +160,7 @@ func (s *Store) CreateIdentityProvider(ctx context.Context, create *IdentityProv
 func (s *Store) ListIdentityProviders(ctx context.Context, find *FindIdentityProvider) ([]*IdentityProvider, error) {
 	where, args := []string{"1 = 1"}, []any{}
 	if v := find.ID; v != nil {
-		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+		where, args = append(where, "id = ?"), append(args, *v)
 	}
 
 	rows, err := s.db.QueryContext(ctx, `
@@ -113,7 +169,8 @@ func (s *Store) ListIdentityProviders(ctx context.Context, find *FindIdentityPro
 			name,
 			type,
 			identifier_filter,
-			config
+			config,
+			provisioning
 		FROM idp
 		WHERE `+strings.Join(where, " AND ")+` ORDER BY id ASC`,
 		args...,
@@ -126,27 +183,26 @@ func (s *Store) ListIdentityProviders(ctx context.Context, find *FindIdentityPro
 	var identityProviders []*IdentityProvider
 	for rows.Next() {
 		var identityProvider IdentityProvider
-		var identityProviderConfig string
+		var identityProviderConfig, identityProviderProvisioning string
 		if err := rows.Scan(
 			&identityProvider.ID,
 			&identityProvider.Name,
 			&identityProvider.Type,
 			&identityProvider.IdentifierFilter,
 			&identityProviderConfig,
+			&identityProviderProvisioning,
 		); err != nil {
 			return nil, err
 		}
 
-		if identityProvider.Type == IdentityProviderOAuth2Type {
-			oauth2Config := &IdentityProviderOAuth2Config{}
-			if err := json.Unmarshal([]byte(identityProviderConfig), oauth2Config); err != nil {
-				return nil, err
-			}
-			identityProvider.Config = &IdentityProviderConfig{
-				OAuth2Config: oauth2Config,
-			}
-		} else {
-			return nil, fmt.Errorf("unsupported idp type %s", string(identityProvider.Type))
+		config, err := unmarshalIdentityProviderConfig(identityProvider.Type, identityProviderConfig)
+		if err != nil {
+			return nil, err
+		}
+		identityProvider.Config = config
+		identityProvider.Provisioning = &IdentityProviderProvisioning{}
+		if err := json.Unmarshal([]byte(identityProviderProvisioning), identityProvider.Provisioning); err != nil {
+			return nil, err
 		}
 		identityProviders = append(identityProviders, &identityProvider)
 	}
@@ -190,51 +246,50 @@ func (s *Store) UpdateIdentityProvider(ctx context.Context, update *UpdateIdenti
 		set, args = append(set, "identifier_filter = ?"), append(args, *v)
 	}
 	if v := update.Config; v != nil {
-		var configBytes []byte
-		if update.Type == IdentityProviderOAuth2Type {
-			bytes, err := json.Marshal(update.Config.OAuth2Config)
-			if err != nil {
-				return nil, err
-			}
-			configBytes = bytes
-		} else {
-			return nil, fmt.Errorf("unsupported idp type %s", string(update.Type))
+		configBytes, err := marshalIdentityProviderConfig(update.Type, v)
+		if err != nil {
+			return nil, err
 		}
 		set, args = append(set, "config = ?"), append(args, string(configBytes))
 	}
+	if v := update.Provisioning; v != nil {
+		provisioningBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		set, args = append(set, "provisioning = ?"), append(args, string(provisioningBytes))
+	}
 	args = append(args, update.ID)
 
 	stmt := `
 		UPDATE idp
 		SET ` + strings.Join(set, ", ") + `
 		WHERE id = ?
-		RETURNING id, name, type, identifier_filter, config
 	`
 	var identityProvider IdentityProvider
-	var identityProviderConfig string
-	if err := s.db.QueryRowContext(ctx, stmt, args...).Scan(
+	var identityProviderConfig, identityProviderProvisioning string
+	if err := s.execReturning(ctx, s.db, "idp", stmt, args, "id = ?", []any{update.ID}, []string{"id", "name", "type", "identifier_filter", "config", "provisioning"},
 		&identityProvider.ID,
 		&identityProvider.Name,
 		&identityProvider.Type,
 		&identityProvider.IdentifierFilter,
 		&identityProviderConfig,
+		&identityProviderProvisioning,
 	); err != nil {
 		return nil, err
 	}
 
-	if identityProvider.Type == IdentityProviderOAuth2Type {
-		oauth2Config := &IdentityProviderOAuth2Config{}
-		if err := json.Unmarshal([]byte(identityProviderConfig), oauth2Config); err != nil {
-			return nil, err
-		}
-		identityProvider.Config = &IdentityProviderConfig{
-			OAuth2Config: oauth2Config,
-		}
-	} else {
-		return nil, fmt.Errorf("unsupported idp type %s", string(identityProvider.Type))
+	config, err := unmarshalIdentityProviderConfig(identityProvider.Type, identityProviderConfig)
+	if err != nil {
+		return nil, err
+	}
+	identityProvider.Config = config
+	identityProvider.Provisioning = &IdentityProviderProvisioning{}
+	if err := json.Unmarshal([]byte(identityProviderProvisioning), identityProvider.Provisioning); err != nil {
+		return nil, err
 	}
 
-	s.idpCache.Store(identityProvider.ID, identityProvider)
+	s.idpCache.Store(identityProvider.ID, &identityProvider)
 	return &identityProvider, nil
 }
 
@@ -251,3 +306,48 @@ func (s *Store) DeleteIdentityProvider(ctx context.Context, delete *DeleteIdenti
 	s.idpCache.Delete(delete.ID)
 	return nil
 }
+
+// marshalIdentityProviderConfig marshals the config of the given idp type, which is stored as JSON.
+func marshalIdentityProviderConfig(identityProviderType IdentityProviderType, config *IdentityProviderConfig) ([]byte, error) {
+	switch identityProviderType {
+	case IdentityProviderOAuth2Type:
+		return json.Marshal(config.OAuth2Config)
+	case IdentityProviderOIDCType:
+		return json.Marshal(config.OIDCConfig)
+	case IdentityProviderLDAPType:
+		return json.Marshal(config.LDAPConfig)
+	default:
+		return nil, fmt.Errorf("unsupported idp type %s", string(identityProviderType))
+	}
+}
+
+func unmarshalIdentityProviderConfig(identityProviderType IdentityProviderType, config string) (*IdentityProviderConfig, error) {
+	switch identityProviderType {
+	case IdentityProviderOAuth2Type:
+		oauth2Config := &IdentityProviderOAuth2Config{}
+		if err := json.Unmarshal([]byte(config), oauth2Config); err != nil {
+			return nil, err
+		}
+		return &IdentityProviderConfig{
+			OAuth2Config: oauth2Config,
+		}, nil
+	case IdentityProviderOIDCType:
+		oidcConfig := &IdentityProviderOIDCConfig{}
+		if err := json.Unmarshal([]byte(config), oidcConfig); err != nil {
+			return nil, err
+		}
+		return &IdentityProviderConfig{
+			OIDCConfig: oidcConfig,
+		}, nil
+	case IdentityProviderLDAPType:
+		ldapConfig := &IdentityProviderLDAPConfig{}
+		if err := json.Unmarshal([]byte(config), ldapConfig); err != nil {
+			return nil, err
+		}
+		return &IdentityProviderConfig{
+			LDAPConfig: ldapConfig,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported idp type %s", string(identityProviderType))
+	}
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// MailTokenType is the type of a mail token.
+type MailTokenType string
+
+const (
+	// MailTokenPasswordReset is the type of the tokens resetting the password of a user.
+	MailTokenPasswordReset MailTokenType = "PASSWORD_RESET"
+	// MailTokenEmailVerification is the type of the tokens verifying the email of a user.
+	MailTokenEmailVerification MailTokenType = "EMAIL_VERIFICATION"
+)
+
+// MailToken is a single-use token sent to the email of a user, which proves the user owns the email.
+// Only the hash of the token is stored.
+type MailToken struct {
+	ID int32
+
+	// Standard fields
+	UserID    int32
+	CreatedTs int64
+
+	// Domain specific fields
+	Type      MailTokenType
+	TokenHash string
+	// Email is the address the token has been sent to.
+	Email     string
+	ExpiresTs int64
+}
+
+type FindMailToken struct {
+	ID        *int32
+	UserID    *int32
+	Type      *MailTokenType
+	TokenHash *string
+}
+
+type DeleteMailToken struct {
+	ID     *int32
+	UserID *int32
+	Type   *MailTokenType
+}
+
+func (s *Store) CreateMailToken(ctx context.Context, create *MailToken) (*MailToken, error) {
+	stmt := `
+		INSERT INTO mail_token (
+			user_id,
+			type,
+			token_hash,
+			email,
+			expires_ts
+		)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	args := []any{create.UserID, create.Type, create.TokenHash, create.Email, create.ExpiresTs}
+	if err := s.insertReturning(ctx, s.db, "mail_token", stmt, args, []string{"id", "created_ts"},
+		&create.ID,
+		&create.CreatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	mailToken := create
+	return mailToken, nil
+}
+
+func (s *Store) ListMailTokens(ctx context.Context, find *FindMailToken) ([]*MailToken, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if v := find.Type; v != nil {
+		where, args = append(where, "type = ?"), append(args, *v)
+	}
+	if v := find.TokenHash; v != nil {
+		where, args = append(where, "token_hash = ?"), append(args, *v)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			user_id,
+			created_ts,
+			type,
+			token_hash,
+			email,
+			expires_ts
+		FROM mail_token
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*MailToken{}
+	for rows.Next() {
+		mailToken := &MailToken{}
+		if err := rows.Scan(
+			&mailToken.ID,
+			&mailToken.UserID,
+			&mailToken.CreatedTs,
+			&mailToken.Type,
+			&mailToken.TokenHash,
+			&mailToken.Email,
+			&mailToken.ExpiresTs,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, mailToken)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetMailToken(ctx context.Context, find *FindMailToken) (*MailToken, error) {
+	list, err := s.ListMailTokens(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	mailToken := list[0]
+	return mailToken, nil
+}
+
+// DeleteMailToken deletes the mail tokens, returning the number of deleted tokens
+// so that concurrent uses of a single-use token are told apart.
+func (s *Store) DeleteMailToken(ctx context.Context, delete *DeleteMailToken) (int64, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := delete.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if v := delete.Type; v != nil {
+		where, args = append(where, "type = ?"), append(args, *v)
+	}
+	if len(args) == 0 {
+		// Prevent deleting all the tokens by accident.
+		return 0, nil
+	}
+
+	stmt := `DELETE FROM mail_token WHERE ` + strings.Join(where, " AND ")
+	result, err := s.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func vacuumMailToken(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		mail_token
+	WHERE
+		user_id NOT IN (
+			SELECT
+				id
+			FROM
+				"user"
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
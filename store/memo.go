@@ -46,29 +46,73 @@ type Memo struct {
 	// Domain specific fields
 	Content    string
 	Visibility Visibility
+	// DeletedTs is the time the memo is moved to trash, or 0 if it isn't in trash.
+	DeletedTs int64
 
 	// Composed fields
 	Pinned         bool
 	ResourceIDList []int32
 	RelationList   []*MemoRelation
+
+	// Search fields
+	// Snippet is the highlighted excerpt of content matching the content search,
+	// as HTML with the content escaped and the matches in <mark> tags.
+	Snippet string
 }
 
 type FindMemo struct {
 	ID *int32
 
 	// Standard fields
-	RowStatus *RowStatus
-	CreatorID *int32
+	RowStatus       *RowStatus
+	CreatorID       *int32
+	CreatedTsAfter  *int64
+	CreatedTsBefore *int64
 
 	// Domain specific fields
-	Pinned         *bool
-	ContentSearch  []string
+	Pinned        *bool
+	ContentSearch []string
+	// TagList finds the memos with all of the tags, a memo with a descendant of a tag such as `work/clientA` has the tag `work` as well.
+	TagList        []string
 	VisibilityList []Visibility
+	// InTrash finds the memos in trash instead of the others.
+	InTrash         bool
+	DeletedTsBefore *int64
+
+	// Filter finds the memos matching the filter expression.
+	Filter *MemoFilter
+	// ViewerID finds the memos visible to the user, which are the public and protected ones and the private ones of the user.
+	ViewerID *int32
 
 	// Pagination
-	Limit            *int
-	Offset           *int
+	Limit  *int
+	Offset *int
+	// Cursor finds the memos after the one of the cursor in the order, it's ignored when ordering by rank.
+	Cursor           *MemoCursor
 	OrderByUpdatedTs bool
+	// OrderByRank orders the memos by the relevance of content search if possible.
+	OrderByRank bool
+}
+
+// MemoCursor is the position of a memo in the order of memos.
+type MemoCursor struct {
+	Pinned bool
+	// Ts is the created or updated ts of the memo, depending on the order.
+	Ts int64
+	ID int32
+}
+
+// NewMemoCursor returns the cursor of the memo in the order of find.
+func NewMemoCursor(memo *Memo, find *FindMemo) *MemoCursor {
+	cursor := &MemoCursor{
+		Pinned: memo.Pinned,
+		Ts:     memo.CreatedTs,
+		ID:     memo.ID,
+	}
+	if find.OrderByUpdatedTs {
+		cursor.Ts = memo.UpdatedTs
+	}
+	return cursor
 }
 
 type UpdateMemo struct {
@@ -78,6 +122,7 @@ type UpdateMemo struct {
 	RowStatus  *RowStatus
 	Content    *string
 	Visibility *Visibility
+	DeletedTs  *int64
 }
 
 type DeleteMemo struct {
@@ -89,6 +134,12 @@ func (s *Store) CreateMemo(ctx context.Context, create *Memo) (*Memo, error) {
 		create.CreatedTs = time.Now().Unix()
 	}
 
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	stmt := `
 		INSERT INTO memo (
 			creator_id,
@@ -97,16 +148,9 @@ func (s *Store) CreateMemo(ctx context.Context, create *Memo) (*Memo, error) {
 			visibility
 		)
 		VALUES (?, ?, ?, ?)
-		RETURNING id, created_ts, updated_ts, row_status
 	`
-	if err := s.db.QueryRowContext(
-		ctx,
-		stmt,
-		create.CreatorID,
-		create.CreatedTs,
-		create.Content,
-		create.Visibility,
-	).Scan(
+	args := []any{create.CreatorID, create.CreatedTs, create.Content, create.Visibility}
+	if err := s.insertReturning(ctx, tx, "memo", stmt, args, []string{"id", "created_ts", "updated_ts", "row_status"},
 		&create.ID,
 		&create.CreatedTs,
 		&create.UpdatedTs,
@@ -114,78 +158,42 @@ func (s *Store) CreateMemo(ctx context.Context, create *Memo) (*Memo, error) {
 	); err != nil {
 		return nil, err
 	}
-
-	memo := create
-	return memo, nil
-}
-
-func (s *Store) ListMemos(ctx context.Context, find *FindMemo) ([]*Memo, error) {
-	where, args := []string{"1 = 1"}, []any{}
-
-	if v := find.ID; v != nil {
-		where, args = append(where, "memo.id = ?"), append(args, *v)
-	}
-	if v := find.CreatorID; v != nil {
-		where, args = append(where, "memo.creator_id = ?"), append(args, *v)
+	if err := s.upsertMemoFTS(ctx, tx, create.ID, create.Content); err != nil {
+		return nil, err
 	}
-	if v := find.RowStatus; v != nil {
-		where, args = append(where, "memo.row_status = ?"), append(args, *v)
+	if err := upsertMemoTags(ctx, tx, create.ID, create.Content); err != nil {
+		return nil, err
 	}
-	if v := find.Pinned; v != nil {
-		where = append(where, "memo_organizer.pinned = 1")
+	if err := createMemoRevision(ctx, tx, create.ID); err != nil {
+		return nil, err
 	}
-	if v := find.ContentSearch; len(v) != 0 {
-		for _, s := range v {
-			where, args = append(where, "memo.content LIKE ?"), append(args, "%"+s+"%")
+	// The resources and relations of the memo are created along with it, so that no memo is left half-created.
+	for _, resourceID := range create.ResourceIDList {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO memo_resource (memo_id, resource_id) VALUES (?, ?)`, create.ID, resourceID); err != nil {
+			return nil, err
 		}
 	}
-	if v := find.VisibilityList; len(v) != 0 {
-		list := []string{}
-		for _, visibility := range v {
-			list = append(list, fmt.Sprintf("$%d", len(args)+1))
-			args = append(args, visibility)
+	for _, relation := range create.RelationList {
+		relation.MemoID = create.ID
+		if _, err := tx.ExecContext(ctx, `INSERT INTO memo_relation (memo_id, related_memo_id, type) VALUES (?, ?, ?)`, relation.MemoID, relation.RelatedMemoID, relation.Type); err != nil {
+			return nil, err
 		}
-		where = append(where, fmt.Sprintf("memo.visibility in (%s)", strings.Join(list, ",")))
 	}
-	orders := []string{"pinned DESC"}
-	if find.OrderByUpdatedTs {
-		orders = append(orders, "updated_ts DESC")
-	} else {
-		orders = append(orders, "created_ts DESC")
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
-	orders = append(orders, "id DESC")
 
-	query := `
-	SELECT
-		memo.id AS id,
-		memo.creator_id AS creator_id,
-		memo.created_ts AS created_ts,
-		memo.updated_ts AS updated_ts,
-		memo.row_status AS row_status,
-		memo.content AS content,
-		memo.visibility AS visibility,
-		CASE WHEN memo_organizer.pinned = 1 THEN 1 ELSE 0 END AS pinned,
-		GROUP_CONCAT(memo_resource.resource_id) AS resource_id_list,
-		(
-				SELECT
-						GROUP_CONCAT(related_memo_id || ':' || type)
-				FROM
-						memo_relation
-				WHERE
-						memo_relation.memo_id = memo.id
-				GROUP BY
-						memo_relation.memo_id
-		) AS relation_list
-	FROM
-		memo
-	LEFT JOIN
-		memo_organizer ON memo.id = memo_organizer.memo_id
-	LEFT JOIN
-		memo_resource ON memo.id = memo_resource.memo_id
-	WHERE ` + strings.Join(where, " AND ") + `
-	GROUP BY memo.id
-	ORDER BY ` + strings.Join(orders, ", ") + `
-	`
+	memo := create
+	return memo, nil
+}
+
+func (s *Store) ListMemos(ctx context.Context, find *FindMemo) ([]*Memo, error) {
+	with, query, args, err := s.buildListMemosQuery(find)
+	if err != nil {
+		return nil, err
+	}
+	query = with + query
 	if find.Limit != nil {
 		query = fmt.Sprintf("%s LIMIT %d", query, *find.Limit)
 		if find.Offset != nil {
@@ -212,12 +220,17 @@ func (s *Store) ListMemos(ctx context.Context, find *FindMemo) ([]*Memo, error)
 			&memo.RowStatus,
 			&memo.Content,
 			&memo.Visibility,
+			&memo.DeletedTs,
 			&memo.Pinned,
 			&memoResourceIDList,
 			&memoRelationList,
+			&memo.Snippet,
 		); err != nil {
 			return nil, err
 		}
+		if memo.Snippet != "" {
+			memo.Snippet = highlightMemoSnippet(memo.Snippet)
+		}
 
 		if memoResourceIDList.Valid {
 			idStringList := strings.Split(memoResourceIDList.String, ",")
@@ -259,6 +272,167 @@ func (s *Store) ListMemos(ctx context.Context, find *FindMemo) ([]*Memo, error)
 	return list, nil
 }
 
+// CountMemos returns the number of memos matching find, regardless of the pagination.
+func (s *Store) CountMemos(ctx context.Context, find *FindMemo) (int, error) {
+	countFind := *find
+	countFind.Cursor = nil
+	with, query, args, err := s.buildListMemosQuery(&countFind)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if err := s.db.QueryRowContext(ctx, with+`SELECT COUNT(*) FROM (`+query+`) AS memo_list`, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// buildListMemosQuery returns the WITH clause, the query and the args of listing memos, without the limit and offset.
+func (s *Store) buildListMemosQuery(find *FindMemo) (string, string, []any, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	with, withArgs := "", []any{}
+	joins := []string{}
+	snippetField := "'' AS snippet"
+
+	if v := find.ID; v != nil {
+		where, args = append(where, "memo.id = ?"), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "memo.creator_id = ?"), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "memo.row_status = ?"), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, "memo.created_ts > ?"), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, "memo.created_ts < ?"), append(args, *v)
+	}
+	if find.InTrash {
+		where = append(where, "memo.deleted_ts > 0")
+	} else {
+		where = append(where, "memo.deleted_ts = 0")
+	}
+	if v := find.DeletedTsBefore; v != nil {
+		where, args = append(where, "memo.deleted_ts < ?"), append(args, *v)
+	}
+	if v := find.Pinned; v != nil {
+		where = append(where, "memo_organizer.pinned = 1")
+	}
+	for _, tag := range find.TagList {
+		condition, conditionArgs := tagCondition(tag)
+		where, args = append(where, "memo.id IN (SELECT memo_id FROM memo_tag WHERE "+condition+")"), append(args, conditionArgs...)
+	}
+	orderByRank := false
+	if v := find.ContentSearch; len(v) != 0 {
+		matchQuery, shortTerms := buildMemoSearchQuery(v)
+		// The full-text index is only built in SQLite, other drivers search all terms by LIKE.
+		if s.Profile.Driver != "sqlite" {
+			matchQuery, shortTerms = "", v
+		}
+		if matchQuery != "" {
+			// The search is materialized, as FTS5 auxiliary functions can't be evaluated in a flattened join.
+			with = fmt.Sprintf(`
+	WITH memo_search AS MATERIALIZED (
+		SELECT
+			rowid,
+			bm25(memo_fts) AS rank,
+			snippet(memo_fts, 0, ?, ?, '...', %d) AS snippet
+		FROM
+			memo_fts
+		WHERE
+			memo_fts MATCH ?
+	)`, memoSearchSnippetTokens)
+			withArgs = append(withArgs, memoSearchSnippetMatchStart, memoSearchSnippetMatchEnd, matchQuery)
+			joins = append(joins, "INNER JOIN memo_search ON memo.id = memo_search.rowid")
+			snippetField = "memo_search.snippet AS snippet"
+			orderByRank = find.OrderByRank
+		}
+		for _, term := range shortTerms {
+			where, args = append(where, s.like("memo.content")), append(args, containsPattern(term))
+		}
+	}
+	if v := find.Filter; v != nil {
+		condition, conditionArgs, err := s.buildMemoFilterCondition(v)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		where, args = append(where, condition), append(args, conditionArgs...)
+	}
+	if v := find.ViewerID; v != nil {
+		where, args = append(where, "(memo.visibility IN (?, ?) OR memo.creator_id = ?)"), append(args, Public, Protected, *v)
+	}
+	if v := find.VisibilityList; len(v) != 0 {
+		list := []string{}
+		for _, visibility := range v {
+			list = append(list, "?")
+			args = append(args, visibility)
+		}
+		where = append(where, fmt.Sprintf("memo.visibility in (%s)", strings.Join(list, ",")))
+	}
+	having := []string{"1 = 1"}
+	if v := find.Cursor; v != nil && !orderByRank {
+		tsField := "memo.created_ts"
+		if find.OrderByUpdatedTs {
+			tsField = "memo.updated_ts"
+		}
+		pinned := 0
+		if v.Pinned {
+			pinned = 1
+		}
+		pinnedField := "MAX(CASE WHEN memo_organizer.pinned = 1 THEN 1 ELSE 0 END)"
+		having = append(having, fmt.Sprintf("(%s < ? OR (%s = ? AND (%s < ? OR (%s = ? AND memo.id < ?))))", pinnedField, pinnedField, tsField, tsField))
+		args = append(args, pinned, pinned, v.Ts, v.Ts, v.ID)
+	}
+	orders := []string{"pinned DESC"}
+	if orderByRank {
+		orders = []string{"memo_search.rank ASC"}
+	} else if find.OrderByUpdatedTs {
+		orders = append(orders, "updated_ts DESC")
+	} else {
+		orders = append(orders, "created_ts DESC")
+	}
+	orders = append(orders, "id DESC")
+
+	query := `
+	SELECT
+		memo.id AS id,
+		memo.creator_id AS creator_id,
+		memo.created_ts AS created_ts,
+		memo.updated_ts AS updated_ts,
+		memo.row_status AS row_status,
+		memo.content AS content,
+		memo.visibility AS visibility,
+		memo.deleted_ts AS deleted_ts,
+		MAX(CASE WHEN memo_organizer.pinned = 1 THEN 1 ELSE 0 END) AS pinned,
+		` + s.groupConcat("memo_resource.resource_id") + ` AS resource_id_list,
+		(
+				SELECT
+						` + s.groupConcat("related_memo_id || ':' || type") + `
+				FROM
+						memo_relation
+				WHERE
+						memo_relation.memo_id = memo.id
+				GROUP BY
+						memo_relation.memo_id
+		) AS relation_list,
+		` + snippetField + `
+	FROM
+		memo
+	LEFT JOIN
+		memo_organizer ON memo.id = memo_organizer.memo_id
+	LEFT JOIN
+		memo_resource ON memo.id = memo_resource.memo_id
+	` + strings.Join(joins, "\n") + `
+	WHERE ` + strings.Join(where, " AND ") + `
+	GROUP BY memo.id
+	HAVING ` + strings.Join(having, " AND ") + `
+	ORDER BY ` + strings.Join(orders, ", ") + `
+	`
+	return with, query, append(withArgs, args...), nil
+}
+
 func (s *Store) GetMemo(ctx context.Context, find *FindMemo) (*Memo, error) {
 	list, err := s.ListMemos(ctx, find)
 	if err != nil {
@@ -273,6 +447,20 @@ func (s *Store) GetMemo(ctx context.Context, find *FindMemo) (*Memo, error) {
 }
 
 func (s *Store) UpdateMemo(ctx context.Context, update *UpdateMemo) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.updateMemo(ctx, tx, update); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) updateMemo(ctx context.Context, tx *sql.Tx, update *UpdateMemo) error {
 	set, args := []string{}, []any{}
 	if v := update.CreatedTs; v != nil {
 		set, args = append(set, "created_ts = ?"), append(args, *v)
@@ -289,34 +477,80 @@ func (s *Store) UpdateMemo(ctx context.Context, update *UpdateMemo) error {
 	if v := update.Visibility; v != nil {
 		set, args = append(set, "visibility = ?"), append(args, *v)
 	}
+	if v := update.DeletedTs; v != nil {
+		set, args = append(set, "deleted_ts = ?"), append(args, *v)
+	}
 	args = append(args, update.ID)
 
+	// Memos created before revisions are introduced have no revision of their current state yet.
+	if update.Content != nil || update.Visibility != nil {
+		if err := createMemoRevision(ctx, tx, update.ID); err != nil {
+			return err
+		}
+	}
+
 	stmt := `
 		UPDATE memo
 		SET ` + strings.Join(set, ", ") + `
 		WHERE id = ?
 	`
-	if _, err := s.db.ExecContext(ctx, stmt, args...); err != nil {
+	if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
 		return err
 	}
+	if v := update.Content; v != nil {
+		if err := s.upsertMemoFTS(ctx, tx, update.ID, *v); err != nil {
+			return err
+		}
+		if err := upsertMemoTags(ctx, tx, update.ID, *v); err != nil {
+			return err
+		}
+	}
+	if update.Content != nil || update.Visibility != nil {
+		if err := createMemoRevision(ctx, tx, update.ID); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *Store) DeleteMemo(ctx context.Context, delete *DeleteMemo) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	where, args := []string{"id = ?"}, []any{delete.ID}
 	stmt := `DELETE FROM memo WHERE ` + strings.Join(where, " AND ")
-	result, err := s.db.ExecContext(ctx, stmt, args...)
+	result, err := tx.ExecContext(ctx, stmt, args...)
 	if err != nil {
 		return err
 	}
 	if _, err := result.RowsAffected(); err != nil {
 		return err
 	}
-	if err := s.Vacuum(ctx); err != nil {
-		// Prevent linter warning.
+	if err := s.deleteMemoFTS(ctx, tx, delete.ID); err != nil {
 		return err
 	}
-	return nil
+	if err := deleteMemoRevisions(ctx, tx, delete.ID); err != nil {
+		return err
+	}
+	if err := deleteMemoTags(ctx, tx, delete.ID); err != nil {
+		return err
+	}
+	// Only the rows of the memo are deleted instead of vacuuming the whole database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_organizer WHERE memo_id = ?`, delete.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_resource WHERE memo_id = ?`, delete.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_relation WHERE memo_id = ? OR related_memo_id = ?`, delete.ID, delete.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (s *Store) FindMemosVisibilityList(ctx context.Context, memoIDs []int32) ([]Visibility, error) {
@@ -360,7 +594,7 @@ func vacuumMemo(ctx context.Context, tx *sql.Tx) error {
 			SELECT 
 				id 
 			FROM 
-				user
+				"user"
 		)`
 	_, err := tx.ExecContext(ctx, stmt)
 	if err != nil {
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MemoFilter is a boolean expression over the fields of memos, which is translated to a parameterized SQL condition.
+// A logical filter combines its children, while a comparison filter compares a field with its values.
+type MemoFilter struct {
+	Operator MemoFilterOperator
+	Children []*MemoFilter
+	Field    MemoFilterField
+	Values   []any
+}
+
+type MemoFilterOperator string
+
+const (
+	MemoFilterAnd MemoFilterOperator = "AND"
+	MemoFilterOr  MemoFilterOperator = "OR"
+	MemoFilterNot MemoFilterOperator = "NOT"
+
+	MemoFilterEqual          MemoFilterOperator = "="
+	MemoFilterLess           MemoFilterOperator = "<"
+	MemoFilterLessOrEqual    MemoFilterOperator = "<="
+	MemoFilterGreater        MemoFilterOperator = ">"
+	MemoFilterGreaterOrEqual MemoFilterOperator = ">="
+	// MemoFilterIn matches any of the values.
+	MemoFilterIn MemoFilterOperator = "IN"
+	// MemoFilterContains matches the text fields containing the value.
+	MemoFilterContains MemoFilterOperator = "CONTAINS"
+)
+
+type MemoFilterField string
+
+const (
+	// MemoFilterCreator is the username of the creator.
+	MemoFilterCreator MemoFilterField = "creator"
+	// MemoFilterTag matches the descendants of the tag as well.
+	MemoFilterTag         MemoFilterField = "tag"
+	MemoFilterVisibility  MemoFilterField = "visibility"
+	MemoFilterRowStatus   MemoFilterField = "row_status"
+	MemoFilterPinned      MemoFilterField = "pinned"
+	MemoFilterCreatedTs   MemoFilterField = "created_ts"
+	MemoFilterUpdatedTs   MemoFilterField = "updated_ts"
+	MemoFilterContent     MemoFilterField = "content"
+	MemoFilterHasResource MemoFilterField = "has_resource"
+)
+
+// buildMemoFilterCondition returns the SQL condition of the filter on the memo table and its args.
+func (s *Store) buildMemoFilterCondition(filter *MemoFilter) (string, []any, error) {
+	switch filter.Operator {
+	case MemoFilterAnd, MemoFilterOr:
+		if len(filter.Children) == 0 {
+			return "", nil, fmt.Errorf("no operand of %s", filter.Operator)
+		}
+		conditions, args := []string{}, []any{}
+		for _, child := range filter.Children {
+			condition, childArgs, err := s.buildMemoFilterCondition(child)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions, args = append(conditions, condition), append(args, childArgs...)
+		}
+		return "(" + strings.Join(conditions, " "+string(filter.Operator)+" ") + ")", args, nil
+	case MemoFilterNot:
+		if len(filter.Children) != 1 {
+			return "", nil, fmt.Errorf("NOT requires exactly one operand")
+		}
+		condition, args, err := s.buildMemoFilterCondition(filter.Children[0])
+		if err != nil {
+			return "", nil, err
+		}
+		return "(NOT " + condition + ")", args, nil
+	}
+
+	if len(filter.Values) == 0 || (filter.Operator != MemoFilterIn && len(filter.Values) != 1) {
+		return "", nil, fmt.Errorf("invalid number of values of %s", filter.Field)
+	}
+	switch filter.Field {
+	case MemoFilterCreator:
+		if condition, args, ok := buildMemoFilterIn(filter, `username`); ok {
+			return `memo.creator_id IN (SELECT id FROM "user" WHERE ` + condition + `)`, args, nil
+		}
+	case MemoFilterTag:
+		if filter.Operator == MemoFilterEqual || filter.Operator == MemoFilterIn {
+			conditions, args := []string{}, []any{}
+			for _, value := range filter.Values {
+				tag, ok := value.(string)
+				if !ok {
+					return "", nil, fmt.Errorf("invalid value of tag: %v", value)
+				}
+				condition, conditionArgs := tagCondition(tag)
+				conditions, args = append(conditions, condition), append(args, conditionArgs...)
+			}
+			return "memo.id IN (SELECT memo_id FROM memo_tag WHERE " + strings.Join(conditions, " OR ") + ")", args, nil
+		}
+	case MemoFilterVisibility:
+		if condition, args, ok := buildMemoFilterIn(filter, "memo.visibility"); ok {
+			return condition, args, nil
+		}
+	case MemoFilterRowStatus:
+		if condition, args, ok := buildMemoFilterIn(filter, "memo.row_status"); ok {
+			return condition, args, nil
+		}
+	case MemoFilterCreatedTs, MemoFilterUpdatedTs:
+		column := "memo." + string(filter.Field)
+		if condition, args, ok := buildMemoFilterIn(filter, column); ok {
+			return condition, args, nil
+		}
+		switch filter.Operator {
+		case MemoFilterLess, MemoFilterLessOrEqual, MemoFilterGreater, MemoFilterGreaterOrEqual:
+			return fmt.Sprintf("%s %s ?", column, filter.Operator), filter.Values, nil
+		}
+	case MemoFilterContent:
+		if filter.Operator == MemoFilterContains {
+			text, ok := filter.Values[0].(string)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid value of content: %v", filter.Values[0])
+			}
+			return s.like("memo.content"), []any{containsPattern(text)}, nil
+		}
+	case MemoFilterPinned, MemoFilterHasResource:
+		if filter.Operator == MemoFilterEqual {
+			value, ok := filter.Values[0].(bool)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid value of %s: %v", filter.Field, filter.Values[0])
+			}
+			condition := "EXISTS (SELECT 1 FROM memo_organizer WHERE memo_organizer.memo_id = memo.id AND memo_organizer.pinned = 1)"
+			if filter.Field == MemoFilterHasResource {
+				condition = "EXISTS (SELECT 1 FROM memo_resource WHERE memo_resource.memo_id = memo.id)"
+			}
+			if !value {
+				condition = "NOT " + condition
+			}
+			return condition, nil, nil
+		}
+	default:
+		return "", nil, fmt.Errorf("unknown field %q", filter.Field)
+	}
+	return "", nil, fmt.Errorf("operator %s isn't supported on %s", filter.Operator, filter.Field)
+}
+
+// buildMemoFilterIn returns the condition of the column equal to any of the values, if the operator is equal or in.
+func buildMemoFilterIn(filter *MemoFilter, column string) (string, []any, bool) {
+	if filter.Operator != MemoFilterEqual && filter.Operator != MemoFilterIn {
+		return "", nil, false
+	}
+	placeholder := []string{}
+	for range filter.Values {
+		placeholder = append(placeholder, "?")
+	}
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholder, ",")), filter.Values, true
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// The memo_fts table is a FTS5 index of memo content built with the trigram tokenizer,
+// so a match behaves like a case-insensitive substring search in any language.
+// It's only built in SQLite, so the helpers below do nothing with other drivers.
+// Trigrams can't match terms shorter than three characters, those fall back to LIKE.
+const memoFTSMinTermLength = 3
+
+// memoSearchSnippetTokens is the maximum number of tokens (trigrams) in a search snippet.
+const memoSearchSnippetTokens = 64
+
+// The matches are delimited in snippets by noncharacters, which are replaced by <mark> tags once the content is escaped.
+const (
+	memoSearchSnippetMatchStart = "\uFDD0"
+	memoSearchSnippetMatchEnd   = "\uFDD1"
+)
+
+// highlightMemoSnippet returns the snippet as HTML, with the content escaped and the matches in <mark> tags.
+func highlightMemoSnippet(snippet string) string {
+	return strings.NewReplacer(
+		memoSearchSnippetMatchStart, "<mark>",
+		memoSearchSnippetMatchEnd, "</mark>",
+	).Replace(html.EscapeString(snippet))
+}
+
+// buildMemoSearchQuery splits the content search terms into a FTS5 match expression and
+// the terms which are too short to be looked up in the index.
+func buildMemoSearchQuery(contentSearch []string) (string, []string) {
+	phrases, shortTerms := []string{}, []string{}
+	for _, term := range contentSearch {
+		if term == "" {
+			continue
+		}
+		if utf8.RuneCountInString(term) < memoFTSMinTermLength {
+			shortTerms = append(shortTerms, term)
+			continue
+		}
+		// Every term is quoted as a phrase so that FTS5 operators in user input are matched literally.
+		phrases = append(phrases, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+	}
+	return strings.Join(phrases, " AND "), shortTerms
+}
+
+func (s *Store) upsertMemoFTS(ctx context.Context, tx *sql.Tx, memoID int32, content string) error {
+	if s.Profile.Driver != "sqlite" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_fts WHERE rowid = ?`, memoID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO memo_fts (rowid, content) VALUES (?, ?)`, memoID, content); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Store) deleteMemoFTS(ctx context.Context, tx *sql.Tx, memoID int32) error {
+	if s.Profile.Driver != "sqlite" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_fts WHERE rowid = ?`, memoID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Store) vacuumMemoFTS(ctx context.Context, tx *sql.Tx) error {
+	if s.Profile.Driver != "sqlite" {
+		return nil
+	}
+	stmt := `
+	DELETE FROM
+		memo_fts
+	WHERE
+		rowid NOT IN (
+			SELECT
+				id
+			FROM
+				memo
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
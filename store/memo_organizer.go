@@ -31,11 +31,13 @@ func (s *Store) UpsertMemoOrganizer(ctx context.Context, upsert *MemoOrganizer)
 			pinned
 		)
 		VALUES (?, ?, ?)
-		ON CONFLICT(memo_id, user_id) DO UPDATE 
-		SET
-			pinned = EXCLUDED.pinned
-	`
-	if _, err := s.db.ExecContext(ctx, stmt, upsert.MemoID, upsert.UserID, upsert.Pinned); err != nil {
+		` + s.onConflictUpdate([]string{"memo_id", "user_id"}, "pinned")
+	// The pinned column is an integer, which PostgreSQL doesn't convert booleans into.
+	pinned := 0
+	if upsert.Pinned {
+		pinned = 1
+	}
+	if _, err := s.db.ExecContext(ctx, stmt, upsert.MemoID, upsert.UserID, pinned); err != nil {
 		return nil, err
 	}
 
@@ -112,7 +114,7 @@ func vacuumMemoOrganizer(ctx context.Context, tx *sql.Tx) error {
 			SELECT 
 				id 
 			FROM 
-				user
+				"user"
 		)`
 	_, err := tx.ExecContext(ctx, stmt)
 	if err != nil {
@@ -39,18 +39,11 @@ func (s *Store) UpsertMemoRelation(ctx context.Context, create *MemoRelation) (*
 			type
 		)
 		VALUES (?, ?, ?)
-		ON CONFLICT (memo_id, related_memo_id, type) DO UPDATE SET
-			type = EXCLUDED.type
-		RETURNING memo_id, related_memo_id, type
-	`
+		` + s.onConflictUpdate([]string{"memo_id", "related_memo_id", "type"}, "type")
 	memoRelation := &MemoRelation{}
-	if err := s.db.QueryRowContext(
-		ctx,
-		stmt,
-		create.MemoID,
-		create.RelatedMemoID,
-		create.Type,
-	).Scan(
+	args := []any{create.MemoID, create.RelatedMemoID, create.Type}
+	where := "memo_id = ? AND related_memo_id = ? AND type = ?"
+	if err := s.execReturning(ctx, s.db, "memo_relation", stmt, args, where, args, []string{"memo_id", "related_memo_id", "type"},
 		&memoRelation.MemoID,
 		&memoRelation.RelatedMemoID,
 		&memoRelation.Type,
@@ -44,13 +44,10 @@ func (s *Store) UpsertMemoResource(ctx context.Context, upsert *UpsertMemoResour
 			` + strings.Join(set, ", ") + `
 		)
 		VALUES (` + strings.Join(placeholder, ",") + `)
-		ON CONFLICT(memo_id, resource_id) DO UPDATE 
-		SET
-			updated_ts = EXCLUDED.updated_ts
-		RETURNING memo_id, resource_id, created_ts, updated_ts
-	`
+		` + s.onConflictUpdate([]string{"memo_id", "resource_id"}, "updated_ts")
 	memoResource := &MemoResource{}
-	if err := s.db.QueryRowContext(ctx, query, args...).Scan(
+	where, whereArgs := "memo_id = ? AND resource_id = ?", []any{upsert.MemoID, upsert.ResourceID}
+	if err := s.execReturning(ctx, s.db, "memo_resource", query, args, where, whereArgs, []string{"memo_id", "resource_id", "created_ts", "updated_ts"},
 		&memoResource.MemoID,
 		&memoResource.ResourceID,
 		&memoResource.CreatedTs,
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// MemoRevision is a snapshot of memo content and visibility, it's saved on every change of them.
+type MemoRevision struct {
+	ID int32
+
+	// Standard fields
+	CreatedTs int64
+
+	// Domain specific fields
+	MemoID     int32
+	Content    string
+	Visibility Visibility
+}
+
+type FindMemoRevision struct {
+	ID     *int32
+	MemoID *int32
+}
+
+func (s *Store) ListMemoRevisions(ctx context.Context, find *FindMemoRevision) ([]*MemoRevision, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "memo_id = ?"), append(args, *v)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			memo_id,
+			content,
+			visibility
+		FROM memo_revision
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*MemoRevision{}
+	for rows.Next() {
+		memoRevision := &MemoRevision{}
+		if err := rows.Scan(
+			&memoRevision.ID,
+			&memoRevision.CreatedTs,
+			&memoRevision.MemoID,
+			&memoRevision.Content,
+			&memoRevision.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, memoRevision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetMemoRevision(ctx context.Context, find *FindMemoRevision) (*MemoRevision, error) {
+	list, err := s.ListMemoRevisions(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	memoRevision := list[0]
+	return memoRevision, nil
+}
+
+// createMemoRevision saves the current content and visibility of the memo as a new revision,
+// unless they are the same as the latest revision.
+func createMemoRevision(ctx context.Context, tx *sql.Tx, memoID int32) error {
+	stmt := `
+		INSERT INTO memo_revision (
+			memo_id,
+			content,
+			visibility
+		)
+		SELECT
+			memo.id,
+			memo.content,
+			memo.visibility
+		FROM
+			memo
+		LEFT JOIN
+			memo_revision ON memo_revision.id = (SELECT MAX(id) FROM memo_revision WHERE memo_id = memo.id)
+		WHERE
+			memo.id = ?
+			AND (memo_revision.id IS NULL OR memo_revision.content != memo.content OR memo_revision.visibility != memo.visibility)
+	`
+	if _, err := tx.ExecContext(ctx, stmt, memoID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func deleteMemoRevisions(ctx context.Context, tx *sql.Tx, memoID int32) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_revision WHERE memo_id = ?`, memoID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumMemoRevision(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		memo_revision
+	WHERE
+		memo_id NOT IN (
+			SELECT
+				id
+			FROM
+				memo
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,418 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/usememos/memos/plugin/gomark/parser"
+)
+
+// MemoTag is a tag written in the content of a memo.
+// The memo_tag table is an index of memo content maintained on every change of it.
+type MemoTag struct {
+	MemoID int32
+	Name   string
+}
+
+type FindMemoTag struct {
+	MemoID    *int32
+	CreatorID *int32
+	Name      *string
+	// RowStatus is the row status of the memos, memos in trash are never included.
+	RowStatus      *RowStatus
+	VisibilityList []Visibility
+}
+
+// TagCount is the number of memos with the tag.
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+func (s *Store) ListMemoTags(ctx context.Context, find *FindMemoTag) ([]*MemoTag, error) {
+	where, args := buildMemoTagFindWhere(find)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			memo_tag.memo_id,
+			memo_tag.name
+		FROM memo_tag
+		INNER JOIN memo ON memo.id = memo_tag.memo_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY memo_tag.memo_id DESC, memo_tag.name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*MemoTag{}
+	for rows.Next() {
+		memoTag := &MemoTag{}
+		if err := rows.Scan(
+			&memoTag.MemoID,
+			&memoTag.Name,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, memoTag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ListTagCounts returns the tags of the memos matching find with the number of memos of each, ordered by name.
+func (s *Store) ListTagCounts(ctx context.Context, find *FindMemoTag) ([]*TagCount, error) {
+	where, args := buildMemoTagFindWhere(find)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			memo_tag.name,
+			COUNT(*)
+		FROM memo_tag
+		INNER JOIN memo ON memo.id = memo_tag.memo_id
+		WHERE `+strings.Join(where, " AND ")+`
+		GROUP BY memo_tag.name
+		ORDER BY memo_tag.name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*TagCount{}
+	for rows.Next() {
+		tagCount := &TagCount{}
+		if err := rows.Scan(
+			&tagCount.Name,
+			&tagCount.Count,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, tagCount)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func buildMemoTagFindWhere(find *FindMemoTag) ([]string, []any) {
+	where, args := []string{"memo.deleted_ts = 0"}, []any{}
+	if v := find.MemoID; v != nil {
+		where, args = append(where, "memo_tag.memo_id = ?"), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "memo.creator_id = ?"), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, "memo_tag.name = ?"), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "memo.row_status = ?"), append(args, *v)
+	}
+	if v := find.VisibilityList; len(v) != 0 {
+		placeholder := []string{}
+		for _, visibility := range v {
+			placeholder, args = append(placeholder, "?"), append(args, visibility)
+		}
+		where = append(where, fmt.Sprintf("memo.visibility IN (%s)", strings.Join(placeholder, ",")))
+	}
+	return where, args
+}
+
+// tagCondition returns the condition on the name column matching the tag and its descendants.
+// SUBSTR is used instead of LIKE, so that wildcards and case of tags are kept as they are.
+func tagCondition(tag string) (string, []any) {
+	return "(name = ? OR SUBSTR(name, 1, ?) = ?)", []any{tag, utf8.RuneCountInString(tag) + 1, tag + "/"}
+}
+
+// TagNode is a node of the tag tree, e.g. `work/clientA` is a child node of `work`.
+type TagNode struct {
+	// Name is the last segment of the path.
+	Name string
+	// Path is the full name of the tag.
+	Path string
+	// MemoCount is the number of memos with the tag or any of its descendants.
+	MemoCount int
+	Children  []*TagNode
+}
+
+// ListTagTree returns the root nodes of the tree of the tags of the memos matching find, ordered by name.
+func (s *Store) ListTagTree(ctx context.Context, find *FindMemoTag) ([]*TagNode, error) {
+	memoTags, err := s.ListMemoTags(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &TagNode{}
+	nodes := map[string]*TagNode{}
+	memoIDSets := map[string]map[int32]bool{}
+	for _, memoTag := range memoTags {
+		parent := root
+		segments := []string{}
+		for _, segment := range strings.Split(memoTag.Name, "/") {
+			if segment == "" {
+				continue
+			}
+			segments = append(segments, segment)
+			path := strings.Join(segments, "/")
+			node, ok := nodes[path]
+			if !ok {
+				node = &TagNode{
+					Name: segment,
+					Path: path,
+				}
+				nodes[path] = node
+				memoIDSets[path] = map[int32]bool{}
+				parent.Children = append(parent.Children, node)
+			}
+			// A memo with several descendants of a tag is counted once for the tag.
+			if !memoIDSets[path][memoTag.MemoID] {
+				memoIDSets[path][memoTag.MemoID] = true
+				node.MemoCount++
+			}
+			parent = node
+		}
+	}
+	sortTagNodes(root.Children)
+	return root.Children, nil
+}
+
+func sortTagNodes(nodes []*TagNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortTagNodes(node.Children)
+	}
+}
+
+type RenameTag struct {
+	CreatorID int32
+	// NameList is the tags to rename, there are more than one when merging tags into one.
+	NameList []string
+	NewName  string
+	// Activity returns the activity recording the change, which is created in the same transaction.
+	Activity func(memoCount int) (*Activity, error)
+}
+
+// RenameTag renames the tags and moves their descendants under the new name, in the content of all the memos of the creator
+// including the ones in trash, as well as in the tag list of the creator. It returns the number of memos changed.
+func (s *Store) RenameTag(ctx context.Context, rename *RenameTag) (int, error) {
+	if len(rename.NameList) == 0 {
+		return 0, nil
+	}
+	renameFunc := func(name string) string {
+		// The longest of the tags matching the name wins, e.g. `work/clientA` over `work` for `work/clientA/urgent`.
+		matched := ""
+		for _, oldName := range rename.NameList {
+			if (name == oldName || strings.HasPrefix(name, oldName+"/")) && len(oldName) > len(matched) {
+				matched = oldName
+			}
+		}
+		if matched == "" {
+			return name
+		}
+		return rename.NewName + strings.TrimPrefix(name, matched)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	conditions, conditionArgs := []string{}, []any{}
+	for _, name := range rename.NameList {
+		condition, args := tagCondition(name)
+		conditions, conditionArgs = append(conditions, condition), append(conditionArgs, args...)
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			content
+		FROM memo
+		WHERE creator_id = ? AND id IN (SELECT memo_id FROM memo_tag WHERE `+strings.Join(conditions, " OR ")+`)
+		ORDER BY id ASC`,
+		append([]any{rename.CreatorID}, conditionArgs...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	memos := []*Memo{}
+	for rows.Next() {
+		memo := &Memo{}
+		if err := rows.Scan(&memo.ID, &memo.Content); err != nil {
+			return 0, err
+		}
+		memos = append(memos, memo)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	count := 0
+	for _, memo := range memos {
+		newContent := parser.ReplaceTags(memo.Content, renameFunc)
+		if newContent == memo.Content {
+			continue
+		}
+		if err := s.updateMemo(ctx, tx, &UpdateMemo{
+			ID:      memo.ID,
+			Content: &newContent,
+		}); err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	for _, name := range rename.NameList {
+		if err := s.renameTagList(ctx, tx, rename.CreatorID, name, renameFunc); err != nil {
+			return 0, err
+		}
+	}
+	if rename.Activity != nil {
+		activity, err := rename.Activity(count)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := s.createActivity(ctx, tx, activity); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// renameTagList renames the tag and its descendants in the tag list of the creator.
+func (s *Store) renameTagList(ctx context.Context, tx *sql.Tx, creatorID int32, name string, renameFunc func(string) string) error {
+	condition, conditionArgs := tagCondition(name)
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM tag WHERE creator_id = ? AND `+condition, append([]any{creatorID}, conditionArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM tag WHERE name = ? AND creator_id = ?`, name, creatorID); err != nil {
+			return err
+		}
+		stmt := `INSERT INTO tag (name, creator_id) VALUES (?, ?) ` + s.onConflictUpdate([]string{"name", "creator_id"}, "name")
+		if _, err := tx.ExecContext(ctx, stmt, renameFunc(name), creatorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillMemoTags indexes the tags of the memos which may have tags but have none indexed,
+// such as the memos created before the memo_tag table is introduced.
+func (s *Store) BackfillMemoTags(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			content
+		FROM memo
+		WHERE content LIKE '%#%' AND NOT EXISTS (SELECT 1 FROM memo_tag WHERE memo_tag.memo_id = memo.id)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	contents := map[int32]string{}
+	for rows.Next() {
+		var id int32
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return err
+		}
+		contents[id] = content
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for id, content := range contents {
+		if err := upsertMemoTags(ctx, tx, id, content); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// upsertMemoTags replaces the indexed tags of a memo with the ones in its content.
+func upsertMemoTags(ctx context.Context, tx *sql.Tx, memoID int32, content string) error {
+	if err := deleteMemoTags(ctx, tx, memoID); err != nil {
+		return err
+	}
+	for _, name := range parser.FindTagList(content) {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO memo_tag (memo_id, name) VALUES (?, ?)`, memoID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteMemoTags(ctx context.Context, tx *sql.Tx, memoID int32) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memo_tag WHERE memo_id = ?`, memoID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumMemoTag(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		memo_tag
+	WHERE
+		memo_id NOT IN (
+			SELECT
+				id
+			FROM
+				memo
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
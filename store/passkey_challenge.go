@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"strings"
+)
+
+// PasskeyChallenge is a challenge of a passkey registration or sign-in, which is kept until it's answered
+// so that each challenge is only answered once.
+type PasskeyChallenge struct {
+	ID int32
+
+	// Standard fields
+	CreatedTs int64
+
+	// Domain specific fields
+	Challenge string
+	ExpiresTs int64
+}
+
+type DeletePasskeyChallenge struct {
+	Challenge *string
+	// ExpiresTsBefore deletes the challenges expired before the time.
+	ExpiresTsBefore *int64
+}
+
+func (s *Store) CreatePasskeyChallenge(ctx context.Context, create *PasskeyChallenge) (*PasskeyChallenge, error) {
+	stmt := `
+		INSERT INTO passkey_challenge (
+			challenge,
+			expires_ts
+		)
+		VALUES (?, ?)
+	`
+	args := []any{create.Challenge, create.ExpiresTs}
+	if err := s.insertReturning(ctx, s.db, "passkey_challenge", stmt, args, []string{"id", "created_ts"},
+		&create.ID,
+		&create.CreatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	passkeyChallenge := create
+	return passkeyChallenge, nil
+}
+
+// DeletePasskeyChallenge deletes the challenges, returning the number of deleted challenges
+// so that concurrent answers of a challenge are told apart.
+func (s *Store) DeletePasskeyChallenge(ctx context.Context, delete *DeletePasskeyChallenge) (int64, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.Challenge; v != nil {
+		where, args = append(where, "challenge = ?"), append(args, *v)
+	}
+	if v := delete.ExpiresTsBefore; v != nil {
+		where, args = append(where, "expires_ts < ?"), append(args, *v)
+	}
+	if len(args) == 0 {
+		// Prevent deleting all the challenges by accident.
+		return 0, nil
+	}
+
+	stmt := `DELETE FROM passkey_challenge WHERE ` + strings.Join(where, " AND ")
+	result, err := s.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
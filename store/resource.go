@@ -51,7 +51,7 @@ func (s *Store) CreateResource(ctx context.Context, create *Resource) (*Resource
 	stmt := `
 		INSERT INTO resource (
 			filename,
-			blob,
+			"blob",
 			external_link,
 			type,
 			size,
@@ -59,19 +59,9 @@ func (s *Store) CreateResource(ctx context.Context, create *Resource) (*Resource
 			internal_path
 		)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
-		RETURNING id, created_ts, updated_ts
 	`
-	if err := s.db.QueryRowContext(
-		ctx,
-		stmt,
-		create.Filename,
-		create.Blob,
-		create.ExternalLink,
-		create.Type,
-		create.Size,
-		create.CreatorID,
-		create.InternalPath,
-	).Scan(&create.ID, &create.CreatedTs, &create.UpdatedTs); err != nil {
+	args := []any{create.Filename, create.Blob, create.ExternalLink, create.Type, create.Size, create.CreatorID, create.InternalPath}
+	if err := s.insertReturning(ctx, s.db, "resource", stmt, args, []string{"id", "created_ts", "updated_ts"}, &create.ID, &create.CreatedTs, &create.UpdatedTs); err != nil {
 		return nil, err
 	}
 
@@ -97,7 +87,7 @@ func (s *Store) ListResources(ctx context.Context, find *FindResource) ([]*Resou
 
 	fields := []string{"resource.id", "resource.filename", "resource.external_link", "resource.type", "resource.size", "resource.creator_id", "resource.created_ts", "resource.updated_ts", "internal_path"}
 	if find.GetBlob {
-		fields = append(fields, "resource.blob")
+		fields = append(fields, `resource."blob"`)
 	}
 
 	query := fmt.Sprintf(`
@@ -180,7 +170,7 @@ func (s *Store) UpdateResource(ctx context.Context, update *UpdateResource) (*Re
 		set, args = append(set, "internal_path = ?"), append(args, *v)
 	}
 	if v := update.Blob; v != nil {
-		set, args = append(set, "blob = ?"), append(args, v)
+		set, args = append(set, `"blob" = ?`), append(args, v)
 	}
 
 	args = append(args, update.ID)
@@ -189,7 +179,7 @@ func (s *Store) UpdateResource(ctx context.Context, update *UpdateResource) (*Re
 		UPDATE resource
 		SET ` + strings.Join(set, ", ") + `
 		WHERE id = ?
-		RETURNING ` + strings.Join(fields, ", ")
+	`
 	resource := Resource{}
 	dests := []any{
 		&resource.ID,
@@ -202,7 +192,7 @@ func (s *Store) UpdateResource(ctx context.Context, update *UpdateResource) (*Re
 		&resource.UpdatedTs,
 		&resource.InternalPath,
 	}
-	if err := s.db.QueryRowContext(ctx, stmt, args...).Scan(dests...); err != nil {
+	if err := s.execReturning(ctx, s.db, "resource", stmt, args, "id = ?", []any{update.ID}, fields, dests...); err != nil {
 		return nil, err
 	}
 
@@ -237,7 +227,7 @@ func vacuumResource(ctx context.Context, tx *sql.Tx) error {
 			SELECT 
 				id 
 			FROM 
-				user
+				"user"
 		)`
 	_, err := tx.ExecContext(ctx, stmt)
 	if err != nil {
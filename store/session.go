@@ -0,0 +1,248 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// Session is a signed-in session of a user, which is identified by a rotating refresh token.
+// Only the hashes of the refresh token and of the previous one are stored.
+type Session struct {
+	ID int32
+
+	// Standard fields
+	UserID    int32
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	ExpiresTs                int64
+	RefreshTokenHash         string
+	PreviousRefreshTokenHash string
+	UserAgent                string
+	IPAddress                string
+}
+
+type FindSession struct {
+	ID                       *int32
+	UserID                   *int32
+	RefreshTokenHash         *string
+	PreviousRefreshTokenHash *string
+}
+
+type UpdateSession struct {
+	ID               int32
+	UpdatedTs        *int64
+	ExpiresTs        *int64
+	RefreshTokenHash *string
+	UserAgent        *string
+	IPAddress        *string
+}
+
+// RotateSessionRefreshToken is the rotation of the refresh token of a session, which only happens
+// if the refresh token is still the presented one.
+type RotateSessionRefreshToken struct {
+	ID                  int32
+	RefreshTokenHash    string
+	NewRefreshTokenHash string
+	UpdatedTs           int64
+	ExpiresTs           int64
+	UserAgent           string
+	IPAddress           string
+}
+
+type DeleteSession struct {
+	ID     *int32
+	UserID *int32
+}
+
+func (s *Store) CreateSession(ctx context.Context, create *Session) (*Session, error) {
+	stmt := `
+		INSERT INTO session (
+			user_id,
+			expires_ts,
+			refresh_token_hash,
+			user_agent,
+			ip_address
+		)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	args := []any{create.UserID, create.ExpiresTs, create.RefreshTokenHash, create.UserAgent, create.IPAddress}
+	if err := s.insertReturning(ctx, s.db, "session", stmt, args, []string{"id", "created_ts", "updated_ts"},
+		&create.ID,
+		&create.CreatedTs,
+		&create.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	session := create
+	return session, nil
+}
+
+func (s *Store) ListSessions(ctx context.Context, find *FindSession) ([]*Session, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if v := find.RefreshTokenHash; v != nil {
+		where, args = append(where, "refresh_token_hash = ?"), append(args, *v)
+	}
+	if v := find.PreviousRefreshTokenHash; v != nil {
+		where, args = append(where, "previous_refresh_token_hash = ?"), append(args, *v)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			user_id,
+			created_ts,
+			updated_ts,
+			expires_ts,
+			refresh_token_hash,
+			previous_refresh_token_hash,
+			user_agent,
+			ip_address
+		FROM session
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY updated_ts DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*Session{}
+	for rows.Next() {
+		session := &Session{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.CreatedTs,
+			&session.UpdatedTs,
+			&session.ExpiresTs,
+			&session.RefreshTokenHash,
+			&session.PreviousRefreshTokenHash,
+			&session.UserAgent,
+			&session.IPAddress,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetSession(ctx context.Context, find *FindSession) (*Session, error) {
+	list, err := s.ListSessions(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	session := list[0]
+	return session, nil
+}
+
+func (s *Store) UpdateSession(ctx context.Context, update *UpdateSession) error {
+	set, args := []string{}, []any{}
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = ?"), append(args, *v)
+	}
+	if v := update.ExpiresTs; v != nil {
+		set, args = append(set, "expires_ts = ?"), append(args, *v)
+	}
+	if v := update.RefreshTokenHash; v != nil {
+		set, args = append(set, "refresh_token_hash = ?"), append(args, *v)
+	}
+	if v := update.UserAgent; v != nil {
+		set, args = append(set, "user_agent = ?"), append(args, *v)
+	}
+	if v := update.IPAddress; v != nil {
+		set, args = append(set, "ip_address = ?"), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	args = append(args, update.ID)
+
+	stmt := `UPDATE session SET ` + strings.Join(set, ", ") + ` WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RotateSessionRefreshToken replaces the refresh token of the session, keeping the hash of the previous one,
+// returning false if the refresh token has already been rotated by a concurrent request.
+func (s *Store) RotateSessionRefreshToken(ctx context.Context, rotate *RotateSessionRefreshToken) (bool, error) {
+	stmt := `
+		UPDATE session
+		SET refresh_token_hash = ?, previous_refresh_token_hash = ?, updated_ts = ?, expires_ts = ?, user_agent = ?, ip_address = ?
+		WHERE id = ? AND refresh_token_hash = ?
+	`
+	result, err := s.db.ExecContext(ctx, stmt, rotate.NewRefreshTokenHash, rotate.RefreshTokenHash, rotate.UpdatedTs, rotate.ExpiresTs, rotate.UserAgent, rotate.IPAddress, rotate.ID, rotate.RefreshTokenHash)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *Store) DeleteSession(ctx context.Context, delete *DeleteSession) error {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := delete.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if len(args) == 0 {
+		// Prevent deleting all the sessions by accident.
+		return nil
+	}
+
+	stmt := `DELETE FROM session WHERE ` + strings.Join(where, " AND ")
+	result, err := s.db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	if _, err := result.RowsAffected(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumSession(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		session
+	WHERE
+		user_id NOT IN (
+			SELECT
+				id
+			FROM
+				"user"
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
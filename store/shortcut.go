@@ -0,0 +1,301 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+)
+
+// ShortcutPayload is the memo filter saved in a shortcut.
+type ShortcutPayload struct {
+	Tags            []string     `json:"tags,omitempty"`
+	VisibilityList  []Visibility `json:"visibilityList,omitempty"`
+	CreatedTsAfter  *int64       `json:"createdTsAfter,omitempty"`
+	CreatedTsBefore *int64       `json:"createdTsBefore,omitempty"`
+	Text            string       `json:"text,omitempty"`
+	Pinned          bool         `json:"pinned,omitempty"`
+}
+
+type Shortcut struct {
+	ID int32
+
+	// Standard fields
+	RowStatus RowStatus
+	CreatorID int32
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	Title   string
+	Payload *ShortcutPayload
+}
+
+type FindShortcut struct {
+	ID *int32
+
+	// Standard fields
+	RowStatus *RowStatus
+	CreatorID *int32
+}
+
+type UpdateShortcut struct {
+	ID        int32
+	UpdatedTs *int64
+	RowStatus *RowStatus
+	Title     *string
+	Payload   *ShortcutPayload
+}
+
+type DeleteShortcut struct {
+	ID int32
+}
+
+// FindMemo returns the memo find of the shortcut filter on the normal memos of creatorID.
+func (p *ShortcutPayload) FindMemo(creatorID int32) *FindMemo {
+	rowStatus := Normal
+	find := &FindMemo{
+		RowStatus:       &rowStatus,
+		CreatorID:       &creatorID,
+		VisibilityList:  p.VisibilityList,
+		CreatedTsAfter:  p.CreatedTsAfter,
+		CreatedTsBefore: p.CreatedTsBefore,
+	}
+	find.TagList = p.Tags
+	if p.Text != "" {
+		find.ContentSearch = append(find.ContentSearch, p.Text)
+	}
+	if p.Pinned {
+		find.Pinned = &p.Pinned
+	}
+	return find
+}
+
+func (s *Store) CreateShortcut(ctx context.Context, create *Shortcut) (*Shortcut, error) {
+	payload, err := marshalShortcutPayload(create.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `
+		INSERT INTO shortcut (
+			creator_id,
+			title,
+			payload
+		)
+		VALUES (?, ?, ?)
+	`
+	args := []any{create.CreatorID, create.Title, payload}
+	if err := s.insertReturning(ctx, s.db, "shortcut", stmt, args, []string{"id", "created_ts", "updated_ts", "row_status"},
+		&create.ID,
+		&create.CreatedTs,
+		&create.UpdatedTs,
+		&create.RowStatus,
+	); err != nil {
+		return nil, err
+	}
+
+	shortcut := create
+	return shortcut, nil
+}
+
+func (s *Store) ListShortcuts(ctx context.Context, find *FindShortcut) ([]*Shortcut, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, "creator_id = ?"), append(args, *v)
+	}
+	if v := find.RowStatus; v != nil {
+		where, args = append(where, "row_status = ?"), append(args, *v)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updated_ts,
+			row_status,
+			title,
+			payload
+		FROM shortcut
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*Shortcut{}
+	for rows.Next() {
+		shortcut := &Shortcut{}
+		var payload string
+		if err := rows.Scan(
+			&shortcut.ID,
+			&shortcut.CreatorID,
+			&shortcut.CreatedTs,
+			&shortcut.UpdatedTs,
+			&shortcut.RowStatus,
+			&shortcut.Title,
+			&payload,
+		); err != nil {
+			return nil, err
+		}
+		shortcutPayload, err := unmarshalShortcutPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		shortcut.Payload = shortcutPayload
+		list = append(list, shortcut)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetShortcut(ctx context.Context, find *FindShortcut) (*Shortcut, error) {
+	list, err := s.ListShortcuts(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	shortcut := list[0]
+	return shortcut, nil
+}
+
+func (s *Store) UpdateShortcut(ctx context.Context, update *UpdateShortcut) (*Shortcut, error) {
+	set, args := []string{}, []any{}
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = ?"), append(args, *v)
+	}
+	if v := update.RowStatus; v != nil {
+		set, args = append(set, "row_status = ?"), append(args, *v)
+	}
+	if v := update.Title; v != nil {
+		set, args = append(set, "title = ?"), append(args, *v)
+	}
+	if v := update.Payload; v != nil {
+		payload, err := marshalShortcutPayload(v)
+		if err != nil {
+			return nil, err
+		}
+		set, args = append(set, "payload = ?"), append(args, payload)
+	}
+	args = append(args, update.ID)
+
+	stmt := `
+		UPDATE shortcut
+		SET ` + strings.Join(set, ", ") + `
+		WHERE id = ?
+	`
+	shortcut := &Shortcut{}
+	var payload string
+	if err := s.execReturning(ctx, s.db, "shortcut", stmt, args, "id = ?", []any{update.ID}, []string{"id", "creator_id", "created_ts", "updated_ts", "row_status", "title", "payload"},
+		&shortcut.ID,
+		&shortcut.CreatorID,
+		&shortcut.CreatedTs,
+		&shortcut.UpdatedTs,
+		&shortcut.RowStatus,
+		&shortcut.Title,
+		&payload,
+	); err != nil {
+		return nil, err
+	}
+	shortcutPayload, err := unmarshalShortcutPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	shortcut.Payload = shortcutPayload
+
+	return shortcut, nil
+}
+
+func (s *Store) DeleteShortcut(ctx context.Context, delete *DeleteShortcut) error {
+	stmt := `DELETE FROM shortcut WHERE id = ?`
+	result, err := s.db.ExecContext(ctx, stmt, delete.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := result.RowsAffected(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func marshalShortcutPayload(payload *ShortcutPayload) (string, error) {
+	if payload == nil {
+		payload = &ShortcutPayload{}
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// legacyShortcutFilter is a filter item of the shortcut payload saved by memos before v0.10.
+// The payload was a list of them, e.g. `[{"type":"TAG","value":{"operator":"CONTAIN","value":"work"},"relation":"AND"}]`.
+type legacyShortcutFilter struct {
+	Type  string `json:"type"`
+	Value struct {
+		Operator string `json:"operator"`
+		Value    string `json:"value"`
+	} `json:"value"`
+}
+
+func unmarshalShortcutPayload(raw string) (*ShortcutPayload, error) {
+	payload := &ShortcutPayload{}
+	if !strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		if err := json.Unmarshal([]byte(raw), payload); err != nil {
+			return nil, err
+		}
+		return payload, nil
+	}
+
+	// Convert the conditions of legacy filters which can be expressed by the current payload,
+	// so that the shortcuts saved by old versions keep working.
+	legacyFilters := []*legacyShortcutFilter{}
+	if err := json.Unmarshal([]byte(raw), &legacyFilters); err != nil {
+		return nil, err
+	}
+	for _, filter := range legacyFilters {
+		switch {
+		case filter.Type == "TAG" && filter.Value.Operator == "CONTAIN":
+			payload.Tags = append(payload.Tags, filter.Value.Value)
+		case filter.Type == "TEXT" && filter.Value.Operator == "CONTAIN":
+			payload.Text = filter.Value.Value
+		case filter.Type == "VISIBILITY" && filter.Value.Operator == "IS":
+			payload.VisibilityList = append(payload.VisibilityList, Visibility(filter.Value.Value))
+		}
+	}
+	return payload, nil
+}
+
+func vacuumShortcut(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		shortcut
+	WHERE
+		creator_id NOT IN (
+			SELECT
+				id
+			FROM
+				"user"
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
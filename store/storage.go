@@ -34,11 +34,9 @@ func (s *Store) CreateStorage(ctx context.Context, create *Storage) (*Storage, e
 			config
 		)
 		VALUES (?, ?, ?)
-		RETURNING id
 	`
-	if err := s.db.QueryRowContext(ctx, stmt, create.Name, create.Type, create.Config).Scan(
-		&create.ID,
-	); err != nil {
+	args := []any{create.Name, create.Type, create.Config}
+	if err := s.insertReturning(ctx, s.db, "storage", stmt, args, []string{"id"}, &create.ID); err != nil {
 		return nil, err
 	}
 
@@ -117,14 +115,9 @@ func (s *Store) UpdateStorage(ctx context.Context, update *UpdateStorage) (*Stor
 		UPDATE storage
 		SET ` + strings.Join(set, ", ") + `
 		WHERE id = ?
-		RETURNING
-			id,
-			name,
-			type,
-			config
 	`
 	storage := &Storage{}
-	if err := s.db.QueryRowContext(ctx, stmt, args...).Scan(
+	if err := s.execReturning(ctx, s.db, "storage", stmt, args, "id = ?", []any{update.ID}, []string{"id", "name", "type", "config"},
 		&storage.ID,
 		&storage.Name,
 		&storage.Type,
@@ -85,17 +85,28 @@ func (s *Store) Vacuum(ctx context.Context) error {
 	}
 
 	// Vacuum sqlite database file size after deleting resource.
-	if _, err := s.db.Exec("VACUUM"); err != nil {
-		return err
+	if s.Profile.Driver == "sqlite" {
+		if _, err := s.db.Exec("VACUUM"); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (*Store) vacuumImpl(ctx context.Context, tx *sql.Tx) error {
+func (s *Store) vacuumImpl(ctx context.Context, tx *sql.Tx) error {
 	if err := vacuumMemo(ctx, tx); err != nil {
 		return err
 	}
+	if err := s.vacuumMemoFTS(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumMemoRevision(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumMemoTag(ctx, tx); err != nil {
+		return err
+	}
 	if err := vacuumResource(ctx, tx); err != nil {
 		return err
 	}
@@ -111,6 +122,24 @@ func (*Store) vacuumImpl(ctx context.Context, tx *sql.Tx) error {
 	if err := vacuumMemoRelations(ctx, tx); err != nil {
 		return err
 	}
+	if err := vacuumShortcut(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumAccessToken(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumSession(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumUserTOTP(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumUserCredential(ctx, tx); err != nil {
+		return err
+	}
+	if err := vacuumMailToken(ctx, tx); err != nil {
+		return err
+	}
 	if err := vacuumTag(ctx, tx); err != nil {
 		// Prevent revive warning.
 		return err
@@ -21,16 +21,13 @@ func (s *Store) UpsertSystemSetting(ctx context.Context, upsert *SystemSetting)
 			name, value, description
 		)
 		VALUES (?, ?, ?)
-		ON CONFLICT(name) DO UPDATE 
-		SET
-			value = EXCLUDED.value,
-			description = EXCLUDED.description
-	`
+		` + s.onConflictUpdate([]string{"name"}, "value", "description")
 	if _, err := s.db.ExecContext(ctx, stmt, upsert.Name, upsert.Value, upsert.Description); err != nil {
 		return nil, err
 	}
 
 	systemSetting := upsert
+	s.systemSettingCache.Store(systemSetting.Name, systemSetting)
 	return systemSetting, nil
 }
 
@@ -26,10 +26,7 @@ func (s *Store) UpsertTag(ctx context.Context, upsert *Tag) (*Tag, error) {
 			name, creator_id
 		)
 		VALUES (?, ?)
-		ON CONFLICT(name, creator_id) DO UPDATE 
-		SET
-			name = EXCLUDED.name
-	`
+		` + s.onConflictUpdate([]string{"name", "creator_id"}, "name")
 	if _, err := s.db.ExecContext(ctx, stmt, upsert.Name, upsert.CreatorID); err != nil {
 		return nil, err
 	}
@@ -96,7 +93,7 @@ func vacuumTag(ctx context.Context, tx *sql.Tx) error {
 			SELECT 
 				id 
 			FROM 
-				user
+				"user"
 		)`
 	_, err := tx.ExecContext(ctx, stmt)
 	if err != nil {
@@ -45,21 +45,29 @@ type User struct {
 	PasswordHash string
 	OpenID       string
 	AvatarURL    string
+	// VerifiedEmail is the email proven to be owned by the user, which is verified as long as it's the email.
+	VerifiedEmail string
+	// IdentityProviderID and IdentityProviderIdentifier link the user to the identity provider it has been
+	// provisioned by, which it signs in with instead of its local password.
+	IdentityProviderID         int32
+	IdentityProviderIdentifier string
 }
 
 type UpdateUser struct {
 	ID int32
 
-	UpdatedTs    *int64
-	RowStatus    *RowStatus
-	Username     *string `json:"username"`
-	Email        *string `json:"email"`
-	Nickname     *string `json:"nickname"`
-	Password     *string `json:"password"`
-	ResetOpenID  *bool   `json:"resetOpenId"`
-	AvatarURL    *string `json:"avatarUrl"`
-	PasswordHash *string
-	OpenID       *string
+	UpdatedTs     *int64
+	RowStatus     *RowStatus
+	Role          *Role
+	Username      *string `json:"username"`
+	Email         *string `json:"email"`
+	Nickname      *string `json:"nickname"`
+	Password      *string `json:"password"`
+	ResetOpenID   *bool   `json:"resetOpenId"`
+	AvatarURL     *string `json:"avatarUrl"`
+	PasswordHash  *string
+	OpenID        *string
+	VerifiedEmail *string
 }
 
 type FindUser struct {
@@ -70,6 +78,9 @@ type FindUser struct {
 	Email     *string
 	Nickname  *string
 	OpenID    *string
+
+	IdentityProviderID         *int32
+	IdentityProviderIdentifier *string
 }
 
 type DeleteUser struct {
@@ -78,27 +89,21 @@ type DeleteUser struct {
 
 func (s *Store) CreateUser(ctx context.Context, create *User) (*User, error) {
 	stmt := `
-		INSERT INTO user (
+		INSERT INTO "user" (
 			username,
 			role,
 			email,
 			nickname,
 			password_hash,
-			open_id
+			open_id,
+			verified_email,
+			idp_id,
+			idp_identifier
 		)
-		VALUES (?, ?, ?, ?, ?, ?)
-		RETURNING id, avatar_url, created_ts, updated_ts, row_status
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	if err := s.db.QueryRowContext(
-		ctx,
-		stmt,
-		create.Username,
-		create.Role,
-		create.Email,
-		create.Nickname,
-		create.PasswordHash,
-		create.OpenID,
-	).Scan(
+	args := []any{create.Username, create.Role, create.Email, create.Nickname, create.PasswordHash, create.OpenID, create.VerifiedEmail, create.IdentityProviderID, create.IdentityProviderIdentifier}
+	if err := s.insertReturning(ctx, s.db, `"user"`, stmt, args, []string{"id", "avatar_url", "created_ts", "updated_ts", "row_status"},
 		&create.ID,
 		&create.AvatarURL,
 		&create.CreatedTs,
@@ -121,6 +126,9 @@ func (s *Store) UpdateUser(ctx context.Context, update *UpdateUser) (*User, erro
 	if v := update.RowStatus; v != nil {
 		set, args = append(set, "row_status = ?"), append(args, *v)
 	}
+	if v := update.Role; v != nil {
+		set, args = append(set, "role = ?"), append(args, *v)
+	}
 	if v := update.Username; v != nil {
 		set, args = append(set, "username = ?"), append(args, *v)
 	}
@@ -139,16 +147,19 @@ func (s *Store) UpdateUser(ctx context.Context, update *UpdateUser) (*User, erro
 	if v := update.OpenID; v != nil {
 		set, args = append(set, "open_id = ?"), append(args, *v)
 	}
+	if v := update.VerifiedEmail; v != nil {
+		set, args = append(set, "verified_email = ?"), append(args, *v)
+	}
 	args = append(args, update.ID)
 
 	query := `
-		UPDATE user
+		UPDATE "user"
 		SET ` + strings.Join(set, ", ") + `
 		WHERE id = ?
-		RETURNING id, username, role, email, nickname, password_hash, open_id, avatar_url, created_ts, updated_ts, row_status
 	`
 	user := &User{}
-	if err := s.db.QueryRowContext(ctx, query, args...).Scan(
+	columns := []string{"id", "username", "role", "email", "nickname", "password_hash", "open_id", "avatar_url", "verified_email", "idp_id", "idp_identifier", "created_ts", "updated_ts", "row_status"}
+	if err := s.execReturning(ctx, s.db, `"user"`, query, args, "id = ?", []any{update.ID}, columns,
 		&user.ID,
 		&user.Username,
 		&user.Role,
@@ -157,6 +168,9 @@ func (s *Store) UpdateUser(ctx context.Context, update *UpdateUser) (*User, erro
 		&user.PasswordHash,
 		&user.OpenID,
 		&user.AvatarURL,
+		&user.VerifiedEmail,
+		&user.IdentityProviderID,
+		&user.IdentityProviderIdentifier,
 		&user.CreatedTs,
 		&user.UpdatedTs,
 		&user.RowStatus,
@@ -189,6 +203,12 @@ func (s *Store) ListUsers(ctx context.Context, find *FindUser) ([]*User, error)
 	if v := find.OpenID; v != nil {
 		where, args = append(where, "open_id = ?"), append(args, *v)
 	}
+	if v := find.IdentityProviderID; v != nil {
+		where, args = append(where, "idp_id = ?"), append(args, *v)
+	}
+	if v := find.IdentityProviderIdentifier; v != nil {
+		where, args = append(where, "idp_identifier = ?"), append(args, *v)
+	}
 
 	query := `
 		SELECT 
@@ -200,10 +220,13 @@ func (s *Store) ListUsers(ctx context.Context, find *FindUser) ([]*User, error)
 			password_hash,
 			open_id,
 			avatar_url,
+			verified_email,
+			idp_id,
+			idp_identifier,
 			created_ts,
 			updated_ts,
 			row_status
-		FROM user
+		FROM "user"
 		WHERE ` + strings.Join(where, " AND ") + `
 		ORDER BY created_ts DESC, row_status DESC
 	`
@@ -225,6 +248,9 @@ func (s *Store) ListUsers(ctx context.Context, find *FindUser) ([]*User, error)
 			&user.PasswordHash,
 			&user.OpenID,
 			&user.AvatarURL,
+			&user.VerifiedEmail,
+			&user.IdentityProviderID,
+			&user.IdentityProviderIdentifier,
 			&user.CreatedTs,
 			&user.UpdatedTs,
 			&user.RowStatus,
@@ -266,7 +292,7 @@ func (s *Store) GetUser(ctx context.Context, find *FindUser) (*User, error) {
 
 func (s *Store) DeleteUser(ctx context.Context, delete *DeleteUser) error {
 	result, err := s.db.ExecContext(ctx, `
-		DELETE FROM user WHERE id = ?
+		DELETE FROM "user" WHERE id = ?
 	`, delete.ID)
 	if err != nil {
 		return err
@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// UserCredential is a WebAuthn credential (passkey) of a user.
+// The credential ID and the COSE public key are stored base64url encoded.
+type UserCredential struct {
+	ID int32
+
+	// Standard fields
+	UserID    int32
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	Name         string
+	CredentialID string
+	PublicKey    string
+	// SignCount is the signature counter of the authenticator, which detects cloned authenticators.
+	SignCount  int64
+	LastUsedTs int64
+}
+
+type FindUserCredential struct {
+	ID           *int32
+	UserID       *int32
+	CredentialID *string
+}
+
+type UpdateUserCredential struct {
+	ID         int32
+	UpdatedTs  *int64
+	Name       *string
+	SignCount  *int64
+	LastUsedTs *int64
+}
+
+// UpdateUserCredentialSignCount is the use of a credential, whose sign count is only updated
+// if it's still the stored one the assertion has been verified against.
+type UpdateUserCredentialSignCount struct {
+	ID              int32
+	StoredSignCount int64
+	SignCount       int64
+	LastUsedTs      int64
+}
+
+type DeleteUserCredential struct {
+	ID     *int32
+	UserID *int32
+}
+
+func (s *Store) CreateUserCredential(ctx context.Context, create *UserCredential) (*UserCredential, error) {
+	stmt := `
+		INSERT INTO user_credential (
+			user_id,
+			name,
+			credential_id,
+			public_key,
+			sign_count
+		)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	args := []any{create.UserID, create.Name, create.CredentialID, create.PublicKey, create.SignCount}
+	if err := s.insertReturning(ctx, s.db, "user_credential", stmt, args, []string{"id", "created_ts", "updated_ts"},
+		&create.ID,
+		&create.CreatedTs,
+		&create.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+
+	userCredential := create
+	return userCredential, nil
+}
+
+func (s *Store) ListUserCredentials(ctx context.Context, find *FindUserCredential) ([]*UserCredential, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if v := find.CredentialID; v != nil {
+		where, args = append(where, "credential_id = ?"), append(args, *v)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			user_id,
+			created_ts,
+			updated_ts,
+			name,
+			credential_id,
+			public_key,
+			sign_count,
+			last_used_ts
+		FROM user_credential
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY created_ts DESC, id DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []*UserCredential{}
+	for rows.Next() {
+		userCredential := &UserCredential{}
+		if err := rows.Scan(
+			&userCredential.ID,
+			&userCredential.UserID,
+			&userCredential.CreatedTs,
+			&userCredential.UpdatedTs,
+			&userCredential.Name,
+			&userCredential.CredentialID,
+			&userCredential.PublicKey,
+			&userCredential.SignCount,
+			&userCredential.LastUsedTs,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, userCredential)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetUserCredential(ctx context.Context, find *FindUserCredential) (*UserCredential, error) {
+	list, err := s.ListUserCredentials(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	userCredential := list[0]
+	return userCredential, nil
+}
+
+func (s *Store) UpdateUserCredential(ctx context.Context, update *UpdateUserCredential) error {
+	set, args := []string{}, []any{}
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = ?"), append(args, *v)
+	}
+	if v := update.Name; v != nil {
+		set, args = append(set, "name = ?"), append(args, *v)
+	}
+	if v := update.SignCount; v != nil {
+		set, args = append(set, "sign_count = ?"), append(args, *v)
+	}
+	if v := update.LastUsedTs; v != nil {
+		set, args = append(set, "last_used_ts = ?"), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	args = append(args, update.ID)
+
+	stmt := `UPDATE user_credential SET ` + strings.Join(set, ", ") + ` WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateUserCredentialSignCount updates the sign count of the credential,
+// returning false if the stored sign count has changed since the assertion was verified.
+func (s *Store) UpdateUserCredentialSignCount(ctx context.Context, update *UpdateUserCredentialSignCount) (bool, error) {
+	stmt := `UPDATE user_credential SET sign_count = ?, updated_ts = ?, last_used_ts = ? WHERE id = ? AND sign_count = ?`
+	result, err := s.db.ExecContext(ctx, stmt, update.SignCount, update.LastUsedTs, update.LastUsedTs, update.ID, update.StoredSignCount)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *Store) DeleteUserCredential(ctx context.Context, delete *DeleteUserCredential) error {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := delete.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := delete.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+	if len(args) == 0 {
+		// Prevent deleting all the credentials by accident.
+		return nil
+	}
+
+	stmt := `DELETE FROM user_credential WHERE ` + strings.Join(where, " AND ")
+	if _, err := s.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumUserCredential(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		user_credential
+	WHERE
+		user_id NOT IN (
+			SELECT
+				id
+			FROM
+				"user"
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
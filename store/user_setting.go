@@ -20,12 +20,10 @@ type FindUserSetting struct {
 func (s *Store) UpsertUserSetting(ctx context.Context, upsert *UserSetting) (*UserSetting, error) {
 	stmt := `
 		INSERT INTO user_setting (
-			user_id, key, value
+			user_id, "key", value
 		)
 		VALUES (?, ?, ?)
-		ON CONFLICT(user_id, key) DO UPDATE 
-		SET value = EXCLUDED.value
-	`
+		` + s.onConflictUpdate([]string{"user_id", `"key"`}, "value")
 	if _, err := s.db.ExecContext(ctx, stmt, upsert.UserID, upsert.Key, upsert.Value); err != nil {
 		return nil, err
 	}
@@ -39,7 +37,7 @@ func (s *Store) ListUserSettings(ctx context.Context, find *FindUserSetting) ([]
 	where, args := []string{"1 = 1"}, []any{}
 
 	if v := find.Key; v != "" {
-		where, args = append(where, "key = ?"), append(args, v)
+		where, args = append(where, `"key" = ?`), append(args, v)
 	}
 	if v := find.UserID; v != nil {
 		where, args = append(where, "user_id = ?"), append(args, *find.UserID)
@@ -48,7 +46,7 @@ func (s *Store) ListUserSettings(ctx context.Context, find *FindUserSetting) ([]
 	query := `
 		SELECT
 			user_id,
-			key,
+			"key",
 			value
 		FROM user_setting
 		WHERE ` + strings.Join(where, " AND ")
@@ -111,7 +109,7 @@ func vacuumUserSetting(ctx context.Context, tx *sql.Tx) error {
 			SELECT 
 				id 
 			FROM 
-				user
+				"user"
 		)`
 	_, err := tx.ExecContext(ctx, stmt)
 	if err != nil {
@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// UserTOTP is the TOTP two-factor authentication of a user, which is enabled once the user confirms a code of the secret.
+// Only the hashes of the recovery codes are stored.
+type UserTOTP struct {
+	UserID int32
+
+	// Standard fields
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+	// LastUsedStep is the time step of the last accepted code, so that a code can't be used twice.
+	LastUsedStep int64
+	// FailedAttempts is the number of attempts since the last accepted code, which limits the guesses of codes.
+	FailedAttempts  int32
+	LastAttemptedTs int64
+}
+
+type FindUserTOTP struct {
+	UserID *int32
+}
+
+type UpdateUserTOTP struct {
+	UserID        int32
+	UpdatedTs     *int64
+	Enabled       *bool
+	RecoveryCodes *[]string
+	LastUsedStep  *int64
+}
+
+type DeleteUserTOTP struct {
+	UserID int32
+}
+
+// AttemptUserTOTP is an attempt to verify a code, which is refused once the user has reached
+// the maximum of failed attempts, until no attempt has been made since the reset time.
+type AttemptUserTOTP struct {
+	UserID            int32
+	AttemptedTs       int64
+	MaxFailedAttempts int32
+	ResetTs           int64
+}
+
+// UpsertUserTOTP sets a new secret for the user, which resets the TOTP to be confirmed again.
+func (s *Store) UpsertUserTOTP(ctx context.Context, upsert *UserTOTP) (*UserTOTP, error) {
+	stmt := `
+		INSERT INTO user_totp (
+			user_id,
+			secret,
+			enabled,
+			recovery_codes,
+			last_used_step
+		)
+		VALUES (?, ?, ?, ?, ?)
+		` + s.onConflictUpdate([]string{"user_id"}, "secret", "enabled", "recovery_codes", "last_used_step")
+	// The enabled column is an integer, which PostgreSQL doesn't convert booleans into.
+	enabled := 0
+	if upsert.Enabled {
+		enabled = 1
+	}
+	if _, err := s.db.ExecContext(ctx, stmt, upsert.UserID, upsert.Secret, enabled, strings.Join(upsert.RecoveryCodes, ","), upsert.LastUsedStep); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserTOTP(ctx, &FindUserTOTP{
+		UserID: &upsert.UserID,
+	})
+}
+
+func (s *Store) GetUserTOTP(ctx context.Context, find *FindUserTOTP) (*UserTOTP, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if v := find.UserID; v != nil {
+		where, args = append(where, "user_id = ?"), append(args, *v)
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		SELECT
+			user_id,
+			created_ts,
+			updated_ts,
+			secret,
+			enabled,
+			recovery_codes,
+			last_used_step,
+			failed_attempts,
+			last_attempted_ts
+		FROM user_totp
+		WHERE `+strings.Join(where, " AND "),
+		args...,
+	)
+	userTOTP := &UserTOTP{}
+	var recoveryCodes string
+	if err := row.Scan(
+		&userTOTP.UserID,
+		&userTOTP.CreatedTs,
+		&userTOTP.UpdatedTs,
+		&userTOTP.Secret,
+		&userTOTP.Enabled,
+		&recoveryCodes,
+		&userTOTP.LastUsedStep,
+		&userTOTP.FailedAttempts,
+		&userTOTP.LastAttemptedTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	userTOTP.RecoveryCodes = []string{}
+	if recoveryCodes != "" {
+		userTOTP.RecoveryCodes = strings.Split(recoveryCodes, ",")
+	}
+
+	return userTOTP, nil
+}
+
+func (s *Store) UpdateUserTOTP(ctx context.Context, update *UpdateUserTOTP) error {
+	set, args := []string{}, []any{}
+	if v := update.UpdatedTs; v != nil {
+		set, args = append(set, "updated_ts = ?"), append(args, *v)
+	}
+	if v := update.Enabled; v != nil {
+		enabled := 0
+		if *v {
+			enabled = 1
+		}
+		set, args = append(set, "enabled = ?"), append(args, enabled)
+	}
+	if v := update.RecoveryCodes; v != nil {
+		set, args = append(set, "recovery_codes = ?"), append(args, strings.Join(*v, ","))
+	}
+	if v := update.LastUsedStep; v != nil {
+		set, args = append(set, "last_used_step = ?"), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	args = append(args, update.UserID)
+
+	stmt := `UPDATE user_totp SET ` + strings.Join(set, ", ") + ` WHERE user_id = ?`
+	if _, err := s.db.ExecContext(ctx, stmt, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AttemptUserTOTP counts the attempt as failed until a code is accepted,
+// returning false without counting it if the user has too many failed attempts.
+func (s *Store) AttemptUserTOTP(ctx context.Context, attempt *AttemptUserTOTP) (bool, error) {
+	stmt := `
+		UPDATE user_totp
+		SET
+			failed_attempts = CASE WHEN last_attempted_ts < ? THEN 1 ELSE failed_attempts + 1 END,
+			last_attempted_ts = ?
+		WHERE user_id = ? AND (failed_attempts < ? OR last_attempted_ts < ?)`
+	result, err := s.db.ExecContext(ctx, stmt, attempt.ResetTs, attempt.AttemptedTs, attempt.UserID, attempt.MaxFailedAttempts, attempt.ResetTs)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// ConsumeUserTOTPStep accepts a code of the time step and resets the failed attempts,
+// returning false if a code of the step or a later one has already been accepted.
+func (s *Store) ConsumeUserTOTPStep(ctx context.Context, userID int32, step int64) (bool, error) {
+	stmt := `UPDATE user_totp SET last_used_step = ?, failed_attempts = 0 WHERE user_id = ? AND last_used_step < ?`
+	result, err := s.db.ExecContext(ctx, stmt, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// ConsumeUserTOTPRecoveryCode replaces the recovery codes without the used one and resets the failed attempts,
+// returning false if the recovery codes have changed since they were read.
+func (s *Store) ConsumeUserTOTPRecoveryCode(ctx context.Context, userID int32, recoveryCodes []string, remainingRecoveryCodes []string) (bool, error) {
+	stmt := `UPDATE user_totp SET recovery_codes = ?, failed_attempts = 0 WHERE user_id = ? AND recovery_codes = ?`
+	result, err := s.db.ExecContext(ctx, stmt, strings.Join(remainingRecoveryCodes, ","), userID, strings.Join(recoveryCodes, ","))
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *Store) DeleteUserTOTP(ctx context.Context, delete *DeleteUserTOTP) error {
+	stmt := `DELETE FROM user_totp WHERE user_id = ?`
+	if _, err := s.db.ExecContext(ctx, stmt, delete.UserID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func vacuumUserTOTP(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+	DELETE FROM
+		user_totp
+	WHERE
+		user_id NOT IN (
+			SELECT
+				id
+			FROM
+				"user"
+		)`
+	_, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// LDAPEntry is an entry of the stand-in LDAP directory.
+type LDAPEntry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// LDAPServer is an in-process stand-in LDAP directory, which supports simple binds and searches
+// with equality, presence and boolean filters.
+type LDAPServer struct {
+	URL string
+
+	mutex   sync.Mutex
+	entries []*LDAPEntry
+}
+
+// NewLDAPServer starts a stand-in LDAP directory with the entries, which is stopped when the test finishes.
+func NewLDAPServer(t *testing.T, entries ...*LDAPEntry) *LDAPServer {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	s := &LDAPServer{
+		URL:     fmt.Sprintf("ldap://%s", listener.Addr().String()),
+		entries: entries,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// SetEntries replaces the entries of the directory.
+func (s *LDAPServer) SetEntries(entries ...*LDAPEntry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = entries
+}
+
+func (s *LDAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value
+		request := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = append(responses, s.bind(request))
+		case ldap.ApplicationSearchRequest:
+			responses = s.search(request)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = append(responses, newLDAPResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultUnwillingToPerform))
+		}
+		for _, response := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+			envelope.AppendChild(response)
+			if _, err := conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *LDAPServer) bind(request *ber.Packet) *ber.Packet {
+	dn := ber.DecodeString(request.Children[1].Data.Bytes())
+	password := ber.DecodeString(request.Children[2].Data.Bytes())
+	// The bind without password is an anonymous bind.
+	if password == "" {
+		return newLDAPResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password == password {
+			return newLDAPResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess)
+		}
+	}
+	return newLDAPResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials)
+}
+
+func (s *LDAPServer) search(request *ber.Packet) []*ber.Packet {
+	baseDN := ber.DecodeString(request.Children[0].Data.Bytes())
+	filter := request.Children[6]
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	responses := []*ber.Packet{}
+	for _, entry := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(entry.DN), strings.ToLower(baseDN)) || !matchLDAPFilter(entry, filter) {
+			continue
+		}
+		response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+		attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for name, values := range entry.Attributes {
+			attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attribute.AppendChild(set)
+			attributes.AppendChild(attribute)
+		}
+		response.AppendChild(attributes)
+		responses = append(responses, response)
+	}
+	return append(responses, newLDAPResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+}
+
+func matchLDAPFilter(entry *LDAPEntry, filter *ber.Packet) bool {
+	getValues := func(name string) []string {
+		for k, values := range entry.Attributes {
+			if strings.EqualFold(k, name) {
+				return values
+			}
+		}
+		return nil
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchLDAPFilter(entry, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchLDAPFilter(entry, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !matchLDAPFilter(entry, filter.Children[0])
+	case ldap.FilterEqualityMatch:
+		value := ber.DecodeString(filter.Children[1].Data.Bytes())
+		for _, v := range getValues(ber.DecodeString(filter.Children[0].Data.Bytes())) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		return len(getValues(ber.DecodeString(filter.Data.Bytes()))) > 0
+	default:
+		return false
+	}
+}
+
+func newLDAPResult(tag ber.Tag, resultCode uint16) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return result
+}
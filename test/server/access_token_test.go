@@ -0,0 +1,180 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/api/auth"
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/store"
+)
+
+func TestAccessTokenServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	_, err = s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "expired",
+		Scopes:    []auth.Scope{auth.ScopeMemoRead},
+		ExpiresTs: time.Now().Unix() - 1,
+	})
+	require.Error(t, err)
+	accessToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "script",
+		Scopes:    []auth.Scope{auth.ScopeMemoRead},
+		ExpiresTs: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(accessToken.Token, auth.PersonalAccessTokenPrefix))
+
+	header := map[string]string{
+		"Authorization": "Bearer " + accessToken.Token,
+	}
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, header)
+	require.NoError(t, err)
+	// The token lacks the scope to write memos, manage the system, or manage access tokens.
+	_, err = s.request("POST", "/api/v1/memo", strings.NewReader(`{"content":"test"}`), nil, header)
+	require.ErrorContains(t, err, "403")
+	_, err = s.request("GET", "/api/v1/system/setting", nil, nil, header)
+	require.ErrorContains(t, err, "403")
+	_, err = s.request("GET", "/api/v1/access-token", nil, nil, header)
+	require.ErrorContains(t, err, "403")
+
+	body, err := s.get("/api/v1/access-token", nil)
+	require.NoError(t, err)
+	accessTokenList := []*apiv1.AccessToken{}
+	require.NoError(t, json.NewDecoder(body).Decode(&accessTokenList))
+	require.Len(t, accessTokenList, 1)
+	require.Empty(t, accessTokenList[0].Token)
+	require.NotZero(t, accessTokenList[0].LastUsedTs)
+
+	_, err = s.delete(fmt.Sprintf("/api/v1/access-token/%d", accessToken.ID), nil)
+	require.NoError(t, err)
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, header)
+	require.ErrorContains(t, err, "401")
+}
+
+func TestAccessTokenArchivedUserServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	hostSignin := &apiv1.SignIn{
+		Username: "testhost",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: hostSignin.Username,
+		Password: hostSignin.Password,
+	})
+	require.NoError(t, err)
+	user, err := s.createUserV2(&apiv2pb.User{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	userSignin := &apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	createAccessToken := func() map[string]string {
+		_, err := s.postAuthSignIn(userSignin)
+		require.NoError(t, err)
+		accessToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+			Name:      "script",
+			Scopes:    []auth.Scope{auth.ScopeMemoRead},
+			ExpiresTs: time.Now().Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+		_, err = s.postAuthSignIn(hostSignin)
+		require.NoError(t, err)
+		return map[string]string{
+			"Authorization": "Bearer " + accessToken.Token,
+		}
+	}
+	setRowStatus := func(rowStatus store.RowStatus) {
+		_, err := s.server.Store.UpdateUser(ctx, &store.UpdateUser{
+			ID:        user.Id,
+			RowStatus: &rowStatus,
+		})
+		require.NoError(t, err)
+	}
+
+	// The access tokens of the archived users are refused by both APIs.
+	header := createAccessToken()
+	setRowStatus(store.Archived)
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, header)
+	require.ErrorContains(t, err, "401")
+	_, err = s.request("GET", "/api/v2/memos", nil, nil, header)
+	require.ErrorContains(t, err, "401")
+	setRowStatus(store.Normal)
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, header)
+	require.NoError(t, err)
+	_, err = s.request("GET", "/api/v2/memos", nil, nil, header)
+	require.NoError(t, err)
+
+	// Archiving users with either API deletes their access tokens.
+	archived := apiv1.Archived
+	_, err = s.patchUser(user.Id, &apiv1.UpdateUserRequest{
+		RowStatus: &archived,
+	})
+	require.NoError(t, err)
+	accessTokenList, err := s.server.Store.ListAccessTokens(ctx, &store.FindAccessToken{
+		UserID: &user.Id,
+	})
+	require.NoError(t, err)
+	require.Len(t, accessTokenList, 0)
+	setRowStatus(store.Normal)
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, header)
+	require.ErrorContains(t, err, "401")
+
+	header = createAccessToken()
+	_, err = s.updateUserV2(&apiv2pb.User{
+		Id:        user.Id,
+		RowStatus: apiv2pb.RowStatus_ARCHIVED,
+	}, "row_status")
+	require.NoError(t, err)
+	setRowStatus(store.Normal)
+	_, err = s.request("GET", "/api/v2/memos", nil, nil, header)
+	require.ErrorContains(t, err, "401")
+}
+
+func (s *TestingServer) postAccessTokenCreate(request *apiv1.CreateAccessTokenRequest) (*apiv1.AccessToken, error) {
+	rawData, err := json.Marshal(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal access token create")
+	}
+	body, err := s.post("/api/v1/access-token", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	accessToken := &apiv1.AccessToken{}
+	if err = json.Unmarshal(buf.Bytes(), accessToken); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post access token create response")
+	}
+	return accessToken, nil
+}
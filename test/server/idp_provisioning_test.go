@@ -0,0 +1,199 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+)
+
+func TestIdentityProviderProvisioningServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	issuer := newMockOIDCIssuer(t, "test-client-id")
+	issuer.claims = map[string]any{
+		"email":   "john@example.com",
+		"picture": "https://example.com/john.png",
+		"groups":  []string{"staff", "memos-admins"},
+		"enabled": true,
+	}
+	provisioning := &apiv1.IdentityProviderProvisioning{
+		DisableSignUp: true,
+		SyncProfile:   true,
+		RoleMappings: []*apiv1.IdentityProviderRoleMapping{
+			{
+				Claim:   "groups",
+				Pattern: "^memos-admins$",
+				Role:    apiv1.RoleAdmin,
+			},
+		},
+		ArchiveRule: &apiv1.IdentityProviderClaimRule{
+			Claim:   "enabled",
+			Pattern: "^false$",
+		},
+	}
+	create := &apiv1.CreateIdentityProviderRequest{
+		Name: "Keycloak",
+		Type: apiv1.IdentityProviderOIDCType,
+		Config: &apiv1.IdentityProviderConfig{
+			OIDCConfig: &apiv1.IdentityProviderOIDCConfig{
+				Issuer:   issuer.URL,
+				ClientID: "test-client-id",
+				FieldMapping: &apiv1.FieldMapping{
+					Identifier:  "preferred_username",
+					DisplayName: "name",
+					Email:       "email",
+					AvatarURL:   "picture",
+				},
+			},
+		},
+		Provisioning: &apiv1.IdentityProviderProvisioning{
+			RoleMappings: []*apiv1.IdentityProviderRoleMapping{
+				{
+					Claim:   "groups",
+					Pattern: "(",
+					Role:    apiv1.RoleAdmin,
+				},
+			},
+		},
+	}
+	_, err = s.postIdentityProviderCreate(create)
+	require.ErrorContains(t, err, "400")
+	create.Provisioning = provisioning
+	identityProvider, err := s.postIdentityProviderCreate(create)
+	require.NoError(t, err)
+	require.Equal(t, provisioning, identityProvider.Provisioning)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	signInSSO := func() (*apiv1.User, error) {
+		authorization, err := s.postAuthSSOAuthorize(&apiv1.SSOAuthorize{
+			IdentityProviderID: identityProvider.ID,
+			RedirectURI:        "http://localhost/auth/callback",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s.postAuthSignInSSO(&apiv1.SSOSignIn{
+			IdentityProviderID: identityProvider.ID,
+			Code:               "test-code",
+			State:              issuer.authorize(t, authorization.AuthorizationURL),
+		})
+	}
+
+	// The unknown users can't sign up with the identity provider.
+	_, err = signInSSO()
+	require.ErrorContains(t, err, "401")
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	provisioning.DisableSignUp = false
+	_, err = s.patchIdentityProvider(identityProvider.ID, &apiv1.UpdateIdentityProviderRequest{
+		Type:         apiv1.IdentityProviderOIDCType,
+		Provisioning: provisioning,
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The user is created with the role and profile mapped from the claims.
+	user, err := signInSSO()
+	require.NoError(t, err)
+	require.Equal(t, "john", user.Username)
+	require.Equal(t, apiv1.RoleAdmin, user.Role)
+	require.Equal(t, "john@example.com", user.Email)
+	require.Equal(t, "https://example.com/john.png", user.AvatarURL)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The role and profile are synced on each sign-in.
+	issuer.claims["name"] = "Johnny"
+	issuer.claims["groups"] = []string{"staff"}
+	user, err = signInSSO()
+	require.NoError(t, err)
+	require.Equal(t, "Johnny", user.Nickname)
+	require.Equal(t, apiv1.RoleUser, user.Role)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The emails which the identity provider doesn't assert to be verified are neither synced nor mapped to a role.
+	require.False(t, user.EmailVerified)
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	provisioning.RoleMappings = append(provisioning.RoleMappings, &apiv1.IdentityProviderRoleMapping{
+		Claim:   "email",
+		Pattern: `@example\.com$`,
+		Role:    apiv1.RoleAdmin,
+	})
+	_, err = s.patchIdentityProvider(identityProvider.ID, &apiv1.UpdateIdentityProviderRequest{
+		Type:         apiv1.IdentityProviderOIDCType,
+		Provisioning: provisioning,
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	issuer.claims["email"] = "johnny@example.com"
+	user, err = signInSSO()
+	require.NoError(t, err)
+	require.Equal(t, "john@example.com", user.Email)
+	require.Equal(t, apiv1.RoleUser, user.Role)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	issuer.claims["email_verified"] = true
+	user, err = signInSSO()
+	require.NoError(t, err)
+	require.Equal(t, "johnny@example.com", user.Email)
+	require.True(t, user.EmailVerified)
+	require.Equal(t, apiv1.RoleAdmin, user.Role)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The user disabled by the identity provider is archived.
+	issuer.claims["enabled"] = false
+	_, err = signInSSO()
+	require.ErrorContains(t, err, "403")
+	issuer.claims["enabled"] = true
+	_, err = signInSSO()
+	require.ErrorContains(t, err, "403")
+
+	// The local users aren't taken over by the users of the identity provider with the same username.
+	issuer.claims["preferred_username"] = "testuser"
+	_, err = signInSSO()
+	require.ErrorContains(t, err, "409")
+}
+
+func (s *TestingServer) patchIdentityProvider(identityProviderID int32, request *apiv1.UpdateIdentityProviderRequest) (*apiv1.IdentityProvider, error) {
+	rawData, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal identity provider patch")
+	}
+	body, err := s.patch(fmt.Sprintf("/api/v1/idp/%d", identityProviderID), bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	identityProvider := &apiv1.IdentityProvider{}
+	if err = json.NewDecoder(body).Decode(identityProvider); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal patch identity provider response")
+	}
+	return identityProvider, nil
+}
@@ -0,0 +1,128 @@
+package testserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestLDAPServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	john := &test.LDAPEntry{
+		DN:       "uid=john,ou=people,dc=example,dc=com",
+		Password: "john-password",
+		Attributes: map[string][]string{
+			"uid":      {"john"},
+			"cn":       {"John Doe"},
+			"memberOf": {"cn=admins,ou=groups,dc=example,dc=com"},
+		},
+	}
+	testuser := &test.LDAPEntry{
+		DN:       "uid=testuser,ou=people,dc=example,dc=com",
+		Password: "ldap-password",
+		Attributes: map[string][]string{
+			"uid": {"testuser"},
+		},
+	}
+	ldapServer := test.NewLDAPServer(t, john, testuser)
+	_, err = s.postIdentityProviderCreate(&apiv1.CreateIdentityProviderRequest{
+		Name: "Directory",
+		Type: apiv1.IdentityProviderLDAPType,
+		Config: &apiv1.IdentityProviderConfig{
+			LDAPConfig: &apiv1.IdentityProviderLDAPConfig{
+				URL:        ldapServer.URL,
+				BaseDN:     "ou=people,dc=example,dc=com",
+				UserFilter: "(uid=%s)",
+				FieldMapping: &apiv1.FieldMapping{
+					Identifier:  "uid",
+					DisplayName: "cn",
+				},
+				GroupAttribute: "memberOf",
+				AdminGroups:    []string{"cn=admins,ou=groups,dc=example,dc=com"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The user of the directory is created on the first sign-in, with the role of its groups.
+	user, err := s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: "john-password",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "John Doe", user.Nickname)
+	require.Equal(t, apiv1.RoleAdmin, user.Role)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: "wrong-password",
+	})
+	require.ErrorContains(t, err, "401")
+
+	// The role follows the groups on each sign-in.
+	john.Attributes["memberOf"] = []string{"cn=staff,ou=groups,dc=example,dc=com"}
+	ldapServer.SetEntries(john, testuser)
+	user, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: "john-password",
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiv1.RoleUser, user.Role)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The local users still sign in with their local password, and aren't taken over by the users
+	// of the directory with the same username.
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "ldap-password",
+	})
+	require.ErrorContains(t, err, "401")
+	user, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiv1.RoleHost, user.Role)
+
+	// The users of the directory don't sign in with a local password.
+	users, err := s.server.Store.ListUsers(ctx, &store.FindUser{
+		Username: &john.Attributes["uid"][0],
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	localPassword := "local-password"
+	_, err = s.patchUser(users[0].ID, &apiv1.UpdateUserRequest{
+		Password: &localPassword,
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: localPassword,
+	})
+	require.ErrorContains(t, err, "401")
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: "john-password",
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,292 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/api/auth"
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/test"
+)
+
+func TestMailServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testhost",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	// Email verification can't be required before SMTP is configured.
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"require-email-verification","value":"true"}`), nil)
+	require.ErrorContains(t, err, "403")
+
+	smtpServer := test.NewSMTPServer(t, "memos", "secret")
+	smtpConfig := &apiv1.SMTPConfig{
+		Host:      smtpServer.Host,
+		Port:      smtpServer.Port,
+		Username:  "memos",
+		Password:  "secret",
+		Security:  "SSL",
+		FromEmail: "noreply@example.com",
+		FromName:  "Memos",
+	}
+	err = s.postSMTPConfig(smtpConfig)
+	require.ErrorContains(t, err, "400")
+	smtpConfig.Security = "NONE"
+	// SMTP can't be enabled before the external URL, which the links are built from, is set.
+	err = s.postSMTPConfig(smtpConfig)
+	require.ErrorContains(t, err, "Cannot enable SMTP if the external URL is not set")
+	err = s.postCustomizedProfile(&apiv1.CustomizedProfile{
+		Name:        "memos",
+		ExternalURL: "https://memos.example.com/",
+	})
+	require.NoError(t, err)
+	err = s.postSMTPConfig(smtpConfig)
+	require.NoError(t, err)
+	err = s.postCustomizedProfile(&apiv1.CustomizedProfile{
+		Name: "memos",
+	})
+	require.ErrorContains(t, err, "Cannot unset the external URL while SMTP is enabled")
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"require-email-verification","value":"true"}`), nil)
+	require.NoError(t, err)
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"allow-signup","value":"true"}`), nil)
+	require.NoError(t, err)
+	// SMTP can't be disabled while email verification is required.
+	err = s.postSMTPConfig(&apiv1.SMTPConfig{})
+	require.ErrorContains(t, err, "403")
+	status, err := s.getSystemStatus()
+	require.NoError(t, err)
+	require.True(t, status.MailEnabled)
+	require.True(t, status.RequireEmailVerification)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The users signing up are let in once they verify their email.
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.ErrorContains(t, err, "Email is required to sign up")
+	body, err := s.post("/api/v1/auth/signup", strings.NewReader(`{"username":"testuser","password":"testpassword","email":"testuser@example.com"}`), nil)
+	require.NoError(t, err)
+	user := &apiv1.User{}
+	require.NoError(t, json.NewDecoder(body).Decode(user))
+	require.Equal(t, "testuser@example.com", user.Email)
+	require.False(t, user.EmailVerified)
+	require.Empty(t, user.OpenID)
+	_, err = s.get("/api/v1/user/me", nil)
+	require.ErrorContains(t, err, "401")
+	require.Len(t, smtpServer.Messages(), 1)
+	message := smtpServer.LastMessage()
+	require.Equal(t, []string{"testuser@example.com"}, message.To)
+	require.Equal(t, "Verify your email on memos", message.Header("Subject"))
+	require.Contains(t, message.Body(), "https://memos.example.com/auth/verify-email?token=")
+	verificationToken := getMailToken(t, message)
+
+	signin := &apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignIn(signin)
+	require.ErrorContains(t, err, "Email address is not verified")
+	// The link isn't sent again while the last one is valid.
+	require.Len(t, smtpServer.Messages(), 1)
+	err = s.postAuthEmailVerification(verificationToken)
+	require.NoError(t, err)
+	// The token is used once.
+	err = s.postAuthEmailVerification(verificationToken)
+	require.ErrorContains(t, err, "Invalid or expired token")
+	user, err = s.postAuthSignIn(signin)
+	require.NoError(t, err)
+	require.True(t, user.EmailVerified)
+	_, err = s.post("/api/v1/user/me/email-verification", nil, nil)
+	require.ErrorContains(t, err, "Email is already verified")
+	// Neither the session nor the open ID let in once the email has changed, until the new one is verified.
+	_, err = s.request("GET", "/api/v1/user/me", nil, map[string]string{
+		"openId": user.OpenID,
+	}, nil)
+	require.NoError(t, err)
+	changedEmail := "changed@example.com"
+	_, err = s.patchUser(user.ID, &apiv1.UpdateUserRequest{
+		Email: &changedEmail,
+	})
+	require.NoError(t, err)
+	_, err = s.get("/api/v1/memo", nil)
+	require.ErrorContains(t, err, "Email address is not verified")
+	err = s.requestV2("GET", "/api/v2/memos", nil, &apiv2pb.ListMemosResponse{})
+	require.ErrorContains(t, err, "email address is not verified")
+	_, err = s.request("GET", "/api/v1/user/me", nil, map[string]string{
+		"openId": user.OpenID,
+	}, nil)
+	require.ErrorContains(t, err, "401")
+	_, err = s.post("/api/v1/user/me/email-verification", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, smtpServer.Messages(), 2)
+	require.Equal(t, []string{changedEmail}, smtpServer.LastMessage().To)
+	err = s.postAuthEmailVerification(getMailToken(t, smtpServer.LastMessage()))
+	require.NoError(t, err)
+	err = s.requestV2("GET", "/api/v2/memos", nil, &apiv2pb.ListMemosResponse{})
+	require.NoError(t, err)
+	_, err = s.request("GET", "/api/v1/user/me", nil, map[string]string{
+		"openId": user.OpenID,
+	}, nil)
+	require.NoError(t, err)
+	accessToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "testuser",
+		Scopes:    []auth.Scope{auth.ScopeMemoRead},
+		ExpiresTs: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	accessTokenHeader := map[string]string{
+		"Authorization": "Bearer " + accessToken.Token,
+	}
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, accessTokenHeader)
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The password reset link is only sent to the users with the email, without telling whether they exist.
+	err = s.postAuthPasswordReset("nobody@example.com")
+	require.NoError(t, err)
+	require.Len(t, smtpServer.Messages(), 2)
+	// The links don't follow the host of the request.
+	rawData, err := json.Marshal(&apiv1.RequestPasswordResetRequest{
+		Email: changedEmail,
+	})
+	require.NoError(t, err)
+	_, err = s.request("POST", "/api/v1/auth/password-reset", bytes.NewReader(rawData), nil, map[string]string{
+		"Host": "evil.example.com",
+	})
+	require.NoError(t, err)
+	require.Len(t, smtpServer.Messages(), 3)
+	message = smtpServer.LastMessage()
+	require.Equal(t, []string{changedEmail}, message.To)
+	require.Equal(t, "Reset your password on memos", message.Header("Subject"))
+	require.Contains(t, message.Body(), "https://memos.example.com/auth/reset-password?token=")
+	require.NotContains(t, message.Body(), "evil.example.com")
+	resetToken := getMailToken(t, message)
+	// The reset token doesn't verify emails.
+	err = s.postAuthEmailVerification(resetToken)
+	require.ErrorContains(t, err, "Invalid or expired token")
+	// Requesting another link invalidates the previous one.
+	err = s.postAuthPasswordReset(changedEmail)
+	require.NoError(t, err)
+	err = s.postAuthPasswordResetConfirm(&apiv1.ResetPasswordRequest{
+		Token:    resetToken,
+		Password: "newpassword",
+	})
+	require.ErrorContains(t, err, "Invalid or expired token")
+	resetToken = getMailToken(t, smtpServer.LastMessage())
+	err = s.postAuthPasswordResetConfirm(&apiv1.ResetPasswordRequest{
+		Token:    resetToken,
+		Password: "a",
+	})
+	require.ErrorContains(t, err, "400")
+	err = s.postAuthPasswordResetConfirm(&apiv1.ResetPasswordRequest{
+		Token:    resetToken,
+		Password: "newpassword",
+	})
+	require.NoError(t, err)
+	err = s.postAuthPasswordResetConfirm(&apiv1.ResetPasswordRequest{
+		Token:    resetToken,
+		Password: "anotherpassword",
+	})
+	require.ErrorContains(t, err, "Invalid or expired token")
+	_, err = s.postAuthSignIn(signin)
+	require.ErrorContains(t, err, fmt.Sprintf("%d", http.StatusUnauthorized))
+	signin.Password = "newpassword"
+	resetUser, err := s.postAuthSignIn(signin)
+	require.NoError(t, err)
+	// The access tokens and the open ID obtained with the forgotten password are revoked too.
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, accessTokenHeader)
+	require.ErrorContains(t, err, "401")
+	require.NotEqual(t, user.OpenID, resetUser.OpenID)
+	_, err = s.request("GET", "/api/v1/user/me", nil, map[string]string{
+		"openId": user.OpenID,
+	}, nil)
+	require.ErrorContains(t, err, "401")
+}
+
+// getMailToken returns the token of the link in the message.
+func getMailToken(t *testing.T, message *test.SMTPMessage) string {
+	matches := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`).FindStringSubmatch(message.Body())
+	require.Len(t, matches, 2)
+	return matches[1]
+}
+
+func (s *TestingServer) postSMTPConfig(smtpConfig *apiv1.SMTPConfig) error {
+	value, err := json.Marshal(smtpConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal smtp config")
+	}
+	rawData, err := json.Marshal(&apiv1.UpsertSystemSettingRequest{
+		Name:  apiv1.SystemSettingSMTPName,
+		Value: string(value),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal system setting")
+	}
+	_, err = s.post("/api/v1/system/setting", bytes.NewReader(rawData), nil)
+	return err
+}
+
+func (s *TestingServer) postCustomizedProfile(customizedProfile *apiv1.CustomizedProfile) error {
+	value, err := json.Marshal(customizedProfile)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal customized profile")
+	}
+	rawData, err := json.Marshal(&apiv1.UpsertSystemSettingRequest{
+		Name:  apiv1.SystemSettingCustomizedProfileName,
+		Value: string(value),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal system setting")
+	}
+	_, err = s.post("/api/v1/system/setting", bytes.NewReader(rawData), nil)
+	return err
+}
+
+func (s *TestingServer) postAuthPasswordReset(email string) error {
+	rawData, err := json.Marshal(&apiv1.RequestPasswordResetRequest{
+		Email: email,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal password reset request")
+	}
+	_, err = s.post("/api/v1/auth/password-reset", bytes.NewReader(rawData), nil)
+	return err
+}
+
+func (s *TestingServer) postAuthPasswordResetConfirm(request *apiv1.ResetPasswordRequest) error {
+	rawData, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal reset password request")
+	}
+	_, err = s.post("/api/v1/auth/password-reset/confirm", bytes.NewReader(rawData), nil)
+	return err
+}
+
+func (s *TestingServer) postAuthEmailVerification(token string) error {
+	rawData, err := json.Marshal(&apiv1.VerifyEmailRequest{
+		Token: token,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal verify email request")
+	}
+	_, err = s.post("/api/v1/auth/email-verification", bytes.NewReader(rawData), nil)
+	return err
+}
@@ -0,0 +1,110 @@
+package testserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+)
+
+func TestMemoImportServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	writer, err := zipWriter.Create("Takeout/Keep/Note.json")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(`{
+		"textContent": "Imported from Keep",
+		"createdTimestampUsec": 1690000000000000,
+		"userEditedTimestampUsec": 1690000000000000,
+		"labels": [{"name": "keep"}],
+		"attachments": [{"filePath": "image.png", "mimetype": "image/png"}]
+	}`))
+	require.NoError(t, err)
+	writer, err = zipWriter.Create("Takeout/Keep/image.png")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("png"))
+	require.NoError(t, err)
+	require.NoError(t, zipWriter.Close())
+
+	result, err := s.postMemoImport("keep", buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, &apiv1.ImportMemosResponse{MemoCount: 1, ResourceCount: 1}, result)
+	memoList, err := s.getMemoList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	require.Equal(t, "Imported from Keep\n\n#keep", memoList[0].Content)
+	require.Equal(t, int64(1690000000), memoList[0].CreatedTs)
+	require.Len(t, memoList[0].ResourceList, 1)
+
+	_, err = s.postMemoImport("unknown", buf.Bytes())
+	require.Error(t, err)
+
+	// The attachments are limited by the max upload size once inflated.
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"max-upload-size-mib","value":"1"}`), nil)
+	require.NoError(t, err)
+	buf = &bytes.Buffer{}
+	zipWriter = zip.NewWriter(buf)
+	writer, err = zipWriter.Create("Vault/Note.md")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("![[bomb.png]]\n"))
+	require.NoError(t, err)
+	writer, err = zipWriter.Create("Vault/bomb.png")
+	require.NoError(t, err)
+	_, err = writer.Write(make([]byte, 2<<20))
+	require.NoError(t, err)
+	require.NoError(t, zipWriter.Close())
+	require.Less(t, buf.Len(), 1<<20)
+	_, err = s.postMemoImport("obsidian", buf.Bytes())
+	require.ErrorContains(t, err, "export is too large")
+}
+
+func (s *TestingServer) postMemoImport(source string, data []byte) (*apiv1.ImportMemosResponse, error) {
+	buf := &bytes.Buffer{}
+	multipartWriter := multipart.NewWriter(buf)
+	if err := multipartWriter.WriteField("source", source); err != nil {
+		return nil, err
+	}
+	fileWriter, err := multipartWriter.CreateFormFile("file", "export.zip")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fileWriter.Write(data); err != nil {
+		return nil, err
+	}
+	if err := multipartWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	body, err := s.request("POST", "/api/v1/memo/import", buf, nil, map[string]string{
+		"Cookie":       s.cookie,
+		"Content-Type": multipartWriter.FormDataContentType(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &apiv1.ImportMemosResponse{}
+	if err := json.NewDecoder(body).Decode(result); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post memo import response")
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/common/util"
+)
+
+func TestMemoRevisionServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "#todo\n- buy milk",
+	})
+	require.NoError(t, err)
+	updatedContent := "#done\n- buy milk"
+	_, err = s.patchMemo(&apiv1.PatchMemoRequest{
+		ID:      memo.ID,
+		Content: &updatedContent,
+	})
+	require.NoError(t, err)
+
+	memoRevisionList, err := s.getMemoRevisionList(memo.ID)
+	require.NoError(t, err)
+	require.Len(t, memoRevisionList, 2)
+	latest, original := memoRevisionList[0], memoRevisionList[1]
+	require.Equal(t, updatedContent, latest.Content)
+
+	body, err := s.get(fmt.Sprintf("/api/v1/memo/%d/revision/diff", memo.ID), map[string]string{
+		"from": fmt.Sprintf("%d", original.ID),
+		"to":   fmt.Sprintf("%d", latest.ID),
+	})
+	require.NoError(t, err)
+	memoRevisionDiff := &apiv1.MemoRevisionDiff{}
+	require.NoError(t, json.NewDecoder(body).Decode(memoRevisionDiff))
+	require.Equal(t, []*apiv1.MemoRevisionDiffLine{
+		{Operation: util.DiffDelete, Text: "#todo"},
+		{Operation: util.DiffInsert, Text: "#done"},
+		{Operation: util.DiffEqual, Text: "- buy milk"},
+	}, memoRevisionDiff.LineList)
+
+	body, err = s.post(fmt.Sprintf("/api/v1/memo/%d/revision/%d/restore", memo.ID, original.ID), nil, nil)
+	require.NoError(t, err)
+	restoredMemo := &apiv1.Memo{}
+	require.NoError(t, json.NewDecoder(body).Decode(restoredMemo))
+	require.Equal(t, "#todo\n- buy milk", restoredMemo.Content)
+	memoRevisionList, err = s.getMemoRevisionList(memo.ID)
+	require.NoError(t, err)
+	require.Len(t, memoRevisionList, 3)
+}
+
+func (s *TestingServer) getMemoRevisionList(memoID int32) ([]*apiv1.MemoRevision, error) {
+	body, err := s.get(fmt.Sprintf("/api/v1/memo/%d/revision", memoID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	memoRevisionList := []*apiv1.MemoRevision{}
+	if err = json.Unmarshal(buf.Bytes(), &memoRevisionList); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal get memo revision list response")
+	}
+	return memoRevisionList, nil
+}
@@ -0,0 +1,262 @@
+package testserver
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/store"
+)
+
+func TestMemoServiceServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	host, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testhost",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	privateMemo, err := s.createMemoV2(&apiv2pb.CreateMemoRequest{
+		Content:    "private memo",
+		Visibility: apiv2pb.Visibility_PRIVATE,
+	})
+	require.NoError(t, err)
+	publicMemo, err := s.createMemoV2(&apiv2pb.CreateMemoRequest{
+		Content:    "public memo",
+		Visibility: apiv2pb.Visibility_PUBLIC,
+	})
+	require.NoError(t, err)
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"allow-signup","value":"true"}`), nil)
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// Anonymous users only see public memos.
+	_, err = s.getMemoV2(privateMemo.Id)
+	require.ErrorContains(t, err, "401")
+	memo, err := s.getMemoV2(publicMemo.Id)
+	require.NoError(t, err)
+	require.Equal(t, "public memo", memo.Content)
+	_, err = s.createMemoV2(&apiv2pb.CreateMemoRequest{
+		Content: "anonymous memo",
+	})
+	require.ErrorContains(t, err, "401")
+
+	user, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.getMemoV2(privateMemo.Id)
+	require.ErrorContains(t, err, "403")
+	resource, err := s.server.Store.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.txt",
+		Blob:      []byte("test"),
+		Type:      "text/plain",
+		Size:      4,
+	})
+	require.NoError(t, err)
+	hostResource, err := s.server.Store.CreateResource(ctx, &store.Resource{
+		CreatorID: host.ID,
+		Filename:  "host.txt",
+		Blob:      []byte("host"),
+		Type:      "text/plain",
+		Size:      4,
+	})
+	require.NoError(t, err)
+
+	// The related memos which aren't visible fail like the missing ones, so that whether they exist isn't told.
+	for _, relatedMemoID := range []int32{privateMemo.Id, 999} {
+		_, err = s.createMemoV2(&apiv2pb.CreateMemoRequest{
+			Content: "memo with a private relation",
+			Relations: []*apiv2pb.MemoRelation{
+				{RelatedMemoId: relatedMemoID, Type: apiv2pb.MemoRelation_REFERENCE},
+			},
+		})
+		require.ErrorContains(t, err, fmt.Sprintf("related memo %d not found", relatedMemoID))
+	}
+	// Nothing is created when the resources can't be bound.
+	_, err = s.createMemoV2(&apiv2pb.CreateMemoRequest{
+		Content:     "memo with a resource of another user",
+		ResourceIds: []int32{resource.ID, hostResource.ID},
+	})
+	require.ErrorContains(t, err, fmt.Sprintf("resource %d not found", hostResource.ID))
+	memos, err := s.listMemosV2()
+	require.NoError(t, err)
+	require.Len(t, memos, 1)
+	require.Equal(t, publicMemo.Id, memos[0].Id)
+
+	memo, err = s.createMemoV2(&apiv2pb.CreateMemoRequest{
+		Content:     "test memo",
+		ResourceIds: []int32{resource.ID, resource.ID},
+		Relations: []*apiv2pb.MemoRelation{
+			{RelatedMemoId: publicMemo.Id, Type: apiv2pb.MemoRelation_REFERENCE},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiv2pb.Visibility_PRIVATE, memo.Visibility)
+	require.Equal(t, []int32{resource.ID}, memo.ResourceIds)
+	require.Len(t, memo.Relations, 1)
+	require.Equal(t, publicMemo.Id, memo.Relations[0].RelatedMemoId)
+
+	resourceList := &apiv2pb.ListMemoResourcesResponse{}
+	err = s.requestV2("POST", fmt.Sprintf("/api/v2/memos/%d/resources", memo.Id), &apiv2pb.SetMemoResourcesRequest{}, nil)
+	require.NoError(t, err)
+	err = s.requestV2("GET", fmt.Sprintf("/api/v2/memos/%d/resources", memo.Id), nil, resourceList)
+	require.NoError(t, err)
+	require.Empty(t, resourceList.ResourceIds)
+	err = s.requestV2("POST", fmt.Sprintf("/api/v2/memos/%d/relations", memo.Id), &apiv2pb.SetMemoRelationsRequest{
+		Relations: []*apiv2pb.MemoRelation{
+			{RelatedMemoId: memo.Id, Type: apiv2pb.MemoRelation_REFERENCE},
+		},
+	}, nil)
+	require.ErrorContains(t, err, "memo can't relate to itself")
+	err = s.requestV2("POST", fmt.Sprintf("/api/v2/memos/%d/relations", memo.Id), &apiv2pb.SetMemoRelationsRequest{
+		Relations: []*apiv2pb.MemoRelation{
+			{RelatedMemoId: privateMemo.Id, Type: apiv2pb.MemoRelation_ADDITIONAL},
+		},
+	}, nil)
+	require.ErrorContains(t, err, fmt.Sprintf("related memo %d not found", privateMemo.Id))
+	relationList := &apiv2pb.ListMemoRelationsResponse{}
+	err = s.requestV2("GET", fmt.Sprintf("/api/v2/memos/%d/relations", memo.Id), nil, relationList)
+	require.NoError(t, err)
+	require.Len(t, relationList.Relations, 1)
+	require.Equal(t, publicMemo.Id, relationList.Relations[0].RelatedMemoId)
+
+	// Only the fields in the update mask are updated.
+	memo, err = s.updateMemoV2(&apiv2pb.Memo{
+		Id:         memo.Id,
+		Content:    "updated memo",
+		Visibility: apiv2pb.Visibility_PROTECTED,
+	}, "content")
+	require.NoError(t, err)
+	require.Equal(t, "updated memo", memo.Content)
+	require.Equal(t, apiv2pb.Visibility_PRIVATE, memo.Visibility)
+	_, err = s.updateMemoV2(&apiv2pb.Memo{
+		Id: memo.Id,
+	}, "creator_id")
+	require.ErrorContains(t, err, "invalid update path: creator_id")
+	_, err = s.updateMemoV2(&apiv2pb.Memo{
+		Id:      publicMemo.Id,
+		Content: "updated by another user",
+	}, "content")
+	require.ErrorContains(t, err, "403")
+
+	// Deleted memos are moved to the trash of their creator until restored.
+	err = s.requestV2("DELETE", fmt.Sprintf("/api/v2/memos/%d", publicMemo.Id), nil, nil)
+	require.ErrorContains(t, err, "403")
+	err = s.requestV2("DELETE", fmt.Sprintf("/api/v2/memos/%d", memo.Id), nil, nil)
+	require.NoError(t, err)
+	_, err = s.getMemoV2(memo.Id)
+	require.ErrorContains(t, err, "404")
+	trashList := &apiv2pb.ListTrashMemosResponse{}
+	err = s.requestV2("GET", "/api/v2/trash/memos", nil, trashList)
+	require.NoError(t, err)
+	require.Len(t, trashList.Memos, 1)
+	require.Equal(t, memo.Id, trashList.Memos[0].Id)
+	require.NotZero(t, trashList.Memos[0].DeletedTs)
+	err = s.requestV2("POST", fmt.Sprintf("/api/v2/memos/%d/restore", publicMemo.Id), nil, nil)
+	require.ErrorContains(t, err, "404")
+	restored := &apiv2pb.RestoreMemoResponse{}
+	err = s.requestV2("POST", fmt.Sprintf("/api/v2/memos/%d/restore", memo.Id), nil, restored)
+	require.NoError(t, err)
+	require.Equal(t, memo.Id, restored.Memo.Id)
+	require.Zero(t, restored.Memo.DeletedTs)
+	memos, err = s.listMemosV2()
+	require.NoError(t, err)
+	require.Len(t, memos, 2)
+
+	// The operators unsupported on the fields are refused as invalid filters.
+	response := &apiv2pb.ListMemosResponse{}
+	err = s.requestV2("GET", "/api/v2/memos?filter="+url.QueryEscape(`content.contains("updated")`), nil, response)
+	require.NoError(t, err)
+	require.Len(t, response.Memos, 1)
+	require.Equal(t, memo.Id, response.Memos[0].Id)
+	for _, filter := range []string{`content == "updated memo"`, `pinned in [true]`} {
+		err = s.requestV2("GET", "/api/v2/memos?filter="+url.QueryEscape(filter), nil, response)
+		require.ErrorContains(t, err, "400")
+		require.ErrorContains(t, err, "isn't supported")
+	}
+}
+
+func TestMemoServicePageTokenServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = s.createMemoV2(&apiv2pb.CreateMemoRequest{
+			Content: fmt.Sprintf("test memo %d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	// The memos ordered by rank are paginated by the offset in the page token.
+	response := &apiv2pb.ListMemosResponse{}
+	err = s.requestV2("GET", "/api/v2/memos?search=memo&pageSize=2", nil, response)
+	require.NoError(t, err)
+	require.Len(t, response.Memos, 2)
+	require.NotEmpty(t, response.NextPageToken)
+	err = s.requestV2("GET", "/api/v2/memos?search=memo&pageSize=2&pageToken="+response.NextPageToken, nil, response)
+	require.NoError(t, err)
+	require.Len(t, response.Memos, 1)
+	require.Empty(t, response.NextPageToken)
+
+	for _, offset := range []string{"-1", "4", "9223372036854775807"} {
+		pageToken := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"offset":%s}`, offset)))
+		err = s.requestV2("GET", "/api/v2/memos?search=memo&pageSize=2&pageToken="+pageToken, nil, response)
+		require.ErrorContains(t, err, "400")
+		require.ErrorContains(t, err, "out of range")
+	}
+	err = s.requestV2("GET", "/api/v2/memos?pageSize=2&pageToken=invalid", nil, response)
+	require.ErrorContains(t, err, "invalid page token")
+}
+
+func (s *TestingServer) createMemoV2(request *apiv2pb.CreateMemoRequest) (*apiv2pb.Memo, error) {
+	response := &apiv2pb.CreateMemoResponse{}
+	if err := s.requestV2("POST", "/api/v2/memos", request, response); err != nil {
+		return nil, err
+	}
+	return response.Memo, nil
+}
+
+func (s *TestingServer) getMemoV2(memoID int32) (*apiv2pb.Memo, error) {
+	response := &apiv2pb.GetMemoResponse{}
+	if err := s.requestV2("GET", fmt.Sprintf("/api/v2/memos/%d", memoID), nil, response); err != nil {
+		return nil, err
+	}
+	return response.Memo, nil
+}
+
+func (s *TestingServer) listMemosV2() ([]*apiv2pb.Memo, error) {
+	response := &apiv2pb.ListMemosResponse{}
+	if err := s.requestV2("GET", "/api/v2/memos", nil, response); err != nil {
+		return nil, err
+	}
+	return response.Memos, nil
+}
+
+func (s *TestingServer) updateMemoV2(memo *apiv2pb.Memo, paths ...string) (*apiv2pb.Memo, error) {
+	response := &apiv2pb.UpdateMemoResponse{}
+	uri := fmt.Sprintf("/api/v2/memos/%d?updateMask=%s", memo.Id, strings.Join(paths, ","))
+	if err := s.requestV2("PATCH", uri, memo, response); err != nil {
+		return nil, err
+	}
+	return response.Memo, nil
+}
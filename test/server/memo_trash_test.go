@@ -0,0 +1,86 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/server"
+)
+
+func TestMemoTrashServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "test memo",
+	})
+	require.NoError(t, err)
+	err = s.deleteMemo(memo.ID)
+	require.NoError(t, err)
+	_, err = s.getMemo(memo.ID)
+	require.Error(t, err)
+	memoList, err := s.getMemoTrashList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	require.Equal(t, memo.ID, memoList[0].ID)
+	require.NotZero(t, memoList[0].DeletedTs)
+
+	body, err := s.post(fmt.Sprintf("/api/v1/memo/%d/restore", memo.ID), nil, nil)
+	require.NoError(t, err)
+	restoredMemo := &apiv1.Memo{}
+	require.NoError(t, json.NewDecoder(body).Decode(restoredMemo))
+	require.Equal(t, memo.ID, restoredMemo.ID)
+	require.Zero(t, restoredMemo.DeletedTs)
+	memoList, err = s.getMemoTrashList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 0)
+
+	// Memos in trash are purged after the retention period.
+	err = s.deleteMemo(memo.ID)
+	require.NoError(t, err)
+	trashPurgeRunner := server.NewTrashPurgeRunner(s.server.Store)
+	err = trashPurgeRunner.Purge(ctx, time.Now())
+	require.NoError(t, err)
+	memoList, err = s.getMemoTrashList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	err = trashPurgeRunner.Purge(ctx, time.Now().AddDate(0, 0, apiv1.DefaultMemoTrashRetentionDays+1))
+	require.NoError(t, err)
+	memoList, err = s.getMemoTrashList()
+	require.NoError(t, err)
+	require.Len(t, memoList, 0)
+}
+
+func (s *TestingServer) getMemoTrashList() ([]*apiv1.Memo, error) {
+	body, err := s.get("/api/v1/memo/trash", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	memoList := []*apiv1.Memo{}
+	if err = json.Unmarshal(buf.Bytes(), &memoList); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal get memo trash list response")
+	}
+	return memoList, nil
+}
@@ -0,0 +1,245 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+	"github.com/usememos/memos/test"
+)
+
+func TestPasskeyServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	user, err := s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	// Password login can't be deactivated before the host has another way to sign in.
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"disable-password-login","value":"true"}`), nil)
+	require.ErrorContains(t, err, "403")
+
+	authenticator := test.NewWebAuthnAuthenticator(fmt.Sprintf("http://localhost:%d", s.profile.Port))
+	registrationChallenge, err := s.postUserPasskeyChallenge()
+	require.NoError(t, err)
+	require.Equal(t, "localhost", registrationChallenge.PublicKey.RP.ID)
+	require.Equal(t, "testuser", registrationChallenge.PublicKey.User.Name)
+	require.Empty(t, registrationChallenge.PublicKey.ExcludeCredentials)
+	attestation, err := authenticator.Register(registrationChallenge.PublicKey.Challenge, registrationChallenge.PublicKey.User.ID)
+	require.NoError(t, err)
+	create := &apiv1.CreateUserPasskeyRequest{
+		Name: "Laptop",
+		Credential: &apiv1.PasskeyAttestationCredential{
+			ID: attestation.CredentialID,
+			Response: &apiv1.PasskeyAttestationResponse{
+				ClientDataJSON:    attestation.ClientDataJSON,
+				AttestationObject: attestation.AttestationObject,
+			},
+		},
+	}
+	passkey, err := s.postUserPasskeyCreate(create)
+	require.NoError(t, err)
+	require.Equal(t, "Laptop", passkey.Name)
+	// The challenge is answered once.
+	_, err = s.postUserPasskeyCreate(create)
+	require.ErrorContains(t, err, "Invalid or expired passkey challenge")
+	registrationChallenge, err = s.postUserPasskeyChallenge()
+	require.NoError(t, err)
+	require.Equal(t, attestation.CredentialID, registrationChallenge.PublicKey.ExcludeCredentials[0].ID)
+
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"disable-password-login","value":"true"}`), nil)
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.ErrorContains(t, err, "Password login is deactivated")
+
+	// The passkey signs in, even though password login is deactivated.
+	signInChallenge, err := s.postAuthPasskeyChallenge()
+	require.NoError(t, err)
+	require.Equal(t, "localhost", signInChallenge.PublicKey.RPID)
+	assertion, err := authenticator.Assert(signInChallenge.PublicKey.Challenge)
+	require.NoError(t, err)
+	signin := &apiv1.PasskeySignIn{
+		Credential: &apiv1.PasskeyAssertionCredential{
+			ID: assertion.CredentialID,
+			Response: &apiv1.PasskeyAssertionResponse{
+				ClientDataJSON:    assertion.ClientDataJSON,
+				AuthenticatorData: assertion.AuthenticatorData,
+				Signature:         assertion.Signature,
+				UserHandle:        assertion.UserHandle,
+			},
+		},
+	}
+	signedInUser, err := s.postAuthSignInPasskey(signin)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, signedInUser.ID)
+	passkeyList, err := s.getUserPasskeyList()
+	require.NoError(t, err)
+	require.Len(t, passkeyList, 1)
+	require.NotZero(t, passkeyList[0].LastUsedTs)
+
+	// The last passkey of passkey-only instances can't be deleted.
+	_, err = s.delete(fmt.Sprintf("/api/v1/user/me/passkey/%d", passkey.ID), nil)
+	require.ErrorContains(t, err, "403")
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The replayed assertion doesn't answer another challenge.
+	_, err = s.postAuthPasskeyChallenge()
+	require.NoError(t, err)
+	_, err = s.postAuthSignInPasskey(signin)
+	require.ErrorContains(t, err, "401")
+}
+
+func TestPasskeyWithoutSignCountServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	authenticator := test.NewWebAuthnAuthenticator(fmt.Sprintf("http://localhost:%d", s.profile.Port))
+	authenticator.WithoutSignCount = true
+	registrationChallenge, err := s.postUserPasskeyChallenge()
+	require.NoError(t, err)
+	attestation, err := authenticator.Register(registrationChallenge.PublicKey.Challenge, registrationChallenge.PublicKey.User.ID)
+	require.NoError(t, err)
+	_, err = s.postUserPasskeyCreate(&apiv1.CreateUserPasskeyRequest{
+		Name: "Phone",
+		Credential: &apiv1.PasskeyAttestationCredential{
+			ID: attestation.CredentialID,
+			Response: &apiv1.PasskeyAttestationResponse{
+				ClientDataJSON:    attestation.ClientDataJSON,
+				AttestationObject: attestation.AttestationObject,
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The sign count can't tell the replays apart, but the challenge is only answered once anyway,
+	// even with the cookie of the challenge kept by the client.
+	signInChallenge, err := s.postAuthPasskeyChallenge()
+	require.NoError(t, err)
+	challengeCookie := s.cookie
+	assertion, err := authenticator.Assert(signInChallenge.PublicKey.Challenge)
+	require.NoError(t, err)
+	signin := &apiv1.PasskeySignIn{
+		Credential: &apiv1.PasskeyAssertionCredential{
+			ID: assertion.CredentialID,
+			Response: &apiv1.PasskeyAssertionResponse{
+				ClientDataJSON:    assertion.ClientDataJSON,
+				AuthenticatorData: assertion.AuthenticatorData,
+				Signature:         assertion.Signature,
+				UserHandle:        assertion.UserHandle,
+			},
+		},
+	}
+	_, err = s.postAuthSignInPasskey(signin)
+	require.NoError(t, err)
+	userCredential, err := s.server.Store.GetUserCredential(ctx, &store.FindUserCredential{
+		CredentialID: &attestation.CredentialID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, userCredential.SignCount)
+	require.NotZero(t, userCredential.LastUsedTs)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	s.cookie = challengeCookie
+	_, err = s.postAuthSignInPasskey(signin)
+	require.ErrorContains(t, err, "Invalid or expired passkey challenge")
+}
+
+func (s *TestingServer) postUserPasskeyChallenge() (*apiv1.PasskeyRegistrationChallenge, error) {
+	body, err := s.post("/api/v1/user/me/passkey/challenge", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &apiv1.PasskeyRegistrationChallenge{}
+	if err = json.NewDecoder(body).Decode(challenge); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post passkey challenge response")
+	}
+	return challenge, nil
+}
+
+func (s *TestingServer) postUserPasskeyCreate(create *apiv1.CreateUserPasskeyRequest) (*apiv1.UserPasskey, error) {
+	rawData, err := json.Marshal(create)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal passkey create")
+	}
+	body, err := s.post("/api/v1/user/me/passkey", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey := &apiv1.UserPasskey{}
+	if err = json.NewDecoder(body).Decode(passkey); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post passkey response")
+	}
+	return passkey, nil
+}
+
+func (s *TestingServer) getUserPasskeyList() ([]*apiv1.UserPasskey, error) {
+	body, err := s.get("/api/v1/user/me/passkey", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeyList := []*apiv1.UserPasskey{}
+	if err = json.NewDecoder(body).Decode(&passkeyList); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal get passkey list response")
+	}
+	return passkeyList, nil
+}
+
+func (s *TestingServer) postAuthPasskeyChallenge() (*apiv1.PasskeySignInChallenge, error) {
+	body, err := s.post("/api/v1/auth/passkey/challenge", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &apiv1.PasskeySignInChallenge{}
+	if err = json.NewDecoder(body).Decode(challenge); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post passkey challenge response")
+	}
+	return challenge, nil
+}
+
+func (s *TestingServer) postAuthSignInPasskey(signin *apiv1.PasskeySignIn) (*apiv1.User, error) {
+	rawData, err := json.Marshal(signin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal passkey signin")
+	}
+	body, err := s.post("/api/v1/auth/signin/passkey", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &apiv1.User{}
+	if err = json.NewDecoder(body).Decode(user); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post passkey signin response")
+	}
+	return user, nil
+}
@@ -0,0 +1,151 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/api/auth"
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+	"github.com/usememos/memos/store"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestResourceServiceServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testhost",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	for _, setting := range []string{
+		`{"name":"allow-signup","value":"true"}`,
+		`{"name":"storage-service-id","value":"-1"}`,
+		`{"name":"max-upload-size-mib","value":"1"}`,
+	} {
+		_, err = s.post("/api/v1/system/setting", strings.NewReader(setting), nil)
+		require.NoError(t, err)
+	}
+
+	// The content is uploaded in chunks after the metadata.
+	resource, err := s.uploadResourceV2(nil, "test.txt", []byte("hello "), []byte("world"))
+	require.NoError(t, err)
+	require.Equal(t, "test.txt", resource.Filename)
+	require.Equal(t, int64(11), resource.Size)
+	storeResource, err := s.server.Store.GetResource(ctx, &store.FindResource{
+		ID: &resource.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(s.profile.Data, "assets", "test.txt"), storeResource.InternalPath)
+	blob, err := os.ReadFile(storeResource.InternalPath)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(blob))
+
+	// The filenames can't leave the directory of the local storage.
+	for _, filename := range []string{"../evil.txt", "assets/evil.txt", `..\evil.txt`, ".."} {
+		_, err = s.uploadResourceV2(nil, filename, []byte("evil"))
+		require.ErrorContains(t, err, "400", filename)
+		require.ErrorContains(t, err, "invalid resource filename", filename)
+	}
+	_, err = os.Stat(filepath.Join(s.profile.Data, "evil.txt"))
+	require.True(t, os.IsNotExist(err))
+	err = s.requestV2("PATCH", fmt.Sprintf("/api/v2/resources/%d?updateMask=filename", resource.Id), &apiv2pb.Resource{
+		Filename: "../evil.txt",
+	}, nil)
+	require.ErrorContains(t, err, "invalid resource filename")
+
+	// The content over the max upload size is refused, without leaving a partial file behind.
+	_, err = s.uploadResourceV2(nil, "large.txt", bytes.Repeat([]byte("a"), apiv1.MebiByte), []byte("a"))
+	require.ErrorContains(t, err, "400")
+	require.ErrorContains(t, err, "file size exceeds allowed limit of 1 MiB")
+	_, err = os.Stat(filepath.Join(s.profile.Data, "assets", "large.txt"))
+	require.True(t, os.IsNotExist(err))
+	resourceList := &apiv2pb.ListResourcesResponse{}
+	err = s.requestV2("GET", "/api/v2/resources", nil, resourceList)
+	require.NoError(t, err)
+	require.Len(t, resourceList.Resources, 1)
+
+	// Uploading requires a user, and the resource upload scope of access tokens.
+	accessToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "reader",
+		Scopes:    []auth.Scope{auth.ScopeMemoRead},
+		ExpiresTs: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	_, err = s.uploadResourceV2(map[string]string{
+		"Authorization": "Bearer " + accessToken.Token,
+	}, "token.txt", []byte("token"))
+	require.ErrorContains(t, err, "access token lacks the resource.upload scope")
+	err = s.postSignOut()
+	require.NoError(t, err)
+	_, err = s.uploadResourceV2(nil, "anonymous.txt", []byte("anonymous"))
+	require.ErrorContains(t, err, "401")
+
+	// The resources belonging to no memo are private to their creator.
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	err = s.requestV2("GET", fmt.Sprintf("/api/v2/resources/%d", resource.Id), nil, &apiv2pb.GetResourceResponse{})
+	require.ErrorContains(t, err, "403")
+	err = s.requestV2("DELETE", fmt.Sprintf("/api/v2/resources/%d", resource.Id), nil, nil)
+	require.ErrorContains(t, err, "404")
+}
+
+// uploadResourceV2 uploads the chunks of a resource to the client streaming upload of API v2,
+// which the gateway reads as JSON messages separated by newlines.
+func (s *TestingServer) uploadResourceV2(header map[string]string, filename string, chunks ...[]byte) (*apiv2pb.Resource, error) {
+	requests := []*apiv2pb.UploadResourceRequest{
+		{Data: &apiv2pb.UploadResourceRequest_Metadata_{Metadata: &apiv2pb.UploadResourceRequest_Metadata{
+			Filename: filename,
+			Type:     "text/plain",
+		}}},
+	}
+	for _, chunk := range chunks {
+		requests = append(requests, &apiv2pb.UploadResourceRequest{
+			Data: &apiv2pb.UploadResourceRequest_Chunk{Chunk: chunk},
+		})
+	}
+	body := &bytes.Buffer{}
+	for _, request := range requests {
+		rawData, err := protojson.Marshal(request)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal upload resource request")
+		}
+		body.Write(rawData)
+		body.WriteString("\n")
+	}
+	if header == nil {
+		header = map[string]string{
+			"Cookie": s.cookie,
+		}
+	}
+	respBody, err := s.request("POST", "/api/v2/resources/upload", body, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+	rawData, err := io.ReadAll(respBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+	response := &apiv2pb.UploadResourceResponse{}
+	if err := protojson.Unmarshal(rawData, response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal upload resource response")
+	}
+	return response.Resource, nil
+}
@@ -0,0 +1,217 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/api/auth"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+)
+
+func TestSCIMServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	host, err := s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	readToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "reader",
+		Scopes:    []auth.Scope{auth.ScopeMemoRead},
+		ExpiresTs: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	adminToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "scim",
+		Scopes:    []auth.Scope{auth.ScopeAdmin},
+		ExpiresTs: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The endpoint requires a personal access token with the admin scope.
+	_, err = s.scimRequest("", "GET", "/scim/v2/Users", nil)
+	require.ErrorContains(t, err, "401")
+	_, err = s.scimRequest(readToken.Token, "GET", "/scim/v2/Users", nil)
+	require.ErrorContains(t, err, "403")
+
+	john := &apiv1.SCIMUser{}
+	err = s.scimRequestJSON(adminToken.Token, "POST", "/scim/v2/Users", &apiv1.SCIMUser{
+		UserName:    "john",
+		DisplayName: "John Doe",
+		Emails: []*apiv1.SCIMEmail{
+			{Value: "john@example.com", Type: "work", Primary: true},
+		},
+		Password: "john-password",
+	}, john)
+	require.NoError(t, err)
+	require.Equal(t, "john", john.UserName)
+	require.Equal(t, "John Doe", john.DisplayName)
+	require.Equal(t, "john@example.com", john.Emails[0].Value)
+	require.True(t, *john.Active)
+	require.Empty(t, john.Password)
+	johnID := john.ID
+	_, err = s.scimRequest(adminToken.Token, "POST", "/scim/v2/Users", &apiv1.SCIMUser{
+		UserName: "john",
+	})
+	require.ErrorContains(t, err, "uniqueness")
+
+	listResponse := &apiv1.SCIMListResponse{}
+	err = s.scimRequestJSON(adminToken.Token, "GET", "/scim/v2/Users?filter="+url.QueryEscape(`userName eq "john"`), nil, listResponse)
+	require.NoError(t, err)
+	require.Equal(t, 1, listResponse.TotalResults)
+	require.Equal(t, johnID, listResponse.Resources[0].ID)
+	err = s.scimRequestJSON(adminToken.Token, "GET", "/scim/v2/Users?startIndex=2&count=1", nil, listResponse)
+	require.NoError(t, err)
+	require.Equal(t, 2, listResponse.TotalResults)
+	require.Equal(t, 1, listResponse.ItemsPerPage)
+	require.Equal(t, johnID, listResponse.Resources[0].ID)
+	_, err = s.scimRequest(adminToken.Token, "GET", "/scim/v2/Users?filter="+url.QueryEscape(`title co "engineer"`), nil)
+	require.ErrorContains(t, err, "invalidFilter")
+
+	user, err := s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: "john-password",
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiv1.RoleUser, user.Role)
+	johnToken, err := s.postAccessTokenCreate(&apiv1.CreateAccessTokenRequest{
+		Name:      "john",
+		Scopes:    []auth.Scope{auth.ScopeMemoRead},
+		ExpiresTs: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	johnHeader := map[string]string{
+		"Authorization": "Bearer " + johnToken.Token,
+	}
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, johnHeader)
+	require.NoError(t, err)
+	_, err = s.get("/api/v1/user/me", map[string]string{"openId": user.OpenID})
+	require.NoError(t, err)
+
+	// The deactivated user is archived, and can't sign in anymore.
+	err = s.scimRequestJSON(adminToken.Token, "PATCH", "/scim/v2/Users/"+johnID, map[string]any{
+		"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]any{
+			{"op": "Replace", "path": "active", "value": "False"},
+			{"op": "replace", "path": `emails[type eq "work"].value`, "value": "john.doe@example.com"},
+		},
+	}, john)
+	require.NoError(t, err)
+	require.False(t, *john.Active)
+	require.Equal(t, "john.doe@example.com", john.Emails[0].Value)
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "john",
+		Password: "john-password",
+	})
+	require.ErrorContains(t, err, "403")
+	// The access tokens of the deactivated user are revoked as well.
+	_, err = s.request("GET", "/api/v1/memo", nil, nil, johnHeader)
+	require.ErrorContains(t, err, "401")
+	accessTokens, err := s.server.Store.ListAccessTokens(ctx, &store.FindAccessToken{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, accessTokens)
+	// The open ID is rotated, and the archived user isn't let in with the new one either.
+	_, err = s.get("/api/v1/user/me", map[string]string{"openId": user.OpenID})
+	require.ErrorContains(t, err, "401")
+	archivedUser, err := s.server.Store.GetUser(ctx, &store.FindUser{
+		ID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, user.OpenID, archivedUser.OpenID)
+	_, err = s.get("/api/v1/user/me", map[string]string{"openId": archivedUser.OpenID})
+	require.ErrorContains(t, err, "401")
+
+	// The replacement reactivates the user by default, and clears the omitted attributes.
+	john = &apiv1.SCIMUser{}
+	err = s.scimRequestJSON(adminToken.Token, "PUT", "/scim/v2/Users/"+johnID, &apiv1.SCIMUser{
+		UserName: "johnny",
+		Name: &apiv1.SCIMName{
+			GivenName:  "Johnny",
+			FamilyName: "Doe",
+		},
+	}, john)
+	require.NoError(t, err)
+	require.True(t, *john.Active)
+	require.Equal(t, "johnny", john.UserName)
+	require.Equal(t, "Johnny Doe", john.DisplayName)
+	require.Empty(t, john.Emails)
+
+	// The host can't be deactivated or deleted.
+	hostID := fmt.Sprintf("%d", host.ID)
+	_, err = s.scimRequest(adminToken.Token, "PATCH", "/scim/v2/Users/"+hostID, map[string]any{
+		"Operations": []map[string]any{
+			{"op": "replace", "value": map[string]any{"active": false}},
+		},
+	})
+	require.ErrorContains(t, err, "403")
+	_, err = s.scimRequest(adminToken.Token, "DELETE", "/scim/v2/Users/"+hostID, nil)
+	require.ErrorContains(t, err, "403")
+
+	_, err = s.scimRequest(adminToken.Token, "DELETE", "/scim/v2/Users/"+johnID, nil)
+	require.NoError(t, err)
+	_, err = s.scimRequest(adminToken.Token, "GET", "/scim/v2/Users/"+johnID, nil)
+	require.ErrorContains(t, err, "404")
+}
+
+func (s *TestingServer) scimRequest(token, method, uri string, request any) ([]byte, error) {
+	header := map[string]string{
+		"Content-Type": "application/scim+json",
+	}
+	if token != "" {
+		header["Authorization"] = "Bearer " + token
+	}
+	var body *bytes.Reader
+	if request != nil {
+		rawData, err := json.Marshal(request)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal scim request")
+		}
+		body = bytes.NewReader(rawData)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	response, err := s.request(method, uri, body, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(response); err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *TestingServer) scimRequestJSON(token, method, uri string, request, response any) error {
+	data, err := s.scimRequest(token, method, uri, request)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), "urn:ietf:params:scim") {
+		return errors.Errorf("unexpected scim response %s", string(data))
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return errors.Wrap(err, "fail to unmarshal scim response")
+	}
+	return nil
+}
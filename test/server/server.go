@@ -1,6 +1,7 @@
 package testserver
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -17,6 +18,8 @@ import (
 	"github.com/usememos/memos/store"
 	"github.com/usememos/memos/store/db"
 	"github.com/usememos/memos/test"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
 	// sqlite driver.
 	_ "modernc.org/sqlite"
@@ -106,6 +109,10 @@ func (s *TestingServer) request(method, uri string, body io.Reader, params, head
 	for k, v := range header {
 		req.Header.Set(k, v)
 	}
+	// The client sends the host of the request instead of the header.
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
 
 	q := url.Values{}
 	for k, v := range params {
@@ -119,7 +126,7 @@ func (s *TestingServer) request(method, uri string, body io.Reader, params, head
 	if err != nil {
 		return nil, errors.Wrapf(err, "fail to send a %s request(%q)", method, fullURL)
 	}
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to read http response body")
@@ -127,23 +134,37 @@ func (s *TestingServer) request(method, uri string, body io.Reader, params, head
 		return nil, errors.Errorf("http response error code %v body %q", resp.StatusCode, string(body))
 	}
 
-	if method == "POST" {
-		if strings.Contains(uri, "/api/v1/auth/login") || strings.Contains(uri, "/api/v1/auth/signup") {
-			cookie := ""
-			h := resp.Header.Get("Set-Cookie")
-			parts := strings.Split(h, "; ")
-			for _, p := range parts {
-				if strings.HasPrefix(p, fmt.Sprintf("%s=", auth.AccessTokenCookieName)) {
-					cookie = p
-					break
+	// The sign-in of users with two-factor authentication is accepted without cookies until its second step.
+	if method == "POST" && resp.StatusCode == http.StatusOK {
+		if strings.Contains(uri, "/api/v1/auth/signin") || strings.Contains(uri, "/api/v1/auth/signup") || strings.Contains(uri, "/api/v1/auth/refresh") {
+			cookies := []string{}
+			for _, cookie := range resp.Cookies() {
+				if cookie.Name == auth.AccessTokenCookieName || cookie.Name == auth.RefreshTokenCookieName {
+					cookies = append(cookies, fmt.Sprintf("%s=%s", cookie.Name, cookie.Value))
 				}
 			}
-			if cookie == "" {
+			if len(cookies) == 0 {
 				return nil, errors.Errorf("unable to find access token in the login response headers")
 			}
-			s.cookie = cookie
+			s.cookie = strings.Join(cookies, "; ")
 		} else if strings.Contains(uri, "/api/v1/auth/signout") {
 			s.cookie = ""
+		} else {
+			for _, cookie := range resp.Cookies() {
+				if cookie.Name == auth.SSOStateCookieName || cookie.Name == auth.PasskeyChallengeCookieName {
+					// The state cookie replaces the one of the previous request, and is removed once used.
+					cookies := []string{}
+					for _, c := range strings.Split(s.cookie, "; ") {
+						if c != "" && !strings.HasPrefix(c, cookie.Name+"=") {
+							cookies = append(cookies, c)
+						}
+					}
+					if cookie.Value != "" {
+						cookies = append(cookies, fmt.Sprintf("%s=%s", cookie.Name, cookie.Value))
+					}
+					s.cookie = strings.Join(cookies, "; ")
+				}
+			}
 		}
 	}
 	return resp.Body, nil
@@ -176,3 +197,33 @@ func (s *TestingServer) delete(url string, params map[string]string) (io.ReadClo
 		"Cookie": s.cookie,
 	})
 }
+
+// requestV2 sends a client request to the gateway of API v2, with the request and response messages in JSON.
+func (s *TestingServer) requestV2(method, uri string, request, response proto.Message) error {
+	var body io.Reader
+	if request != nil {
+		rawData, err := protojson.Marshal(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+		body = bytes.NewReader(rawData)
+	}
+	respBody, err := s.request(method, uri, body, nil, map[string]string{
+		"Cookie": s.cookie,
+	})
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+	if response == nil {
+		return nil
+	}
+	rawData, err := io.ReadAll(respBody)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+	if err := protojson.Unmarshal(rawData, response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
+)
+
+func TestSessionServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	signUpCookie := s.cookie
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+
+	sessionList, err := s.getSessionList()
+	require.NoError(t, err)
+	require.Len(t, sessionList, 2)
+	require.True(t, sessionList[0].Current)
+	require.False(t, sessionList[1].Current)
+
+	// The refresh token is rotated, so the previous one can't be used anymore.
+	signInCookie := s.cookie
+	_, err = s.post("/api/v1/auth/refresh", nil, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, signInCookie, s.cookie)
+	refreshedCookie := s.cookie
+	s.cookie = signInCookie
+	_, err = s.post("/api/v1/auth/refresh", nil, nil)
+	require.ErrorContains(t, err, "401")
+	s.cookie = refreshedCookie
+	_, err = s.getCurrentUser()
+	require.NoError(t, err)
+
+	// The revoked session can't be used anymore.
+	_, err = s.delete(fmt.Sprintf("/api/v1/session/%d", sessionList[1].ID), nil)
+	require.NoError(t, err)
+	s.cookie = signUpCookie
+	_, err = s.getCurrentUser()
+	require.ErrorContains(t, err, "401")
+	s.cookie = refreshedCookie
+	sessionList, err = s.getSessionList()
+	require.NoError(t, err)
+	require.Len(t, sessionList, 1)
+
+	// Signing out revokes the session too.
+	err = s.postSignOut()
+	require.NoError(t, err)
+	s.cookie = refreshedCookie
+	_, err = s.getCurrentUser()
+	require.ErrorContains(t, err, "401")
+
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.delete("/api/v1/session", nil)
+	require.NoError(t, err)
+	_, err = s.getCurrentUser()
+	require.ErrorContains(t, err, "401")
+
+	// The previous refresh token reused after the grace period revokes the whole session.
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	signInCookie = s.cookie
+	_, err = s.post("/api/v1/auth/refresh", nil, nil)
+	require.NoError(t, err)
+	refreshedCookie = s.cookie
+	sessionList, err = s.getSessionList()
+	require.NoError(t, err)
+	require.Len(t, sessionList, 1)
+	updatedTs := time.Now().Add(-time.Minute).Unix()
+	err = s.server.Store.UpdateSession(ctx, &store.UpdateSession{
+		ID:        sessionList[0].ID,
+		UpdatedTs: &updatedTs,
+	})
+	require.NoError(t, err)
+	s.cookie = signInCookie
+	_, err = s.post("/api/v1/auth/refresh", nil, nil)
+	require.ErrorContains(t, err, "401")
+	s.cookie = refreshedCookie
+	_, err = s.getCurrentUser()
+	require.ErrorContains(t, err, "401")
+}
+
+func (s *TestingServer) getSessionList() ([]*apiv1.Session, error) {
+	body, err := s.get("/api/v1/session", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionList := []*apiv1.Session{}
+	if err = json.NewDecoder(body).Decode(&sessionList); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal get session list response")
+	}
+	return sessionList, nil
+}
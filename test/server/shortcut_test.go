@@ -0,0 +1,128 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+)
+
+func TestShortcutServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	for _, content := range []string{"#work meeting notes", "#work plan", "#life"} {
+		_, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+			Content: content,
+		})
+		require.NoError(t, err)
+	}
+
+	shortcut, err := s.postShortcutCreate(&apiv1.CreateShortcutRequest{
+		Title: "work",
+		Payload: &apiv1.ShortcutPayload{
+			Tags: []string{"work"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "work", shortcut.Title)
+	memoList, err := s.getShortcutMemoList(shortcut.ID)
+	require.NoError(t, err)
+	require.Len(t, memoList, 2)
+
+	shortcut, err = s.patchShortcut(shortcut.ID, &apiv1.UpdateShortcutRequest{
+		Payload: &apiv1.ShortcutPayload{
+			Tags: []string{"work"},
+			Text: "meeting",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "meeting", shortcut.Payload.Text)
+	memoList, err = s.getShortcutMemoList(shortcut.ID)
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	require.Equal(t, "#work meeting notes", memoList[0].Content)
+
+	_, err = s.delete(fmt.Sprintf("/api/v1/shortcut/%d", shortcut.ID), nil)
+	require.NoError(t, err)
+	_, err = s.getShortcutMemoList(shortcut.ID)
+	require.Error(t, err)
+}
+
+func (s *TestingServer) postShortcutCreate(request *apiv1.CreateShortcutRequest) (*apiv1.Shortcut, error) {
+	rawData, err := json.Marshal(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal shortcut create")
+	}
+	body, err := s.post("/api/v1/shortcut", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	shortcut := &apiv1.Shortcut{}
+	if err = json.Unmarshal(buf.Bytes(), shortcut); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post shortcut create response")
+	}
+	return shortcut, nil
+}
+
+func (s *TestingServer) patchShortcut(shortcutID int32, request *apiv1.UpdateShortcutRequest) (*apiv1.Shortcut, error) {
+	rawData, err := json.Marshal(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal shortcut patch")
+	}
+	body, err := s.patch(fmt.Sprintf("/api/v1/shortcut/%d", shortcutID), bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	shortcut := &apiv1.Shortcut{}
+	if err = json.Unmarshal(buf.Bytes(), shortcut); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal patch shortcut response")
+	}
+	return shortcut, nil
+}
+
+func (s *TestingServer) getShortcutMemoList(shortcutID int32) ([]*apiv1.Memo, error) {
+	body, err := s.get(fmt.Sprintf("/api/v1/shortcut/%d/memo", shortcutID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	memoList := []*apiv1.Memo{}
+	if err = json.Unmarshal(buf.Bytes(), &memoList); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal get shortcut memo list response")
+	}
+	return memoList, nil
+}
@@ -0,0 +1,87 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/api/auth"
+	apiv1 "github.com/usememos/memos/api/v1"
+)
+
+func TestSigningKeyServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	require.Equal(t, auth.KeyID, s.getAccessTokenKeyID(t))
+	signingKeyList, err := s.getSigningKeyList()
+	require.NoError(t, err)
+	require.Len(t, signingKeyList, 1)
+	require.Equal(t, auth.KeyID, signingKeyList[0].ID)
+	require.True(t, signingKeyList[0].Current)
+
+	// The new tokens are signed with the newest key, while the older ones remain valid.
+	_, err = s.post("/api/v1/system/signing-key", nil, nil)
+	require.NoError(t, err)
+	signUpCookie := s.cookie
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v2", s.getAccessTokenKeyID(t))
+	signInCookie := s.cookie
+	s.cookie = signUpCookie
+	_, err = s.getCurrentUser()
+	require.NoError(t, err)
+
+	// Retiring a key invalidates the tokens signed with it.
+	s.cookie = signInCookie
+	_, err = s.delete("/api/v1/system/signing-key/v1", nil)
+	require.NoError(t, err)
+	s.cookie = signUpCookie
+	_, err = s.getCurrentUser()
+	require.ErrorContains(t, err, "401")
+	s.cookie = signInCookie
+	_, err = s.getCurrentUser()
+	require.NoError(t, err)
+	_, err = s.delete("/api/v1/system/signing-key/v2", nil)
+	require.ErrorContains(t, err, "400")
+}
+
+func (s *TestingServer) getSigningKeyList() ([]*apiv1.SigningKey, error) {
+	body, err := s.get("/api/v1/system/signing-key", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKeyList := []*apiv1.SigningKey{}
+	if err = json.NewDecoder(body).Decode(&signingKeyList); err != nil {
+		return nil, err
+	}
+	return signingKeyList, nil
+}
+
+// getAccessTokenKeyID returns the kid header of the access token in the cookie.
+func (s *TestingServer) getAccessTokenKeyID(t *testing.T) string {
+	for _, cookie := range strings.Split(s.cookie, "; ") {
+		if token, ok := strings.CutPrefix(cookie, auth.AccessTokenCookieName+"="); ok {
+			parsedToken, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			kid, _ := parsedToken.Header["kid"].(string)
+			return kid
+		}
+	}
+	return ""
+}
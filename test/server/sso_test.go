@@ -0,0 +1,230 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+)
+
+func TestSSOServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	issuer := newMockOIDCIssuer(t, "test-client-id")
+	identityProvider, err := s.postIdentityProviderCreate(&apiv1.CreateIdentityProviderRequest{
+		Name: "Keycloak",
+		Type: apiv1.IdentityProviderOIDCType,
+		Config: &apiv1.IdentityProviderConfig{
+			OIDCConfig: &apiv1.IdentityProviderOIDCConfig{
+				Issuer:       issuer.URL,
+				ClientID:     "test-client-id",
+				ClientSecret: "test-client-secret",
+				Scopes:       []string{"profile"},
+				FieldMapping: &apiv1.FieldMapping{
+					Identifier:  "preferred_username",
+					DisplayName: "name",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiv1.IdentityProviderOIDCType, identityProvider.Type)
+	require.Nil(t, identityProvider.Config.OAuth2Config)
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	authorization, err := s.postAuthSSOAuthorize(&apiv1.SSOAuthorize{
+		IdentityProviderID: identityProvider.ID,
+		RedirectURI:        "http://localhost/auth/callback",
+	})
+	require.NoError(t, err)
+	state := issuer.authorize(t, authorization.AuthorizationURL)
+	require.Regexp(t, fmt.Sprintf(`^auth\.signin\..+-%d$`, identityProvider.ID), state)
+
+	// The sign-in is rejected with the state of another authorization request.
+	_, err = s.postAuthSignInSSO(&apiv1.SSOSignIn{
+		IdentityProviderID: identityProvider.ID,
+		Code:               "test-code",
+		State:              "auth.signin.forged-1",
+	})
+	require.ErrorContains(t, err, "400")
+	user, err := s.postAuthSignInSSO(&apiv1.SSOSignIn{
+		IdentityProviderID: identityProvider.ID,
+		Code:               "test-code",
+		State:              state,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "john", user.Username)
+	require.Equal(t, "John Doe", user.Nickname)
+	user, err = s.getCurrentUser()
+	require.NoError(t, err)
+	require.Equal(t, "john", user.Username)
+
+	// The authorization request can't be used without its cookie.
+	_, err = s.postAuthSignInSSO(&apiv1.SSOSignIn{
+		IdentityProviderID: identityProvider.ID,
+		Code:               "test-code",
+		State:              state,
+	})
+	require.ErrorContains(t, err, "400")
+}
+
+// mockOIDCIssuer is a local stand-in OpenID Provider, which issues ID tokens of john to the code "test-code".
+type mockOIDCIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+	// codeChallenge and nonce are the ones of the last authorization request.
+	codeChallenge string
+	nonce         string
+	// claims are added to the ID tokens, overriding the default ones.
+	claims map[string]any
+}
+
+func newMockOIDCIssuer(t *testing.T, clientID string) *mockOIDCIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m := &mockOIDCIssuer{
+		key: key,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		}))
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "key-1",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		}))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		codeVerifierSum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("code") != "test-code" || base64.RawURLEncoding.EncodeToString(codeVerifierSum[:]) != m.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":                m.URL,
+			"sub":                "248289761001",
+			"aud":                clientID,
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"nonce":              m.nonce,
+			"preferred_username": "john",
+			"name":               "John Doe",
+		}
+		for k, v := range m.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		idToken, err := token.SignedString(key)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "test-access-token",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		}))
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize records the authorization request and returns its state, as the authorization endpoint would do.
+func (m *mockOIDCIssuer) authorize(t *testing.T, authorizationURL string) string {
+	u, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	require.Equal(t, m.URL+"/authorize", fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path))
+	require.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	m.codeChallenge = u.Query().Get("code_challenge")
+	m.nonce = u.Query().Get("nonce")
+	return u.Query().Get("state")
+}
+
+func (s *TestingServer) postIdentityProviderCreate(create *apiv1.CreateIdentityProviderRequest) (*apiv1.IdentityProvider, error) {
+	rawData, err := json.Marshal(create)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal identity provider create")
+	}
+	body, err := s.post("/api/v1/idp", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	identityProvider := &apiv1.IdentityProvider{}
+	if err = json.NewDecoder(body).Decode(identityProvider); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post identity provider response")
+	}
+	return identityProvider, nil
+}
+
+func (s *TestingServer) postAuthSSOAuthorize(authorize *apiv1.SSOAuthorize) (*apiv1.SSOAuthorization, error) {
+	rawData, err := json.Marshal(authorize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal sso authorize")
+	}
+	body, err := s.post("/api/v1/auth/sso/authorize", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization := &apiv1.SSOAuthorization{}
+	if err = json.NewDecoder(body).Decode(authorization); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post sso authorize response")
+	}
+	return authorization, nil
+}
+
+func (s *TestingServer) postAuthSignInSSO(signin *apiv1.SSOSignIn) (*apiv1.User, error) {
+	rawData, err := json.Marshal(signin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal sso signin")
+	}
+	body, err := s.post("/api/v1/auth/signin/sso", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &apiv1.User{}
+	if err = json.NewDecoder(body).Decode(user); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post sso signin response")
+	}
+	return user, nil
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/store"
 )
 
 func TestSystemServer(t *testing.T) {
@@ -33,6 +35,15 @@ func TestSystemServer(t *testing.T) {
 	status, err = s.getSystemStatus()
 	require.NoError(t, err)
 	require.Equal(t, user.ID, status.Host.ID)
+
+	// The tags of the existing memos are indexed once.
+	backfilledSetting, err := s.server.Store.GetSystemSetting(ctx, &store.FindSystemSetting{
+		Name: apiv1.SystemSettingMemoTagsBackfilledName.String(),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, backfilledSetting)
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"memo-tags-backfilled","value":"false"}`), nil)
+	require.ErrorContains(t, err, "400")
 }
 
 func (s *TestingServer) pingSystem() error {
@@ -0,0 +1,88 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+)
+
+func TestTagRenameServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	_, err = s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	memo, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "#work #work/clientA meeting",
+	})
+	require.NoError(t, err)
+	other, err := s.postMemoCreate(&apiv1.CreateMemoRequest{
+		Content: "#todo #later list",
+	})
+	require.NoError(t, err)
+
+	response, err := s.postTagAction("/api/v1/tag/rename", &apiv1.RenameTagRequest{
+		Name:    "work",
+		NewName: "job",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, response.MemoCount)
+	memo, err = s.getMemo(memo.ID)
+	require.NoError(t, err)
+	require.Equal(t, "#job #job/clientA meeting", memo.Content)
+
+	response, err = s.postTagAction("/api/v1/tag/merge", &apiv1.MergeTagsRequest{
+		NameList: []string{"todo", "later"},
+		NewName:  "backlog",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, response.MemoCount)
+	other, err = s.getMemo(other.ID)
+	require.NoError(t, err)
+	require.Equal(t, "#backlog #backlog list", other.Content)
+
+	_, err = s.postTagAction("/api/v1/tag/rename", &apiv1.RenameTagRequest{
+		Name:    "job",
+		NewName: "two words",
+	})
+	require.Error(t, err)
+
+	// Each rename or merge is recorded as a single activity.
+	var count int
+	err = s.server.Store.GetDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM activity WHERE type = ?", apiv1.ActivityTagRename.String()).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func (s *TestingServer) postTagAction(url string, request any) (*apiv1.RenameTagResponse, error) {
+	rawData, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal tag request")
+	}
+	body, err := s.post(url, bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = buf.ReadFrom(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read response body")
+	}
+
+	response := &apiv1.RenameTagResponse{}
+	if err = json.Unmarshal(buf.Bytes(), response); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal post tag response")
+	}
+	return response, nil
+}
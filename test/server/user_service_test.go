@@ -0,0 +1,215 @@
+package testserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	apiv2pb "github.com/usememos/memos/proto/gen/api/v2"
+)
+
+func TestUserServiceServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	host, err := s.postAuthSignUp(&apiv1.SignUp{
+		Username: "testhost",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	admin, err := s.createUserV2(&apiv2pb.User{
+		Username: "testadmin",
+		Role:     apiv2pb.Role_ADMIN,
+		Email:    "testadmin@example.com",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	require.Equal(t, apiv2pb.Role_ADMIN, admin.Role)
+	require.Empty(t, admin.OpenId)
+	user, err := s.createUserV2(&apiv2pb.User{
+		Username: "testuser",
+		Role:     apiv2pb.Role_USER,
+		Email:    "testuser@example.com",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.createUserV2(&apiv2pb.User{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.ErrorContains(t, err, "username testuser is already taken")
+	_, err = s.createUserV2(&apiv2pb.User{
+		Username: "anotherhost",
+		Role:     apiv2pb.Role_HOST,
+		Password: "testpassword",
+	})
+	require.ErrorContains(t, err, "could not create host user")
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// Admins manage the users no more than in API v1, where only the host manages the others.
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testadmin",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	_, err = s.createUserV2(&apiv2pb.User{
+		Username: "anotheruser",
+		Password: "testpassword",
+	})
+	require.ErrorContains(t, err, "only host can create users")
+	_, err = s.updateUserV2(&apiv2pb.User{
+		Id:       user.Id,
+		Nickname: "changed by admin",
+	}, "nickname")
+	require.ErrorContains(t, err, "403")
+	_, err = s.updateUserV2(&apiv2pb.User{
+		Id:        user.Id,
+		RowStatus: apiv2pb.RowStatus_ARCHIVED,
+	}, "row_status")
+	require.ErrorContains(t, err, "403")
+	err = s.requestV2("DELETE", fmt.Sprintf("/api/v2/users/%d", user.Id), nil, nil)
+	require.ErrorContains(t, err, "403")
+	updatedAdmin, err := s.updateUserV2(&apiv2pb.User{
+		Id:       admin.Id,
+		Nickname: "Admin",
+	}, "nickname")
+	require.NoError(t, err)
+	require.Equal(t, "Admin", updatedAdmin.Nickname)
+	_, err = s.updateUserV2(&apiv2pb.User{
+		Id:   admin.Id,
+		Role: apiv2pb.Role_HOST,
+	}, "role")
+	require.ErrorContains(t, err, "only host can change the role of admins and users")
+	_, err = s.updateUserV2(&apiv2pb.User{
+		Id:     admin.Id,
+		OpenId: "changed",
+	}, "open_id")
+	require.ErrorContains(t, err, "invalid update path: open_id")
+
+	// The open IDs of the other users are hidden, and their emails are only shown to admins.
+	users, err := s.listUsersV2()
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	for _, listedUser := range users {
+		if listedUser.Id != admin.Id {
+			require.Empty(t, listedUser.OpenId)
+		}
+		if listedUser.Id == user.Id {
+			require.Equal(t, "testuser@example.com", listedUser.Email)
+		}
+	}
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	users, err = s.listUsersV2()
+	require.NoError(t, err)
+	for _, listedUser := range users {
+		if listedUser.Id != user.Id {
+			require.Empty(t, listedUser.Email)
+		}
+	}
+
+	// The settings are only visible to their user, and saved only if all of them are valid.
+	err = s.requestV2("GET", fmt.Sprintf("/api/v2/users/%d/settings", admin.Id), nil, &apiv2pb.GetUserSettingsResponse{})
+	require.ErrorContains(t, err, "403")
+	settings := []*apiv2pb.UserSetting{
+		{
+			Key:   apiv2pb.UserSetting_LOCALE,
+			Value: &apiv2pb.UserSettingValue{Value: &apiv2pb.UserSettingValue_StringValue{StringValue: "de"}},
+		},
+		{
+			Key:   apiv2pb.UserSetting_APPEARANCE,
+			Value: &apiv2pb.UserSettingValue{Value: &apiv2pb.UserSettingValue_StringValue{StringValue: "purple"}},
+		},
+	}
+	err = s.requestV2("PATCH", fmt.Sprintf("/api/v2/users/%d/settings", user.Id), &apiv2pb.UpdateUserSettingsRequest{
+		Settings: settings,
+	}, nil)
+	require.ErrorContains(t, err, "invalid user setting appearance value")
+	settingList := &apiv2pb.GetUserSettingsResponse{}
+	err = s.requestV2("GET", fmt.Sprintf("/api/v2/users/%d/settings", user.Id), nil, settingList)
+	require.NoError(t, err)
+	require.Empty(t, settingList.Settings)
+	settings[1] = &apiv2pb.UserSetting{
+		Key:   apiv2pb.UserSetting_MEMO_VISIBILITY,
+		Value: &apiv2pb.UserSettingValue{Value: &apiv2pb.UserSettingValue_VisibilityValue{VisibilityValue: apiv2pb.Visibility_PROTECTED}},
+	}
+	err = s.requestV2("PATCH", fmt.Sprintf("/api/v2/users/%d/settings", user.Id), &apiv2pb.UpdateUserSettingsRequest{
+		Settings: settings,
+	}, nil)
+	require.NoError(t, err)
+	err = s.requestV2("GET", fmt.Sprintf("/api/v2/users/%d/settings", user.Id), nil, settingList)
+	require.NoError(t, err)
+	require.Len(t, settingList.Settings, 2)
+	for _, setting := range settingList.Settings {
+		switch setting.Key {
+		case apiv2pb.UserSetting_LOCALE:
+			require.Equal(t, "de", setting.Value.GetStringValue())
+		case apiv2pb.UserSetting_MEMO_VISIBILITY:
+			require.Equal(t, apiv2pb.Visibility_PROTECTED, setting.Value.GetVisibilityValue())
+		default:
+			require.Fail(t, "unexpected user setting", setting.Key.String())
+		}
+	}
+	err = s.postSignOut()
+	require.NoError(t, err)
+
+	// The host manages everyone except itself.
+	_, err = s.postAuthSignIn(&apiv1.SignIn{
+		Username: "testhost",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	updatedUser, err := s.updateUserV2(&apiv2pb.User{
+		Id:        user.Id,
+		Nickname:  "Test User",
+		Role:      apiv2pb.Role_ADMIN,
+		RowStatus: apiv2pb.RowStatus_ARCHIVED,
+	}, "nickname", "role", "row_status")
+	require.NoError(t, err)
+	require.Equal(t, "Test User", updatedUser.Nickname)
+	require.Equal(t, apiv2pb.Role_ADMIN, updatedUser.Role)
+	require.Equal(t, apiv2pb.RowStatus_ARCHIVED, updatedUser.RowStatus)
+	err = s.requestV2("DELETE", fmt.Sprintf("/api/v2/users/%d", host.ID), nil, nil)
+	require.ErrorContains(t, err, "403")
+	err = s.requestV2("DELETE", fmt.Sprintf("/api/v2/users/%d", user.Id), nil, nil)
+	require.NoError(t, err)
+	err = s.requestV2("GET", "/api/v2/users/testuser", nil, &apiv2pb.GetUserResponse{})
+	require.ErrorContains(t, err, "404")
+}
+
+func (s *TestingServer) createUserV2(user *apiv2pb.User) (*apiv2pb.User, error) {
+	response := &apiv2pb.CreateUserResponse{}
+	if err := s.requestV2("POST", "/api/v2/users", user, response); err != nil {
+		return nil, err
+	}
+	return response.User, nil
+}
+
+func (s *TestingServer) listUsersV2() ([]*apiv2pb.User, error) {
+	response := &apiv2pb.ListUsersResponse{}
+	if err := s.requestV2("GET", "/api/v2/users", nil, response); err != nil {
+		return nil, err
+	}
+	return response.Users, nil
+}
+
+func (s *TestingServer) updateUserV2(user *apiv2pb.User, paths ...string) (*apiv2pb.User, error) {
+	response := &apiv2pb.UpdateUserResponse{}
+	uri := fmt.Sprintf("/api/v2/users/%d?updateMask=%s", user.Id, strings.Join(paths, ","))
+	if err := s.requestV2("PATCH", uri, user, response); err != nil {
+		return nil, err
+	}
+	return response.User, nil
+}
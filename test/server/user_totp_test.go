@@ -0,0 +1,153 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "github.com/usememos/memos/api/v1"
+	"github.com/usememos/memos/plugin/totp"
+)
+
+func TestUserTOTPServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewTestingServer(ctx, t)
+	require.NoError(t, err)
+	defer s.Shutdown(ctx)
+
+	signup := &apiv1.SignUp{
+		Username: "testuser",
+		Password: "testpassword",
+	}
+	_, err = s.postAuthSignUp(signup)
+	require.NoError(t, err)
+	body, err := s.post("/api/v1/user/me/totp", nil, nil)
+	require.NoError(t, err)
+	enrollment := &apiv1.UserTOTPEnrollment{}
+	require.NoError(t, json.NewDecoder(body).Decode(enrollment))
+	require.True(t, strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/memos:testuser?"))
+
+	_, err = s.post("/api/v1/user/me/totp/enable", strings.NewReader(`{"code":"000000"}`), nil)
+	require.ErrorContains(t, err, "400")
+	step := time.Now().Unix() / totp.Period
+	recoveryCodes, err := s.postUserTOTPEnable(enrollment.Secret, step)
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes.RecoveryCodes, 10)
+
+	// The password sign-in becomes a two-step flow.
+	err = s.postSignOut()
+	require.NoError(t, err)
+	signInBody, err := json.Marshal(&apiv1.SignIn{
+		Username: "testuser",
+		Password: "testpassword",
+	})
+	require.NoError(t, err)
+	body, err = s.post("/api/v1/auth/signin", bytes.NewReader(signInBody), nil)
+	require.NoError(t, err)
+	challenge := &apiv1.TwoFactorChallenge{}
+	require.NoError(t, json.NewDecoder(body).Decode(challenge))
+	require.NotEmpty(t, challenge.TwoFactorToken)
+	require.Empty(t, s.cookie)
+	// The code confirmed by the enrollment can't be replayed.
+	code, err := totp.GenerateCode(enrollment.Secret, step)
+	require.NoError(t, err)
+	_, err = s.postAuthSignInTOTP(challenge.TwoFactorToken, code)
+	require.ErrorContains(t, err, "401")
+	code, err = totp.GenerateCode(enrollment.Secret, step+1)
+	require.NoError(t, err)
+	_, err = s.postAuthSignInTOTP(challenge.TwoFactorToken, code)
+	require.NoError(t, err)
+	_, err = s.getCurrentUser()
+	require.NoError(t, err)
+
+	// Recovery codes can only be used once.
+	_, err = s.postAuthSignInTOTP(challenge.TwoFactorToken, recoveryCodes.RecoveryCodes[0])
+	require.NoError(t, err)
+	_, err = s.postAuthSignInTOTP(challenge.TwoFactorToken, recoveryCodes.RecoveryCodes[0])
+	require.ErrorContains(t, err, "401")
+	body, err = s.get("/api/v1/user/me/totp", nil)
+	require.NoError(t, err)
+	userTOTP := &apiv1.UserTOTP{}
+	require.NoError(t, json.NewDecoder(body).Decode(userTOTP))
+	require.True(t, userTOTP.Enabled)
+	require.Equal(t, 9, userTOTP.RecoveryCodesLeft)
+
+	// The host has to enable two-factor authentication again once it's required.
+	_, err = s.post("/api/v1/system/setting", strings.NewReader(`{"name":"require-admin-two-factor","value":"true"}`), nil)
+	require.NoError(t, err)
+	_, err = s.post("/api/v1/user/me/totp/disable", strings.NewReader(`{"code":"`+recoveryCodes.RecoveryCodes[1]+`"}`), nil)
+	require.NoError(t, err)
+	_, err = s.get("/api/v1/memo", nil)
+	require.ErrorContains(t, err, "403")
+	_, err = s.getCurrentUser()
+	require.NoError(t, err)
+
+	// The codes are refused after too many incorrect ones, even the correct one.
+	body, err = s.post("/api/v1/user/me/totp", nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(body).Decode(enrollment))
+	step = time.Now().Unix() / totp.Period
+	_, err = s.postUserTOTPEnable(enrollment.Secret, step)
+	require.NoError(t, err)
+	err = s.postSignOut()
+	require.NoError(t, err)
+	body, err = s.post("/api/v1/auth/signin", bytes.NewReader(signInBody), nil)
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(body).Decode(challenge))
+	for i := 0; i < 5; i++ {
+		_, err = s.postAuthSignInTOTP(challenge.TwoFactorToken, "incorrect")
+		require.ErrorContains(t, err, "401")
+	}
+	code, err = totp.GenerateCode(enrollment.Secret, step+1)
+	require.NoError(t, err)
+	_, err = s.postAuthSignInTOTP(challenge.TwoFactorToken, code)
+	require.ErrorContains(t, err, "429")
+	require.Empty(t, s.cookie)
+}
+
+func (s *TestingServer) postUserTOTPEnable(secret string, step int64) (*apiv1.UserTOTPRecoveryCodes, error) {
+	code, err := totp.GenerateCode(secret, step)
+	if err != nil {
+		return nil, err
+	}
+	rawData, err := json.Marshal(&apiv1.UserTOTPCodeRequest{
+		Code: code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.post("/api/v1/user/me/totp/enable", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := &apiv1.UserTOTPRecoveryCodes{}
+	if err = json.NewDecoder(body).Decode(recoveryCodes); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+func (s *TestingServer) postAuthSignInTOTP(twoFactorToken, code string) (*apiv1.User, error) {
+	rawData, err := json.Marshal(&apiv1.TOTPSignIn{
+		TwoFactorToken: twoFactorToken,
+		Code:           code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.post("/api/v1/auth/signin/totp", bytes.NewReader(rawData), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &apiv1.User{}
+	if err = json.NewDecoder(body).Decode(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
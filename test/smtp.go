@@ -0,0 +1,209 @@
+package test
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SMTPMessage is an email received by the stand-in SMTP server.
+type SMTPMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Header returns the header of the message.
+func (m *SMTPMessage) Header(key string) string {
+	message, err := mail.ReadMessage(strings.NewReader(m.Data))
+	if err != nil {
+		return ""
+	}
+	return message.Header.Get(key)
+}
+
+// Body returns the decoded body of the message.
+func (m *SMTPMessage) Body() string {
+	message, err := mail.ReadMessage(strings.NewReader(m.Data))
+	if err != nil {
+		return ""
+	}
+	var reader io.Reader = message.Body
+	if strings.EqualFold(message.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		reader = quotedprintable.NewReader(reader)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return ""
+	}
+	return strings.ReplaceAll(string(body), "\r\n", "\n")
+}
+
+// SMTPServer is an in-process stand-in SMTP server, which supports plain authentication
+// and keeps the received messages in memory.
+type SMTPServer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	mutex    sync.Mutex
+	messages []*SMTPMessage
+}
+
+// NewSMTPServer starts a stand-in SMTP server, which is stopped when the test finishes.
+// The server requires authentication if the username is not empty.
+func NewSMTPServer(t *testing.T, username, password string) *SMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	addr := listener.Addr().(*net.TCPAddr)
+	s := &SMTPServer{
+		Host:     addr.IP.String(),
+		Port:     addr.Port,
+		Username: username,
+		Password: password,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Messages returns the received messages.
+func (s *SMTPServer) Messages() []*SMTPMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*SMTPMessage{}, s.messages...)
+}
+
+// LastMessage returns the last received message, or nil if no message is received.
+func (s *SMTPServer) LastMessage() *SMTPMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.messages) == 0 {
+		return nil
+	}
+	return s.messages[len(s.messages)-1]
+}
+
+func (s *SMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(code int, message string) {
+		fmt.Fprintf(conn, "%d %s\r\n", code, message)
+	}
+
+	reply(220, "localhost ESMTP stand-in")
+	authenticated := s.Username == ""
+	var message *SMTPMessage
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			if s.Username != "" {
+				fmt.Fprint(conn, "250-localhost\r\n250 AUTH PLAIN\r\n")
+			} else {
+				reply(250, "localhost")
+			}
+		case "HELO":
+			reply(250, "localhost")
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(argument, " ")
+			if !strings.EqualFold(mechanism, "PLAIN") {
+				reply(504, "unrecognized authentication type")
+				continue
+			}
+			credentials, err := base64.StdEncoding.DecodeString(initial)
+			if err != nil {
+				reply(501, "invalid credentials")
+				continue
+			}
+			parts := strings.Split(string(credentials), "\x00")
+			if len(parts) != 3 || parts[1] != s.Username || parts[2] != s.Password {
+				reply(535, "authentication failed")
+				continue
+			}
+			authenticated = true
+			reply(235, "authentication succeeded")
+		case "MAIL":
+			if !authenticated {
+				reply(530, "authentication required")
+				continue
+			}
+			message = &SMTPMessage{From: parsePath(argument)}
+			reply(250, "ok")
+		case "RCPT":
+			if message == nil {
+				reply(503, "need MAIL command")
+				continue
+			}
+			message.To = append(message.To, parsePath(argument))
+			reply(250, "ok")
+		case "DATA":
+			if message == nil || len(message.To) == 0 {
+				reply(503, "need RCPT command")
+				continue
+			}
+			reply(354, "end data with <CR><LF>.<CR><LF>")
+			data := &strings.Builder{}
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			message.Data = data.String()
+			s.mutex.Lock()
+			s.messages = append(s.messages, message)
+			s.mutex.Unlock()
+			message = nil
+			reply(250, "ok: queued as "+strconv.Itoa(len(s.Messages())))
+		case "RSET":
+			message = nil
+			reply(250, "ok")
+		case "NOOP":
+			reply(250, "ok")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+// parsePath returns the address of a "FROM:<address>" or "TO:<address>" argument.
+func parsePath(argument string) string {
+	_, path, _ := strings.Cut(argument, ":")
+	path, _, _ = strings.Cut(strings.TrimSpace(path), " ")
+	return strings.Trim(path, "<>")
+}
@@ -0,0 +1,69 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestAccessTokenStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	accessToken, err := ts.CreateAccessToken(ctx, &store.AccessToken{
+		UserID:    user.ID,
+		Name:      "script",
+		TokenHash: "hash",
+		Scopes:    []string{"memo.read", "memo.write"},
+		ExpiresTs: 1700000000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), accessToken.LastUsedTs)
+
+	lastUsedTs := int64(1690000000)
+	err = ts.UpdateAccessToken(ctx, &store.UpdateAccessToken{
+		ID:         accessToken.ID,
+		LastUsedTs: &lastUsedTs,
+	})
+	require.NoError(t, err)
+	tokenHash := "hash"
+	accessToken, err = ts.GetAccessToken(ctx, &store.FindAccessToken{
+		TokenHash: &tokenHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"memo.read", "memo.write"}, accessToken.Scopes)
+	require.Equal(t, lastUsedTs, accessToken.LastUsedTs)
+
+	err = ts.DeleteAccessToken(ctx, &store.DeleteAccessToken{
+		ID: &accessToken.ID,
+	})
+	require.NoError(t, err)
+	accessTokenList, err := ts.ListAccessTokens(ctx, &store.FindAccessToken{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, accessTokenList, 0)
+
+	// All the access tokens of a user are deleted at once.
+	for _, tokenHash := range []string{"hash1", "hash2"} {
+		_, err = ts.CreateAccessToken(ctx, &store.AccessToken{
+			UserID:    user.ID,
+			Name:      "script",
+			TokenHash: tokenHash,
+			ExpiresTs: 1700000000,
+		})
+		require.NoError(t, err)
+	}
+	err = ts.DeleteAccessToken(ctx, &store.DeleteAccessToken{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	accessTokenList, err = ts.ListAccessTokens(ctx, &store.FindAccessToken{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, accessTokenList, 0)
+}
@@ -0,0 +1,120 @@
+package teststore
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/server/archive"
+	"github.com/usememos/memos/store"
+)
+
+func TestArchiveExportImport(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	resource, err := ts.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "hello.txt",
+		Blob:      []byte("hello world"),
+		Type:      "text/plain",
+		Size:      11,
+	})
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		CreatedTs:  1690000000,
+		Content:    "first memo #work #ideas",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoResource(ctx, &store.UpsertMemoResource{
+		MemoID:     memo.ID,
+		ResourceID: resource.ID,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: memo.ID,
+		UserID: user.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	relatedMemo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "---\nsecond memo\n",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	archived := store.Archived
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        relatedMemo.ID,
+		RowStatus: &archived,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoRelation(ctx, &store.MemoRelation{
+		MemoID:        memo.ID,
+		RelatedMemoID: relatedMemo.ID,
+		Type:          store.MemoRelationReference,
+	})
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	exportResult, err := archive.Export(ctx, ts, buf, []*store.User{user})
+	require.NoError(t, err)
+	require.Equal(t, 1, exportResult.UserCount)
+	require.Equal(t, 2, exportResult.MemoCount)
+	require.Equal(t, 1, exportResult.ResourceCount)
+
+	// Import into another instance, in which the IDs are taken by other memos.
+	targetStore := NewTestingStore(ctx, t)
+	targetUser, err := createTestingHostUser(ctx, targetStore)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := targetStore.CreateMemo(ctx, &store.Memo{
+			CreatorID:  targetUser.ID,
+			Content:    "existing memo",
+			Visibility: store.Private,
+		})
+		require.NoError(t, err)
+	}
+	importResult, err := archive.Import(ctx, targetStore, bytes.NewReader(buf.Bytes()), int64(buf.Len()), &archive.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, importResult.MemoCount)
+	require.Equal(t, 1, importResult.ResourceCount)
+	require.Equal(t, 1, importResult.RelationCount)
+
+	importedMemoID := importResult.MemoIDMap[memo.ID]
+	importedMemo, err := targetStore.GetMemo(ctx, &store.FindMemo{ID: &importedMemoID})
+	require.NoError(t, err)
+	require.NotEqual(t, memo.ID, importedMemo.ID)
+	require.Equal(t, targetUser.ID, importedMemo.CreatorID)
+	require.Equal(t, memo.Content, importedMemo.Content)
+	require.Equal(t, store.Public, importedMemo.Visibility)
+	require.Equal(t, int64(1690000000), importedMemo.CreatedTs)
+	require.True(t, importedMemo.Pinned)
+	require.Len(t, importedMemo.RelationList, 1)
+	require.Equal(t, importResult.MemoIDMap[relatedMemo.ID], importedMemo.RelationList[0].RelatedMemoID)
+	require.Len(t, importedMemo.ResourceIDList, 1)
+
+	importedResource, err := targetStore.GetResource(ctx, &store.FindResource{ID: &importedMemo.ResourceIDList[0], GetBlob: true})
+	require.NoError(t, err)
+	require.Equal(t, "hello.txt", importedResource.Filename)
+	require.Equal(t, []byte("hello world"), importedResource.Blob)
+
+	importedRelatedMemoID := importResult.MemoIDMap[relatedMemo.ID]
+	importedRelatedMemo, err := targetStore.GetMemo(ctx, &store.FindMemo{ID: &importedRelatedMemoID})
+	require.NoError(t, err)
+	require.Equal(t, "---\nsecond memo\n", importedRelatedMemo.Content)
+	require.Equal(t, store.Archived, importedRelatedMemo.RowStatus)
+
+	tags, err := targetStore.ListTags(ctx, &store.FindTag{CreatorID: targetUser.ID})
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+
+	// Memos can't be imported into a missing user.
+	_, err = archive.Import(ctx, targetStore, bytes.NewReader(buf.Bytes()), int64(buf.Len()), &archive.ImportOptions{Username: "nobody"})
+	require.Error(t, err)
+}
@@ -54,3 +54,100 @@ func TestIdentityProviderStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 0, len(idpList))
 }
+
+func TestOIDCIdentityProviderStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	createdIDP, err := ts.CreateIdentityProvider(ctx, &store.IdentityProvider{
+		Name:             "Keycloak",
+		Type:             store.IdentityProviderOIDCType,
+		IdentifierFilter: "",
+		Config: &store.IdentityProviderConfig{
+			OIDCConfig: &store.IdentityProviderOIDCConfig{
+				Issuer:       "https://keycloak.example.com/realms/memos",
+				ClientID:     "client_id",
+				ClientSecret: "client_secret",
+				Scopes:       []string{"openid", "profile", "email"},
+				FieldMapping: &store.FieldMapping{
+					Identifier:  "preferred_username",
+					DisplayName: "name",
+					Email:       "email",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	idp, err := ts.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &createdIDP.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, createdIDP, idp)
+	require.Nil(t, idp.Config.OAuth2Config)
+	newIssuer := "https://keycloak.example.com/realms/notes"
+	updatedIdp, err := ts.UpdateIdentityProvider(ctx, &store.UpdateIdentityProvider{
+		ID:   idp.ID,
+		Type: store.IdentityProviderOIDCType,
+		Config: &store.IdentityProviderConfig{
+			OIDCConfig: &store.IdentityProviderOIDCConfig{
+				Issuer:   newIssuer,
+				ClientID: "client_id",
+				FieldMapping: &store.FieldMapping{
+					Identifier: "sub",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, newIssuer, updatedIdp.Config.OIDCConfig.Issuer)
+	idp, err = ts.GetIdentityProvider(ctx, &store.FindIdentityProvider{
+		ID: &createdIDP.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, updatedIdp, idp)
+}
+
+func TestIdentityProviderProvisioningStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	createdIDP, err := ts.CreateIdentityProvider(ctx, &store.IdentityProvider{
+		Name: "Keycloak",
+		Type: store.IdentityProviderOIDCType,
+		Config: &store.IdentityProviderConfig{
+			OIDCConfig: &store.IdentityProviderOIDCConfig{
+				Issuer:   "https://keycloak.example.com/realms/memos",
+				ClientID: "client_id",
+				FieldMapping: &store.FieldMapping{
+					Identifier: "preferred_username",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &store.IdentityProviderProvisioning{}, createdIDP.Provisioning)
+
+	provisioning := &store.IdentityProviderProvisioning{
+		DisableSignUp: true,
+		SyncProfile:   true,
+		RoleMappings: []*store.IdentityProviderRoleMapping{
+			{
+				Claim:   "groups",
+				Pattern: "^admins$",
+				Role:    store.RoleAdmin,
+			},
+		},
+		ArchiveRule: &store.IdentityProviderClaimRule{
+			Claim:   "active",
+			Pattern: "^false$",
+		},
+	}
+	updatedIdp, err := ts.UpdateIdentityProvider(ctx, &store.UpdateIdentityProvider{
+		ID:           createdIDP.ID,
+		Provisioning: provisioning,
+	})
+	require.NoError(t, err)
+	require.Equal(t, provisioning, updatedIdp.Provisioning)
+	idpList, err := ts.ListIdentityProviders(ctx, &store.FindIdentityProvider{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(idpList))
+	require.Equal(t, provisioning, idpList[0].Provisioning)
+}
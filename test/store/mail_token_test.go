@@ -0,0 +1,68 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestMailTokenStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	mailToken, err := ts.CreateMailToken(ctx, &store.MailToken{
+		UserID:    user.ID,
+		Type:      store.MailTokenPasswordReset,
+		TokenHash: "hash",
+		Email:     "test@example.com",
+		ExpiresTs: 1700000000,
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateMailToken(ctx, &store.MailToken{
+		UserID:    user.ID,
+		Type:      store.MailTokenEmailVerification,
+		TokenHash: "another-hash",
+		Email:     "test@example.com",
+		ExpiresTs: 1700000000,
+	})
+	require.NoError(t, err)
+
+	tokenHash, tokenType := "hash", store.MailTokenPasswordReset
+	found, err := ts.GetMailToken(ctx, &store.FindMailToken{
+		Type:      &tokenType,
+		TokenHash: &tokenHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, mailToken.ID, found.ID)
+	require.Equal(t, "test@example.com", found.Email)
+	require.Equal(t, int64(1700000000), found.ExpiresTs)
+
+	// The token is deleted once.
+	deleted, err := ts.DeleteMailToken(ctx, &store.DeleteMailToken{
+		ID: &mailToken.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	deleted, err = ts.DeleteMailToken(ctx, &store.DeleteMailToken{
+		ID: &mailToken.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), deleted)
+	mailTokenList, err := ts.ListMailTokens(ctx, &store.FindMailToken{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, mailTokenList, 1)
+	require.Equal(t, store.MailTokenEmailVerification, mailTokenList[0].Type)
+
+	verifiedEmail := "test@example.com"
+	user, err = ts.UpdateUser(ctx, &store.UpdateUser{
+		ID:            user.ID,
+		VerifiedEmail: &verifiedEmail,
+	})
+	require.NoError(t, err)
+	require.Equal(t, verifiedEmail, user.VerifiedEmail)
+}
@@ -0,0 +1,65 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestMemoRevisionStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	memoRevisionList, err := ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoRevisionList))
+	require.Equal(t, "test_content", memoRevisionList[0].Content)
+
+	content, visibility := "test_content_2", store.Public
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:         memo.ID,
+		Visibility: &visibility,
+	})
+	require.NoError(t, err)
+	// Unchanged content doesn't make a new revision.
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	memoRevisionList, err = ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(memoRevisionList))
+	require.Equal(t, content, memoRevisionList[0].Content)
+	require.Equal(t, store.Public, memoRevisionList[0].Visibility)
+	require.Equal(t, content, memoRevisionList[1].Content)
+	require.Equal(t, store.Private, memoRevisionList[1].Visibility)
+
+	err = ts.DeleteMemo(ctx, &store.DeleteMemo{
+		ID: memo.ID,
+	})
+	require.NoError(t, err)
+	memoRevisionList, err = ts.ListMemoRevisions(ctx, &store.FindMemoRevision{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoRevisionList))
+}
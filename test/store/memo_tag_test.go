@@ -0,0 +1,223 @@
+package teststore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestMemoTagStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#work #ideas first memo",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "second memo #work",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	memoTags, err := ts.ListMemoTags(ctx, &store.FindMemoTag{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.MemoTag{{MemoID: memo.ID, Name: "ideas"}, {MemoID: memo.ID, Name: "work"}}, memoTags)
+
+	tagCounts, err := ts.ListTagCounts(ctx, &store.FindMemoTag{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.TagCount{{Name: "ideas", Count: 1}, {Name: "work", Count: 2}}, tagCounts)
+	memoList, err := ts.ListMemos(ctx, &store.FindMemo{TagList: []string{"work"}})
+	require.NoError(t, err)
+	require.Len(t, memoList, 2)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{TagList: []string{"work", "ideas"}})
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	require.Equal(t, memo.ID, memoList[0].ID)
+
+	// The tags are replaced when the content is updated.
+	content := "first memo #reading"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memo.ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	memoTags, err = ts.ListMemoTags(ctx, &store.FindMemoTag{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.MemoTag{{MemoID: memo.ID, Name: "reading"}}, memoTags)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{TagList: []string{"ideas"}})
+	require.NoError(t, err)
+	require.Len(t, memoList, 0)
+
+	// Memos in trash aren't counted.
+	deletedTs := time.Now().Unix()
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
+	})
+	require.NoError(t, err)
+	tagCounts, err = ts.ListTagCounts(ctx, &store.FindMemoTag{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.TagCount{{Name: "work", Count: 1}}, tagCounts)
+
+	err = ts.DeleteMemo(ctx, &store.DeleteMemo{ID: memo.ID})
+	require.NoError(t, err)
+	var count int
+	err = ts.GetDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM memo_tag WHERE memo_id = ?", memo.ID).Scan(&count)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestBackfillMemoTags(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#work memo",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+
+	// Memos created before the memo_tag table have no tags indexed.
+	_, err = ts.GetDB().ExecContext(ctx, "DELETE FROM memo_tag")
+	require.NoError(t, err)
+	err = ts.BackfillMemoTags(ctx)
+	require.NoError(t, err)
+	memoTags, err := ts.ListMemoTags(ctx, &store.FindMemoTag{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.MemoTag{{MemoID: memo.ID, Name: "work"}}, memoTags)
+}
+
+func TestTagTree(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#work/clientA #work/clientB first memo",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#work/clientA/urgent #ideas second memo",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#workout third memo",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+
+	// Filtering by a tag matches its descendants, but not the tags with the same prefix.
+	memoList, err := ts.ListMemos(ctx, &store.FindMemo{TagList: []string{"work"}})
+	require.NoError(t, err)
+	require.Len(t, memoList, 2)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{TagList: []string{"work/clientA"}})
+	require.NoError(t, err)
+	require.Len(t, memoList, 2)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{TagList: []string{"work/clientB"}})
+	require.NoError(t, err)
+	require.Len(t, memoList, 1)
+	require.Equal(t, memo.ID, memoList[0].ID)
+
+	nodes, err := ts.ListTagTree(ctx, &store.FindMemoTag{CreatorID: &user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.TagNode{
+		{Name: "ideas", Path: "ideas", MemoCount: 1},
+		{Name: "work", Path: "work", MemoCount: 2, Children: []*store.TagNode{
+			{Name: "clientA", Path: "work/clientA", MemoCount: 2, Children: []*store.TagNode{
+				{Name: "urgent", Path: "work/clientA/urgent", MemoCount: 1},
+			}},
+			{Name: "clientB", Path: "work/clientB", MemoCount: 1},
+		}},
+		{Name: "workout", Path: "workout", MemoCount: 1},
+	}, nodes)
+	nodes, err = ts.ListTagTree(ctx, &store.FindMemoTag{CreatorID: &user.ID, VisibilityList: []store.Visibility{store.Public}})
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+	require.Equal(t, 1, nodes[0].MemoCount)
+}
+
+func TestRenameTag(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#work meeting with #work/clientA",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	other, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "#workout every day",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertTag(ctx, &store.Tag{Name: "work/clientA", CreatorID: user.ID})
+	require.NoError(t, err)
+
+	// The rename is rolled back with its activity.
+	_, err = ts.RenameTag(ctx, &store.RenameTag{
+		CreatorID: user.ID,
+		NameList:  []string{"work"},
+		NewName:   "job",
+		Activity: func(int) (*store.Activity, error) {
+			return nil, errors.New("failed to build activity")
+		},
+	})
+	require.Error(t, err)
+	memo, err = ts.GetMemo(ctx, &store.FindMemo{ID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, "#work meeting with #work/clientA", memo.Content)
+
+	count, err := ts.RenameTag(ctx, &store.RenameTag{
+		CreatorID: user.ID,
+		NameList:  []string{"work"},
+		NewName:   "job",
+		Activity: func(memoCount int) (*store.Activity, error) {
+			return &store.Activity{
+				CreatorID: user.ID,
+				Type:      "tag.rename",
+				Level:     "INFO",
+				Payload:   fmt.Sprintf(`{"memoCount":%d}`, memoCount),
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	var payload string
+	err = ts.GetDB().QueryRowContext(ctx, "SELECT payload FROM activity WHERE type = 'tag.rename'").Scan(&payload)
+	require.NoError(t, err)
+	require.Equal(t, `{"memoCount":1}`, payload)
+	memo, err = ts.GetMemo(ctx, &store.FindMemo{ID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, "#job meeting with #job/clientA", memo.Content)
+	other, err = ts.GetMemo(ctx, &store.FindMemo{ID: &other.ID})
+	require.NoError(t, err)
+	require.Equal(t, "#workout every day", other.Content)
+	memoTags, err := ts.ListMemoTags(ctx, &store.FindMemoTag{MemoID: &memo.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.MemoTag{{MemoID: memo.ID, Name: "job"}, {MemoID: memo.ID, Name: "job/clientA"}}, memoTags)
+	tags, err := ts.ListTags(ctx, &store.FindTag{CreatorID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []*store.Tag{{Name: "job/clientA", CreatorID: user.ID}}, tags)
+}
@@ -44,3 +44,234 @@ func TestMemoStore(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestMemoContentSearch(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	for _, content := range []string{
+		"Grocery list: apples and bananas",
+		"Meeting notes about apples supply #work",
+		"你好世界，今天天气不错",
+	} {
+		_, err := ts.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			Content:    content,
+			Visibility: store.Private,
+		})
+		require.NoError(t, err)
+	}
+
+	memoList, err := ts.ListMemos(ctx, &store.FindMemo{
+		ContentSearch: []string{"APPLES"},
+		OrderByRank:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(memoList))
+	// Snippets come from the full-text index of SQLite.
+	if ts.Profile.Driver == "sqlite" {
+		require.Contains(t, memoList[0].Snippet, "<mark>apples</mark>")
+	}
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		ContentSearch: []string{"apples", "#work"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoList))
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		ContentSearch: []string{"天气"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoList))
+	require.Equal(t, "", memoList[0].Snippet)
+
+	// The content of snippets is escaped, only the matches are marked up.
+	_, err = ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    `<script>alert("apricots")</script> <mark>`,
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		ContentSearch: []string{"apricots"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoList))
+	if ts.Profile.Driver == "sqlite" {
+		require.Equal(t, `&lt;script&gt;alert(&#34;<mark>apricots</mark>&#34;)&lt;/script&gt; &lt;mark&gt;`, memoList[0].Snippet)
+	}
+
+	// The index is kept in sync with memo content.
+	content := "Grocery list: pears"
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:      memoList[0].ID,
+		Content: &content,
+	})
+	require.NoError(t, err)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		ContentSearch: []string{"grocery"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(memoList))
+	for _, memo := range memoList {
+		err = ts.DeleteMemo(ctx, &store.DeleteMemo{
+			ID: memo.ID,
+		})
+		require.NoError(t, err)
+	}
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		ContentSearch: []string{"grocery"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoList))
+}
+
+func TestMemoPagination(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	other, err := ts.CreateUser(ctx, &store.User{
+		Username: "other",
+		Role:     store.RoleUser,
+		Email:    "other@test.com",
+	})
+	require.NoError(t, err)
+
+	for i, visibility := range []store.Visibility{store.Public, store.Protected, store.Private, store.Public, store.Private} {
+		_, err := ts.CreateMemo(ctx, &store.Memo{
+			CreatorID:  user.ID,
+			CreatedTs:  int64(1000 + i),
+			Content:    "memo",
+			Visibility: visibility,
+		})
+		require.NoError(t, err)
+	}
+	pinned, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  other.ID,
+		CreatedTs:  900,
+		Content:    "pinned memo",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: pinned.ID,
+		UserID: other.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+
+	// The other user sees the public and protected memos and its own private one.
+	find := &store.FindMemo{ViewerID: &other.ID}
+	count, err := ts.CountMemos(ctx, find)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+
+	limit := 2
+	find.Limit = &limit
+	ids := []int32{}
+	for {
+		memos, err := ts.ListMemos(ctx, find)
+		require.NoError(t, err)
+		for _, memo := range memos {
+			ids = append(ids, memo.ID)
+		}
+		if len(memos) < limit {
+			break
+		}
+		find.Cursor = store.NewMemoCursor(memos[len(memos)-1], find)
+	}
+	require.Equal(t, []int32{pinned.ID, 4, 2, 1}, ids)
+	count, err = ts.CountMemos(ctx, find)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+}
+
+func TestMemoFilter(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+
+	work, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		CreatedTs:  1000,
+		Content:    "#work/clientA meeting notes",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	ideas, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		CreatedTs:  2000,
+		Content:    "#ideas for the weekend",
+		Visibility: store.Private,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: ideas.ID,
+		UserID: user.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		filter *store.MemoFilter
+		want   []int32
+	}{
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterTag, Values: []any{"work"}},
+			want:   []int32{work.ID},
+		},
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterEqual, Field: store.MemoFilterCreator, Values: []any{user.Username}},
+			want:   []int32{ideas.ID, work.ID},
+		},
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterNot, Children: []*store.MemoFilter{
+				{Operator: store.MemoFilterEqual, Field: store.MemoFilterPinned, Values: []any{true}},
+			}},
+			want: []int32{work.ID},
+		},
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterOr, Children: []*store.MemoFilter{
+				{Operator: store.MemoFilterContains, Field: store.MemoFilterContent, Values: []any{"weekend"}},
+				{Operator: store.MemoFilterLess, Field: store.MemoFilterCreatedTs, Values: []any{int64(1500)}},
+			}},
+			want: []int32{ideas.ID, work.ID},
+		},
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterAnd, Children: []*store.MemoFilter{
+				{Operator: store.MemoFilterIn, Field: store.MemoFilterVisibility, Values: []any{store.Private, store.Protected}},
+				{Operator: store.MemoFilterEqual, Field: store.MemoFilterHasResource, Values: []any{false}},
+			}},
+			want: []int32{ideas.ID},
+		},
+		// The wildcards of LIKE are matched as they are.
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterContains, Field: store.MemoFilterContent, Values: []any{"%"}},
+			want:   []int32{},
+		},
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterContains, Field: store.MemoFilterContent, Values: []any{"meeting_notes"}},
+			want:   []int32{},
+		},
+		{
+			filter: &store.MemoFilter{Operator: store.MemoFilterContains, Field: store.MemoFilterContent, Values: []any{`\`}},
+			want:   []int32{},
+		},
+	}
+	for _, test := range tests {
+		memos, err := ts.ListMemos(ctx, &store.FindMemo{Filter: test.filter})
+		require.NoError(t, err)
+		ids := []int32{}
+		for _, memo := range memos {
+			ids = append(ids, memo.ID)
+		}
+		require.Equal(t, test.want, ids)
+	}
+
+	_, err = ts.ListMemos(ctx, &store.FindMemo{
+		Filter: &store.MemoFilter{Operator: store.MemoFilterContains, Field: store.MemoFilterTag, Values: []any{"work"}},
+	})
+	require.Error(t, err)
+}
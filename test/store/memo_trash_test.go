@@ -0,0 +1,141 @@
+package teststore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestMemoTrashStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	memo2, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content_2",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+
+	deletedTs := int64(100)
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
+	})
+	require.NoError(t, err)
+	memoList, err := ts.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoList))
+	require.Equal(t, memo2.ID, memoList[0].ID)
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		CreatorID: &user.ID,
+		InTrash:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoList))
+	require.Equal(t, memo.ID, memoList[0].ID)
+	require.Equal(t, deletedTs, memoList[0].DeletedTs)
+	deletedTsBefore := deletedTs
+	memoList, err = ts.ListMemos(ctx, &store.FindMemo{
+		InTrash:         true,
+		DeletedTsBefore: &deletedTsBefore,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoList))
+
+	// Restore the memo from trash.
+	deletedTs = 0
+	err = ts.UpdateMemo(ctx, &store.UpdateMemo{
+		ID:        memo.ID,
+		DeletedTs: &deletedTs,
+	})
+	require.NoError(t, err)
+	memo, err = ts.GetMemo(ctx, &store.FindMemo{
+		ID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, memo)
+	require.Equal(t, int64(0), memo.DeletedTs)
+}
+
+func TestDeleteMemoWithOrphanedRows(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	memo, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	memo2, err := ts.CreateMemo(ctx, &store.Memo{
+		CreatorID:  user.ID,
+		Content:    "test_content_2",
+		Visibility: store.Public,
+	})
+	require.NoError(t, err)
+	resource, err := ts.CreateResource(ctx, &store.Resource{
+		CreatorID: user.ID,
+		Filename:  "test.epub",
+		Blob:      []byte("test"),
+		Type:      "application/epub+zip",
+		Size:      637607,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoResource(ctx, &store.UpsertMemoResource{
+		MemoID:     memo.ID,
+		ResourceID: resource.ID,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoOrganizer(ctx, &store.MemoOrganizer{
+		MemoID: memo.ID,
+		UserID: user.ID,
+		Pinned: true,
+	})
+	require.NoError(t, err)
+	_, err = ts.UpsertMemoRelation(ctx, &store.MemoRelation{
+		MemoID:        memo2.ID,
+		RelatedMemoID: memo.ID,
+		Type:          store.MemoRelationReference,
+	})
+	require.NoError(t, err)
+
+	err = ts.DeleteMemo(ctx, &store.DeleteMemo{
+		ID: memo.ID,
+	})
+	require.NoError(t, err)
+	memoResourceList, err := ts.ListMemoResources(ctx, &store.FindMemoResource{
+		MemoID: &memo.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoResourceList))
+	_, err = ts.GetMemoOrganizer(ctx, &store.FindMemoOrganizer{
+		MemoID: memo.ID,
+		UserID: user.ID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	memoRelationList, err := ts.ListMemoRelations(ctx, &store.FindMemoRelation{
+		MemoID: &memo2.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(memoRelationList))
+	// The resource itself is kept.
+	resource, err = ts.GetResource(ctx, &store.FindResource{
+		ID: &resource.ID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resource)
+}
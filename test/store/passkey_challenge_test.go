@@ -0,0 +1,60 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestPasskeyChallengeStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	_, err := ts.CreatePasskeyChallenge(ctx, &store.PasskeyChallenge{
+		Challenge: "challenge",
+		ExpiresTs: 1700000000,
+	})
+	require.NoError(t, err)
+	_, err = ts.CreatePasskeyChallenge(ctx, &store.PasskeyChallenge{
+		Challenge: "another-challenge",
+		ExpiresTs: 1800000000,
+	})
+	require.NoError(t, err)
+	// The challenge is unique.
+	_, err = ts.CreatePasskeyChallenge(ctx, &store.PasskeyChallenge{
+		Challenge: "challenge",
+		ExpiresTs: 1700000000,
+	})
+	require.Error(t, err)
+
+	// Only the first deletion of a challenge deletes it.
+	challenge := "challenge"
+	deleted, err := ts.DeletePasskeyChallenge(ctx, &store.DeletePasskeyChallenge{
+		Challenge: &challenge,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	deleted, err = ts.DeletePasskeyChallenge(ctx, &store.DeletePasskeyChallenge{
+		Challenge: &challenge,
+	})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	// Nothing is deleted without a filter.
+	deleted, err = ts.DeletePasskeyChallenge(ctx, &store.DeletePasskeyChallenge{})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	expiresTsBefore := int64(1750000000)
+	deleted, err = ts.DeletePasskeyChallenge(ctx, &store.DeletePasskeyChallenge{
+		ExpiresTsBefore: &expiresTsBefore,
+	})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	expiresTsBefore = 1850000000
+	deleted, err = ts.DeletePasskeyChallenge(ctx, &store.DeletePasskeyChallenge{
+		ExpiresTsBefore: &expiresTsBefore,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
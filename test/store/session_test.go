@@ -0,0 +1,87 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestSessionStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	session, err := ts.CreateSession(ctx, &store.Session{
+		UserID:           user.ID,
+		ExpiresTs:        1700000000,
+		RefreshTokenHash: "hash",
+		UserAgent:        "Firefox",
+		IPAddress:        "127.0.0.1",
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateSession(ctx, &store.Session{
+		UserID:           user.ID,
+		ExpiresTs:        1700000000,
+		RefreshTokenHash: "another-hash",
+	})
+	require.NoError(t, err)
+
+	refreshTokenHash := "rotated-hash"
+	err = ts.UpdateSession(ctx, &store.UpdateSession{
+		ID:               session.ID,
+		RefreshTokenHash: &refreshTokenHash,
+	})
+	require.NoError(t, err)
+	session, err = ts.GetSession(ctx, &store.FindSession{
+		RefreshTokenHash: &refreshTokenHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Firefox", session.UserAgent)
+	require.Equal(t, "127.0.0.1", session.IPAddress)
+
+	// The refresh token is only rotated once, and the previous one is kept.
+	rotate := &store.RotateSessionRefreshToken{
+		ID:                  session.ID,
+		RefreshTokenHash:    refreshTokenHash,
+		NewRefreshTokenHash: "rotated-again-hash",
+		UpdatedTs:           1600000000,
+		ExpiresTs:           1700000000,
+		UserAgent:           "Chrome",
+		IPAddress:           "127.0.0.2",
+	}
+	rotated, err := ts.RotateSessionRefreshToken(ctx, rotate)
+	require.NoError(t, err)
+	require.True(t, rotated)
+	rotate.NewRefreshTokenHash = "concurrent-hash"
+	rotated, err = ts.RotateSessionRefreshToken(ctx, rotate)
+	require.NoError(t, err)
+	require.False(t, rotated)
+	session, err = ts.GetSession(ctx, &store.FindSession{
+		PreviousRefreshTokenHash: &refreshTokenHash,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "rotated-again-hash", session.RefreshTokenHash)
+	require.Equal(t, "Chrome", session.UserAgent)
+
+	err = ts.DeleteSession(ctx, &store.DeleteSession{
+		ID: &session.ID,
+	})
+	require.NoError(t, err)
+	sessionList, err := ts.ListSessions(ctx, &store.FindSession{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, sessionList, 1)
+
+	err = ts.DeleteSession(ctx, &store.DeleteSession{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	sessionList, err = ts.ListSessions(ctx, &store.FindSession{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, sessionList, 0)
+}
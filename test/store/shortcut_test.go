@@ -0,0 +1,66 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestShortcutStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	shortcut, err := ts.CreateShortcut(ctx, &store.Shortcut{
+		CreatorID: user.ID,
+		Title:     "work",
+		Payload: &store.ShortcutPayload{
+			Tags: []string{"work"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "work", shortcut.Title)
+	require.Equal(t, store.Normal, shortcut.RowStatus)
+	title := "public work"
+	shortcut, err = ts.UpdateShortcut(ctx, &store.UpdateShortcut{
+		ID:    shortcut.ID,
+		Title: &title,
+		Payload: &store.ShortcutPayload{
+			Tags:           []string{"work"},
+			VisibilityList: []store.Visibility{store.Public},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, title, shortcut.Title)
+	shortcutList, err := ts.ListShortcuts(ctx, &store.FindShortcut{
+		CreatorID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(shortcutList))
+	require.Equal(t, shortcut, shortcutList[0])
+
+	for _, memo := range []*store.Memo{
+		{CreatorID: user.ID, Content: "#work public", Visibility: store.Public},
+		{CreatorID: user.ID, Content: "#work private", Visibility: store.Private},
+		{CreatorID: user.ID, Content: "#life public", Visibility: store.Public},
+	} {
+		_, err := ts.CreateMemo(ctx, memo)
+		require.NoError(t, err)
+	}
+	memoList, err := ts.ListMemos(ctx, shortcut.Payload.FindMemo(user.ID))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(memoList))
+	require.Equal(t, "#work public", memoList[0].Content)
+
+	err = ts.DeleteShortcut(ctx, &store.DeleteShortcut{
+		ID: shortcut.ID,
+	})
+	require.NoError(t, err)
+	shortcutList, err = ts.ListShortcuts(ctx, &store.FindShortcut{
+		CreatorID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(shortcutList))
+}
@@ -0,0 +1,100 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestUserCredentialStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	userCredential, err := ts.CreateUserCredential(ctx, &store.UserCredential{
+		UserID:       user.ID,
+		Name:         "Laptop",
+		CredentialID: "credential-id",
+		PublicKey:    "public-key",
+	})
+	require.NoError(t, err)
+	_, err = ts.CreateUserCredential(ctx, &store.UserCredential{
+		UserID:       user.ID,
+		Name:         "Phone",
+		CredentialID: "another-credential-id",
+		PublicKey:    "another-public-key",
+	})
+	require.NoError(t, err)
+	// The credential ID is unique.
+	_, err = ts.CreateUserCredential(ctx, &store.UserCredential{
+		UserID:       user.ID,
+		CredentialID: "credential-id",
+		PublicKey:    "public-key",
+	})
+	require.Error(t, err)
+
+	signCount, lastUsedTs := int64(42), int64(1700000000)
+	err = ts.UpdateUserCredential(ctx, &store.UpdateUserCredential{
+		ID:         userCredential.ID,
+		SignCount:  &signCount,
+		LastUsedTs: &lastUsedTs,
+	})
+	require.NoError(t, err)
+	credentialID := "credential-id"
+	userCredential, err = ts.GetUserCredential(ctx, &store.FindUserCredential{
+		CredentialID: &credentialID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.ID, userCredential.UserID)
+	require.Equal(t, "Laptop", userCredential.Name)
+	require.Equal(t, "public-key", userCredential.PublicKey)
+	require.Equal(t, signCount, userCredential.SignCount)
+	require.Equal(t, lastUsedTs, userCredential.LastUsedTs)
+
+	// The sign count is only updated from the stored one.
+	updated, err := ts.UpdateUserCredentialSignCount(ctx, &store.UpdateUserCredentialSignCount{
+		ID:              userCredential.ID,
+		StoredSignCount: signCount,
+		SignCount:       signCount + 1,
+		LastUsedTs:      lastUsedTs + 1,
+	})
+	require.NoError(t, err)
+	require.True(t, updated)
+	updated, err = ts.UpdateUserCredentialSignCount(ctx, &store.UpdateUserCredentialSignCount{
+		ID:              userCredential.ID,
+		StoredSignCount: signCount,
+		SignCount:       signCount + 1,
+		LastUsedTs:      lastUsedTs + 2,
+	})
+	require.NoError(t, err)
+	require.False(t, updated)
+	userCredential, err = ts.GetUserCredential(ctx, &store.FindUserCredential{
+		CredentialID: &credentialID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, signCount+1, userCredential.SignCount)
+	require.Equal(t, lastUsedTs+1, userCredential.LastUsedTs)
+
+	err = ts.DeleteUserCredential(ctx, &store.DeleteUserCredential{
+		ID: &userCredential.ID,
+	})
+	require.NoError(t, err)
+	userCredentialList, err := ts.ListUserCredentials(ctx, &store.FindUserCredential{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, userCredentialList, 1)
+	require.Equal(t, "Phone", userCredentialList[0].Name)
+
+	err = ts.DeleteUserCredential(ctx, &store.DeleteUserCredential{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	userCredentialList, err = ts.ListUserCredentials(ctx, &store.FindUserCredential{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, userCredentialList, 0)
+}
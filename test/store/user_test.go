@@ -36,6 +36,44 @@ func TestUserStore(t *testing.T) {
 	require.Equal(t, 0, len(users))
 }
 
+func TestUserIdentityProviderLink(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	_, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	user, err := ts.CreateUser(ctx, &store.User{
+		Username:                   "john",
+		Role:                       store.RoleUser,
+		OpenID:                     "john_open_id",
+		IdentityProviderID:         1,
+		IdentityProviderIdentifier: "248289761001",
+	})
+	require.NoError(t, err)
+
+	identityProviderID, identifier := int32(1), "248289761001"
+	find := &store.FindUser{
+		IdentityProviderID:         &identityProviderID,
+		IdentityProviderIdentifier: &identifier,
+	}
+	users, err := ts.ListUsers(ctx, find)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, user.ID, users[0].ID)
+	// The link is kept when the user is renamed.
+	username := "johnny"
+	user, err = ts.UpdateUser(ctx, &store.UpdateUser{
+		ID:       user.ID,
+		Username: &username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, identityProviderID, user.IdentityProviderID)
+	require.Equal(t, identifier, user.IdentityProviderIdentifier)
+	identityProviderID = 2
+	users, err = ts.ListUsers(ctx, find)
+	require.NoError(t, err)
+	require.Len(t, users, 0)
+}
+
 func createTestingHostUser(ctx context.Context, ts *store.Store) (*store.User, error) {
 	userCreate := &store.User{
 		Username: "test",
@@ -0,0 +1,109 @@
+package teststore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usememos/memos/store"
+)
+
+func TestUserTOTPStore(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTestingStore(ctx, t)
+	user, err := createTestingHostUser(ctx, ts)
+	require.NoError(t, err)
+	userTOTP, err := ts.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Nil(t, userTOTP)
+
+	userTOTP, err = ts.UpsertUserTOTP(ctx, &store.UserTOTP{
+		UserID: user.ID,
+		Secret: "JBSWY3DPEHPK3PXP",
+	})
+	require.NoError(t, err)
+	require.False(t, userTOTP.Enabled)
+	require.Empty(t, userTOTP.RecoveryCodes)
+
+	enabled, recoveryCodes, lastUsedStep := true, []string{"hash1", "hash2"}, int64(56666666)
+	err = ts.UpdateUserTOTP(ctx, &store.UpdateUserTOTP{
+		UserID:        user.ID,
+		Enabled:       &enabled,
+		RecoveryCodes: &recoveryCodes,
+		LastUsedStep:  &lastUsedStep,
+	})
+	require.NoError(t, err)
+	userTOTP, err = ts.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, userTOTP.Enabled)
+	require.Equal(t, recoveryCodes, userTOTP.RecoveryCodes)
+	require.Equal(t, lastUsedStep, userTOTP.LastUsedStep)
+
+	// A code and a recovery code are only consumed once.
+	consumed, err := ts.ConsumeUserTOTPStep(ctx, user.ID, lastUsedStep+1)
+	require.NoError(t, err)
+	require.True(t, consumed)
+	consumed, err = ts.ConsumeUserTOTPStep(ctx, user.ID, lastUsedStep+1)
+	require.NoError(t, err)
+	require.False(t, consumed)
+	consumed, err = ts.ConsumeUserTOTPRecoveryCode(ctx, user.ID, recoveryCodes, recoveryCodes[1:])
+	require.NoError(t, err)
+	require.True(t, consumed)
+	consumed, err = ts.ConsumeUserTOTPRecoveryCode(ctx, user.ID, recoveryCodes, recoveryCodes[1:])
+	require.NoError(t, err)
+	require.False(t, consumed)
+
+	// The attempts are refused once the maximum is reached, until none has been made since the reset time.
+	attempt := &store.AttemptUserTOTP{
+		UserID:            user.ID,
+		AttemptedTs:       1000,
+		MaxFailedAttempts: 2,
+		ResetTs:           100,
+	}
+	for _, expected := range []bool{true, true, false} {
+		attempted, err := ts.AttemptUserTOTP(ctx, attempt)
+		require.NoError(t, err)
+		require.Equal(t, expected, attempted)
+	}
+	attempt.AttemptedTs, attempt.ResetTs = 3000, 2000
+	attempted, err := ts.AttemptUserTOTP(ctx, attempt)
+	require.NoError(t, err)
+	require.True(t, attempted)
+	userTOTP, err = ts.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), userTOTP.FailedAttempts)
+	require.Equal(t, int64(3000), userTOTP.LastAttemptedTs)
+	consumed, err = ts.ConsumeUserTOTPStep(ctx, user.ID, lastUsedStep+2)
+	require.NoError(t, err)
+	require.True(t, consumed)
+	userTOTP, err = ts.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, userTOTP.FailedAttempts)
+
+	// Enrolling a new secret resets the two-factor authentication.
+	userTOTP, err = ts.UpsertUserTOTP(ctx, &store.UserTOTP{
+		UserID: user.ID,
+		Secret: "KRSXG5CTMVRXEZLU",
+	})
+	require.NoError(t, err)
+	require.False(t, userTOTP.Enabled)
+	require.Equal(t, "KRSXG5CTMVRXEZLU", userTOTP.Secret)
+
+	err = ts.DeleteUserTOTP(ctx, &store.DeleteUserTOTP{
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	userTOTP, err = ts.GetUserTOTP(ctx, &store.FindUserTOTP{
+		UserID: &user.ID,
+	})
+	require.NoError(t, err)
+	require.Nil(t, userTOTP)
+}
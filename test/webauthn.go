@@ -0,0 +1,249 @@
+package test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// The COSE algorithms supported by the software authenticator.
+const (
+	WebAuthnAlgorithmES256 = -7
+	WebAuthnAlgorithmEdDSA = -8
+	WebAuthnAlgorithmRS256 = -257
+)
+
+// WebAuthnAttestation is the response of the software authenticator to a registration, with base64url encoded fields.
+type WebAuthnAttestation struct {
+	CredentialID      string
+	ClientDataJSON    string
+	AttestationObject string
+}
+
+// WebAuthnAssertion is the response of the software authenticator to an authentication, with base64url encoded fields.
+type WebAuthnAssertion struct {
+	CredentialID      string
+	ClientDataJSON    string
+	AuthenticatorData string
+	Signature         string
+	UserHandle        string
+}
+
+// WebAuthnAuthenticator is a software authenticator, which creates discoverable credentials and signs assertions
+// with them as a browser along with a platform authenticator would do. The user is always present and verified.
+type WebAuthnAuthenticator struct {
+	// Origin is the origin of the page calling the WebAuthn API, whose host is the relying party ID.
+	Origin string
+	// Algorithm is the COSE algorithm of the credentials created.
+	Algorithm int
+	// WithoutSignCount keeps the signature counter at zero, as many synced passkeys do.
+	WithoutSignCount bool
+
+	credentials []*webAuthnCredential
+}
+
+type webAuthnCredential struct {
+	id         []byte
+	rpID       string
+	userHandle string
+	privateKey crypto.Signer
+	signCount  uint32
+}
+
+// NewWebAuthnAuthenticator returns a software authenticator for the origin creating ES256 credentials.
+func NewWebAuthnAuthenticator(origin string) *WebAuthnAuthenticator {
+	return &WebAuthnAuthenticator{
+		Origin:    origin,
+		Algorithm: WebAuthnAlgorithmES256,
+	}
+}
+
+// Register creates a new credential for the user handle, and signs the challenge of the registration.
+func (a *WebAuthnAuthenticator) Register(challenge, userHandle string) (*WebAuthnAttestation, error) {
+	rpID, err := a.rpID()
+	if err != nil {
+		return nil, err
+	}
+	credential := &webAuthnCredential{
+		id:         make([]byte, 32),
+		rpID:       rpID,
+		userHandle: userHandle,
+	}
+	if _, err := rand.Read(credential.id); err != nil {
+		return nil, err
+	}
+	coseKey := cborMap{{1, nil}, {3, a.Algorithm}}
+	switch a.Algorithm {
+	case WebAuthnAlgorithmES256:
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		credential.privateKey = privateKey
+		coseKey[0][1] = 2
+		coseKey = append(coseKey, cborMap{{-1, 1}, {-2, privateKey.X.FillBytes(make([]byte, 32))}, {-3, privateKey.Y.FillBytes(make([]byte, 32))}}...)
+	case WebAuthnAlgorithmEdDSA:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		credential.privateKey = privateKey
+		coseKey[0][1] = 1
+		coseKey = append(coseKey, cborMap{{-1, 6}, {-2, []byte(publicKey)}}...)
+	case WebAuthnAlgorithmRS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		credential.privateKey = privateKey
+		coseKey[0][1] = 3
+		coseKey = append(coseKey, cborMap{{-1, privateKey.N.Bytes()}, {-2, big.NewInt(int64(privateKey.E)).Bytes()}}...)
+	default:
+		return nil, errors.Errorf("unsupported algorithm %d", a.Algorithm)
+	}
+
+	// The attested credential data is the zero AAGUID, the length of the credential ID, the credential ID and the COSE key.
+	attestedCredentialData := make([]byte, 18, 18+len(credential.id))
+	binary.BigEndian.PutUint16(attestedCredentialData[16:], uint16(len(credential.id)))
+	attestedCredentialData = append(attestedCredentialData, credential.id...)
+	attestedCredentialData = append(attestedCredentialData, encodeCBOR(coseKey)...)
+	authenticatorData := append(credential.authenticatorData(0x45), attestedCredentialData...)
+	clientDataJSON, err := a.clientDataJSON("webauthn.create", challenge)
+	if err != nil {
+		return nil, err
+	}
+	attestationObject := encodeCBOR(cborMap{
+		{"fmt", "none"},
+		{"attStmt", cborMap{}},
+		{"authData", authenticatorData},
+	})
+	a.credentials = append(a.credentials, credential)
+
+	return &WebAuthnAttestation{
+		CredentialID:      base64.RawURLEncoding.EncodeToString(credential.id),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+		AttestationObject: base64.RawURLEncoding.EncodeToString(attestationObject),
+	}, nil
+}
+
+// Assert signs the challenge of an authentication with the last credential created for the relying party,
+// increasing its signature counter.
+func (a *WebAuthnAuthenticator) Assert(challenge string) (*WebAuthnAssertion, error) {
+	rpID, err := a.rpID()
+	if err != nil {
+		return nil, err
+	}
+	var credential *webAuthnCredential
+	for _, c := range a.credentials {
+		if c.rpID == rpID {
+			credential = c
+		}
+	}
+	if credential == nil {
+		return nil, errors.Errorf("no credential found for %s", rpID)
+	}
+
+	if !a.WithoutSignCount {
+		credential.signCount++
+	}
+	authenticatorData := credential.authenticatorData(0x05)
+	clientDataJSON, err := a.clientDataJSON("webauthn.get", challenge)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte{}, authenticatorData...), clientDataHash[:]...)
+	var signature []byte
+	if _, ok := credential.privateKey.(ed25519.PrivateKey); ok {
+		signature, err = credential.privateKey.Sign(rand.Reader, signedData, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(signedData)
+		signature, err = credential.privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnAssertion{
+		CredentialID:      base64.RawURLEncoding.EncodeToString(credential.id),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authenticatorData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+		UserHandle:        credential.userHandle,
+	}, nil
+}
+
+func (a *WebAuthnAuthenticator) rpID() (string, error) {
+	u, err := url.Parse(a.Origin)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse origin")
+	}
+	return u.Hostname(), nil
+}
+
+func (a *WebAuthnAuthenticator) clientDataJSON(ceremonyType, challenge string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        ceremonyType,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+}
+
+// authenticatorData returns the relying party ID hash, the flags and the signature counter.
+func (c *webAuthnCredential) authenticatorData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(c.rpID))
+	data := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[33:], c.signCount)
+	return data
+}
+
+// cborMap is a CBOR map, whose entries are encoded in order.
+type cborMap [][2]any
+
+// encodeCBOR encodes the integers, byte strings, text strings and maps used by the WebAuthn structures.
+func encodeCBOR(v any) []byte {
+	header := func(majorType byte, argument uint64) []byte {
+		switch {
+		case argument < 24:
+			return []byte{majorType<<5 | byte(argument)}
+		case argument <= 0xff:
+			return []byte{majorType<<5 | 24, byte(argument)}
+		case argument <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{majorType<<5 | 25}, uint16(argument))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{majorType<<5 | 26}, uint32(argument))
+		}
+	}
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return header(1, uint64(-1-v))
+		}
+		return header(0, uint64(v))
+	case []byte:
+		return append(header(2, uint64(len(v))), v...)
+	case string:
+		return append(header(3, uint64(len(v))), v...)
+	case cborMap:
+		data := header(5, uint64(len(v)))
+		for _, entry := range v {
+			data = append(data, encodeCBOR(entry[0])...)
+			data = append(data, encodeCBOR(entry[1])...)
+		}
+		return data
+	default:
+		panic(errors.Errorf("unsupported cbor type %T", v))
+	}
+}